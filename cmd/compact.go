@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"moviebot/internal/config"
+	"moviebot/internal/trace"
+)
+
+// runCompact backs the configured store up and compacts it.
+func runCompact(cfg *config.Config) error {
+	store := newStore(cfg)
+
+	ctx := trace.NewContext(context.Background())
+	dir, err := store.Backup(ctx)
+	if err != nil {
+		return fmt.Errorf("backup failed, nothing compacted: %w", err)
+	}
+	report := store.Compact(ctx)
+
+	log.Printf("[COMPACT] Backup in %s\n%s", dir, report)
+	return nil
+}
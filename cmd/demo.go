@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"moviebot/internal/omdb"
+	"moviebot/internal/storage"
+	"moviebot/internal/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	demoChatID = 1
+	demoUserID = 1
+)
+
+// runDemo runs the bot fully offline: OMDb is replaced by the bundled sample
+// dataset, Telegram by a console. Lines starting with "/" are commands, a bare
+// number presses that button on the last card, "react 👍" reacts to it ("react"
+// alone takes the reaction away), anything else is sent as a reply to the
+// bot's last message (which is how the /movie prompt works).
+func runDemo() error {
+	tmpDir, err := os.MkdirTemp("", "moviebot-demo-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// The regular logs would drown the conversation.
+	log.SetOutput(io.Discard)
+
+	store := storage.NewStore(
+		filepath.Join(tmpDir, "movies.json"),
+		filepath.Join(tmpDir, "message_index.json"),
+		time.Second,
+		10,
+	)
+
+	var (
+		mu         sync.Mutex
+		lastMsgID  int
+		lastCardID int
+		buttons    []tgbotapi.InlineKeyboardButton
+	)
+
+	api := telegram.NewLogAPI()
+	api.Echo = func(msg tgbotapi.Message, btns []tgbotapi.InlineKeyboardButton) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		text := msg.Text
+		if text == "" {
+			text = msg.Caption
+		}
+		if text != "" {
+			fmt.Printf("\n🤖 %s\n", strings.ReplaceAll(text, "\n", "\n   "))
+			lastMsgID = msg.MessageID
+		}
+		if len(btns) > 0 {
+			buttons = btns
+			lastCardID = msg.MessageID
+			for i, b := range btns {
+				fmt.Printf("   [%d] %s\n", i+1, b.Text)
+			}
+		}
+	}
+
+	bot := telegram.NewBot(api, omdb.NewDemoClient(), store, maxAlt)
+	bot.OwnerIDs = []int64{demoUserID} // so operator commands can be tried too
+	bot.Discussions = true
+
+	fmt.Println("🎬 moviebot demo — no Telegram, no OMDb key, nothing is kept.")
+	fmt.Println("Try /movie dune, /movie (then type a title), /list. Type a number to press a button, 'react 👍' to react, 'quit' to leave.")
+
+	user := &tgbotapi.User{ID: demoUserID, UserName: "demo", FirstName: "Demo"}
+	chat := &tgbotapi.Chat{ID: demoChatID, Type: "private"}
+	updateID, messageID := 0, 0
+	reactions := make(map[int][]telegram.ReactionType) // card -> our reactions
+
+	in := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("\n> ")
+		if !in.Scan() {
+			return in.Err()
+		}
+		line := strings.TrimSpace(in.Text())
+		if line == "" {
+			continue
+		}
+		if line == "quit" || line == "exit" {
+			return nil
+		}
+
+		updateID++
+		messageID++
+		update := telegram.Update{Update: tgbotapi.Update{UpdateID: updateID}}
+
+		if n, err := strconv.Atoi(line); err == nil {
+			mu.Lock()
+			if n < 1 || n > len(buttons) || buttons[n-1].CallbackData == nil {
+				mu.Unlock()
+				fmt.Println("No such button.")
+				continue
+			}
+			data, cardID := *buttons[n-1].CallbackData, lastCardID
+			mu.Unlock()
+
+			update.CallbackQuery = &tgbotapi.CallbackQuery{
+				ID:      strconv.Itoa(updateID),
+				From:    user,
+				Message: &tgbotapi.Message{MessageID: cardID, Chat: chat},
+				Data:    data,
+			}
+			bot.HandleUpdate(update)
+			continue
+		}
+
+		if emoji, ok := strings.CutPrefix(line, "react"); ok && (emoji == "" || emoji[0] == ' ') {
+			mu.Lock()
+			cardID := lastCardID
+			mu.Unlock()
+			var now []telegram.ReactionType
+			if emoji = strings.TrimSpace(emoji); emoji != "" {
+				now = []telegram.ReactionType{{Type: "emoji", Emoji: emoji}}
+			}
+			update.MessageReaction = &telegram.MessageReactionUpdated{
+				Chat:        *chat,
+				MessageID:   cardID,
+				User:        user,
+				Date:        int(time.Now().Unix()),
+				OldReaction: reactions[cardID],
+				NewReaction: now,
+			}
+			reactions[cardID] = now
+			bot.HandleUpdate(update)
+			continue
+		}
+
+		msg := &tgbotapi.Message{MessageID: messageID, From: user, Chat: chat, Text: line}
+		if strings.HasPrefix(line, "/") {
+			cmdLen := len(strings.Fields(line)[0])
+			msg.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: cmdLen}}
+		} else {
+			mu.Lock()
+			msg.ReplyToMessage = &tgbotapi.Message{MessageID: lastMsgID, Chat: chat}
+			mu.Unlock()
+		}
+		update.Message = msg
+		bot.HandleUpdate(update)
+	}
+}
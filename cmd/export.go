@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"moviebot/internal/config"
+	"moviebot/internal/storage"
+)
+
+// runExportHTML renders a chat's library as a static HTML page; chat 0 is
+// the shared library.
+func runExportHTML(cfg *config.Config, path string, chatID int64) error {
+	store := newStore(cfg)
+
+	data, err := storage.ExportHTML(store.GetLibrary(store.LibraryOf(chatID)), "Movie night watchlist")
+	if err != nil {
+		return fmt.Errorf("failed to render HTML: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	log.Printf("[EXPORT] Wrote %s (%d bytes)", path, len(data))
+	return nil
+}
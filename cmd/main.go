@@ -1,64 +1,200 @@
 package main
 
 import (
-	"os"
-	"time"
+	"context"
+	"expvar"
+	"flag"
 	"log"
+	"net/http"
+	"net/http/pprof"
+	"os"
 	"os/exec"
-	
+	"os/signal"
+	"path/filepath"
+	"runtime/debug"
+	"syscall"
+	"time"
+	_ "time/tzdata" // chat time zones work on images without zoneinfo
+
+	"moviebot/internal/alerts"
 	"moviebot/internal/config"
+	"moviebot/internal/digest"
+	"moviebot/internal/events"
+	"moviebot/internal/frontend"
+	"moviebot/internal/maintenance"
+	"moviebot/internal/matrix"
+	"moviebot/internal/mediaserver"
+	"moviebot/internal/notion"
 	"moviebot/internal/omdb"
+	"moviebot/internal/posters"
+	"moviebot/internal/refresh"
+	"moviebot/internal/sheets"
+	"moviebot/internal/slack"
+	"moviebot/internal/storage"
+	"moviebot/internal/streaming"
 	"moviebot/internal/telegram"
-    "moviebot/internal/storage"
-	
+	"moviebot/internal/tmdb"
+	"moviebot/internal/trace"
+	"moviebot/internal/trailers"
+	"moviebot/internal/transcribe"
+	"moviebot/internal/watchparty"
+	"moviebot/internal/web"
+	"moviebot/internal/webhooks"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 const (
-	baseDir    = "/config"
-	configDir  = "/config/config"
-	moviesFile = "/config/data/movies.json"
+	baseDir          = "/config"
+	configDir        = "/config/config"
+	moviesFile       = "/config/data/movies.json"
 	messageIndexFile = "/config/data/message_index.json"
- 	maxAlt          = 5
+	maxAlt           = 5
 )
 
+// Set at build via ldflags.
+var (
+	BuildTime string
+	Commit    string
+)
 
-var BuildTime string // set at build via ldflags
+// buildCommit is the Commit from the ldflags, else the revision Go recorded
+// when building from a git checkout, "-dirty" with uncommitted changes.
+func buildCommit() string {
+	if Commit != "" {
+		return Commit
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var rev, dirty string
+	for _, s := range info.Settings {
+		switch {
+		case s.Key == "vcs.revision":
+			rev = s.Value[:min(len(s.Value), 12)]
+		case s.Key == "vcs.modified" && s.Value == "true":
+			dirty = "-dirty"
+		}
+	}
+	if rev == "" {
+		return ""
+	}
+	return rev + dirty
+}
 
 func main() {
+	cfgDir := flag.String("config", configDir, "directory containing config.json")
+	replayFile := flag.String("replay", "", "replay recorded updates from a JSON file against a temp store, without contacting Telegram")
+	mergeFile := flag.String("merge", "", "merge another instance's movies.json into the configured store and exit")
+	exportHTML := flag.String("export-html", "", "write the watchlist as a self-contained HTML page to this path and exit")
+	exportChat := flag.Int64("chat", 0, "chat whose library -export-html writes, with per_chat_libraries (default: the shared library)")
+	sendDigest := flag.Bool("send-digest", false, "email the weekly digest now and exit")
+	compact := flag.Bool("compact", false, "back up and compact the configured store (merge duplicates, drop stale message refs) and exit")
+	importPlays := flag.Bool("import-plays", false, "mark what mapped users watched on the media server as watched, whole history, and exit")
+	demo := flag.Bool("demo", false, "run an offline console demo with bundled sample movies (no tokens needed)")
+	flag.Parse()
+
+	if *demo {
+		if err := runDemo(); err != nil {
+			log.Fatal("[DEMO] ", err)
+		}
+		return
+	}
+
 	log.Println("[BOT] Starting movie bot")
 	log.Println("[BOT] Build time:", BuildTime)
-
-	// Optional: self-restart watcher
-	go watchSelf()
+	log.Println("[BOT] Commit:", buildCommit())
 
 	/* =========================
 	   LOAD CONFIG
 	   ========================= */
 
-	cfg, err := config.Load("/config/config")
+	cfg, err := config.Load(*cfgDir)
 	if err != nil {
 		log.Fatal("[BOT] Failed to load config:", err)
 	}
 
+	if *replayFile != "" {
+		if err := runReplay(cfg, *replayFile); err != nil {
+			log.Fatal("[REPLAY] ", err)
+		}
+		return
+	}
+
+	if *mergeFile != "" {
+		if err := runMerge(cfg, *mergeFile); err != nil {
+			log.Fatal("[MERGE] ", err)
+		}
+		return
+	}
+
+	if *exportHTML != "" {
+		if err := runExportHTML(cfg, *exportHTML, *exportChat); err != nil {
+			log.Fatal("[EXPORT] ", err)
+		}
+		return
+	}
+
+	if *sendDigest {
+		if err := newMailer(cfg).SendWeekly(newStore(cfg)); err != nil {
+			log.Fatal("[DIGEST] ", err)
+		}
+		return
+	}
+
+	if *compact {
+		if err := runCompact(cfg); err != nil {
+			log.Fatal("[COMPACT] ", err)
+		}
+		return
+	}
+
+	if *importPlays {
+		if err := runImportPlays(cfg); err != nil {
+			log.Fatal("[MEDIA] ", err)
+		}
+		return
+	}
+
+	// Optional: self-restart watcher
+	go watchSelf()
+
+	if cfg.Pprof.Enabled {
+		go startPprof(cfg.Pprof.Listen)
+	}
+
 	/* =========================
 	   INIT STORAGE
 	   ========================= */
 
-	store := storage.NewStore(
-		cfg.Storage.MoviesFile,
-		cfg.Storage.MessageIndexFile,
-		cfg.Storage.SessionTTL,
-		cfg.Storage.MaxMessages,
-	)
+	store := newStore(cfg)
 
+	var alerter *alerts.Alerter
+	if cfg.Alerts.Enabled {
+		alerter = alerts.New(cfg.Alerts.Threshold, cfg.Alerts.Window, cfg.Alerts.Cooldown)
+		store.SetAlerts(alerter)
+	}
 
 	/* =========================
 	   INIT OMDb
 	   ========================= */
 
 	omdbClient := omdb.NewClient(cfg.OmdbAPIKey)
+	omdbClient.Alerts = alerter
+
+	var tmdbClient *tmdb.Client
+	if cfg.TMDB.APIKey != "" {
+		tmdbClient = tmdb.NewClient(cfg.TMDB.APIKey)
+	}
+
+	mode := maintenance.New(cfg.Maintenance.Enabled, cfg.Maintenance.Message)
 
+	if cfg.Email.Enabled {
+		mailer := newMailer(cfg)
+		mailer.Maintenance = mode
+		go mailer.RunWeekly(store)
+	}
 
 	// Telegram bot
 	tgBot, err := tgbotapi.NewBotAPI(cfg.TelegramToken)
@@ -67,50 +203,273 @@ func main() {
 	}
 	log.Printf("[Bot] Authorized on %s", tgBot.Self.UserName)
 
-	bot := telegram.NewBot(tgBot, omdbClient, store, maxAlt)
+	bot := telegram.NewBot(telegram.NewAPI(tgBot), omdbClient, store, maxAlt)
+	configureBot(bot, cfg, tmdbClient, mode)
+	bot.Alerts = alerter
+	bot.BuildTime = BuildTime
+	bot.Commit = buildCommit()
+	alerter.SetNotify(bot.AlertOwners)
+	expvar.Publish("handlers", expvar.Func(func() any { return bot.HandlerStats() }))
+
+	if cfg.Web.Enabled {
+		srv := web.New(cfg.Web, store)
+		srv.OnGuestVote = bot.RefreshRound
+		go func() {
+			if err := srv.Run(context.Background()); err != nil {
+				log.Println("[WEB] Server stopped:", err)
+			}
+		}()
+		bot.PublicBaseURL = cfg.Web.BaseURL
+	}
+
+	bus := events.NewBus()
+	if len(cfg.Webhooks) > 0 {
+		bus.Subscribe(webhooks.NewDispatcher(cfg.Webhooks).Handle)
+	}
+	if cfg.Sheets.Enabled {
+		if syncer, err := sheets.NewSyncer(cfg.Sheets, store); err != nil {
+			log.Println("[SHEETS] Disabled:", err)
+		} else {
+			bus.Subscribe(syncer.Handle)
+			go syncer.Handle(events.Event{}) // initial full sync
+			bot.FullSyncs = append(bot.FullSyncs, func() { syncer.Handle(events.Event{}) })
+		}
+	}
+	if cfg.Notion.Enabled {
+		syncer := notion.NewSyncer(cfg.Notion, store)
+		bus.Subscribe(syncer.Handle)
+		go syncer.SyncAll()
+		bot.FullSyncs = append(bot.FullSyncs, syncer.SyncAll)
+	}
+	bot.Events = bus
+	bus.Subscribe(bot.HandleEvent)
+
+	if cfg.Refresh.Enabled {
+		job := refresh.NewJob(cfg.Refresh, store, omdbClient, tmdbClient, bus)
+		job.Maintenance = mode
+		go job.Run()
+	}
 
+	if cfg.Matrix.Enabled {
+		go runFrontend(matrix.New(cfg.Matrix, &frontend.Core{
+			Source:  "matrix",
+			Store:   store,
+			Library: store.LibraryOf(cfg.Matrix.ChatID),
+			OMDb:    omdbClient,
+			TMDB:    tmdbClient,
+			Events:  bus,
+			Format:  storage.DefaultTableFormat(),
+			MaxAlt:  maxAlt,
+		}))
+	}
 
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
-	updates := tgBot.GetUpdatesChan(u)
+	if cfg.Slack.Enabled {
+		go runFrontend(slack.New(cfg.Slack, &frontend.Core{
+			Source:  "slack",
+			Store:   store,
+			Library: store.LibraryOf(cfg.Slack.ChatID),
+			OMDb:    omdbClient,
+			TMDB:    tmdbClient,
+			Events:  bus,
+			Format:  storage.DefaultTableFormat(),
+			MaxAlt:  maxAlt,
+		}))
+	}
+
+	bot.RegisterCommands(context.Background())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	updates, teardown := receiveUpdates(ctx, cfg.Telegram, tgBot, bot)
+	go bot.RunDeadlines(ctx)
+	if cfg.MediaServer.Enabled {
+		if syncer, err := newMediaSyncer(cfg, store, bus); err != nil {
+			log.Printf("[MEDIA][WARN] Watch history sync disabled: %v", err)
+		} else {
+			syncer.Maintenance = mode
+			go syncer.Run(ctx)
+		}
+	}
+	go bot.RunReminders(ctx)
 
 	log.Println("[Bot] Listening for updates...")
-	for update := range updates {
-		bot.HandleUpdate(update)
+	for running := true; running; {
+		select {
+		case update, ok := <-updates:
+			if ok {
+				bot.HandleUpdate(update)
+			}
+			running = ok
+		case <-ctx.Done():
+			running = false
+		}
+	}
+
+	log.Println("[Bot] Shutting down")
+	teardown()
+	store.Flush()
+}
+
+// newStore opens the store at the configured paths.
+func newStore(cfg *config.Config) *storage.Store {
+	store := storage.NewStore(
+		cfg.Storage.MoviesFile,
+		cfg.Storage.MessageIndexFile,
+		cfg.Storage.SaveDelay,
+		cfg.Storage.MaxMessages,
+	)
+	store.SetIndexLimits(cfg.Storage.MaxIndexRefs, cfg.Storage.MessageMaxAge)
+	store.SetAttendanceWindow(cfg.Nights.AttendanceWindow)
+	store.SetPerChatLibraries(cfg.PerChatLibraries)
+	store.SetPrivateLibraries(cfg.PrivateWatchlists)
+	var options []storage.VoteOption
+	for _, o := range cfg.VoteOptions {
+		options = append(options, storage.VoteOption{Emoji: o.Emoji, Label: o.Label, Weight: o.Weight})
+	}
+	storage.SetVoteOptions(options)
+	durability, ok := storage.ParseDurability(cfg.Storage.Durability)
+	if !ok {
+		log.Printf("[CONFIG][WARN] Unknown storage.durability %q, using the default", cfg.Storage.Durability)
 	}
+	store.SetDurability(durability)
+	return store
 }
 
+// configureBot applies the config to a bot: who owns it, which features are
+// on, language, list and night settings, and the optional clients.
+func configureBot(bot *telegram.Bot, cfg *config.Config, tmdbClient *tmdb.Client, mode *maintenance.Mode) {
+	bot.OwnerIDs = cfg.OwnerIDs
+	bot.AdminIDs = cfg.AdminIDs
+	bot.SessionTTL = cfg.Storage.SessionTTL
+	bot.Maintenance = mode
+	bot.TMDB = tmdbClient
+	bot.Discussions = cfg.Discussions.Enabled
+	bot.DiscussionTopics = cfg.Discussions.Topics
+	bot.NightCooldown = cfg.Nights.Cooldown
+	bot.NightReminders = cfg.Nights.Reminders
+	bot.ReminderDMs = cfg.Nights.ReminderDMs
+	bot.ListPageSize = max(cfg.ListPageSize, 0)
+	bot.ListSyncIdle = max(cfg.ListSyncIdle, 0)
+	if cfg.LanguageDefault != "" {
+		bot.Language = cfg.LanguageDefault
+	}
+	bot.SlowHandler = cfg.Pprof.SlowHandler
+	bot.NotifySuggesters = cfg.Notifications.Enabled
+	bot.Quorum = cfg.Notifications.Quorum
+	if cfg.WatchParty.URLTemplate != "" || cfg.WatchParty.Jellyfin.URL != "" {
+		bot.WatchParty = watchparty.New(cfg.WatchParty)
+	}
+	if cfg.Transcription.Enabled {
+		bot.Transcriber = transcribe.NewClient(cfg.Transcription)
+	}
+	if cfg.Trailers.Enabled {
+		bot.Trailers = trailers.NewClient(cfg.Trailers, tmdbClient)
+	}
+	if cfg.Streaming.Enabled {
+		if tmdbClient == nil {
+			log.Printf("[CONFIG][WARN] streaming.enabled needs tmdb.api_key, /where stays off")
+		} else {
+			bot.Streaming = streaming.NewClient(cfg.Streaming, tmdbClient)
+		}
+	}
+	bot.Posters = posterCache(cfg)
+	bot.Disabled = make(map[string]bool)
+	for name, on := range cfg.Features.Flags() {
+		bot.Disabled[name] = !on
+	}
+}
+
+// newMailer builds the digest mailer, with collages from posterCache.
+func newMailer(cfg *config.Config) *digest.Mailer {
+	m := digest.NewMailer(cfg.Email)
+	m.Posters = posterCache(cfg)
+	return m
+}
+
+// posterCache keeps downloaded posters next to the movies file.
+func posterCache(cfg *config.Config) *posters.Cache {
+	return posters.NewCache(filepath.Join(filepath.Dir(cfg.Storage.MoviesFile), "posters"))
+}
+
+// newMediaSyncer builds the watch history sync; its state is kept next to
+// the movies file.
+func newMediaSyncer(cfg *config.Config, store *storage.Store, bus *events.Bus) (*mediaserver.Syncer, error) {
+	return mediaserver.NewSyncer(cfg.MediaServer, store, bus, filepath.Join(filepath.Dir(cfg.Storage.MoviesFile), "mediaserver.json"))
+}
+
+// runImportPlays imports the media server's whole watch history and writes
+// the result to disk before returning.
+func runImportPlays(cfg *config.Config) error {
+	store := newStore(cfg)
+	syncer, err := newMediaSyncer(cfg, store, nil)
+	if err != nil {
+		return err
+	}
+	res, err := syncer.Import(trace.NewContext(context.Background()), true)
+	store.Flush()
+	if err != nil {
+		return err
+	}
+	log.Printf("[MEDIA] Imported %s", res)
+	return nil
+}
+
+// runFrontend runs an additional chat frontend next to Telegram. A failing
+// frontend is logged but does not take the Telegram bot down with it.
+func runFrontend(f frontend.Frontend) {
+	if err := f.Run(context.Background()); err != nil {
+		log.Printf("[BOT] Frontend %s stopped: %v", f.Name(), err)
+	}
+}
 
 func watchSelf() {
-    log.Println("[Watcher] Starting...")
-    exePath, err := os.Executable()
-    if err != nil {
-        log.Println("[Watcher] Cannot get executable path:", err)
-        return
-    }
-
-    info, err := os.Stat(exePath)
-    if err != nil {
-        log.Println("[Watcher] Cannot stat executable:", err)
-        return
-    }
-    lastMod := info.ModTime()
-
-    for {
-        time.Sleep(2 * time.Second)
-        info, err := os.Stat(exePath)
-        if err != nil {
-            log.Println("[Watcher] Cannot stat executable:", err)
-            continue
-        }
-        if info.ModTime().After(lastMod) {
-            log.Println("[Watcher] Executable changed, restarting...")
-            // Re-exec self
-            err := exec.Command(exePath, os.Args[1:]...).Start()
-            if err != nil {
-                log.Println("[Watcher] Failed to restart:", err)
-            }
-            os.Exit(0)
-        }
-    }
-}
\ No newline at end of file
+	log.Println("[Watcher] Starting...")
+	exePath, err := os.Executable()
+	if err != nil {
+		log.Println("[Watcher] Cannot get executable path:", err)
+		return
+	}
+
+	info, err := os.Stat(exePath)
+	if err != nil {
+		log.Println("[Watcher] Cannot stat executable:", err)
+		return
+	}
+	lastMod := info.ModTime()
+
+	for {
+		time.Sleep(2 * time.Second)
+		info, err := os.Stat(exePath)
+		if err != nil {
+			log.Println("[Watcher] Cannot stat executable:", err)
+			continue
+		}
+		if info.ModTime().After(lastMod) {
+			log.Println("[Watcher] Executable changed, restarting...")
+			// Re-exec self
+			err := exec.Command(exePath, os.Args[1:]...).Start()
+			if err != nil {
+				log.Println("[Watcher] Failed to restart:", err)
+			}
+			os.Exit(0)
+		}
+	}
+}
+
+// startPprof serves the net/http/pprof handlers and expvars on their own mux
+// so nothing else registered on http.DefaultServeMux is exposed alongside
+// them.
+func startPprof(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	log.Printf("[PPROF] Listening on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Println("[PPROF] Listener stopped:", err)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"moviebot/internal/config"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// runMerge imports another instance's movies.json into the configured store
+// and writes the result to disk before returning.
+func runMerge(cfg *config.Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	var movies []storage.Movie
+	if err := json.Unmarshal(data, &movies); err != nil {
+		return fmt.Errorf("invalid JSON in import file: %w", err)
+	}
+
+	store := newStore(cfg)
+
+	ctx := trace.NewContext(context.Background())
+	report := store.MergeMovies(ctx, movies)
+	store.Flush()
+
+	log.Printf("[MERGE] %s", report)
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/maintenance"
+	"moviebot/internal/omdb"
+	"moviebot/internal/telegram"
+	"moviebot/internal/tmdb"
+)
+
+// runReplay feeds a JSON array of recorded Telegram updates through the
+// regular handlers, with the store and bot set up from the config just as
+// they are when running for real. Storage lives in a throwaway directory
+// seeded with copies of every data file, and all outgoing Telegram calls
+// are logged instead of sent. OMDb and the other configured services are
+// still queried.
+func runReplay(cfg *config.Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read replay file: %w", err)
+	}
+
+	var updates []telegram.Update
+	if err := json.Unmarshal(data, &updates); err != nil {
+		return fmt.Errorf("invalid JSON in replay file: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "moviebot-replay-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	replayCfg, err := copyData(cfg, tmpDir)
+	if err != nil {
+		return err
+	}
+	store := newStore(replayCfg)
+
+	var tmdbClient *tmdb.Client
+	if cfg.TMDB.APIKey != "" {
+		tmdbClient = tmdb.NewClient(cfg.TMDB.APIKey)
+	}
+	bot := telegram.NewBot(telegram.NewLogAPI(), omdb.NewClient(cfg.OmdbAPIKey), store, maxAlt)
+	configureBot(bot, replayCfg, tmdbClient, maintenance.New(cfg.Maintenance.Enabled, cfg.Maintenance.Message))
+
+	log.Printf("[REPLAY] Replaying %d updates from %s (temp store: %s)", len(updates), path, tmpDir)
+	start := time.Now()
+	for i, update := range updates {
+		log.Printf("[REPLAY] Update %d/%d (id %d)", i+1, len(updates), update.UpdateID)
+		bot.HandleUpdate(update)
+	}
+	log.Printf("[REPLAY] Done in %v, %d movies in store", time.Since(start), len(store.GetAllMovies()))
+	return nil
+}
+
+// copyData copies the data files, the movies file and everything kept next
+// to it plus the message index, into dir and returns a copy of cfg that
+// points at them.
+func copyData(cfg *config.Config, dir string) (*config.Config, error) {
+	dataDir := filepath.Dir(cfg.Storage.MoviesFile)
+	entries, err := os.ReadDir(dataDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read data dir: %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			files = append(files, filepath.Join(dataDir, e.Name()))
+		}
+	}
+	files = append(files, cfg.Storage.MessageIndexFile)
+
+	for _, src := range files {
+		data, err := os.ReadFile(src)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", src, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(src)), data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", src, err)
+		}
+	}
+
+	c := *cfg
+	c.Storage.MoviesFile = filepath.Join(dir, filepath.Base(cfg.Storage.MoviesFile))
+	c.Storage.MessageIndexFile = filepath.Join(dir, filepath.Base(cfg.Storage.MessageIndexFile))
+	return &c, nil
+}
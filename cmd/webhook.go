@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// receiveUpdates starts delivering Telegram updates the configured way. In
+// webhook mode it returns a teardown that removes the webhook again; when
+// the webhook can't be set up, or its server dies, it falls back to long
+// polling. Only the polling channel closes when ctx is done, the webhook one
+// stays open, so callers stop reading on ctx rather than on a close.
+func receiveUpdates(ctx context.Context, cfg config.TelegramConfig, tgBot *tgbotapi.BotAPI, bot *telegram.Bot) (<-chan telegram.Update, func()) {
+	poll := func() <-chan telegram.Update {
+		// getUpdates is refused while a webhook is set, e.g. one left
+		// behind by a crashed webhook run.
+		if _, err := tgBot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+			log.Println("[Bot] Removing the webhook failed:", err)
+		}
+		u := tgbotapi.NewUpdate(0)
+		u.Timeout = 60
+		u.AllowedUpdates = telegram.AllowedUpdates
+		return bot.PollUpdates(ctx, telegram.GetUpdates(tgBot), u)
+	}
+
+	if cfg.Mode != "webhook" {
+		return poll(), func() {}
+	}
+
+	out := make(chan telegram.Update, 100)
+	failed, err := serveWebhook(ctx, cfg.Webhook, tgBot, out)
+	if err != nil {
+		log.Println("[Bot] Webhook unavailable, falling back to polling:", err)
+		return poll(), func() {}
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-failed:
+			log.Println("[Bot] Webhook server stopped, falling back to polling:", err)
+		}
+		for u := range poll() {
+			out <- u
+		}
+	}()
+
+	teardown := func() {
+		if _, err := tgBot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+			log.Println("[Bot] Removing the webhook failed:", err)
+			return
+		}
+		log.Println("[Bot] Webhook removed")
+	}
+	return out, teardown
+}
+
+// serveWebhook listens for Telegram's webhook calls and registers the
+// webhook once the listener is up. Updates go to out; the returned channel
+// reports the server dying later on.
+func serveWebhook(ctx context.Context, cfg config.TelegramWebhookConfig, tgBot *tgbotapi.BotAPI, out chan<- telegram.Update) (<-chan error, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("telegram.webhook.url is not set")
+	}
+	public, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	path := cfg.Path
+	if path == "" {
+		path = public.Path
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	secret := cfg.SecretToken
+	if secret == "" {
+		b := make([]byte, 24)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("generating the webhook secret: %w", err)
+		}
+		secret = hex.EncodeToString(b)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+path, func(w http.ResponseWriter, r *http.Request) {
+		got := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		var u telegram.Update
+		if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+			http.Error(w, "bad update", http.StatusBadRequest)
+			return
+		}
+		select {
+		case out <- u:
+		case <-r.Context().Done():
+		}
+	})
+
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	params := tgbotapi.Params{"url": cfg.URL, "secret_token": secret}
+	if err := params.AddInterface("allowed_updates", telegram.AllowedUpdates); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	if cfg.SelfSigned && cfg.CertFile != "" {
+		_, err = tgBot.UploadFiles("setWebhook", params, []tgbotapi.RequestFile{
+			{Name: "certificate", Data: tgbotapi.FilePath(cfg.CertFile)},
+		})
+	} else {
+		_, err = tgBot.MakeRequest("setWebhook", params)
+	}
+	if err != nil {
+		ln.Close()
+		return nil, err
+	}
+
+	failed := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	go func() {
+		var err error
+		if cfg.CertFile != "" && cfg.KeyFile != "" {
+			err = srv.ServeTLS(ln, cfg.CertFile, cfg.KeyFile)
+		} else {
+			err = srv.Serve(ln)
+		}
+		if ctx.Err() == nil {
+			failed <- err
+		}
+	}()
+
+	log.Printf("[Bot] Webhook set to %s, serving %s on %s", cfg.URL, path, cfg.Listen)
+	return failed, nil
+}
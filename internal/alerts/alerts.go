@@ -0,0 +1,134 @@
+package alerts
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Defaults for a zero Threshold, Window or Cooldown.
+const (
+	DefaultThreshold = 5
+	DefaultWindow    = 10 * time.Minute
+	DefaultCooldown  = time.Hour
+)
+
+// Alerter tells the bot owners about failures that keep happening: storage
+// writes, requests Telegram rejects, a bad OMDb key. Failures are counted
+// per kind; once a kind fails Threshold times within Window the owners get
+// one note, and then no other about that kind for Cooldown, however often
+// it keeps failing. A nil Alerter drops everything.
+type Alerter struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+
+	mu     sync.Mutex
+	notify func(text string)
+	kinds  map[string]*failures
+}
+
+// failures is the recent history of one kind.
+type failures struct {
+	at         []time.Time // within the window, oldest first
+	last       error
+	alertedAt  time.Time
+	suppressed int // failures since the last alert
+}
+
+func New(threshold int, window, cooldown time.Duration) *Alerter {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	return &Alerter{threshold: threshold, window: window, cooldown: cooldown, kinds: make(map[string]*failures)}
+}
+
+// SetNotify sets where alerts go. Until it is set they are only logged.
+func (a *Alerter) SetNotify(fn func(text string)) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.notify = fn
+}
+
+// Failure records one failure of a kind ("storage", "OMDb", ...). Alerts
+// are delivered on their own goroutine, so it is safe to call with locks
+// held and from the notify path itself.
+func (a *Alerter) Failure(kind string, err error) {
+	if a == nil {
+		return
+	}
+	now := time.Now()
+
+	a.mu.Lock()
+	f := a.kinds[kind]
+	if f == nil {
+		f = &failures{}
+		a.kinds[kind] = f
+	}
+	for len(f.at) > 0 && now.Sub(f.at[0]) > a.window {
+		f.at = f.at[1:]
+	}
+	f.at = append(f.at, now)
+	f.last = err
+	f.suppressed++
+
+	if len(f.at) < a.threshold || now.Sub(f.alertedAt) < a.cooldown {
+		a.mu.Unlock()
+		return
+	}
+	text := fmt.Sprintf("🚨 %s: %d failures in the last %s.\nLatest: %v", kind, len(f.at), a.window, err)
+	if !f.alertedAt.IsZero() && f.suppressed > len(f.at) {
+		text += fmt.Sprintf("\n(%d failures since the last alert)", f.suppressed)
+	}
+	f.alertedAt = now
+	f.suppressed = 0
+	notify := a.notify
+	a.mu.Unlock()
+
+	log.Printf("[ALERT] %s", text)
+	if notify != nil {
+		go notify(text)
+	}
+}
+
+// Status is one kind with its recent failures, for the admin panel.
+type Status struct {
+	Kind      string
+	Recent    int // within the window
+	Last      error
+	AlertedAt time.Time
+}
+
+// Recent returns every kind that failed within the window.
+func (a *Alerter) Recent() []Status {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	var out []Status
+	for kind, f := range a.kinds {
+		n := 0
+		for _, t := range f.at {
+			if now.Sub(t) <= a.window {
+				n++
+			}
+		}
+		if n > 0 {
+			out = append(out, Status{Kind: kind, Recent: n, Last: f.last, AlertedAt: f.alertedAt})
+		}
+	}
+	return out
+}
@@ -4,26 +4,434 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Debug           bool   `json:"debug"`
-	TelegramToken   string `json:"telegram_token"`
-	OmdbAPIKey      string `json:"omdb_api_key"`
-	LanguageDefault string `json:"language_fallback"`
-	MaxAlternatives int    `json:"max_alternatives"`
+	Debug           bool    `json:"debug"`
+	TelegramToken   string  `json:"telegram_token"`
+	OmdbAPIKey      string  `json:"omdb_api_key"`
+	LanguageDefault string  `json:"language_fallback"`
+	MaxAlternatives int     `json:"max_alternatives"`
+	OwnerIDs        []int64 `json:"owner_ids"`
+	// AdminIDs count as chat admins in every chat, for curation and
+	// destructive commands, without the owners' operator commands.
+	AdminIDs []int64 `json:"admin_ids"`
+	// ListPageSize is how many movies one /list page shows (default 25);
+	// negative sends the whole list in one message.
+	ListPageSize int `json:"list_page_size"`
+	// ListSyncIdle stops live list updates in chats quiet for this long
+	// (nanoseconds, default 7 days); negative keeps every chat in sync.
+	ListSyncIdle time.Duration `json:"list_sync_idle"`
+	// PerChatLibraries gives every chat its own watchlist, lists and votes
+	// instead of one library shared by all; /adopt moves the shared one
+	// into a chat.
+	PerChatLibraries bool `json:"per_chat_libraries"`
+	// PrivateWatchlists gives everyone a watchlist of their own in their
+	// private chat with the bot, also while the groups share one library.
+	PrivateWatchlists bool `json:"private_watchlists"`
+	// VoteOptions replaces the 👍 vote with reactions of different weight,
+	// e.g. 😍 must watch (2), 🙂 fine (1), 😴 pass (0). Movies are then
+	// sorted and picked by their summed weight. Empty keeps plain votes.
+	VoteOptions []VoteOption `json:"vote_options"`
 
-	Storage StorageConfig `json:"storage"`
+	Telegram TelegramConfig `json:"telegram"`
+	Storage  StorageConfig  `json:"storage"`
+	Pprof    PprofConfig    `json:"pprof"`
+	Features FeaturesConfig `json:"features"`
+
+	Webhooks []WebhookConfig `json:"webhooks"`
+
+	Matrix MatrixConfig `json:"matrix"`
+	Slack  SlackConfig  `json:"slack"`
+
+	Email  EmailConfig  `json:"email"`
+	Sheets SheetsConfig `json:"sheets"`
+	Notion NotionConfig `json:"notion"`
+
+	Refresh RefreshConfig `json:"refresh"`
+	TMDB    TMDBConfig    `json:"tmdb"`
+
+	WatchParty    WatchPartyConfig    `json:"watch_party"`
+	MediaServer   MediaServerConfig   `json:"media_server"`
+	Trailers      TrailersConfig      `json:"trailers"`
+	Streaming     StreamingConfig     `json:"streaming"`
+	Transcription TranscriptionConfig `json:"transcription"`
+	Maintenance   MaintenanceConfig   `json:"maintenance"`
+	Discussions   DiscussionsConfig   `json:"discussions"`
+	Nights        NightsConfig        `json:"nights"`
+	Notifications NotificationsConfig `json:"notifications"`
+	Alerts        AlertsConfig        `json:"alerts"`
+	Web           WebConfig           `json:"web"`
+}
+
+// VoteOption is one reaction on vote cards. A reaction worth more than 0
+// also counts as a vote.
+type VoteOption struct {
+	Emoji  string  `json:"emoji"`
+	Label  string  `json:"label"`
+	Weight float64 `json:"weight"`
+}
+
+// TelegramConfig picks how updates arrive: Mode "polling" (the default)
+// asks Telegram for them, "webhook" has Telegram push them to Webhook.URL.
+type TelegramConfig struct {
+	Mode    string                `json:"mode"`
+	Webhook TelegramWebhookConfig `json:"webhook"`
+}
+
+// TelegramWebhookConfig is the HTTPS endpoint Telegram posts updates to. URL
+// is the public address, path included; Listen is where the bot serves it,
+// usually behind a TLS proxy. With CertFile and KeyFile the bot serves TLS
+// itself, and SelfSigned uploads the certificate to Telegram. Requests must
+// carry SecretToken; a random one is used when it is empty. The webhook is
+// registered on start and removed on shutdown, and the bot falls back to
+// polling if it can't be set up.
+type TelegramWebhookConfig struct {
+	URL         string `json:"url"`
+	Listen      string `json:"listen"`
+	Path        string `json:"path"` // defaults to the path of URL
+	SecretToken string `json:"secret_token"`
+	CertFile    string `json:"cert_file"`
+	KeyFile     string `json:"key_file"`
+	SelfSigned  bool   `json:"self_signed"`
+}
+
+// FeaturesConfig switches whole subsystems off for a deployment, whatever
+// their own settings say. Everything left out of the file is on.
+type FeaturesConfig struct {
+	InlineMode   bool                `json:"inline_mode"`   // @bot searches from any chat
+	WebDashboard bool                `json:"web_dashboard"` // the web server, public lists and guest votes
+	Scheduler    bool                `json:"scheduler"`     // movie nights: /schedule, /tonight and their reminders
+	Games        bool                `json:"games"`         // /quiz and badges
+	Integrations IntegrationFeatures `json:"integrations"`
+}
+
+// IntegrationFeatures switches the outside services the bot talks to.
+type IntegrationFeatures struct {
+	Webhooks    bool `json:"webhooks"`
+	Matrix      bool `json:"matrix"`
+	Slack       bool `json:"slack"`
+	Email       bool `json:"email"`
+	Sheets      bool `json:"sheets"`
+	Notion      bool `json:"notion"`
+	MediaServer bool `json:"media_server"`
 }
 
+func defaultFeatures() FeaturesConfig {
+	return FeaturesConfig{
+		InlineMode:   true,
+		WebDashboard: true,
+		Scheduler:    true,
+		Games:        true,
+		Integrations: IntegrationFeatures{
+			Webhooks:    true,
+			Matrix:      true,
+			Slack:       true,
+			Email:       true,
+			Sheets:      true,
+			Notion:      true,
+			MediaServer: true,
+		},
+	}
+}
+
+// Flags lists every feature by its name in the config file, integrations
+// as "integrations.matrix", with whether it is on.
+func (f FeaturesConfig) Flags() map[string]bool {
+	return map[string]bool{
+		"inline_mode":               f.InlineMode,
+		"web_dashboard":             f.WebDashboard,
+		"scheduler":                 f.Scheduler,
+		"games":                     f.Games,
+		"integrations.webhooks":     f.Integrations.Webhooks,
+		"integrations.matrix":       f.Integrations.Matrix,
+		"integrations.slack":        f.Integrations.Slack,
+		"integrations.email":        f.Integrations.Email,
+		"integrations.sheets":       f.Integrations.Sheets,
+		"integrations.notion":       f.Integrations.Notion,
+		"integrations.media_server": f.Integrations.MediaServer,
+	}
+}
+
+// applyFeatures turns off the settings of the subsystems whose feature is
+// off, so the rest of the bot only has to look at those.
+func (c *Config) applyFeatures() {
+	f := c.Features
+	if !f.WebDashboard {
+		c.Web.Enabled = false
+	}
+	if !f.Integrations.Webhooks {
+		c.Webhooks = nil
+	}
+	if !f.Integrations.Matrix {
+		c.Matrix.Enabled = false
+	}
+	if !f.Integrations.Slack {
+		c.Slack.Enabled = false
+	}
+	if !f.Integrations.Email {
+		c.Email.Enabled = false
+	}
+	if !f.Integrations.Sheets {
+		c.Sheets.Enabled = false
+	}
+	if !f.Integrations.Notion {
+		c.Notion.Enabled = false
+	}
+	if !f.Integrations.MediaServer {
+		c.MediaServer.Enabled = false
+	}
+	flags := f.Flags()
+	for _, name := range slices.Sorted(maps.Keys(flags)) {
+		if !flags[name] {
+			log.Printf("[CONFIG] Feature %s is off", name)
+		}
+	}
+}
+
+// Save delay bounds. Shorter delays turn bursts of votes into bursts of
+// writes; longer ones risk losing that much on a crash.
+const (
+	defaultSaveDelay = 2 * time.Second
+	minSaveDelay     = 100 * time.Millisecond
+	maxSaveDelay     = time.Minute
+)
+
 type StorageConfig struct {
 	MoviesFile       string        `json:"movies_file"`
 	MessageIndexFile string        `json:"message_index_file"`
-	SessionTTL       time.Duration `json:"session_ttl"`
-	MaxMessages      int 		   `json:"max_messages"`
+	SessionTTL       time.Duration `json:"session_ttl"` // how long a /movie prompt waits for a title (default 5m)
+	MaxMessages      int           `json:"max_messages"`
+	// SaveDelay is how long after the last change the data files are
+	// written, so a burst of votes is one write (nanoseconds, default 2s,
+	// kept between 100ms and 1m). Durability "fsync-per-mutation" ignores it.
+	SaveDelay time.Duration `json:"save_delay"`
+	// MaxIndexRefs caps the message index across all movies and lists,
+	// oldest refs evicted first; MessageMaxAge evicts refs older than that.
+	// Evicted messages simply stop being kept in sync. 0 disables either.
+	MaxIndexRefs  int           `json:"max_index_refs"`
+	MessageMaxAge time.Duration `json:"message_max_age"`
+	// Durability is "" (batched writes, the OS syncs), "fsync" (batched,
+	// fsynced and atomically replaced) or "fsync-per-mutation" (every change
+	// is on disk before the bot answers).
+	Durability string `json:"durability"`
+}
+
+// PprofConfig controls the optional net/http/pprof listener. Keep it bound to
+// localhost unless the port is otherwise firewalled. When it is enabled,
+// handler latencies are also published as the "handlers" expvar at
+// /debug/vars on the same listener. SlowHandler is how long one update may
+// take before a warning with its trace ID is logged (default 2s).
+type PprofConfig struct {
+	Enabled     bool          `json:"enabled"`
+	Listen      string        `json:"listen"`
+	SlowHandler time.Duration `json:"slow_handler"`
+}
+
+// WebhookConfig is one outgoing webhook. Events lists the event types to
+// deliver (movie_added, vote_changed, movie_watched, night_scheduled,
+// movie_removed); empty means all. When Secret is set, payloads are signed with HMAC-SHA256.
+type WebhookConfig struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+// WebConfig enables the bot's HTTP server, which serves read-only public
+// list pages. BaseURL is how the server is reached from outside (e.g.
+// https://movies.example.com) and is used to build the links the bot hands
+// out; it defaults to the listen address on localhost.
+type WebConfig struct {
+	Enabled bool   `json:"enabled"`
+	Listen  string `json:"listen"`
+	BaseURL string `json:"base_url"`
+}
+
+// MatrixConfig enables the Matrix frontend. It shares the store with
+// Telegram, so votes from either platform land on the same list.
+type MatrixConfig struct {
+	Enabled     bool     `json:"enabled"`
+	Homeserver  string   `json:"homeserver"`
+	UserID      string   `json:"user_id"`
+	AccessToken string   `json:"access_token"`
+	Rooms       []string `json:"rooms"`
+	ChatID      int64    `json:"chat_id,omitempty"` // chat whose library the rooms share, 0 for the shared one
+}
+
+// SlackConfig enables the Slack slash-command surface. Listen is the address
+// of the HTTP endpoint Slack posts commands and button clicks to.
+type SlackConfig struct {
+	Enabled       bool   `json:"enabled"`
+	Listen        string `json:"listen"`
+	SigningSecret string `json:"signing_secret"`
+	ChatID        int64  `json:"chat_id,omitempty"` // chat whose library Slack uses, 0 for the shared one
+}
+
+// EmailConfig enables the weekly email digest. It is sent every Weekday
+// (e.g. "sunday") at Hour local time.
+type EmailConfig struct {
+	Enabled  bool     `json:"enabled"`
+	SMTPHost string   `json:"smtp_host"`
+	SMTPPort int      `json:"smtp_port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	Weekday  string   `json:"weekday"`
+	Hour     int      `json:"hour"`
+	ChatID   int64    `json:"chat_id,omitempty"` // chat whose library is digested, 0 for the shared one
+}
+
+// SheetsConfig mirrors the list into a Google Sheet using a service account
+// key file. Share the spreadsheet with the service account's email.
+type SheetsConfig struct {
+	Enabled         bool   `json:"enabled"`
+	CredentialsFile string `json:"credentials_file"`
+	SpreadsheetID   string `json:"spreadsheet_id"`
+	SheetName       string `json:"sheet_name"`
+	ChatID          int64  `json:"chat_id,omitempty"` // chat whose library is mirrored, 0 for the shared one
+}
+
+// NotionConfig upserts movies into a Notion database through an internal
+// integration token. Share the database with the integration.
+type NotionConfig struct {
+	Enabled    bool   `json:"enabled"`
+	Token      string `json:"token"`
+	DatabaseID string `json:"database_id"`
+	ChatID     int64  `json:"chat_id,omitempty"` // chat whose library is mirrored, 0 for the shared one
+}
+
+// RefreshConfig drives the background metadata refresh. Durations are
+// nanoseconds in JSON, like session_ttl.
+type RefreshConfig struct {
+	Enabled     bool          `json:"enabled"`
+	Interval    time.Duration `json:"interval"`     // time between batches
+	BatchSize   int           `json:"batch_size"`   // movies per batch
+	MaxAge      time.Duration `json:"max_age"`      // refresh movies older than this
+	DailyBudget int           `json:"daily_budget"` // OMDb calls the job may spend per day
+}
+
+// TMDBConfig holds the optional TMDB v3 API key used for data OMDb lacks
+// (collections, popularity for ranking search results, ...). Features
+// depending on it stay off when it is empty.
+type TMDBConfig struct {
+	APIKey string `json:"api_key"`
+}
+
+// TrailersConfig turns on /trailer and the Trailer button on vote cards.
+// Trailers are looked up on TMDB when tmdb.api_key is set, then through
+// the YouTube Data API when YouTubeAPIKey is.
+type TrailersConfig struct {
+	Enabled       bool   `json:"enabled"`
+	YouTubeAPIKey string `json:"youtube_api_key"`
+	Language      string `json:"language"` // ISO 639-1 of the preferred trailers, "en" when empty
+}
+
+// StreamingConfig turns on /where and the Where button on vote cards.
+// Availability comes from TMDB's watch providers (data by JustWatch), so
+// tmdb.api_key must be set too.
+type StreamingConfig struct {
+	Enabled bool   `json:"enabled"`
+	Region  string `json:"region"` // ISO 3166-1 country the services are listed for, "US" when empty
+}
+
+// WatchPartyConfig attaches a watch-together link to scheduled movie nights.
+// URLTemplate may use {title}, {year}, {imdb_id} and {time}, e.g. a
+// Teleparty or Syncplay room URL. A Jellyfin server holding the movie takes
+// precedence.
+type WatchPartyConfig struct {
+	URLTemplate string         `json:"url_template"`
+	Jellyfin    JellyfinConfig `json:"jellyfin"`
+}
+
+// JellyfinConfig points at a Jellyfin server and an API key created in its
+// dashboard.
+type JellyfinConfig struct {
+	URL    string `json:"url"`
+	APIKey string `json:"api_key"`
+}
+
+// MediaServerConfig imports watch history from a Jellyfin or Plex server:
+// movies a mapped user played there are marked watched for them. Users maps
+// server users (Jellyfin user names, Plex account IDs) to Telegram user IDs.
+// -import-plays imports the whole history once; Enabled keeps syncing new
+// plays every Interval (default 1h). Chats maps server users to the chats
+// whose libraries their plays mark; users not in it mark the library of
+// their private chat with the bot.
+type MediaServerConfig struct {
+	Enabled  bool               `json:"enabled"`
+	Kind     string             `json:"kind"` // "jellyfin" or "plex"
+	URL      string             `json:"url"`
+	Token    string             `json:"token"` // Jellyfin API key or Plex token
+	Users    map[string]int64   `json:"users"`
+	Chats    map[string][]int64 `json:"chats,omitempty"`
+	Interval time.Duration      `json:"interval"`
+}
+
+// TranscriptionConfig lets users answer the "What movie?" prompt with a voice
+// message. URL is an OpenAI-compatible transcriptions endpoint, either the
+// OpenAI API or a local Whisper server.
+type TranscriptionConfig struct {
+	Enabled  bool   `json:"enabled"`
+	URL      string `json:"url"`
+	APIKey   string `json:"api_key"`
+	Model    string `json:"model"`
+	Language string `json:"language"` // ISO-639-1 hint, empty to autodetect
+}
+
+// MaintenanceConfig starts the bot in maintenance mode, e.g. while data
+// files are being migrated. Owners can toggle it at runtime with /maintenance.
+type MaintenanceConfig struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"` // shown to users, a default when empty
+}
+
+// DiscussionsConfig opens a discussion thread per movie once a chat has
+// watched it, where members can post 1-10 ratings. With Topics, forum-enabled
+// groups get a dedicated topic (the bot needs the "manage topics" right);
+// other chats get a reply chain under the vote card.
+type DiscussionsConfig struct {
+	Enabled bool `json:"enabled"`
+	Topics  bool `json:"topics"`
+}
+
+// NightsConfig tunes movie night scheduling. Cooldown is the minimum gap
+// between two nights in a chat (nanoseconds in JSON); while a scheduled
+// movie is still unwatched, no new night can be set up either. Chat admins
+// can override with /schedule --force. Marking a movie watched within
+// AttendanceWindow of its night counts as attending it. Chats are reminded
+// of a night Reminders before it (default 24h and 1h, [] for none), and
+// with ReminderDMs those who answered Going or Maybe get a DM too.
+type NightsConfig struct {
+	Cooldown         time.Duration   `json:"cooldown"`
+	AttendanceWindow time.Duration   `json:"attendance_window"`
+	Reminders        []time.Duration `json:"reminders"`
+	ReminderDMs      bool            `json:"reminder_dms"`
+}
+
+// NotificationsConfig controls the DMs a suggester gets about the movie they
+// added: its first vote, reaching Quorum votes and being scheduled. Users
+// can still opt out with /notify off.
+type NotificationsConfig struct {
+	Enabled bool `json:"enabled"`
+	Quorum  int  `json:"quorum"`
+}
+
+// AlertsConfig DMs the owners when something keeps failing: storage writes,
+// requests Telegram rejects (400/403) or the OMDb key. A kind of failure
+// alerts once it happens Threshold times within Window (nanoseconds in
+// JSON), then stays quiet for Cooldown.
+type AlertsConfig struct {
+	Enabled   bool          `json:"enabled"`
+	Threshold int           `json:"threshold"`
+	Window    time.Duration `json:"window"`
+	Cooldown  time.Duration `json:"cooldown"`
 }
 
 // Load reads the config file. If it does not exist, it creates a template but
@@ -50,11 +458,104 @@ func Load(configDir string) (*Config, error) {
 			OmdbAPIKey:      "PUT_OMDB_API_KEY_HERE",
 			LanguageDefault: "en",
 			MaxAlternatives: 5,
+			OwnerIDs:        []int64{},
+			AdminIDs:        []int64{},
+			VoteOptions:     []VoteOption{},
+			ListPageSize:    25,
+			ListSyncIdle:    7 * 24 * time.Hour,
+			Telegram: TelegramConfig{
+				Mode: "polling",
+				Webhook: TelegramWebhookConfig{
+					URL:    "https://bot.example.com/telegram",
+					Listen: ":8443",
+				},
+			},
 			Storage: StorageConfig{
 				MoviesFile:       "/config/data/movies.json",
 				MessageIndexFile: "/config/data/message_index.json",
-				SessionTTL:       30 * time.Second,
+				SessionTTL:       5 * time.Minute,
+				SaveDelay:        defaultSaveDelay,
 				MaxMessages:      10,
+				MaxIndexRefs:     5000,
+				MessageMaxAge:    90 * 24 * time.Hour,
+			},
+			Pprof: PprofConfig{
+				Enabled:     false,
+				Listen:      "127.0.0.1:6060",
+				SlowHandler: 2 * time.Second,
+			},
+			Features: defaultFeatures(),
+			Webhooks: []WebhookConfig{},
+			Matrix: MatrixConfig{
+				Enabled:    false,
+				Homeserver: "https://matrix.org",
+				Rooms:      []string{},
+			},
+			Slack: SlackConfig{
+				Enabled: false,
+				Listen:  ":8081",
+			},
+			Web: WebConfig{
+				Enabled: false,
+				Listen:  ":8090",
+			},
+			Email: EmailConfig{
+				Enabled:  false,
+				SMTPPort: 587,
+				To:       []string{},
+				Weekday:  "sunday",
+				Hour:     18,
+			},
+			Sheets: SheetsConfig{
+				Enabled:         false,
+				CredentialsFile: "/config/config/google-service-account.json",
+				SheetName:       "Movies",
+			},
+			Notion: NotionConfig{
+				Enabled: false,
+			},
+			Refresh: RefreshConfig{
+				Enabled:     true,
+				Interval:    15 * time.Minute,
+				BatchSize:   5,
+				MaxAge:      7 * 24 * time.Hour,
+				DailyBudget: 200,
+			},
+			Nights: NightsConfig{
+				Cooldown:         24 * time.Hour,
+				AttendanceWindow: 12 * time.Hour,
+				Reminders:        []time.Duration{24 * time.Hour, time.Hour},
+				ReminderDMs:      true,
+			},
+			Notifications: NotificationsConfig{
+				Enabled: true,
+				Quorum:  3,
+			},
+			Alerts: AlertsConfig{
+				Enabled:   true,
+				Threshold: 5,
+				Window:    10 * time.Minute,
+				Cooldown:  time.Hour,
+			},
+			MediaServer: MediaServerConfig{
+				Enabled:  false,
+				Kind:     "jellyfin",
+				URL:      "http://jellyfin:8096",
+				Users:    map[string]int64{},
+				Interval: time.Hour,
+			},
+			Trailers: TrailersConfig{
+				Enabled:  false,
+				Language: "en",
+			},
+			Streaming: StreamingConfig{
+				Enabled: false,
+				Region:  "US",
+			},
+			Transcription: TranscriptionConfig{
+				Enabled: false,
+				URL:     "https://api.openai.com/v1/audio/transcriptions",
+				Model:   "whisper-1",
 			},
 		}
 
@@ -72,16 +573,83 @@ func Load(configDir string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var cfg Config
+	cfg := Config{Features: defaultFeatures()}
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("invalid JSON in config file: %w", err)
 	}
 
+	switch cfg.Telegram.Mode {
+	case "":
+		cfg.Telegram.Mode = "polling"
+	case "polling", "webhook":
+	default:
+		log.Printf("[CONFIG][WARN] Unknown telegram.mode %q, using polling", cfg.Telegram.Mode)
+		cfg.Telegram.Mode = "polling"
+	}
+	if cfg.Telegram.Webhook.Listen == "" {
+		cfg.Telegram.Webhook.Listen = ":8443"
+	}
+	switch d := cfg.Storage.SaveDelay; {
+	case d == 0:
+		cfg.Storage.SaveDelay = defaultSaveDelay
+	case d < minSaveDelay:
+		log.Printf("[CONFIG][WARN] storage.save_delay %s is too short, using %s", d, minSaveDelay)
+		cfg.Storage.SaveDelay = minSaveDelay
+	case d > maxSaveDelay:
+		log.Printf("[CONFIG][WARN] storage.save_delay %s is too long, using %s", d, maxSaveDelay)
+		cfg.Storage.SaveDelay = maxSaveDelay
+	}
+	if cfg.Refresh.Interval <= 0 {
+		cfg.Refresh.Interval = 15 * time.Minute
+	}
+	if cfg.Refresh.BatchSize <= 0 {
+		cfg.Refresh.BatchSize = 5
+	}
+	if cfg.Refresh.MaxAge <= 0 {
+		cfg.Refresh.MaxAge = 7 * 24 * time.Hour
+	}
+	if cfg.Refresh.DailyBudget <= 0 {
+		cfg.Refresh.DailyBudget = 200
+	}
+	if cfg.ListPageSize == 0 {
+		cfg.ListPageSize = 25
+	}
+	if cfg.ListSyncIdle == 0 {
+		cfg.ListSyncIdle = 7 * 24 * time.Hour
+	}
+	if cfg.Nights.Cooldown <= 0 {
+		cfg.Nights.Cooldown = 24 * time.Hour
+	}
+	if cfg.Nights.Reminders == nil {
+		cfg.Nights.Reminders = []time.Duration{24 * time.Hour, time.Hour}
+	}
+
+	if cfg.Pprof.Listen == "" {
+		cfg.Pprof.Listen = "127.0.0.1:6060"
+	}
+	if cfg.Pprof.SlowHandler <= 0 {
+		cfg.Pprof.SlowHandler = 2 * time.Second
+	}
+	if cfg.Web.Listen == "" {
+		cfg.Web.Listen = ":8090"
+	}
+	if cfg.Web.BaseURL == "" {
+		host := cfg.Web.Listen
+		if strings.HasPrefix(host, ":") {
+			host = "localhost" + host
+		}
+		cfg.Web.BaseURL = "http://" + host
+	}
+	cfg.applyFeatures()
+
 	// Log loaded configuration
 	log.Printf("[CONFIG] Configuration loaded successfully")
 	log.Printf("[CONFIG] Debug: %v, Language: %s, MaxAlt: %d", cfg.Debug, cfg.LanguageDefault, cfg.MaxAlternatives)
-	log.Printf("[CONFIG] Storage: Movies=%s, Index=%s, SessionTTL=%s, MaxMessages=%s",
-		cfg.Storage.MoviesFile, cfg.Storage.MessageIndexFile, cfg.Storage.SessionTTL, cfg.Storage.MaxMessages)
+	log.Printf("[CONFIG] Storage: Movies=%s, Index=%s, SaveDelay=%s, MaxMessages=%d",
+		cfg.Storage.MoviesFile, cfg.Storage.MessageIndexFile, cfg.Storage.SaveDelay, cfg.Storage.MaxMessages)
+	if cfg.Pprof.Enabled {
+		log.Printf("[CONFIG] pprof enabled on %s", cfg.Pprof.Listen)
+	}
 
 	// Warn if tokens not set
 	if cfg.TelegramToken == "" || cfg.TelegramToken == "PUT_TELEGRAM_TOKEN_HERE" {
@@ -92,4 +660,4 @@ func Load(configDir string) (*Config, error) {
 	}
 
 	return &cfg, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,112 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"log"
+	"strconv"
+
+	"moviebot/internal/posters"
+	"moviebot/internal/storage"
+)
+
+// Collage tile geometry, poster aspect ratio 2:3.
+const (
+	tileW       = 200
+	tileH       = 300
+	tileGap     = 8
+	collageCols = 3
+)
+
+var (
+	collageBG   = color.RGBA{0x18, 0x18, 0x1c, 0xff}
+	missingTile = color.RGBA{0x3a, 0x3a, 0x42, 0xff}
+	badgeBG     = color.RGBA{0x00, 0x00, 0x00, 0xc0}
+	badgeFG     = color.RGBA{0xff, 0xd2, 0x3f, 0xff}
+)
+
+// Collage renders the posters of movies as a grid, each with its vote count
+// in the corner, and encodes it as JPEG. Posters that cannot be loaded
+// become blank tiles.
+func Collage(ctx context.Context, cache *posters.Cache, movies []storage.Movie) ([]byte, error) {
+	cols := min(len(movies), collageCols)
+	rows := (len(movies) + collageCols - 1) / collageCols
+	canvas := image.NewRGBA(image.Rect(0, 0,
+		cols*tileW+(cols+1)*tileGap,
+		rows*tileH+(rows+1)*tileGap))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(collageBG), image.Point{}, draw.Src)
+
+	for i, m := range movies {
+		x := tileGap + (i%collageCols)*(tileW+tileGap)
+		y := tileGap + (i/collageCols)*(tileH+tileGap)
+		tile := image.Rect(x, y, x+tileW, y+tileH)
+
+		poster, err := cache.Get(ctx, m)
+		if err != nil {
+			log.Printf("[DIGEST] Collage without poster of %s: %v", m.Title, err)
+			draw.Draw(canvas, tile, image.NewUniform(missingTile), image.Point{}, draw.Src)
+		} else {
+			scaleInto(canvas, tile, poster)
+		}
+		drawBadge(canvas, tile.Min, len(m.Votes))
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleInto draws src stretched over r using nearest-neighbour sampling;
+// posters are all roughly 2:3, so the distortion is negligible.
+func scaleInto(dst *image.RGBA, r image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		sy := sb.Min.Y + (y-r.Min.Y)*sb.Dy()/r.Dy()
+		for x := r.Min.X; x < r.Max.X; x++ {
+			sx := sb.Min.X + (x-r.Min.X)*sb.Dx()/r.Dx()
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+}
+
+// digitFont is a 3x5 bitmap font for 0-9, one row per byte, high bit left.
+var digitFont = [10][5]byte{
+	{7, 5, 5, 5, 7}, {2, 6, 2, 2, 7}, {7, 1, 7, 4, 7}, {7, 1, 7, 1, 7}, {5, 5, 7, 1, 1},
+	{7, 4, 7, 1, 7}, {7, 4, 7, 5, 7}, {7, 1, 1, 1, 1}, {7, 5, 7, 5, 7}, {7, 5, 7, 1, 7},
+}
+
+// Badge glyph scale and padding, in pixels.
+const (
+	glyphScale = 5
+	badgePad   = 6
+)
+
+// drawBadge paints n as a number on a dark box at the tile's top-left.
+func drawBadge(dst *image.RGBA, at image.Point, n int) {
+	digits := strconv.Itoa(n)
+	w := len(digits)*4*glyphScale - glyphScale + 2*badgePad
+	h := 5*glyphScale + 2*badgePad
+	box := image.Rect(at.X, at.Y, at.X+w, at.Y+h)
+	draw.Draw(dst, box, image.NewUniform(badgeBG), image.Point{}, draw.Over)
+
+	for i, d := range digits {
+		glyph := digitFont[d-'0']
+		ox := at.X + badgePad + i*4*glyphScale
+		oy := at.Y + badgePad
+		for row, bits := range glyph {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) == 0 {
+					continue
+				}
+				px := image.Rect(0, 0, glyphScale, glyphScale).Add(image.Pt(ox+col*glyphScale, oy+row*glyphScale))
+				draw.Draw(dst, px, image.NewUniform(badgeFG), image.Point{}, draw.Src)
+			}
+		}
+	}
+}
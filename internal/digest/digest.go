@@ -0,0 +1,75 @@
+package digest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"moviebot/internal/storage"
+)
+
+// topN is how many vote leaders a digest lists.
+const topN = 5
+
+// Summary is what happened on the watchlist over one period.
+type Summary struct {
+	Since time.Time
+	Until time.Time
+	New   []storage.Movie // added during the period
+	Top   []storage.Movie // unwatched movies with the most votes
+	Total int
+}
+
+// Build summarizes movies for the period [since, until).
+func Build(movies []storage.Movie, since, until time.Time) Summary {
+	s := Summary{Since: since, Until: until, Total: len(movies)}
+
+	var candidates []storage.Movie
+	for _, m := range movies {
+		if !m.AddedAt.Before(since) && m.AddedAt.Before(until) {
+			s.New = append(s.New, m)
+		}
+		if len(m.Watched) == 0 && len(m.Votes) > 0 {
+			candidates = append(candidates, m)
+		}
+	}
+
+	sort.Slice(s.New, func(i, j int) bool { return s.New[i].AddedAt.Before(s.New[j].AddedAt) })
+	sort.SliceStable(candidates, func(i, j int) bool { return storage.Score(candidates[i]) > storage.Score(candidates[j]) })
+	if len(candidates) > topN {
+		candidates = candidates[:topN]
+	}
+	s.Top = candidates
+	return s
+}
+
+// Subject is a one-line title for the summary.
+func (s Summary) Subject() string {
+	return fmt.Sprintf("🎬 Movie night digest — week of %s", s.Since.Format("2 Jan"))
+}
+
+// Text renders the summary as plain text.
+func (s Summary) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n\n", s.Subject())
+
+	sb.WriteString("New this week:\n")
+	if len(s.New) == 0 {
+		sb.WriteString("  nothing new\n")
+	}
+	for _, m := range s.New {
+		fmt.Fprintf(&sb, "  • %s (%d)\n", m.Title, m.Year)
+	}
+
+	sb.WriteString("\nTop votes:\n")
+	if len(s.Top) == 0 {
+		sb.WriteString("  no votes yet\n")
+	}
+	for i, m := range s.Top {
+		fmt.Fprintf(&sb, "  %d. %s (%d) — %d votes\n", i+1, m.Title, m.Year, len(m.Votes))
+	}
+
+	fmt.Fprintf(&sb, "\n%d movies on the list in total.\n", s.Total)
+	return sb.String()
+}
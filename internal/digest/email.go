@@ -0,0 +1,158 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/maintenance"
+	"moviebot/internal/posters"
+	"moviebot/internal/storage"
+)
+
+// Mailer sends digests over SMTP. smtp.SendMail upgrades to STARTTLS when
+// the server offers it.
+type Mailer struct {
+	cfg config.EmailConfig
+
+	// Posters, when set, adds a collage of the top candidates to the weekly
+	// digest.
+	Posters *posters.Cache
+
+	// Maintenance holds back the scheduled digest while active; may be nil.
+	Maintenance *maintenance.Mode
+}
+
+func NewMailer(cfg config.EmailConfig) *Mailer {
+	return &Mailer{cfg: cfg}
+}
+
+func (m *Mailer) Send(subject, body string) error {
+	return m.send(subject, body, nil)
+}
+
+// send mails body as plain text, with image attached as collage.jpg when
+// it is not nil.
+func (m *Mailer) send(subject, body string, image []byte) error {
+	if len(m.cfg.To) == 0 {
+		return fmt.Errorf("no recipients configured")
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.SMTPHost)
+	}
+
+	headers := []string{
+		"From: " + m.cfg.From,
+		"To: " + strings.Join(m.cfg.To, ", "),
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+	}
+
+	var msg bytes.Buffer
+	if image == nil {
+		headers = append(headers, "Content-Type: text/plain; charset=UTF-8")
+		msg.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n" + body)
+	} else {
+		mw := multipart.NewWriter(&msg)
+		headers = append(headers, "Content-Type: multipart/mixed; boundary="+mw.Boundary())
+		msg.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+		text, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {"text/plain; charset=UTF-8"},
+		})
+		io.WriteString(text, body)
+
+		att, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"image/jpeg"},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {`attachment; filename="collage.jpg"`},
+		})
+		writeBase64Lines(att, image)
+		mw.Close()
+	}
+
+	addr := fmt.Sprintf("%s:%d", m.cfg.SMTPHost, m.cfg.SMTPPort)
+	return smtp.SendMail(addr, auth, m.cfg.From, m.cfg.To, msg.Bytes())
+}
+
+// writeBase64Lines writes data base64-encoded in 76 character lines, as MIME
+// requires.
+func writeBase64Lines(w io.Writer, data []byte) {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 76 {
+		io.WriteString(w, enc[:76]+"\r\n")
+		enc = enc[76:]
+	}
+	io.WriteString(w, enc+"\r\n")
+}
+
+// SendWeekly builds the digest of the configured chat's library for the
+// week ending now and mails it.
+func (m *Mailer) SendWeekly(store *storage.Store) error {
+	now := time.Now()
+	s := Build(store.GetLibrary(store.LibraryOf(m.cfg.ChatID)), now.AddDate(0, 0, -7), now)
+
+	var collage []byte
+	if m.Posters != nil && len(s.Top) > 0 {
+		img, err := Collage(context.Background(), m.Posters, s.Top)
+		if err != nil {
+			log.Printf("[DIGEST] Failed to build poster collage: %v", err)
+		}
+		collage = img
+	}
+
+	if err := m.send(s.Subject(), s.Text(), collage); err != nil {
+		return err
+	}
+	log.Printf("[DIGEST] Emailed weekly digest to %d recipients", len(m.cfg.To))
+	return nil
+}
+
+// RunWeekly mails the digest every week at the configured weekday and hour
+// (local time). It never returns.
+func (m *Mailer) RunWeekly(store *storage.Store) {
+	day := parseWeekday(m.cfg.Weekday)
+	for {
+		next := nextOccurrence(time.Now(), day, m.cfg.Hour)
+		log.Printf("[DIGEST] Next email digest at %s", next.Format(time.RFC1123))
+		time.Sleep(time.Until(next))
+
+		if m.Maintenance.Active() {
+			log.Printf("[DIGEST] Skipped, maintenance mode")
+			continue
+		}
+		if err := m.SendWeekly(store); err != nil {
+			log.Printf("[DIGEST] Failed to email digest: %v", err)
+		}
+	}
+}
+
+func parseWeekday(s string) time.Weekday {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), s) {
+			return d
+		}
+	}
+	return time.Sunday
+}
+
+// nextOccurrence returns the first time strictly after now falling on day at
+// hour:00.
+func nextOccurrence(now time.Time, day time.Weekday, hour int) time.Time {
+	t := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+	for t.Weekday() != day || !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
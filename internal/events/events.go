@@ -0,0 +1,75 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// Event types published by the bot.
+const (
+	MovieAdded     = "movie_added"
+	VoteChanged    = "vote_changed"
+	MovieWatched   = "movie_watched"
+	NightScheduled = "night_scheduled"
+	MovieUpdated   = "movie_updated"
+	MovieRemoved   = "movie_removed"
+)
+
+// Event is a notable change, delivered to integrations (webhooks, syncs).
+type Event struct {
+	Type     string         `json:"type"`
+	Time     time.Time      `json:"time"`
+	TraceID  string         `json:"trace_id,omitempty"`
+	Source   string         `json:"source,omitempty"` // frontend that caused it: telegram, matrix, ...
+	ChatID   int64          `json:"chat_id,omitempty"`
+	UserID   int64          `json:"user_id,omitempty"`
+	Username string         `json:"username,omitempty"`
+	Active   bool           `json:"active"` // vote added / marked watched, false when undone
+	Movie    *storage.Movie `json:"movie,omitempty"`
+	Night    *storage.Night `json:"night,omitempty"` // set for night_scheduled
+}
+
+// Bus fans events out to subscribers. Handlers run synchronously on the
+// publishing goroutine, so they must hand slow work off themselves.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []func(Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(h func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Publish stamps the event with the time and trace ID from ctx and hands it
+// to every subscriber. A nil Bus is a no-op so callers need not check.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.TraceID == "" {
+		e.TraceID = trace.ID(ctx)
+	}
+
+	b.mu.RLock()
+	handlers := make([]func(Event), len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	trace.Logf(ctx, "[EVENTS] %s -> %d subscribers", e.Type, len(handlers))
+	for _, h := range handlers {
+		h(e)
+	}
+}
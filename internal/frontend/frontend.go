@@ -0,0 +1,126 @@
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"moviebot/internal/events"
+	"moviebot/internal/omdb"
+	"moviebot/internal/search"
+	"moviebot/internal/storage"
+	"moviebot/internal/tmdb"
+)
+
+// Frontend is a chat platform the bot serves besides Telegram. Run blocks
+// until ctx is cancelled or the frontend fails for good.
+type Frontend interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// User identifies someone on a frontend. ID must be unique across platforms
+// because it keys the shared Votes/Watched maps, so non-Telegram frontends
+// use their native, already namespaced IDs (e.g. "@alice:matrix.org").
+type User struct {
+	ID   string
+	Name string
+}
+
+// Core is the platform-neutral part of the bot: the same store, search and
+// list rendering every text frontend builds its commands on.
+type Core struct {
+	Source  string // frontend name, recorded on published events
+	Store   *storage.Store
+	Library int64 // library the frontend lists and adds to, 0 for the shared one
+	OMDb    omdb.API
+	TMDB    *tmdb.Client // ranks search results by popularity; may be nil
+	Events  *events.Bus
+	Format  storage.TableFormat
+	MaxAlt  int
+}
+
+// Search returns at most MaxAlt candidates for query.
+func (c *Core) Search(ctx context.Context, query string) ([]omdb.SearchResult, error) {
+	results, err := search.Query(ctx, c.OMDb, c.TMDB, query)
+	if err != nil {
+		return nil, err
+	}
+	if c.MaxAlt > 0 && len(results) > c.MaxAlt {
+		results = results[:c.MaxAlt]
+	}
+	return results, nil
+}
+
+// Add stores a search result and announces it when it is new.
+func (c *Core) Add(ctx context.Context, r omdb.SearchResult, u User) (storage.Movie, bool) {
+	year, _ := strconv.Atoi(r.Year)
+	id, created := c.Store.NotifyNewMovie(ctx, storage.Movie{
+		Title:   r.Title,
+		Year:    year,
+		Poster:  r.Poster,
+		ImdbID:  r.ImdbID,
+		ChatID:  c.Library,
+		AddedBy: u.ID,
+	})
+	movie, _ := c.Store.GetMovieByID(id)
+	if created {
+		c.publish(ctx, events.MovieAdded, u, movie, true)
+	}
+	return movie, created
+}
+
+// ToggleVote flips u's vote on the movie and reports the new state.
+func (c *Core) ToggleVote(ctx context.Context, movieID string, u User) (storage.Movie, bool, error) {
+	if !c.inLibrary(movieID) {
+		return storage.Movie{}, false, fmt.Errorf("movie not found")
+	}
+	movie, err := c.Store.ToggleVoteByID(ctx, movieID, u.ID)
+	if err != nil {
+		return movie, false, err
+	}
+	active := movie.Votes[u.ID]
+	c.publish(ctx, events.VoteChanged, u, movie, active)
+	return movie, active, nil
+}
+
+// ToggleWatched flips u's watched mark on the movie and reports the new state.
+func (c *Core) ToggleWatched(ctx context.Context, movieID string, u User) (storage.Movie, bool, error) {
+	if !c.inLibrary(movieID) {
+		return storage.Movie{}, false, fmt.Errorf("movie not found")
+	}
+	movie, err := c.Store.ToggleWatchedByID(ctx, movieID, u.ID)
+	if err != nil {
+		return movie, false, err
+	}
+	active := movie.Watched[u.ID]
+	c.publish(ctx, events.MovieWatched, u, movie, active)
+	return movie, active, nil
+}
+
+// inLibrary reports whether the movie is in the frontend's library. Buttons
+// carry bare movie IDs, which could otherwise reach another chat's movies.
+func (c *Core) inLibrary(movieID string) bool {
+	movie, ok := c.Store.GetMovieByID(movieID)
+	return ok && movie.ChatID == c.Library
+}
+
+// Find resolves a free-text reference to exactly one movie on the main list.
+func (c *Core) Find(ref string) (storage.Movie, error) {
+	return storage.FindMovie(c.Store.GetMovies(c.Library, ""), ref)
+}
+
+// List renders the frontend's watchlist table.
+func (c *Core) List() string {
+	return storage.BuildListMessage(c.Store.GetMovies(c.Library, ""), c.Format)
+}
+
+func (c *Core) publish(ctx context.Context, typ string, u User, movie storage.Movie, active bool) {
+	c.Events.Publish(ctx, events.Event{
+		Type:     typ,
+		Source:   c.Source,
+		Username: u.Name,
+		Active:   active,
+		Movie:    &movie,
+	})
+}
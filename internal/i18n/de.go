@@ -0,0 +1,76 @@
+package i18n
+
+// de holds the German messages.
+var de = map[string]string{
+	// Toasts
+	"Vote added":                         "Stimme abgegeben",
+	"Vote removed":                       "Stimme zurückgenommen",
+	"Marked as watched":                  "Als gesehen markiert",
+	"No longer marked as watched":        "Nicht mehr als gesehen markiert",
+	"Voting is closed here":              "Die Abstimmung ist hier beendet",
+	"Sorry, this message is too old":     "Diese Nachricht ist leider zu alt",
+	"This movie selection isn’t for you": "Diese Filmauswahl ist nicht für dich",
+	"You're down as %s":                  "Du bist eingetragen: %s",
+	"Answer taken back.":                 "Antwort zurückgenommen.",
+	"Reaction taken back.":               "Reaktion zurückgenommen.",
+	"You're in!":                         "Du bist dabei!",
+	"Maybe next time.":                   "Vielleicht beim nächsten Mal.",
+	"Voted for %s":                       "Für %s gestimmt",
+	"Vote for %s taken back":             "Stimme für %s zurückgenommen",
+
+	// Prompts
+	"What movie would you like to search for?": "Nach welchem Film möchtest du suchen?",
+	"Reply to this message with the title.":    "Antworte auf diese Nachricht mit dem Titel.",
+	"Cancel":                                   "Abbrechen",
+	"Search cancelled.":                        "Suche abgebrochen.",
+	"Nothing to cancel.":                       "Nichts abzubrechen.",
+	"No results found":                         "Keine Treffer",
+	"No more alternatives available.":          "Keine weiteren Vorschläge.",
+	"Select this movie":                        "Diesen Film wählen",
+	"Search Another":                           "Anderer Film",
+
+	// Someone else searched the same title
+	"%s just added that one, vote on it here.":                                  "%s hat den gerade hinzugefügt, stimm hier ab.",
+	"%s is picking from the results for that right now, the vote card follows.": "%s wählt gerade aus den Treffern dafür, die Abstimmungskarte folgt.",
+	"%s is already searching for that, hang on.":                                "%s sucht schon danach, einen Moment.",
+
+	// Errors
+	"OMDb can't be reached right now. You can still add the movie by hand:\n/movie add-manual Title;Year": "OMDb ist gerade nicht erreichbar. Du kannst den Film trotzdem von Hand hinzufügen:\n/movie add-manual Titel;Jahr",
+	"Only chat admins can change the table format.":                                                       "Nur Chat-Admins können das Tabellenformat ändern.",
+	"Unknown table format or list. Please choose one of the available formats (or see /lists):":           "Unbekanntes Tabellenformat oder unbekannte Liste. Wähle eines der Formate (oder sieh in /lists nach):",
+	"%s is already here, vote on it above.":                                                               "%s ist schon da, stimm oben ab.",
+
+	// Vote cards and movie night answers
+	"Votes":   "Stimmen",
+	"Watched": "Gesehen",
+	"Vote":    "Abstimmen",
+	"Vote 👍 to add to the list or mark as watched.":   "Stimm mit 👍 für den Film oder markiere ihn als gesehen.",
+	"React to rank it, or mark as watched.":           "Reagiere, um ihn einzuordnen, oder markiere ihn als gesehen.",
+	"Releases in %dd (%s)":                            "Kinostart in %d T. (%s)",
+	"Voting opens once it's out":                      "Abstimmen erst ab dem Kinostart",
+	"Voting opens once it's out.":                     "Abgestimmt wird ab dem Kinostart.",
+	"%s is out! Voting on it is open.":                "%s ist erschienen! Die Abstimmung ist offen.",
+	"Votes are frozen until an admin reopens voting.": "Die Stimmen sind eingefroren, bis ein Admin die Abstimmung wieder öffnet.",
+	"Rewatch":    "Nochmal schauen",
+	"Discussion": "Diskussion",
+	"Going":      "Dabei",
+	"Maybe":      "Vielleicht",
+	"Can't":      "Kann nicht",
+
+	// Lists
+	"No movies yet":                                         "Noch keine Filme",
+	"No movies match":                                       "Keine passenden Filme",
+	"Nothing left to watch":                                 "Nichts mehr zu schauen",
+	"Watchlist — %d movies (/list)":                         "Merkliste — %d Filme (/list)",
+	"%s — %d movies (/list %s)":                             "%s — %d Filme (/list %s)",
+	"Add to a named list with /movie --list <name> <title>": "In eine eigene Liste mit /movie --list <name> <titel>",
+}
+
+var deColumns = map[string]string{
+	"Title": "Titel",
+	"Year":  "Jahr",
+	"Votes": "Stim.",
+	"Seen":  "Ges.",
+	"Ours":  "Wir",
+	"Added": "Ergänzt",
+}
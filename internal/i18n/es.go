@@ -0,0 +1,77 @@
+package i18n
+
+// es holds the Spanish messages.
+var es = map[string]string{
+	// Toasts
+	"Vote added":                         "Voto añadido",
+	"Vote removed":                       "Voto retirado",
+	"Marked as watched":                  "Marcada como vista",
+	"No longer marked as watched":        "Ya no está marcada como vista",
+	"Voting is closed here":              "La votación está cerrada aquí",
+	"Sorry, this message is too old":     "Lo siento, este mensaje es demasiado antiguo",
+	"This movie selection isn’t for you": "Esta selección de película no es para ti",
+	"You're down as %s":                  "Te apuntaste: %s",
+	"Answer taken back.":                 "Respuesta retirada.",
+	"Reaction taken back.":               "Reacción retirada.",
+	"You're in!":                         "¡Te apuntas!",
+	"Maybe next time.":                   "Quizás la próxima vez.",
+	"Voted for %s":                       "Votaste por %s",
+	"Vote for %s taken back":             "Voto por %s retirado",
+
+	// Prompts
+	"What movie would you like to search for?": "¿Qué película quieres buscar?",
+	"Reply to this message with the title.":    "Responde a este mensaje con el título.",
+	"Cancel":                                   "Cancelar",
+	"Search cancelled.":                        "Búsqueda cancelada.",
+	"Nothing to cancel.":                       "No hay nada que cancelar.",
+	"No results found":                         "No se encontraron resultados",
+	"No more alternatives available.":          "No hay más alternativas.",
+	"Select this movie":                        "Elegir esta película",
+	"Search Another":                           "Buscar otra",
+
+	// Someone else searched the same title
+	"%s just added that one, vote on it here.":                                  "%s acaba de añadirla, vota aquí.",
+	"%s is picking from the results for that right now, the vote card follows.": "%s está eligiendo entre los resultados ahora mismo, la tarjeta de votación llegará enseguida.",
+	"%s is already searching for that, hang on.":                                "%s ya la está buscando, un momento.",
+
+	// Errors
+	"OMDb can't be reached right now. You can still add the movie by hand:\n/movie add-manual Title;Year": "No se puede contactar con OMDb ahora mismo. Aún puedes añadir la película a mano:\n/movie add-manual Título;Año",
+	"Only chat admins can change the table format.":                                                       "Solo los admins del chat pueden cambiar el formato de la tabla.",
+	"Unknown table format or list. Please choose one of the available formats (or see /lists):":           "Formato de tabla o lista desconocido. Elige uno de los formatos disponibles (o mira /lists):",
+	"%s is already here, vote on it above.":                                                               "%s ya está aquí, vota arriba.",
+
+	// Vote cards and movie night answers
+	"Votes":   "Votos",
+	"Watched": "Vista",
+	"Vote":    "Votar",
+	"Vote 👍 to add to the list or mark as watched.":   "Vota 👍 para añadirla a la lista o márcala como vista.",
+	"React to rank it, or mark as watched.":           "Reacciona para puntuarla o márcala como vista.",
+	"Releases in %dd (%s)":                            "Se estrena en %d d (%s)",
+	"Voting opens once it's out":                      "Se podrá votar cuando se estrene",
+	"Voting opens once it's out.":                     "Se podrá votar cuando se estrene.",
+	"%s is out! Voting on it is open.":                "¡%s ya se ha estrenado! La votación está abierta.",
+	"Votes are frozen until an admin reopens voting.": "Los votos están congelados hasta que un admin reabra la votación.",
+	"Rewatch":    "Volver a ver",
+	"Discussion": "Debate",
+	"Going":      "Voy",
+	"Maybe":      "Quizás",
+	"Can't":      "No puedo",
+
+	// Lists
+	"No movies yet":                                         "Todavía no hay películas",
+	"No movies match":                                       "Ninguna película coincide",
+	"Nothing left to watch":                                 "No queda nada por ver",
+	"Watchlist — %d movies (/list)":                         "Lista — %d películas (/list)",
+	"%s — %d movies (/list %s)":                             "%s — %d películas (/list %s)",
+	"Add to a named list with /movie --list <name> <title>": "Añade a una lista con nombre con /movie --list <nombre> <título>",
+}
+
+var esColumns = map[string]string{
+	"Title": "Título",
+	"Year":  "Año",
+	"Votes": "Votos",
+	"Seen":  "Vist",
+	"Ours":  "Nos.",
+	"Added": "Añadida",
+	"Genre": "Género",
+}
@@ -0,0 +1,75 @@
+package i18n
+
+// fr holds the French messages.
+var fr = map[string]string{
+	// Toasts
+	"Vote added":                         "Vote ajouté",
+	"Vote removed":                       "Vote retiré",
+	"Marked as watched":                  "Marqué comme vu",
+	"No longer marked as watched":        "N'est plus marqué comme vu",
+	"Voting is closed here":              "Le vote est clos ici",
+	"Sorry, this message is too old":     "Désolé, ce message est trop ancien",
+	"This movie selection isn’t for you": "Cette sélection de film n'est pas pour toi",
+	"You're down as %s":                  "Tu es inscrit : %s",
+	"Answer taken back.":                 "Réponse retirée.",
+	"Reaction taken back.":               "Réaction retirée.",
+	"You're in!":                         "Tu en es !",
+	"Maybe next time.":                   "Une prochaine fois peut-être.",
+	"Voted for %s":                       "Vote pour %s enregistré",
+	"Vote for %s taken back":             "Vote pour %s retiré",
+
+	// Prompts
+	"What movie would you like to search for?": "Quel film veux-tu chercher ?",
+	"Reply to this message with the title.":    "Réponds à ce message avec le titre.",
+	"Cancel":                                   "Annuler",
+	"Search cancelled.":                        "Recherche annulée.",
+	"Nothing to cancel.":                       "Rien à annuler.",
+	"No results found":                         "Aucun résultat",
+	"No more alternatives available.":          "Plus d'autres propositions.",
+	"Select this movie":                        "Choisir ce film",
+	"Search Another":                           "Chercher un autre",
+
+	// Someone else searched the same title
+	"%s just added that one, vote on it here.":                                  "%s vient de l'ajouter, vote ici.",
+	"%s is picking from the results for that right now, the vote card follows.": "%s choisit parmi les résultats en ce moment, la carte de vote suit.",
+	"%s is already searching for that, hang on.":                                "%s le cherche déjà, un instant.",
+
+	// Errors
+	"OMDb can't be reached right now. You can still add the movie by hand:\n/movie add-manual Title;Year": "OMDb est injoignable pour le moment. Tu peux quand même ajouter le film à la main :\n/movie add-manual Titre;Année",
+	"Only chat admins can change the table format.":                                                       "Seuls les admins du chat peuvent changer le format du tableau.",
+	"Unknown table format or list. Please choose one of the available formats (or see /lists):":           "Format de tableau ou liste inconnu. Choisis un des formats disponibles (ou vois /lists) :",
+	"%s is already here, vote on it above.":                                                               "%s est déjà là, vote au-dessus.",
+
+	// Vote cards and movie night answers
+	"Votes":   "Votes",
+	"Watched": "Vu",
+	"Vote":    "Voter",
+	"Vote 👍 to add to the list or mark as watched.":   "Vote 👍 pour l'ajouter à la liste ou marque-le comme vu.",
+	"React to rank it, or mark as watched.":           "Réagis pour le classer, ou marque-le comme vu.",
+	"Releases in %dd (%s)":                            "Sortie dans %d j (%s)",
+	"Voting opens once it's out":                      "On pourra voter dès sa sortie",
+	"Voting opens once it's out.":                     "On pourra voter dès sa sortie.",
+	"%s is out! Voting on it is open.":                "%s est sorti ! Le vote est ouvert.",
+	"Votes are frozen until an admin reopens voting.": "Les votes sont gelés jusqu'à ce qu'un admin rouvre le vote.",
+	"Rewatch":    "Revoir",
+	"Discussion": "Discussion",
+	"Going":      "Je viens",
+	"Maybe":      "Peut-être",
+	"Can't":      "Impossible",
+
+	// Lists
+	"No movies yet":                                         "Pas encore de films",
+	"No movies match":                                       "Aucun film ne correspond",
+	"Nothing left to watch":                                 "Plus rien à voir",
+	"Watchlist — %d movies (/list)":                         "Liste — %d films (/list)",
+	"%s — %d movies (/list %s)":                             "%s — %d films (/list %s)",
+	"Add to a named list with /movie --list <name> <title>": "Ajoute à une liste nommée avec /movie --list <nom> <titre>",
+}
+
+var frColumns = map[string]string{
+	"Title": "Titre",
+	"Year":  "An",
+	"Seen":  "Vus",
+	"Ours":  "Nous",
+	"Added": "Ajouté",
+}
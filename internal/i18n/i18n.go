@@ -0,0 +1,67 @@
+// Package i18n translates the bot's messages. Messages are looked up by
+// their English text, so the code reads as before and anything without a
+// translation stays English. Formats keep their verbs in the same order.
+package i18n
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// catalogs are the translations by language, keyed by the English text.
+var catalogs = map[string]map[string]string{
+	"de": de,
+	"es": es,
+	"fr": fr,
+	"it": it,
+}
+
+// columns are the list column headers by language; see Column.
+var columns = map[string]map[string]string{
+	"de": deColumns,
+	"es": esColumns,
+	"fr": frColumns,
+	"it": itColumns,
+}
+
+// Languages lists the languages messages come in, English included.
+func Languages() []string {
+	langs := append([]string{"en"}, slices.Collect(maps.Keys(catalogs))...)
+	slices.Sort(langs)
+	return langs
+}
+
+// Match maps a Telegram language code ("de", "pt-br") to a language there
+// are messages in, "" when there is none.
+func Match(code string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(code), "-")
+	if _, ok := catalogs[lang]; ok || lang == "en" {
+		return lang
+	}
+	return ""
+}
+
+// T translates msg into lang.
+func T(lang, msg string) string {
+	if s, ok := catalogs[lang][msg]; ok {
+		return s
+	}
+	return msg
+}
+
+// Tf translates format into lang and fills it in.
+func Tf(lang, format string, args ...any) string {
+	return fmt.Sprintf(T(lang, format), args...)
+}
+
+// Column translates a list column header. Headers have their own, shorter
+// words since they must fit the column; a translation that doesn't keeps
+// the English one.
+func Column(lang, header string, width int) string {
+	if s, ok := columns[lang][header]; ok && len([]rune(s)) <= width {
+		return s
+	}
+	return header
+}
@@ -0,0 +1,77 @@
+package i18n
+
+// it holds the Italian messages.
+var it = map[string]string{
+	// Toasts
+	"Vote added":                         "Voto aggiunto",
+	"Vote removed":                       "Voto ritirato",
+	"Marked as watched":                  "Segnato come visto",
+	"No longer marked as watched":        "Non più segnato come visto",
+	"Voting is closed here":              "Qui la votazione è chiusa",
+	"Sorry, this message is too old":     "Spiacente, questo messaggio è troppo vecchio",
+	"This movie selection isn’t for you": "Questa scelta del film non è per te",
+	"You're down as %s":                  "Sei segnato come: %s",
+	"Answer taken back.":                 "Risposta ritirata.",
+	"Reaction taken back.":               "Reazione ritirata.",
+	"You're in!":                         "Ci sei!",
+	"Maybe next time.":                   "Magari la prossima volta.",
+	"Voted for %s":                       "Hai votato per %s",
+	"Vote for %s taken back":             "Voto per %s ritirato",
+
+	// Prompts
+	"What movie would you like to search for?": "Quale film vuoi cercare?",
+	"Reply to this message with the title.":    "Rispondi a questo messaggio con il titolo.",
+	"Cancel":                                   "Annulla",
+	"Search cancelled.":                        "Ricerca annullata.",
+	"Nothing to cancel.":                       "Niente da annullare.",
+	"No results found":                         "Nessun risultato",
+	"No more alternatives available.":          "Non ci sono altre alternative.",
+	"Select this movie":                        "Scegli questo film",
+	"Search Another":                           "Cerca un altro",
+
+	// Someone else searched the same title
+	"%s just added that one, vote on it here.":                                  "%s l'ha appena aggiunto, vota qui.",
+	"%s is picking from the results for that right now, the vote card follows.": "%s sta scegliendo tra i risultati proprio ora, la scheda di voto arriva a breve.",
+	"%s is already searching for that, hang on.":                                "%s lo sta già cercando, un attimo.",
+
+	// Errors
+	"OMDb can't be reached right now. You can still add the movie by hand:\n/movie add-manual Title;Year": "OMDb non è raggiungibile al momento. Puoi comunque aggiungere il film a mano:\n/movie add-manual Titolo;Anno",
+	"Only chat admins can change the table format.":                                                       "Solo gli admin della chat possono cambiare il formato della tabella.",
+	"Unknown table format or list. Please choose one of the available formats (or see /lists):":           "Formato della tabella o lista sconosciuti. Scegli uno dei formati disponibili (o guarda /lists):",
+	"%s is already here, vote on it above.":                                                               "%s è già qui, vota sopra.",
+
+	// Vote cards and movie night answers
+	"Votes":   "Voti",
+	"Watched": "Visto",
+	"Vote":    "Vota",
+	"Vote 👍 to add to the list or mark as watched.":   "Vota 👍 per aggiungerlo alla lista o segnalo come visto.",
+	"React to rank it, or mark as watched.":           "Reagisci per votarlo o segnalo come visto.",
+	"Releases in %dd (%s)":                            "Esce tra %d g (%s)",
+	"Voting opens once it's out":                      "Si potrà votare quando esce",
+	"Voting opens once it's out.":                     "Si potrà votare quando esce.",
+	"%s is out! Voting on it is open.":                "%s è uscito! La votazione è aperta.",
+	"Votes are frozen until an admin reopens voting.": "I voti sono congelati finché un admin non riapre la votazione.",
+	"Rewatch":    "Rivedere",
+	"Discussion": "Discussione",
+	"Going":      "Ci sono",
+	"Maybe":      "Forse",
+	"Can't":      "Non posso",
+
+	// Lists
+	"No movies yet":                                         "Ancora nessun film",
+	"No movies match":                                       "Nessun film corrisponde",
+	"Nothing left to watch":                                 "Non resta niente da vedere",
+	"Watchlist — %d movies (/list)":                         "Lista — %d film (/list)",
+	"%s — %d movies (/list %s)":                             "%s — %d film (/list %s)",
+	"Add to a named list with /movie --list <name> <title>": "Aggiungi a una lista con nome con /movie --list <nome> <titolo>",
+}
+
+var itColumns = map[string]string{
+	"Title": "Titolo",
+	"Year":  "Anno",
+	"Votes": "Voti",
+	"Seen":  "Vist",
+	"Ours":  "Noi",
+	"Added": "Aggiunto",
+	"Genre": "Genere",
+}
@@ -0,0 +1,57 @@
+package maintenance
+
+import (
+	"log"
+	"sync"
+)
+
+// DefaultMessage is what users get while the bot is down for maintenance.
+const DefaultMessage = "🛠 The bot is under maintenance, back soon."
+
+// Mode is the process-wide maintenance switch. While it is on, chat
+// frontends only answer owners and background jobs skip their runs, so the
+// data files can be migrated safely. A nil Mode is never active.
+type Mode struct {
+	mu      sync.RWMutex
+	on      bool
+	message string
+}
+
+func New(on bool, message string) *Mode {
+	if message == "" {
+		message = DefaultMessage
+	}
+	return &Mode{on: on, message: message}
+}
+
+// Active reports whether maintenance mode is on.
+func (m *Mode) Active() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.on
+}
+
+// Message is the notice shown to users while maintenance mode is on.
+func (m *Mode) Message() string {
+	if m == nil {
+		return DefaultMessage
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.message
+}
+
+// Set switches maintenance mode; a non-empty message replaces the notice.
+func (m *Mode) Set(on bool, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.on = on
+	if message != "" {
+		m.message = message
+	}
+	log.Printf("[MAINTENANCE] Maintenance mode: %v", on)
+}
@@ -0,0 +1,138 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client is a minimal Matrix Client-Server API (v3) client: just enough to
+// join rooms, long-poll /sync and post messages.
+type Client struct {
+	homeserver string
+	token      string
+	http       *http.Client
+}
+
+func NewClient(homeserver, token string) *Client {
+	return &Client{
+		homeserver: strings.TrimRight(homeserver, "/"),
+		token:      token,
+		http:       &http.Client{Timeout: 90 * time.Second},
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	u := c.homeserver + "/_matrix/client/v3" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var rd io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, rd)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			ErrCode string `json:"errcode"`
+			Error   string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("matrix %s %s: HTTP %d %s %s", method, path, resp.StatusCode, e.ErrCode, e.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Join joins a room by ID or alias and returns its room ID, which is what
+// sync responses are keyed by.
+func (c *Client) Join(ctx context.Context, room string) (string, error) {
+	var resp struct {
+		RoomID string `json:"room_id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/join/"+url.PathEscape(room), nil, struct{}{}, &resp); err != nil {
+		return "", err
+	}
+	return resp.RoomID, nil
+}
+
+type roomEvent struct {
+	Type    string `json:"type"`
+	Sender  string `json:"sender"`
+	EventID string `json:"event_id"`
+	Content struct {
+		MsgType string `json:"msgtype"`
+		Body    string `json:"body"`
+	} `json:"content"`
+}
+
+type syncResponse struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join map[string]struct {
+			Timeline struct {
+				Events []roomEvent `json:"events"`
+			} `json:"timeline"`
+		} `json:"join"`
+	} `json:"rooms"`
+}
+
+// syncFilter keeps /sync responses down to room messages.
+const syncFilter = `{"presence":{"types":[]},"account_data":{"types":[]},` +
+	`"room":{"state":{"types":[]},"ephemeral":{"types":[]},"account_data":{"types":[]},` +
+	`"timeline":{"limit":20,"types":["m.room.message"]}}}`
+
+// Sync long-polls for new events after since ("" for an initial sync).
+func (c *Client) Sync(ctx context.Context, since string, timeout time.Duration) (*syncResponse, error) {
+	q := url.Values{}
+	q.Set("filter", syncFilter)
+	q.Set("timeout", fmt.Sprint(timeout.Milliseconds()))
+	if since != "" {
+		q.Set("since", since)
+	}
+
+	var r syncResponse
+	if err := c.do(ctx, http.MethodGet, "/sync", q, nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// SendNotice posts a bot notice; formatted, when not empty, is its HTML body.
+func (c *Client) SendNotice(ctx context.Context, room, txnID, body, formatted string) error {
+	content := map[string]string{"msgtype": "m.notice", "body": body}
+	if formatted != "" {
+		content["format"] = "org.matrix.custom.html"
+		content["formatted_body"] = formatted
+	}
+	path := fmt.Sprintf("/rooms/%s/send/m.room.message/%s", url.PathEscape(room), url.PathEscape(txnID))
+	return c.do(ctx, http.MethodPut, path, nil, content, nil)
+}
@@ -0,0 +1,210 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/frontend"
+	"moviebot/internal/omdb"
+	"moviebot/internal/trace"
+)
+
+// Bot serves the movie commands in Matrix rooms. Matrix has no inline
+// keyboards, so the Telegram buttons become "!" commands:
+//
+//	!movie <title>   search, then !pick <n>
+//	!vote <title>    toggle your vote
+//	!seen <title>    toggle watched
+//	!list            show the list
+type Bot struct {
+	cfg    config.MatrixConfig
+	client *Client
+	core   *frontend.Core
+
+	mu      sync.Mutex
+	pending map[string][]omdb.SearchResult // room|sender -> last search
+	txn     int64
+}
+
+func New(cfg config.MatrixConfig, core *frontend.Core) *Bot {
+	return &Bot{
+		cfg:     cfg,
+		client:  NewClient(cfg.Homeserver, cfg.AccessToken),
+		core:    core,
+		pending: make(map[string][]omdb.SearchResult),
+	}
+}
+
+func (b *Bot) Name() string { return "matrix" }
+
+func (b *Bot) Run(ctx context.Context) error {
+	log.Printf("[MATRIX] Starting as %s on %s", b.cfg.UserID, b.cfg.Homeserver)
+
+	rooms := make(map[string]bool) // by room ID, aliases resolved by joining
+	for _, room := range b.cfg.Rooms {
+		id, err := b.client.Join(ctx, room)
+		switch {
+		case err == nil && id != "":
+			rooms[id] = true
+		case strings.HasPrefix(room, "!"):
+			// A room ID can still be listened to if we are already in it.
+			log.Printf("[MATRIX] Failed to join %s: %v", room, err)
+			rooms[room] = true
+		default:
+			log.Printf("[MATRIX] Failed to join %s, ignoring it: %v", room, err)
+		}
+	}
+
+	// The initial sync only gives us a position; history is not replayed.
+	first, err := b.client.Sync(ctx, "", 0)
+	if err != nil {
+		return fmt.Errorf("initial sync: %w", err)
+	}
+	since := first.NextBatch
+	log.Printf("[MATRIX] Listening in %d rooms", len(rooms))
+
+	backoff := time.Second
+	for ctx.Err() == nil {
+		resp, err := b.client.Sync(ctx, since, 30*time.Second)
+		if err != nil {
+			log.Printf("[MATRIX] Sync failed, retrying in %s: %v", backoff, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(backoff):
+			}
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+			continue
+		}
+		backoff = time.Second
+		since = resp.NextBatch
+
+		for roomID, room := range resp.Rooms.Join {
+			if !rooms[roomID] {
+				continue
+			}
+			for _, ev := range room.Timeline.Events {
+				if ev.Type != "m.room.message" || ev.Sender == b.cfg.UserID {
+					continue
+				}
+				if strings.HasPrefix(ev.Content.Body, "!") {
+					b.handleCommand(trace.NewContext(ctx), roomID, ev)
+				}
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+func (b *Bot) handleCommand(ctx context.Context, room string, ev roomEvent) {
+	cmd, args, _ := strings.Cut(strings.TrimSpace(ev.Content.Body), " ")
+	args = strings.TrimSpace(args)
+	user := frontend.User{ID: ev.Sender, Name: ev.Sender}
+	key := room + "|" + ev.Sender
+
+	trace.Logf(ctx, "[MATRIX] %s from %s in %s", cmd, ev.Sender, room)
+
+	switch strings.ToLower(cmd) {
+	case "!movie":
+		if args == "" {
+			b.reply(ctx, room, "Usage: !movie <title>")
+			return
+		}
+		results, err := b.core.Search(ctx, args)
+		if err != nil || len(results) == 0 {
+			b.reply(ctx, room, "No results found")
+			return
+		}
+		b.mu.Lock()
+		b.pending[key] = results
+		b.mu.Unlock()
+
+		var sb strings.Builder
+		for i, r := range results {
+			fmt.Fprintf(&sb, "%d. %s (%s)\n", i+1, r.Title, r.Year)
+		}
+		sb.WriteString("Reply !pick <number> to add one.")
+		b.reply(ctx, room, sb.String())
+
+	case "!pick":
+		n, err := strconv.Atoi(args)
+		b.mu.Lock()
+		results := b.pending[key]
+		if err == nil && n >= 1 && n <= len(results) {
+			delete(b.pending, key)
+		}
+		b.mu.Unlock()
+		if err != nil || n < 1 || n > len(results) {
+			b.reply(ctx, room, "Pick a number from your last !movie search.")
+			return
+		}
+		movie, created := b.core.Add(ctx, results[n-1], user)
+		if created {
+			b.reply(ctx, room, fmt.Sprintf("🎬 Added %s (%d). Vote with !vote %s", movie.Title, movie.Year, movie.Title))
+		} else {
+			b.reply(ctx, room, fmt.Sprintf("%s (%d) is already on the list.", movie.Title, movie.Year))
+		}
+
+	case "!vote", "!seen":
+		movie, err := b.core.Find(args)
+		if err != nil {
+			b.reply(ctx, room, "❌ "+err.Error())
+			return
+		}
+		if cmd == "!vote" {
+			movie, active, err := b.core.ToggleVote(ctx, movie.ID, user)
+			if err == nil {
+				b.reply(ctx, room, fmt.Sprintf("👍 %s %s — %d votes", voteVerb(active), movie.Title, len(movie.Votes)))
+			}
+			return
+		}
+		movie, active, err := b.core.ToggleWatched(ctx, movie.ID, user)
+		if err == nil {
+			b.reply(ctx, room, fmt.Sprintf("👁 %s %s — seen by %d", watchVerb(active), movie.Title, len(movie.Watched)))
+		}
+
+	case "!list":
+		list := b.core.List()
+		b.send(ctx, room, list, "<pre>"+html.EscapeString(list)+"</pre>")
+
+	case "!help":
+		b.reply(ctx, room, "!movie <title>, !pick <n>, !vote <title>, !seen <title>, !list")
+	}
+}
+
+func voteVerb(active bool) string {
+	if active {
+		return "Voted for"
+	}
+	return "Removed vote for"
+}
+
+func watchVerb(active bool) string {
+	if active {
+		return "Watched"
+	}
+	return "Unwatched"
+}
+
+func (b *Bot) reply(ctx context.Context, room, text string) {
+	b.send(ctx, room, text, "")
+}
+
+func (b *Bot) send(ctx context.Context, room, body, formatted string) {
+	b.mu.Lock()
+	b.txn++
+	txnID := fmt.Sprintf("moviebot-%d-%d", time.Now().UnixNano(), b.txn)
+	b.mu.Unlock()
+
+	if err := b.client.SendNotice(ctx, room, txnID, body, formatted); err != nil {
+		trace.Logf(ctx, "[MATRIX] Send to %s failed: %v", room, err)
+	}
+}
@@ -0,0 +1,76 @@
+package mediaserver
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+// jellyfin reads the played movies of each user, with an API key created
+// in the dashboard.
+type jellyfin struct {
+	base   string
+	token  string
+	client *http.Client
+}
+
+func (j *jellyfin) header() http.Header {
+	return http.Header{"X-Emby-Token": {j.token}}
+}
+
+// Plays returns the movies users played, users given by name or ID. An
+// item only knows when it was last played, so a rewatch counts as of then.
+func (j *jellyfin) Plays(ctx context.Context, users []string, since time.Time) ([]Play, error) {
+	var accounts []struct {
+		ID   string `json:"Id"`
+		Name string `json:"Name"`
+	}
+	if err := getJSON(ctx, j.client, j.base+"/Users", j.header(), &accounts); err != nil {
+		return nil, err
+	}
+
+	var plays []Play
+	for _, user := range users {
+		id := ""
+		for _, a := range accounts {
+			if strings.EqualFold(a.Name, user) || a.ID == user {
+				id = a.ID
+			}
+		}
+		if id == "" {
+			trace.Logf(ctx, "[MEDIA][WARN] No Jellyfin user %q", user)
+			continue
+		}
+
+		q := url.Values{}
+		q.Set("Recursive", "true")
+		q.Set("IncludeItemTypes", "Movie")
+		q.Set("Filters", "IsPlayed")
+		q.Set("Fields", "ProviderIds,ProductionYear")
+		var out struct {
+			Items []struct {
+				Name           string            `json:"Name"`
+				ProductionYear int               `json:"ProductionYear"`
+				ProviderIds    map[string]string `json:"ProviderIds"`
+				UserData       struct {
+					LastPlayedDate time.Time `json:"LastPlayedDate"`
+				} `json:"UserData"`
+			} `json:"Items"`
+		}
+		if err := getJSON(ctx, j.client, j.base+"/Users/"+url.PathEscape(id)+"/Items?"+q.Encode(), j.header(), &out); err != nil {
+			return nil, err
+		}
+		for _, it := range out.Items {
+			at := it.UserData.LastPlayedDate
+			if !since.IsZero() && !at.After(since) {
+				continue
+			}
+			plays = append(plays, Play{User: user, Title: it.Name, Year: it.ProductionYear, ImdbID: it.ProviderIds["Imdb"], At: at})
+		}
+	}
+	return plays, nil
+}
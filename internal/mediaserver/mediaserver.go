@@ -0,0 +1,256 @@
+// Package mediaserver imports watch history from a Jellyfin or Plex server,
+// so movies people watched there are marked watched without tapping 👁.
+package mediaserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/events"
+	"moviebot/internal/maintenance"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// defaultInterval is how often new plays are synced when Interval is unset.
+const defaultInterval = time.Hour
+
+// Play is a movie a server user finished.
+type Play struct {
+	User   string // as in the config's users: Jellyfin user name, Plex account ID
+	Title  string
+	Year   int
+	ImdbID string    // "" when the server doesn't know it
+	At     time.Time // zero when the server doesn't say
+}
+
+// Source lists the plays of users since a time, all of them for a zero one.
+type Source interface {
+	Plays(ctx context.Context, users []string, since time.Time) ([]Play, error)
+}
+
+// NewSource returns the client for the configured kind of server.
+func NewSource(cfg config.MediaServerConfig) (Source, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("media_server.url is not set")
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	base := strings.TrimRight(cfg.URL, "/")
+	switch strings.ToLower(cfg.Kind) {
+	case "jellyfin", "":
+		return &jellyfin{base: base, token: cfg.Token, client: client}, nil
+	case "plex":
+		return &plex{base: base, token: cfg.Token, client: client}, nil
+	}
+	return nil, fmt.Errorf("unknown media_server.kind %q, want jellyfin or plex", cfg.Kind)
+}
+
+// getJSON fetches url with the given headers into out.
+func getJSON(ctx context.Context, client *http.Client, url string, header http.Header, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// =====================================================
+// SYNC
+// =====================================================
+
+// Syncer marks the movies mapped users played as watched by them. It
+// remembers the newest play it imported in a state file, so a mark taken
+// back by hand is not set again by the next sync.
+type Syncer struct {
+	cfg    config.MediaServerConfig
+	source Source
+	store  *storage.Store
+	events *events.Bus
+	state  string
+
+	// Maintenance pauses the sync while active; may be nil.
+	Maintenance *maintenance.Mode
+}
+
+// NewSyncer syncs into store, announcing marks on bus (may be nil). The
+// sync state is kept in the file at state.
+func NewSyncer(cfg config.MediaServerConfig, store *storage.Store, bus *events.Bus, state string) (*Syncer, error) {
+	source, err := NewSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Syncer{cfg: cfg, source: source, store: store, events: bus, state: state}, nil
+}
+
+// Result sums up one import.
+type Result struct {
+	Plays     int // plays of mapped users
+	Marked    int // watched marks set
+	Unmatched int // plays of movies not on the watchlist
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%d plays, %d marked watched, %d not on the watchlist", r.Plays, r.Marked, r.Unmatched)
+}
+
+// Import marks the plays since the last import as watched; full imports the
+// whole history.
+func (s *Syncer) Import(ctx context.Context, full bool) (Result, error) {
+	var since time.Time
+	if !full {
+		since = s.since()
+	}
+	users := make([]string, 0, len(s.cfg.Users))
+	for u := range s.cfg.Users {
+		users = append(users, u)
+	}
+	plays, err := s.source.Plays(ctx, users, since)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var res Result
+	newest := since
+	movies := s.store.GetAllMovies()
+	for _, p := range plays {
+		if !since.IsZero() && !p.At.After(since) {
+			continue
+		}
+		telegramID, ok := s.cfg.Users[p.User]
+		if !ok {
+			continue
+		}
+		res.Plays++
+		if p.At.After(newest) {
+			newest = p.At
+		}
+
+		libraries := s.libraries(p.User, telegramID)
+		matched := false
+		for _, m := range movies {
+			if !slices.Contains(libraries, m.ChatID) || !matches(m, p) {
+				continue
+			}
+			matched = true
+			movie, ok := s.store.MarkWatched(ctx, m.ID, strconv.FormatInt(telegramID, 10), p.At)
+			if !ok {
+				continue
+			}
+			res.Marked++
+			s.events.Publish(ctx, events.Event{
+				Type:     events.MovieWatched,
+				Source:   "mediaserver",
+				UserID:   telegramID,
+				Username: p.User,
+				Active:   true,
+				Movie:    &movie,
+			})
+		}
+		if !matched {
+			res.Unmatched++
+		}
+	}
+
+	if newest.After(since) {
+		s.setSince(ctx, newest)
+	}
+	return res, nil
+}
+
+// libraries returns the libraries a server user's plays mark: those of the
+// chats mapped to them, else that of their private chat with the bot.
+func (s *Syncer) libraries(user string, telegramID int64) []int64 {
+	chats, ok := s.cfg.Chats[user]
+	if !ok {
+		chats = []int64{telegramID}
+	}
+	libraries := make([]int64, 0, len(chats))
+	for _, chatID := range chats {
+		libraries = append(libraries, s.store.LibraryOf(chatID))
+	}
+	return libraries
+}
+
+// matches reports whether a play is of movie m: by IMDb ID when both know
+// it, else by title and year.
+func matches(m storage.Movie, p Play) bool {
+	if m.ImdbID != "" && p.ImdbID != "" {
+		return m.ImdbID == p.ImdbID
+	}
+	return strings.EqualFold(strings.TrimSpace(m.Title), strings.TrimSpace(p.Title)) && (p.Year == 0 || m.Year == p.Year)
+}
+
+// Run syncs new plays right away and then every Interval, until ctx is done.
+func (s *Syncer) Run(ctx context.Context) {
+	interval := s.cfg.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	log.Printf("[MEDIA] Syncing %s plays every %s", s.cfg.Kind, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if s.Maintenance.Active() {
+			log.Printf("[MEDIA] Skipped, maintenance mode")
+		} else {
+			ctx := trace.NewContext(ctx)
+			res, err := s.Import(ctx, false)
+			switch {
+			case err != nil:
+				trace.Logf(ctx, "[MEDIA] Sync failed: %v", err)
+			case res.Plays > 0:
+				trace.Logf(ctx, "[MEDIA] Synced %s", res)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncState is the state file's content.
+type syncState struct {
+	Since time.Time `json:"since"` // the newest play imported
+}
+
+// since returns the newest play imported, zero before the first import.
+func (s *Syncer) since() time.Time {
+	var st syncState
+	if data, err := os.ReadFile(s.state); err == nil {
+		json.Unmarshal(data, &st)
+	}
+	return st.Since
+}
+
+func (s *Syncer) setSince(ctx context.Context, t time.Time) {
+	data, _ := json.Marshal(syncState{Since: t})
+	if err := os.WriteFile(s.state, data, 0644); err != nil {
+		trace.Logf(ctx, "[MEDIA][WARN] Saving the sync state failed: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package mediaserver
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+// plex reads the server's play history, with an X-Plex-Token of the owner.
+type plex struct {
+	base   string
+	token  string
+	client *http.Client
+}
+
+func (p *plex) header() http.Header {
+	return http.Header{"X-Plex-Token": {p.token}}
+}
+
+// plexMetadata is the part of a Plex history entry or library item the
+// import needs.
+type plexMetadata struct {
+	RatingKey             string `json:"ratingKey"`
+	Title                 string `json:"title"`
+	Year                  int    `json:"year"`
+	OriginallyAvailableAt string `json:"originallyAvailableAt"`
+	ViewedAt              int64  `json:"viewedAt"`
+	AccountID             int64  `json:"accountID"`
+	Guid                  []struct {
+		ID string `json:"id"` // "imdb://tt0078748"
+	} `json:"Guid"`
+}
+
+// Plays returns the movies users played, users given by account ID (the
+// owner is 1). History entries don't carry IMDb IDs, so each movie is
+// looked up once.
+func (p *plex) Plays(ctx context.Context, users []string, since time.Time) ([]Play, error) {
+	q := url.Values{}
+	q.Set("type", "1") // movies
+	q.Set("sort", "viewedAt:desc")
+	if !since.IsZero() {
+		q.Set("viewedAt>", strconv.FormatInt(since.Unix(), 10)) // viewedAt>=since
+	}
+	var out struct {
+		MediaContainer struct {
+			Metadata []plexMetadata `json:"Metadata"`
+		} `json:"MediaContainer"`
+	}
+	if err := getJSON(ctx, p.client, p.base+"/status/sessions/history/all?"+q.Encode(), p.header(), &out); err != nil {
+		return nil, err
+	}
+
+	imdbIDs := make(map[string]string) // ratingKey -> IMDb ID
+	var plays []Play
+	for _, h := range out.MediaContainer.Metadata {
+		user := strconv.FormatInt(h.AccountID, 10)
+		at := time.Unix(h.ViewedAt, 0)
+		if !slices.Contains(users, user) || (!since.IsZero() && !at.After(since)) {
+			continue
+		}
+		id, ok := imdbIDs[h.RatingKey]
+		if !ok && h.RatingKey != "" {
+			id = p.imdbID(ctx, h.RatingKey)
+			imdbIDs[h.RatingKey] = id
+		}
+		plays = append(plays, Play{User: user, Title: h.Title, Year: plexYear(h), ImdbID: id, At: at})
+	}
+	return plays, nil
+}
+
+// imdbID looks up the IMDb ID of a library item, "" if it is gone or has
+// none.
+func (p *plex) imdbID(ctx context.Context, ratingKey string) string {
+	var out struct {
+		MediaContainer struct {
+			Metadata []plexMetadata `json:"Metadata"`
+		} `json:"MediaContainer"`
+	}
+	if err := getJSON(ctx, p.client, p.base+"/library/metadata/"+url.PathEscape(ratingKey)+"?includeGuids=1", p.header(), &out); err != nil {
+		trace.Logf(ctx, "[MEDIA] Plex item %s: %v", ratingKey, err)
+		return ""
+	}
+	for _, m := range out.MediaContainer.Metadata {
+		for _, g := range m.Guid {
+			if id, ok := strings.CutPrefix(g.ID, "imdb://"); ok {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// plexYear is an entry's year, from its release date when not given.
+func plexYear(m plexMetadata) int {
+	if m.Year != 0 {
+		return m.Year
+	}
+	year, _ := strconv.Atoi(strings.SplitN(m.OriginallyAvailableAt, "-", 2)[0])
+	return year
+}
@@ -0,0 +1,206 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+)
+
+const (
+	apiBase    = "https://api.notion.com/v1"
+	apiVersion = "2022-06-28"
+
+	// Notion allows about three requests per second per integration.
+	requestGap = 350 * time.Millisecond
+)
+
+// Syncer upserts movies into a Notion database. The database needs these
+// properties: Name (title), Year (number), Poster (url), Votes (number),
+// Watched (number), Status (select) and Movie ID (rich text), the latter
+// being the key pages are matched on.
+type Syncer struct {
+	cfg    config.NotionConfig
+	store  *storage.Store
+	client *http.Client
+	queue  chan string
+
+	mu    sync.Mutex
+	pages map[string]string // movie ID -> Notion page ID
+}
+
+func NewSyncer(cfg config.NotionConfig, store *storage.Store) *Syncer {
+	s := &Syncer{
+		cfg:    cfg,
+		store:  store,
+		client: &http.Client{Timeout: 15 * time.Second},
+		queue:  make(chan string, 256),
+		pages:  make(map[string]string),
+	}
+	go s.run()
+	return s
+}
+
+// Handle is an events.Bus subscriber queueing the changed movie, if it is
+// in the mirrored library.
+func (s *Syncer) Handle(e events.Event) {
+	if e.Movie == nil || e.Movie.ChatID != s.library() {
+		return
+	}
+	select {
+	case s.queue <- e.Movie.ID:
+	default:
+		log.Printf("[NOTION] Queue full, dropping update for %s", e.Movie.Title)
+	}
+}
+
+// library is the library the database mirrors: the configured chat's.
+func (s *Syncer) library() int64 {
+	return s.store.LibraryOf(s.cfg.ChatID)
+}
+
+// SyncAll queues every movie of the library, used once at startup.
+func (s *Syncer) SyncAll() {
+	for _, m := range s.store.GetLibrary(s.library()) {
+		s.queue <- m.ID
+	}
+}
+
+func (s *Syncer) run() {
+	for id := range s.queue {
+		// Always push the current state, not the one from the event.
+		if movie, ok := s.store.GetMovieByID(id); ok && movie.ChatID == s.library() {
+			if err := s.upsert(context.Background(), movie); err != nil {
+				log.Printf("[NOTION] Upsert of %s failed: %v", movie.Title, err)
+			}
+		}
+		time.Sleep(requestGap)
+	}
+}
+
+func (s *Syncer) upsert(ctx context.Context, m storage.Movie) error {
+	pageID, err := s.findPage(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+
+	props := properties(m)
+	if pageID == "" {
+		var created struct {
+			ID string `json:"id"`
+		}
+		body := map[string]any{
+			"parent":     map[string]string{"database_id": s.cfg.DatabaseID},
+			"properties": props,
+		}
+		if err := s.call(ctx, http.MethodPost, "/pages", body, &created); err != nil {
+			return err
+		}
+		s.remember(m.ID, created.ID)
+		log.Printf("[NOTION] Created page for %s", m.Title)
+		return nil
+	}
+
+	return s.call(ctx, http.MethodPatch, "/pages/"+pageID, map[string]any{"properties": props}, nil)
+}
+
+func (s *Syncer) remember(movieID, pageID string) {
+	s.mu.Lock()
+	s.pages[movieID] = pageID
+	s.mu.Unlock()
+}
+
+// findPage looks up the page for a movie, first in the cache, then by
+// querying the database on the Movie ID property.
+func (s *Syncer) findPage(ctx context.Context, movieID string) (string, error) {
+	s.mu.Lock()
+	pageID, ok := s.pages[movieID]
+	s.mu.Unlock()
+	if ok {
+		return pageID, nil
+	}
+
+	var res struct {
+		Results []struct {
+			ID string `json:"id"`
+		} `json:"results"`
+	}
+	query := map[string]any{
+		"filter": map[string]any{
+			"property":  "Movie ID",
+			"rich_text": map[string]string{"equals": movieID},
+		},
+		"page_size": 1,
+	}
+	if err := s.call(ctx, http.MethodPost, "/databases/"+s.cfg.DatabaseID+"/query", query, &res); err != nil {
+		return "", err
+	}
+	if len(res.Results) == 0 {
+		return "", nil
+	}
+	s.remember(movieID, res.Results[0].ID)
+	return res.Results[0].ID, nil
+}
+
+func properties(m storage.Movie) map[string]any {
+	status := "To watch"
+	if len(m.Watched) > 0 {
+		status = "Watched"
+	}
+	props := map[string]any{
+		"Name":     map[string]any{"title": []any{text(m.Title)}},
+		"Year":     map[string]any{"number": m.Year},
+		"Votes":    map[string]any{"number": len(m.Votes)},
+		"Watched":  map[string]any{"number": len(m.Watched)},
+		"Status":   map[string]any{"select": map[string]string{"name": status}},
+		"Movie ID": map[string]any{"rich_text": []any{text(m.ID)}},
+	}
+	if m.Poster != "" && m.Poster != "N/A" {
+		props["Poster"] = map[string]any{"url": m.Poster}
+	}
+	return props
+}
+
+func text(s string) map[string]any {
+	return map[string]any{"type": "text", "text": map[string]string{"content": s}}
+}
+
+func (s *Syncer) call(ctx context.Context, method, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiBase+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.cfg.Token)
+	req.Header.Set("Notion-Version", apiVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("%s %s: HTTP %d %s", method, path, resp.StatusCode, e.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,82 @@
+package omdb
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"moviebot/internal/trace"
+)
+
+//go:embed demo_movies.json
+var demoMovies []byte
+
+// DemoClient answers searches from a small bundled dataset so the bot can be
+// tried without an OMDb key.
+type DemoClient struct {
+	movies []SearchResult
+}
+
+func NewDemoClient() *DemoClient {
+	var movies []SearchResult
+	if err := json.Unmarshal(demoMovies, &movies); err != nil {
+		panic(fmt.Sprintf("omdb: bundled demo data is invalid: %v", err))
+	}
+	return &DemoClient{movies: movies}
+}
+
+// Search does a case-insensitive substring match on titles, mimicking the
+// shape of a real OMDb response.
+func (c *DemoClient) Search(ctx context.Context, title string) ([]SearchResult, error) {
+	trace.Logf(ctx, "[OMDb][DEMO] Searching for: %s", title)
+	q := strings.ToLower(strings.TrimSpace(title))
+
+	var out []SearchResult
+	for _, m := range c.movies {
+		if strings.Contains(strings.ToLower(m.Title), q) {
+			out = append(out, m)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("OMDb error: %s: %w", "Movie not found!", ErrNoResults)
+	}
+	return out, nil
+}
+
+func (c *DemoClient) GetByID(ctx context.Context, imdbID string) (*Details, error) {
+	for _, m := range c.movies {
+		if m.ImdbID == imdbID {
+			return demoDetails(m), nil
+		}
+	}
+	return nil, fmt.Errorf("OMDb error: %s", "Incorrect IMDb ID.")
+}
+
+func (c *DemoClient) GetByTitle(ctx context.Context, title string, year int) (*Details, error) {
+	for _, m := range c.movies {
+		if strings.EqualFold(m.Title, title) && (year == 0 || m.Year == fmt.Sprint(year)) {
+			return demoDetails(m), nil
+		}
+	}
+	return nil, fmt.Errorf("OMDb error: %s", "Movie not found!")
+}
+
+// demoDetails fills in what the bundled data knows; the rest stays "N/A"
+// as OMDb itself does for missing fields.
+func demoDetails(m SearchResult) *Details {
+	return &Details{
+		Title:      m.Title,
+		Year:       m.Year,
+		ImdbID:     m.ImdbID,
+		Type:       m.Type,
+		Poster:     m.Poster,
+		Runtime:    "N/A",
+		Genre:      "N/A",
+		Director:   "N/A",
+		Plot:       "N/A",
+		ImdbRating: "N/A",
+		Response:   "True",
+	}
+}
@@ -1,15 +1,37 @@
 package omdb
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+
+	"moviebot/internal/alerts"
+	"moviebot/internal/trace"
 )
 
+// ErrNoResults is wrapped into the error of a search OMDb answered but
+// found nothing for, as opposed to one that failed.
+var ErrNoResults = errors.New("no results")
+
+// API is what the bot needs from a movie metadata provider. OMDbClient is
+// the real one; DemoClient serves bundled sample data.
+type API interface {
+	Search(ctx context.Context, title string) ([]SearchResult, error)
+	GetByID(ctx context.Context, imdbID string) (*Details, error)
+	GetByTitle(ctx context.Context, title string, year int) (*Details, error)
+}
+
 type OMDbClient struct {
 	APIKey string
+
+	// Alerts hears about requests failing because of the key; may be nil.
+	Alerts *alerts.Alerter
+
+	calls usage
 }
 
 type SearchResult struct {
@@ -27,6 +49,33 @@ type SearchResponse struct {
 	Error        string         `json:"Error,omitempty"`
 }
 
+// Details is OMDb's full record for one title (plot=short).
+type Details struct {
+	Title      string `json:"Title"`
+	Year       string `json:"Year"`
+	Rated      string `json:"Rated"`
+	Released   string `json:"Released"`
+	Runtime    string `json:"Runtime"`
+	Genre      string `json:"Genre"`
+	Director   string `json:"Director"`
+	Actors     string `json:"Actors"`
+	Plot       string `json:"Plot"`
+	Poster     string `json:"Poster"`
+	ImdbRating string `json:"imdbRating"`
+	ImdbID     string `json:"imdbID"`
+	Type       string `json:"Type"`
+	Response   string `json:"Response"`
+	Error      string `json:"Error,omitempty"`
+
+	Ratings []Rating `json:"Ratings"`
+}
+
+// Rating is one outlet's score, e.g. Rotten Tomatoes "94%".
+type Rating struct {
+	Source string `json:"Source"`
+	Value  string `json:"Value"`
+}
+
 func NewClient(apiKey string) *OMDbClient {
 	if apiKey == "" {
 		log.Fatal("[OMDb] API key not set")
@@ -60,32 +109,102 @@ func (c *OMDbClient) TestKey() bool {
 }
 
 // Search for a movie by title
-func (c *OMDbClient) Search(title string) ([]SearchResult, error) {
-	log.Printf("[OMDb] Searching for: %s\n", title)
+func (c *OMDbClient) Search(ctx context.Context, title string) ([]SearchResult, error) {
+	trace.Logf(ctx, "[OMDb] Searching for: %s", title)
 	baseURL := "http://www.omdbapi.com/"
 	params := url.Values{}
 	params.Set("apikey", c.APIKey)
 	params.Set("s", title)
 
 	fullURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-	resp, err := http.Get(fullURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
 	if err != nil {
-		log.Println("[OMDb] HTTP error:", err)
+		return nil, err
+	}
+	c.calls.add()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		trace.Logf(ctx, "[OMDb] HTTP error: %v", err)
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var r SearchResponse
 	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
-		log.Println("[OMDb] JSON decode error:", err)
+		trace.Logf(ctx, "[OMDb] JSON decode error: %v", err)
 		return nil, err
 	}
 
 	if r.Response != "True" {
-		log.Println("[OMDb] No results found or error:", r.Error)
+		trace.Logf(ctx, "[OMDb] No results found or error: %s", r.Error)
+		c.reportKey(resp.StatusCode, r.Error)
+		if r.Error == "Movie not found!" || r.Error == "Too many results." {
+			return nil, fmt.Errorf("OMDb error: %s: %w", r.Error, ErrNoResults)
+		}
 		return nil, fmt.Errorf("OMDb error: %s", r.Error)
 	}
 
-	log.Printf("[OMDb] Found %d results\n", len(r.Search))
+	trace.Logf(ctx, "[OMDb] Found %d results", len(r.Search))
 	return r.Search, nil
-}
\ No newline at end of file
+}
+
+// GetByID fetches the full record for an IMDb ID.
+func (c *OMDbClient) GetByID(ctx context.Context, imdbID string) (*Details, error) {
+	params := url.Values{}
+	params.Set("i", imdbID)
+	return c.details(ctx, params)
+}
+
+// GetByTitle fetches the full record for an exact title, narrowed by year
+// when year > 0. Used for movies stored before IMDb IDs were kept.
+func (c *OMDbClient) GetByTitle(ctx context.Context, title string, year int) (*Details, error) {
+	params := url.Values{}
+	params.Set("t", title)
+	if year > 0 {
+		params.Set("y", fmt.Sprint(year))
+	}
+	return c.details(ctx, params)
+}
+
+func (c *OMDbClient) details(ctx context.Context, params url.Values) (*Details, error) {
+	params.Set("apikey", c.APIKey)
+	params.Set("plot", "short")
+	trace.Logf(ctx, "[OMDb] Fetching details for %s%s", params.Get("i"), params.Get("t"))
+
+	fullURL := fmt.Sprintf("%s?%s", "http://www.omdbapi.com/", params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.calls.add()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		trace.Logf(ctx, "[OMDb] HTTP error: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var d Details
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		trace.Logf(ctx, "[OMDb] JSON decode error: %v", err)
+		return nil, err
+	}
+	if d.Response != "True" {
+		c.reportKey(resp.StatusCode, d.Error)
+		return nil, fmt.Errorf("OMDb error: %s", d.Error)
+	}
+	return &d, nil
+}
+
+// reportKey passes errors meaning the key is unusable (wrong, missing or
+// over its daily limit) on to the alerts.
+func (c *OMDbClient) reportKey(status int, msg string) {
+	switch msg {
+	case "Invalid API key!", "No API key provided.", "Request limit reached!":
+	default:
+		if status != http.StatusUnauthorized {
+			return
+		}
+	}
+	c.Alerts.Failure("OMDb key", fmt.Errorf("OMDb error: %s (HTTP %d)", msg, status))
+}
@@ -0,0 +1,42 @@
+package omdb
+
+import (
+	"sync"
+	"time"
+)
+
+// DailyLimit is the request quota of an OMDb free-tier key.
+const DailyLimit = 1000
+
+// usage counts requests made today (UTC) with one key.
+type usage struct {
+	mu  sync.Mutex
+	day string
+	n   int
+}
+
+func (u *usage) add() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if today := time.Now().UTC().Format(time.DateOnly); u.day != today {
+		u.day, u.n = today, 0
+	}
+	u.n++
+}
+
+func (u *usage) today() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.day != time.Now().UTC().Format(time.DateOnly) {
+		return 0
+	}
+	return u.n
+}
+
+// CallsToday is how many requests this client sent to OMDb today (UTC),
+// to compare against DailyLimit.
+func (c *OMDbClient) CallsToday() int {
+	return c.calls.today()
+}
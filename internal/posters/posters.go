@@ -0,0 +1,90 @@
+package posters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg" // decoders for the poster formats OMDb serves
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"moviebot/internal/storage"
+)
+
+// maxPosterSize caps a single poster download.
+const maxPosterSize = 5 << 20
+
+// Cache keeps downloaded posters on disk so collages and exports don't hit
+// the poster CDN every time. Files are named after the IMDb ID (or the movie
+// ID when there is none).
+type Cache struct {
+	dir    string
+	client *http.Client
+}
+
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Get returns the decoded poster of m, downloading it on first use.
+func (c *Cache) Get(ctx context.Context, m storage.Movie) (image.Image, error) {
+	data, err := c.Bytes(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode poster of %s: %w", m.Title, err)
+	}
+	return img, nil
+}
+
+// Bytes returns the raw poster file of m, downloading it on first use.
+func (c *Cache) Bytes(ctx context.Context, m storage.Movie) ([]byte, error) {
+	if m.Poster == "" || m.Poster == "N/A" {
+		return nil, fmt.Errorf("%s has no poster", m.Title)
+	}
+
+	key := m.ImdbID
+	if key == "" {
+		key = m.ID
+	}
+	path := filepath.Join(c.dir, key+filepath.Ext(m.Poster))
+
+	if data, err := os.ReadFile(path); err == nil {
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.Poster, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download poster of %s: %w", m.Title, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download poster of %s: HTTP %d", m.Title, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPosterSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		log.Printf("[POSTERS] Failed to create cache dir: %v", err)
+		return data, nil
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Printf("[POSTERS] Failed to cache poster of %s: %v", m.Title, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,64 @@
+package posters
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand/v2"
+)
+
+// Teaser geometry: the share of the poster's width and height kept, the
+// size the crop is scaled to (posters are 2:3), and how many blocks across
+// it is pixelated into.
+const (
+	teaserCrop   = 0.45
+	teaserW      = 320
+	teaserH      = 480
+	teaserBlocks = 10
+)
+
+// Teaser crops a random part of a poster and pixelates it, for guessing
+// games, and encodes it as JPEG.
+func Teaser(poster image.Image) ([]byte, error) {
+	b := poster.Bounds()
+	w, h := max(int(float64(b.Dx())*teaserCrop), 1), max(int(float64(b.Dy())*teaserCrop), 1)
+	crop := image.Rect(0, 0, w, h).Add(b.Min).Add(image.Pt(rand.IntN(b.Dx()-w+1), rand.IntN(b.Dy()-h+1)))
+
+	out := image.NewRGBA(image.Rect(0, 0, teaserW, teaserH))
+	block := teaserW / teaserBlocks
+	for by := 0; by < teaserH; by += block {
+		for bx := 0; bx < teaserW; bx += block {
+			cell := image.Rect(bx, by, min(bx+block, teaserW), min(by+block, teaserH))
+			src := image.Rect(
+				crop.Min.X+cell.Min.X*w/teaserW, crop.Min.Y+cell.Min.Y*h/teaserH,
+				crop.Min.X+cell.Max.X*w/teaserW, crop.Min.Y+cell.Max.Y*h/teaserH,
+			)
+			c := average(poster, src)
+			for y := cell.Min.Y; y < cell.Max.Y; y++ {
+				for x := cell.Min.X; x < cell.Max.X; x++ {
+					out.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, out, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// average is the mean colour of img over r, at least one pixel.
+func average(img image.Image, r image.Rectangle) color.RGBA {
+	r.Max = r.Max.Add(image.Pt(max(0, 1-r.Dx()), max(0, 1-r.Dy())))
+	var sr, sg, sb, n uint64
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cr, cg, cb, _ := img.At(x, y).RGBA()
+			sr, sg, sb, n = sr+uint64(cr), sg+uint64(cg), sb+uint64(cb), n+1
+		}
+	}
+	return color.RGBA{uint8(sr / n >> 8), uint8(sg / n >> 8), uint8(sb / n >> 8), 0xff}
+}
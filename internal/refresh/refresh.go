@@ -0,0 +1,187 @@
+package refresh
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/events"
+	"moviebot/internal/maintenance"
+	"moviebot/internal/omdb"
+	"moviebot/internal/storage"
+	"moviebot/internal/tmdb"
+	"moviebot/internal/trace"
+)
+
+// Job re-fetches OMDb details for stored movies on a slow schedule, oldest
+// refresh first, and announces changes so tracked messages are re-rendered.
+// It spends at most DailyBudget OMDb calls per day, leaving the rest of the
+// key's quota to interactive searches.
+type Job struct {
+	cfg    config.RefreshConfig
+	store  *storage.Store
+	omdb   omdb.API
+	events *events.Bus
+	tmdb   *tmdb.Client // optional, adds collections
+
+	// Maintenance pauses the job while active; may be nil.
+	Maintenance *maintenance.Mode
+
+	day  int // YearDay the budget counter belongs to
+	used int
+}
+
+func NewJob(cfg config.RefreshConfig, store *storage.Store, client omdb.API, tmdbClient *tmdb.Client, bus *events.Bus) *Job {
+	return &Job{cfg: cfg, store: store, omdb: client, tmdb: tmdbClient, events: bus}
+}
+
+// Run refreshes a batch every Interval. It never returns.
+func (j *Job) Run() {
+	log.Printf("[REFRESH] Metadata refresh every %s, %d per batch, budget %d/day",
+		j.cfg.Interval, j.cfg.BatchSize, j.cfg.DailyBudget)
+	for {
+		time.Sleep(j.cfg.Interval)
+		if j.Maintenance.Active() {
+			log.Printf("[REFRESH] Skipped, maintenance mode")
+			continue
+		}
+		j.RunOnce(trace.NewContext(context.Background()))
+	}
+}
+
+// RunOnce refreshes up to BatchSize stale movies within today's budget.
+func (j *Job) RunOnce(ctx context.Context) {
+	now := time.Now()
+	if now.YearDay() != j.day {
+		j.day, j.used = now.YearDay(), 0
+	}
+
+	for _, m := range j.stale(now) {
+		if j.used >= j.cfg.DailyBudget {
+			trace.Logf(ctx, "[REFRESH] Daily OMDb budget of %d used up", j.cfg.DailyBudget)
+			return
+		}
+		j.used++
+
+		var (
+			d   *omdb.Details
+			err error
+		)
+		if m.ImdbID != "" {
+			d, err = j.omdb.GetByID(ctx, m.ImdbID)
+		} else {
+			d, err = j.omdb.GetByTitle(ctx, m.Title, m.Year)
+		}
+		if err != nil {
+			trace.Logf(ctx, "[REFRESH] %s (%d): %v", m.Title, m.Year, err)
+			// Stamp it anyway so one broken entry cannot eat the budget.
+			j.store.UpdateMetadata(ctx, m.ID, storage.Metadata{})
+			continue
+		}
+
+		md := MetadataFrom(d)
+		j.addTMDB(ctx, &md, m.TmdbID)
+
+		movie, changed, err := j.store.UpdateMetadata(ctx, m.ID, md)
+		if err == nil && changed {
+			j.events.Publish(ctx, events.Event{Type: events.MovieUpdated, Source: "refresh", Movie: &movie, Active: true})
+		}
+	}
+}
+
+// addTMDB fills in TMDB-only data (collection, localized titles, and the
+// release date OMDb lacks for many upcoming movies) when a TMDB key is set.
+func (j *Job) addTMDB(ctx context.Context, md *storage.Metadata, tmdbID int) {
+	if j.tmdb == nil || md.ImdbID == "" {
+		return
+	}
+	if tmdbID == 0 {
+		id, err := j.tmdb.FindByImdbID(ctx, md.ImdbID)
+		if err != nil || id == 0 {
+			return
+		}
+		tmdbID = id
+	}
+	md.TmdbID = tmdbID
+
+	tm, err := j.tmdb.Movie(ctx, tmdbID)
+	if err != nil {
+		trace.Logf(ctx, "[REFRESH] TMDB lookup for %s failed: %v", md.ImdbID, err)
+		return
+	}
+	if tm.BelongsToCollection != nil {
+		md.Collection = tm.BelongsToCollection.Name
+	}
+	md.Titles = localTitles(tm)
+	if t, err := time.Parse("2006-01-02", tm.ReleaseDate); err == nil && md.Released.IsZero() {
+		md.Released = t
+	}
+}
+
+// localTitles keeps the titles in the languages a chat can pick with
+// /settings. English is left out: the OMDb title already is the English one.
+func localTitles(tm *tmdb.Movie) map[string]string {
+	all := tm.LocalTitles()
+	titles := make(map[string]string)
+	for _, lang := range storage.TimeLocales() {
+		if t := all[lang]; t != "" && lang != "en" {
+			titles[lang] = t
+		}
+	}
+	return titles
+}
+
+// manualRetry is how often a movie added by hand is looked up again until
+// OMDb knows it.
+const manualRetry = time.Hour
+
+// stale returns the movies due for a refresh: movies added by hand first,
+// then the least recently refreshed.
+func (j *Job) stale(now time.Time) []storage.Movie {
+	var due []storage.Movie
+	for _, m := range j.store.GetAllMovies() {
+		maxAge := j.cfg.MaxAge
+		if m.Manual {
+			maxAge = manualRetry
+		}
+		if now.Sub(m.RefreshedAt) >= maxAge {
+			due = append(due, m)
+		}
+	}
+	sort.Slice(due, func(a, b int) bool {
+		if due[a].Manual != due[b].Manual {
+			return due[a].Manual
+		}
+		return due[a].RefreshedAt.Before(due[b].RefreshedAt)
+	})
+	if len(due) > j.cfg.BatchSize {
+		due = due[:j.cfg.BatchSize]
+	}
+	return due
+}
+
+// MetadataFrom maps an OMDb record onto the fields the store keeps fresh.
+func MetadataFrom(d *omdb.Details) storage.Metadata {
+	md := storage.Metadata{
+		ImdbID:     d.ImdbID,
+		Poster:     d.Poster,
+		Runtime:    d.Runtime,
+		ImdbRating: d.ImdbRating,
+		Plot:       d.Plot,
+		Genre:      d.Genre,
+		Director:   d.Director,
+		Rated:      d.Rated,
+	}
+	if t, err := time.Parse("02 Jan 2006", d.Released); err == nil {
+		md.Released = t
+	}
+	for _, r := range d.Ratings {
+		if md.CriticRatings == nil {
+			md.CriticRatings = make(map[string]string)
+		}
+		md.CriticRatings[r.Source] = r.Value
+	}
+	return md
+}
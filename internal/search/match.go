@@ -0,0 +1,56 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Fold lowercases s and reduces it to letters and digits separated by single
+// spaces, so titles compare equal regardless of punctuation and case.
+func Fold(s string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteRune(r)
+			space = false
+		} else {
+			space = true
+		}
+	}
+	return sb.String()
+}
+
+// Similarity scores how alike two titles are, from 0 (nothing in common) to
+// 1 (equal after folding), based on edit distance.
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(Fold(a)), []rune(Fold(b))
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	videoExt     = regexp.MustCompile(`(?i)\.(mkv|mp4|avi|m4v|mov|wmv|webm|ts)$`)
+	bracketed    = regexp.MustCompile(`[\[(]\s*((?:19|20)\d{2})?[^\])]*[\])]`)
+	bareYear     = regexp.MustCompile(`\b((?:19|20)\d{2})\b`)
+	trailingThe  = regexp.MustCompile(`(?i)^(.+),\s*(the|a|an)$`)
+	releaseJunk  = regexp.MustCompile(`(?i)\b(480p|576p|720p|1080p|2160p|4k|uhd|hdr10?|blu-?ray|brrip|bdrip|web-?dl|webrip|hdtv|dvdrip|dvdscr|hdrip|x26[45]|h\.?26[45]|hevc|aac|ac3|dts|ddp?5\.1|remux|repack|extended|unrated)\b`)
+	spaceRun     = regexp.MustCompile(`\s+`)
+	trailingJunk = " .,!?;:-_~*"
+)
+
+// NormalizeQuery cleans a copy-pasted title before it is searched: file
+// names ("The.Matrix.1999.1080p.BluRay.x264.mkv"), release tags, emoji, a
+// year in brackets and trailing punctuation go, "Matrix, The" becomes
+// "The Matrix". A year found on the way is returned separately, 0 if none.
+func NormalizeQuery(q string) (string, int) {
+	q = strings.TrimSpace(q)
+	q = videoExt.ReplaceAllString(q, "")
+
+	// Release file names use dots or underscores for spaces.
+	fileName := !strings.Contains(q, " ") && strings.ContainsAny(q, "._")
+	q = strings.ReplaceAll(q, "_", " ")
+	if fileName {
+		q = strings.ReplaceAll(q, ".", " ")
+	}
+
+	q = stripSymbols(q)
+
+	year := 0
+	for _, m := range bracketed.FindAllStringSubmatch(q, -1) {
+		if y := plausibleYear(m[1]); y > 0 {
+			year = y
+			break
+		}
+	}
+	q = bracketed.ReplaceAllString(q, " ")
+
+	// Everything from the first release tag on is noise; so is a bare year
+	// right before it ("Alien 1979 1080p"), but not one that is part of
+	// the title ("Wonder Woman 1984", "1917").
+	if loc := releaseJunk.FindStringIndex(q); loc != nil && loc[0] > 0 {
+		q = cutTrailingYear(q[:loc[0]], &year)
+	} else if fileName {
+		q = cutTrailingYear(q, &year)
+	}
+
+	q = spaceRun.ReplaceAllString(q, " ")
+	q = strings.Trim(q, trailingJunk)
+	if m := trailingThe.FindStringSubmatch(q); m != nil {
+		q = m[2] + " " + m[1]
+	}
+	return q, year
+}
+
+// cutTrailingYear removes a year at the end of q (keeping q if that is all
+// there is) and records it.
+func cutTrailingYear(q string, year *int) string {
+	q = strings.TrimRight(q, trailingJunk)
+	locs := bareYear.FindAllStringSubmatchIndex(q, -1)
+	if len(locs) == 0 {
+		return q
+	}
+	last := locs[len(locs)-1]
+	if last[1] != len(q) || last[0] == 0 {
+		return q
+	}
+	if y := plausibleYear(q[last[2]:last[3]]); y > 0 {
+		*year = y
+		return q[:last[0]]
+	}
+	return q
+}
+
+func plausibleYear(s string) int {
+	y, err := strconv.Atoi(s)
+	if err != nil || y < 1888 || y > time.Now().Year()+5 {
+		return 0
+	}
+	return y
+}
+
+// stripSymbols drops emoji and other pictographs, keeping letters, digits,
+// spaces and the punctuation titles use.
+func stripSymbols(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), unicode.IsSpace(r):
+			return r
+		case unicode.IsPunct(r):
+			return r
+		case r == '&' || r == '+' || r == '$':
+			return r
+		}
+		return -1
+	}, s)
+}
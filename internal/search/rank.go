@@ -0,0 +1,92 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"moviebot/internal/omdb"
+	"moviebot/internal/tmdb"
+	"moviebot/internal/trace"
+)
+
+// popularityTimeout bounds the TMDB lookup so it never holds up a search.
+const popularityTimeout = 2 * time.Second
+
+// Rank orders OMDb results by how likely each is the movie meant by title:
+// title similarity first, then the year asked for, movies over series and
+// the rest, results with a poster, and TMDB popularity when known. Ties keep
+// OMDb's order.
+func Rank(results []omdb.SearchResult, title string, year int, popularity map[string]float64) []omdb.SearchResult {
+	score := make(map[string]float64, len(results))
+	for _, r := range results {
+		score[r.ImdbID] = relevance(r, title, year, popularity)
+	}
+	out := append([]omdb.SearchResult(nil), results...)
+	sort.SliceStable(out, func(i, j int) bool { return score[out[i].ImdbID] > score[out[j].ImdbID] })
+	return out
+}
+
+func relevance(r omdb.SearchResult, title string, year int, popularity map[string]float64) float64 {
+	s := 3 * Similarity(title, r.Title)
+	if folded := Fold(title); Fold(r.Title) == folded {
+		s += 1
+	} else if strings.HasPrefix(Fold(r.Title), folded) {
+		s += 0.5
+	}
+	if year > 0 && strings.HasPrefix(r.Year, strconv.Itoa(year)) {
+		s += 2
+	}
+	switch r.Type {
+	case "movie":
+		s += 1
+	case "series":
+		s += 0.5
+	}
+	if r.Poster != "" && r.Poster != "N/A" {
+		s += 0.5
+	}
+	// TMDB popularity runs from ~0 for obscure titles into the thousands
+	// for the week's blockbuster; its log keeps it from outweighing a match.
+	if p, ok := popularity[popularityKey(r.Title, leadingYear(r.Year))]; ok {
+		s += min(math.Log10(1+p)/2, 1.5)
+	}
+	return s
+}
+
+// Popularity looks query up on TMDB and returns the popularity of what it
+// finds, keyed by popularityKey. It returns nil without a client or when
+// TMDB is slow or failing; ranking then does without.
+func Popularity(ctx context.Context, client *tmdb.Client, query string) map[string]float64 {
+	if client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, popularityTimeout)
+	defer cancel()
+
+	movies, err := client.Search(ctx, query)
+	if err != nil {
+		trace.Logf(ctx, "[SEARCH] TMDB popularity for '%s' unavailable: %v", query, err)
+		return nil
+	}
+	out := make(map[string]float64, len(movies))
+	for _, m := range movies {
+		key := popularityKey(m.Title, leadingYear(m.ReleaseDate))
+		out[key] = max(out[key], m.Popularity)
+	}
+	return out
+}
+
+// popularityKey matches TMDB and OMDb records of the same movie.
+func popularityKey(title string, year int) string {
+	return Fold(title) + "|" + strconv.Itoa(year)
+}
+
+// leadingYear reads the year off "1999", "2010–2014" or "1999-03-31".
+func leadingYear(s string) int {
+	y, _ := strconv.Atoi(s[:min(4, len(s))])
+	return y
+}
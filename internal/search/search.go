@@ -0,0 +1,36 @@
+package search
+
+import (
+	"context"
+
+	"moviebot/internal/omdb"
+	"moviebot/internal/tmdb"
+	"moviebot/internal/trace"
+)
+
+// Query searches client for a user-typed title: the query is normalized
+// first, falling back to it verbatim when the cleaned-up form finds nothing,
+// and the results are ranked by relevance (see Rank). tm adds TMDB
+// popularity to the ranking and may be nil.
+func Query(ctx context.Context, client omdb.API, tm *tmdb.Client, q string) ([]omdb.SearchResult, error) {
+	title, year := NormalizeQuery(q)
+	if title == "" || title == q {
+		results, err := client.Search(ctx, q)
+		return rank(ctx, tm, results, q, year), err
+	}
+
+	trace.Logf(ctx, "[SEARCH] Normalized '%s' to '%s' (year %d)", q, title, year)
+	results, err := client.Search(ctx, title)
+	if err != nil || len(results) == 0 {
+		results, err := client.Search(ctx, q)
+		return rank(ctx, tm, results, q, year), err
+	}
+	return rank(ctx, tm, results, title, year), nil
+}
+
+func rank(ctx context.Context, tm *tmdb.Client, results []omdb.SearchResult, title string, year int) []omdb.SearchResult {
+	if len(results) < 2 {
+		return results
+	}
+	return Rank(results, title, year, Popularity(ctx, tm, title))
+}
@@ -0,0 +1,130 @@
+package sheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+)
+
+// syncDelay batches bursts of votes into a single sheet rewrite.
+const syncDelay = 5 * time.Second
+
+var header = []any{"Title", "Year", "IMDb ID", "Votes", "Watched", "Status", "Added", "Poster"}
+
+// Syncer mirrors the movie list into a Google Sheet. The sync is one-way:
+// the bot owns the sheet tab and rewrites it whenever the list changes, so
+// manual edits there are overwritten.
+type Syncer struct {
+	cfg    config.SheetsConfig
+	store  *storage.Store
+	tokens *tokenSource
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+func NewSyncer(cfg config.SheetsConfig, store *storage.Store) (*Syncer, error) {
+	tokens, err := newTokenSource(cfg.CredentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SheetName == "" {
+		cfg.SheetName = "Movies"
+	}
+	return &Syncer{cfg: cfg, store: store, tokens: tokens}, nil
+}
+
+// Handle is an events.Bus subscriber scheduling a debounced sync when a
+// movie of the mirrored library changes.
+func (s *Syncer) Handle(e events.Event) {
+	if e.Movie != nil && e.Movie.ChatID != s.library() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = time.AfterFunc(syncDelay, func() {
+		if err := s.Sync(context.Background()); err != nil {
+			log.Printf("[SHEETS] Sync failed: %v", err)
+		}
+	})
+}
+
+// library is the library the sheet mirrors: the configured chat's.
+func (s *Syncer) library() int64 {
+	return s.store.LibraryOf(s.cfg.ChatID)
+}
+
+// Sync rewrites the sheet with the current list.
+func (s *Syncer) Sync(ctx context.Context) error {
+	movies := s.store.GetLibrary(s.library())
+	sort.SliceStable(movies, func(i, j int) bool { return len(movies[i].Votes) > len(movies[j].Votes) })
+
+	rows := [][]any{header}
+	for _, m := range movies {
+		status := "unwatched"
+		if len(m.Watched) > 0 {
+			status = "watched"
+		}
+		rows = append(rows, []any{
+			m.Title, m.Year, m.ImdbID, len(m.Votes), len(m.Watched), status,
+			m.AddedAt.Format("2006-01-02"), m.Poster,
+		})
+	}
+
+	rng := url.PathEscape(s.cfg.SheetName)
+	base := fmt.Sprintf("https://sheets.googleapis.com/v4/spreadsheets/%s/values/", url.PathEscape(s.cfg.SpreadsheetID))
+
+	// Clear first so rows of removed movies do not linger below the data.
+	if err := s.call(ctx, http.MethodPost, base+rng+":clear", struct{}{}); err != nil {
+		return err
+	}
+	body := map[string]any{"range": s.cfg.SheetName, "majorDimension": "ROWS", "values": rows}
+	if err := s.call(ctx, http.MethodPut, base+rng+"?valueInputOption=RAW", body); err != nil {
+		return err
+	}
+
+	log.Printf("[SHEETS] Synced %d movies to sheet %s", len(movies), s.cfg.SheetName)
+	return nil
+}
+
+func (s *Syncer) call(ctx context.Context, method, u string, body any) error {
+	token, err := s.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: HTTP %d", method, u, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/frontend"
+	"moviebot/internal/omdb"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// App serves a Slack app's slash commands (/movie, /movielist) and its
+// interactive buttons. Point the app's "Slash Commands" request URL at
+// /slack/commands and "Interactivity" at /slack/interactive.
+type App struct {
+	cfg    config.SlackConfig
+	core   *frontend.Core
+	client *http.Client
+}
+
+func New(cfg config.SlackConfig, core *frontend.Core) *App {
+	return &App{cfg: cfg, core: core, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (a *App) Name() string { return "slack" }
+
+func (a *App) Run(ctx context.Context) error {
+	// Without a secret anyone can compute a valid signature.
+	if a.cfg.SigningSecret == "" {
+		return errors.New("slack.signing_secret is not set, refusing to listen")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slack/commands", a.handleCommand)
+	mux.HandleFunc("/slack/interactive", a.handleInteractive)
+
+	srv := &http.Server{Addr: a.cfg.Listen, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	log.Printf("[SLACK] Listening on %s", a.cfg.Listen)
+	return srv.ListenAndServe()
+}
+
+// verify checks Slack's v0 request signature and returns the raw body.
+func (a *App) verify(r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, false
+	}
+
+	ts := r.Header.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || time.Since(time.Unix(sec, 0)).Abs() > 5*time.Minute {
+		return nil, false
+	}
+
+	mac := hmac.New(sha256.New, []byte(a.cfg.SigningSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", ts, body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return body, hmac.Equal([]byte(want), []byte(r.Header.Get("X-Slack-Signature")))
+}
+
+func user(id, name string) frontend.User {
+	return frontend.User{ID: "slack:" + id, Name: name}
+}
+
+// =====================================================
+// SLASH COMMANDS
+// =====================================================
+
+func (a *App) handleCommand(w http.ResponseWriter, r *http.Request) {
+	body, ok := a.verify(r)
+	if !ok {
+		http.Error(w, "bad signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	ctx := trace.NewContext(r.Context())
+	command, text := form.Get("command"), strings.TrimSpace(form.Get("text"))
+	trace.Logf(ctx, "[SLACK] %s '%s' from %s", command, text, form.Get("user_name"))
+
+	switch command {
+	case "/movie":
+		if text == "" {
+			writeJSON(w, ephemeral("Usage: /movie <title>"))
+			return
+		}
+		results, err := a.core.Search(ctx, text)
+		if err != nil || len(results) == 0 {
+			writeJSON(w, ephemeral("No results found"))
+			return
+		}
+		writeJSON(w, message{ResponseType: "ephemeral", Text: "Pick a movie", Blocks: searchBlocks(results)})
+
+	case "/movielist":
+		writeJSON(w, message{ResponseType: "in_channel", Text: "```\n" + a.core.List() + "\n```"})
+
+	default:
+		writeJSON(w, ephemeral("Unknown command"))
+	}
+}
+
+// =====================================================
+// BUTTONS
+// =====================================================
+
+type interaction struct {
+	Type        string `json:"type"`
+	ResponseURL string `json:"response_url"`
+	User        struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+func (a *App) handleInteractive(w http.ResponseWriter, r *http.Request) {
+	body, ok := a.verify(r)
+	if !ok {
+		http.Error(w, "bad signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	var in interaction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &in); err != nil || len(in.Actions) == 0 {
+		http.Error(w, "bad payload", http.StatusBadRequest)
+		return
+	}
+	// Slack wants a 200 within 3 seconds; the visible answer goes to response_url.
+	w.WriteHeader(http.StatusOK)
+
+	ctx := trace.NewContext(context.Background())
+	u := user(in.User.ID, in.User.Username)
+	action := in.Actions[0]
+	trace.Logf(ctx, "[SLACK] Action %s from %s", action.ActionID, in.User.Username)
+
+	switch action.ActionID {
+	case "add":
+		var res omdb.SearchResult
+		if err := json.Unmarshal([]byte(action.Value), &res); err != nil {
+			return
+		}
+		movie, _ := a.core.Add(ctx, res, u)
+		a.respond(ctx, in.ResponseURL, message{DeleteOriginal: true})
+		a.respond(ctx, in.ResponseURL, message{ResponseType: "in_channel", Text: cardText(movie), Blocks: cardBlocks(movie)})
+
+	case "vote", "seen":
+		var (
+			movie storage.Movie
+			err   error
+		)
+		if action.ActionID == "vote" {
+			movie, _, err = a.core.ToggleVote(ctx, action.Value, u)
+		} else {
+			movie, _, err = a.core.ToggleWatched(ctx, action.Value, u)
+		}
+		if err != nil {
+			return
+		}
+		a.respond(ctx, in.ResponseURL, message{ReplaceOriginal: true, Text: cardText(movie), Blocks: cardBlocks(movie)})
+	}
+}
+
+func (a *App) respond(ctx context.Context, responseURL string, m message) {
+	data, _ := json.Marshal(m)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		trace.Logf(ctx, "[SLACK] response_url post failed: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// =====================================================
+// MESSAGES
+// =====================================================
+
+type message struct {
+	ResponseType    string  `json:"response_type,omitempty"`
+	ReplaceOriginal bool    `json:"replace_original,omitempty"`
+	DeleteOriginal  bool    `json:"delete_original,omitempty"`
+	Text            string  `json:"text,omitempty"`
+	Blocks          []block `json:"blocks,omitempty"`
+}
+
+type block map[string]any
+
+func ephemeral(text string) message {
+	return message{ResponseType: "ephemeral", Text: text}
+}
+
+func mrkdwn(text string) map[string]string {
+	return map[string]string{"type": "mrkdwn", "text": text}
+}
+
+func button(text, actionID, value string) map[string]any {
+	return map[string]any{
+		"type":      "button",
+		"text":      map[string]any{"type": "plain_text", "text": text, "emoji": true},
+		"action_id": actionID,
+		"value":     value,
+	}
+}
+
+func searchBlocks(results []omdb.SearchResult) []block {
+	var blocks []block
+	for _, r := range results {
+		value, _ := json.Marshal(r)
+		blocks = append(blocks, block{
+			"type":      "section",
+			"text":      mrkdwn(fmt.Sprintf("*%s* (%s)", r.Title, r.Year)),
+			"accessory": button("✅ Add", "add", string(value)),
+		})
+	}
+	return blocks
+}
+
+func cardText(m storage.Movie) string {
+	return fmt.Sprintf("*%s* (%d)\n👍 Votes: *%d*   👁 Watched: %d", m.Title, m.Year, len(m.Votes), len(m.Watched))
+}
+
+func cardBlocks(m storage.Movie) []block {
+	section := block{"type": "section", "text": mrkdwn(cardText(m))}
+	if m.Poster != "" && m.Poster != "N/A" {
+		section["accessory"] = map[string]string{"type": "image", "image_url": m.Poster, "alt_text": m.Title}
+	}
+	return []block{
+		section,
+		{"type": "actions", "elements": []any{
+			button(fmt.Sprintf("👍 Vote (%d)", len(m.Votes)), "vote", m.ID),
+			button(fmt.Sprintf("👁️ Watched (%d)", len(m.Watched)), "seen", m.ID),
+		}},
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- ACHIEVEMENTS --------------------
+//
+
+// Achievement is a badge users unlock in a library by reaching a number
+// of nominations, votes or watched movies there.
+type Achievement struct {
+	ID    string // stored in User.Badges, never change it
+	Badge string
+	Name  string
+	Desc  string
+
+	reached func(UserStats) bool
+}
+
+// Achievements lists every badge in the order they are shown.
+var Achievements = []Achievement{
+	{"first_nomination", "🌱", "First nomination", "Nominated a movie",
+		func(st UserStats) bool { return st.Nominated >= 1 }},
+	{"ten_nominations", "🎬", "Talent scout", "Nominated 10 movies",
+		func(st UserStats) bool { return st.Nominated >= 10 }},
+	{"first_win", "🥇", "Crowd pleaser", "The group watched one of your picks",
+		func(st UserStats) bool { return st.Won >= 1 }},
+	{"five_wins", "🏆", "Tastemaker", "The group watched 5 of your picks",
+		func(st UserStats) bool { return st.Won >= 5 }},
+	{"ten_votes", "🗳", "Voice of the people", "Voted for 10 movies",
+		func(st UserStats) bool { return st.Voted >= 10 }},
+	{"fifty_votes", "📣", "Ballot stuffer", "Voted for 50 movies",
+		func(st UserStats) bool { return st.Voted >= 50 }},
+	{"ten_watched", "🍿", "10 movies watched", "Watched 10 movies",
+		func(st UserStats) bool { return st.Watched >= 10 }},
+	{"fifty_watched", "🎞", "50 movies watched", "Watched 50 movies",
+		func(st UserStats) bool { return st.Watched >= 50 }},
+}
+
+// UnlockAchievements records the achievements a user has reached in a
+// library and not unlocked before, and returns them. Badges stay unlocked
+// when the votes or marks that earned them are taken back.
+func (s *Store) UnlockAchievements(ctx context.Context, library int64, userID string) []Achievement {
+	st := s.UserStats(library, userID)
+
+	s.userMu.Lock()
+	defer s.userMu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		u = User{ID: userID, LastSeen: time.Now()}
+	}
+	var unlocked []Achievement
+	for _, a := range Achievements {
+		if a.reached(st) && !slices.Contains(u.Badges[library], a.ID) {
+			unlocked = append(unlocked, a)
+		}
+	}
+	if len(unlocked) == 0 {
+		return nil
+	}
+
+	badges := make(map[int64][]string, len(u.Badges)+1) // copies handed out by GetUser stay as they were
+	for lib, ids := range u.Badges {
+		badges[lib] = slices.Clone(ids)
+	}
+	for _, a := range unlocked {
+		badges[library] = append(badges[library], a.ID)
+		trace.Logf(ctx, "[STORE] User %s unlocked %s in library %d", userID, a.ID, library)
+	}
+	u.Badges = badges
+	s.users[userID] = u
+	s.usersFile.markDirty()
+	return unlocked
+}
+
+// UserAchievements returns the achievements a user has unlocked in a
+// library, in the order of Achievements.
+func (s *Store) UserAchievements(library int64, userID string) []Achievement {
+	u, _ := s.GetUser(userID)
+	var out []Achievement
+	for _, a := range Achievements {
+		if slices.Contains(u.Badges[library], a.ID) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
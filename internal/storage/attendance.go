@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- ATTENDANCE --------------------
+//
+
+// DefaultAttendanceWindow is how long before or after a night's start a
+// watched mark still counts as attending it.
+const DefaultAttendanceWindow = 12 * time.Hour
+
+// AttendanceStats is how regularly one user shows up to a chat's nights.
+// Streak counts the latest nights attended in a row; a night still within
+// its window doesn't break it yet.
+type AttendanceStats struct {
+	UserID   string
+	Attended int
+	Nights   int // nights held so far in the chat
+	Streak   int
+	Best     int
+}
+
+// SetAttendanceWindow changes DefaultAttendanceWindow; zero or less keeps
+// the default.
+func (s *Store) SetAttendanceWindow(d time.Duration) {
+	if d <= 0 {
+		d = DefaultAttendanceWindow
+	}
+	s.nightMu.Lock()
+	defer s.nightMu.Unlock()
+	s.attendanceWindow = d
+}
+
+// recordAttendance adds (or, when the mark was taken back, removes) userID
+// on every night of the movie whose start is within the window of at.
+func (s *Store) recordAttendance(ctx context.Context, movieID, userID string, watched bool, at time.Time) {
+	s.nightMu.Lock()
+	defer s.nightMu.Unlock()
+
+	for i := range s.nights {
+		n := &s.nights[i]
+		if n.MovieID != movieID || at.Sub(n.At).Abs() > s.attendanceWindow {
+			continue
+		}
+		if watched {
+			if n.Attended == nil {
+				n.Attended = make(map[string]time.Time)
+			}
+			n.Attended[userID] = at
+			trace.Logf(ctx, "[STORE] User %s attended %s [%s]", userID, n.Title, n.ID)
+		} else if _, ok := n.Attended[userID]; ok {
+			delete(n.Attended, userID)
+			trace.Logf(ctx, "[STORE] User %s no longer attended %s [%s]", userID, n.Title, n.ID)
+		} else {
+			continue
+		}
+		s.nightsFile.markDirty()
+	}
+}
+
+// Attendance returns the stats of everyone who attended at least one of the
+// chat's nights that started before now, best streak first.
+func (s *Store) Attendance(chatID int64, now time.Time) []AttendanceStats {
+	nights := s.heldNights(chatID, now)
+
+	users := make(map[string]bool)
+	for _, n := range nights {
+		for id := range n.Attended {
+			users[id] = true
+		}
+	}
+
+	out := make([]AttendanceStats, 0, len(users))
+	for id := range users {
+		out = append(out, s.attendanceOf(nights, id, now))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Streak != b.Streak {
+			return a.Streak > b.Streak
+		}
+		if a.Attended != b.Attended {
+			return a.Attended > b.Attended
+		}
+		return a.UserID < b.UserID
+	})
+	return out
+}
+
+// UserAttendance returns one user's stats in a chat.
+func (s *Store) UserAttendance(chatID int64, userID string, now time.Time) AttendanceStats {
+	return s.attendanceOf(s.heldNights(chatID, now), userID, now)
+}
+
+// heldNights returns the chat's nights that started before now, oldest
+// first, with their own copy of Attended.
+func (s *Store) heldNights(chatID int64, now time.Time) []Night {
+	s.nightMu.RLock()
+	defer s.nightMu.RUnlock()
+
+	var held []Night
+	for _, n := range s.nights {
+		if n.ChatID == chatID && n.At.Before(now) {
+			n.Attended = maps.Clone(n.Attended)
+			held = append(held, n)
+		}
+	}
+	sort.Slice(held, func(i, j int) bool { return held[i].At.Before(held[j].At) })
+	return held
+}
+
+func (s *Store) attendanceOf(nights []Night, userID string, now time.Time) AttendanceStats {
+	s.nightMu.RLock()
+	window := s.attendanceWindow
+	s.nightMu.RUnlock()
+
+	st := AttendanceStats{UserID: userID, Nights: len(nights)}
+	for _, n := range nights {
+		if _, ok := n.Attended[userID]; ok {
+			st.Attended++
+			st.Streak++
+			st.Best = max(st.Best, st.Streak)
+		} else if now.Sub(n.At) > window {
+			st.Streak = 0
+		}
+	}
+	return st
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- STATS & BACKUPS --------------------
+//
+
+// Stats is a snapshot of how much the store holds.
+type Stats struct {
+	Movies   int
+	Watched  int
+	Lists    int
+	Messages int
+	Evicted  int // message refs dropped by the index limits since start
+	Users    int
+	Chats    int
+	Nights   int
+}
+
+func (s *Store) Stats() Stats {
+	var st Stats
+
+	s.mu.RLock()
+	st.Movies = len(s.movies)
+	lists := make(map[string]bool)
+	for _, m := range s.movies {
+		lists[m.List] = true
+		if IsWatched(m) {
+			st.Watched++
+		}
+	}
+	st.Lists = len(lists)
+	s.mu.RUnlock()
+
+	s.msgMu.RLock()
+	for _, refs := range s.index {
+		st.Messages += len(refs)
+	}
+	st.Evicted = s.evictedRefs
+	s.msgMu.RUnlock()
+
+	s.userMu.RLock()
+	st.Users = len(s.users)
+	s.userMu.RUnlock()
+
+	s.chatMu.RLock()
+	st.Chats = len(s.chats)
+	s.chatMu.RUnlock()
+
+	s.nightMu.RLock()
+	st.Nights = len(s.nights)
+	s.nightMu.RUnlock()
+
+	return st
+}
+
+// Backup flushes pending changes and copies every data file into a new
+// timestamped directory under backups/ next to movies.json. It returns that
+// directory.
+func (s *Store) Backup(ctx context.Context) (string, error) {
+	s.Flush()
+
+	dir := filepath.Join(filepath.Dir(s.moviesPath), "backups", time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	files := []string{s.moviesPath, s.indexPath, s.nightsFile.path, s.usersFile.path, s.chatsFile.path, s.pollsFile.path, s.electionsFile.path, s.roundsFile.path, s.tonightFile.path}
+	copied := 0
+	for _, src := range files {
+		ok, err := copyFile(src, filepath.Join(dir, filepath.Base(src)))
+		if err != nil {
+			return "", fmt.Errorf("backup %s: %w", filepath.Base(src), err)
+		}
+		if ok {
+			copied++
+		}
+	}
+
+	trace.Logf(ctx, "[STORE] Backed up %d files to %s", copied, dir)
+	return dir, nil
+}
+
+// copyFile copies src to dst; a missing src is skipped and reported as false.
+func copyFile(src, dst string) (bool, error) {
+	in, err := os.Open(src)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return false, err
+	}
+	return true, out.Close()
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- BLOCKLIST --------------------
+//
+
+// normalizeTerm lowercases s and turns everything but letters and digits
+// into single spaces, so "Cats!" and "cats" are the same term.
+func normalizeTerm(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// BlockTerm adds a title or keyword to a chat's blocklist. It reports false
+// when the term is empty or already blocked.
+func (s *Store) BlockTerm(ctx context.Context, id int64, term string) bool {
+	term = normalizeTerm(term)
+	if term == "" {
+		return false
+	}
+
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	c, ok := s.chats[id]
+	if !ok {
+		c = Chat{ID: id, FirstSeen: time.Now()}
+	}
+	if slices.Contains(c.Blocklist, term) {
+		return false
+	}
+	// Copy on write: GetChat hands out the slice.
+	c.Blocklist = append(slices.Clone(c.Blocklist), term)
+	s.chats[id] = c
+	s.chatsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Chat %d blocked %q", id, term)
+	return true
+}
+
+// UnblockTerm removes a term from a chat's blocklist and reports whether it
+// was there.
+func (s *Store) UnblockTerm(ctx context.Context, id int64, term string) bool {
+	term = normalizeTerm(term)
+
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	c, ok := s.chats[id]
+	if !ok || !slices.Contains(c.Blocklist, term) {
+		return false
+	}
+	c.Blocklist = slices.DeleteFunc(slices.Clone(c.Blocklist), func(t string) bool { return t == term })
+	s.chats[id] = c
+	s.chatsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Chat %d unblocked %q", id, term)
+	return true
+}
+
+// Blocklist returns a chat's blocked terms in the order they were added.
+func (s *Store) Blocklist(id int64) []string {
+	s.chatMu.RLock()
+	defer s.chatMu.RUnlock()
+	return slices.Clone(s.chats[id].Blocklist)
+}
+
+// BlockedTerm returns the term that keeps title off a chat's lists, if any.
+// Terms match whole words, so blocking "cats" leaves "Concats" alone.
+func (s *Store) BlockedTerm(id int64, title string) (string, bool) {
+	padded := " " + normalizeTerm(title) + " "
+	for _, term := range s.Blocklist(id) {
+		if strings.Contains(padded, " "+term+" ") {
+			return term, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"maps"
+	"sort"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- CHAT REGISTRY --------------------
+//
+
+// Chat is a chat the bot is (or was) active in.
+type Chat struct {
+	ID           int64             `json:"id"`
+	Title        string            `json:"title"`
+	Type         string            `json:"type"`              // private, group, supergroup, channel
+	Members      int               `json:"members,omitempty"` // estimate, refreshed daily
+	MembersAt    time.Time         `json:"members_at,omitzero"`
+	Settings     map[string]string `json:"settings,omitempty"`
+	FirstSeen    time.Time         `json:"first_seen"`
+	LastActivity time.Time         `json:"last_activity"`
+	Left         bool              `json:"left,omitempty"`          // bot was removed or blocked
+	PublicToken  string            `json:"public_token,omitempty"`  // read-only web list link
+	Blocklist    []string          `json:"blocklist,omitempty"`     // titles and keywords that can't be added
+	VoteDeadline time.Time         `json:"vote_deadline,omitzero"`  // when voting closes, zero for no deadline
+	VotingClosed bool              `json:"voting_closed,omitempty"` // the deadline passed; votes are frozen
+}
+
+// chatActivityResolution is how stale LastActivity may get before an update
+// is worth a save.
+const chatActivityResolution = time.Hour
+
+// TouchChat records activity in a chat, creating its registry entry on first
+// sight. It reports whether the chat is new.
+func (s *Store) TouchChat(ctx context.Context, id int64, title, typ string) bool {
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	now := time.Now()
+	c, known := s.chats[id]
+	if known && !c.Left && c.Title == title && c.Type == typ &&
+		now.Sub(c.LastActivity) < chatActivityResolution {
+		return false
+	}
+
+	if !known {
+		c = Chat{ID: id, FirstSeen: now}
+		trace.Logf(ctx, "[STORE] New chat %d (%s %q)", id, typ, title)
+	}
+	c.Title = title
+	c.Type = typ
+	c.LastActivity = now
+	c.Left = false
+	s.chats[id] = c
+	s.chatsFile.markDirty()
+	return !known
+}
+
+// SetChatMembers stores a fresh member count estimate.
+func (s *Store) SetChatMembers(ctx context.Context, id int64, n int) {
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	c, ok := s.chats[id]
+	if !ok {
+		return
+	}
+	c.Members = n
+	c.MembersAt = time.Now()
+	s.chats[id] = c
+	s.chatsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Chat %d has ~%d members", id, n)
+}
+
+// MarkChatLeft flags a chat the bot was removed from. The entry is kept so
+// its settings survive a re-add.
+func (s *Store) MarkChatLeft(ctx context.Context, id int64) {
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	c, ok := s.chats[id]
+	if !ok || c.Left {
+		return
+	}
+	c.Left = true
+	s.chats[id] = c
+	s.chatsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Left chat %d (%q)", id, c.Title)
+}
+
+// SetChatSetting stores a per-chat setting; an empty value removes it.
+func (s *Store) SetChatSetting(ctx context.Context, id int64, key, value string) {
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	c, ok := s.chats[id]
+	if !ok {
+		c = Chat{ID: id, FirstSeen: time.Now()}
+	}
+	if c.Settings == nil {
+		c.Settings = make(map[string]string)
+	}
+	if value == "" {
+		delete(c.Settings, key)
+	} else {
+		c.Settings[key] = value
+	}
+	s.chats[id] = c
+	s.chatsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Chat %d setting %s=%q", id, key, value)
+}
+
+// ReplaceChatSettings swaps all settings of a chat at once, e.g. when a
+// setup is imported from another chat.
+func (s *Store) ReplaceChatSettings(ctx context.Context, id int64, settings map[string]string) {
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	c, ok := s.chats[id]
+	if !ok {
+		c = Chat{ID: id, FirstSeen: time.Now()}
+	}
+	c.Settings = maps.Clone(settings)
+	s.chats[id] = c
+	s.chatsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Chat %d settings replaced (%d keys)", id, len(settings))
+}
+
+// SetVoteDeadline makes voting in a chat close at the given time, reopening
+// it if it was closed. A zero time drops the deadline.
+func (s *Store) SetVoteDeadline(ctx context.Context, id int64, at time.Time) {
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	c, ok := s.chats[id]
+	if !ok {
+		c = Chat{ID: id, FirstSeen: time.Now()}
+	}
+	c.VoteDeadline = at
+	c.VotingClosed = false
+	s.chats[id] = c
+	s.chatsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Chat %d vote deadline %v", id, at)
+}
+
+// CloseVoting freezes the votes of a chat whose deadline is up. It reports
+// false when there is no deadline or voting was closed already, so only one
+// caller announces the result.
+func (s *Store) CloseVoting(ctx context.Context, id int64) bool {
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	c, ok := s.chats[id]
+	if !ok || c.VoteDeadline.IsZero() || c.VotingClosed {
+		return false
+	}
+	c.VotingClosed = true
+	s.chats[id] = c
+	s.chatsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Chat %d voting closed", id)
+	return true
+}
+
+// ChatSetting returns a per-chat setting, "" when unset.
+func (s *Store) ChatSetting(id int64, key string) string {
+	s.chatMu.RLock()
+	defer s.chatMu.RUnlock()
+	return s.chats[id].Settings[key]
+}
+
+// PublicToken returns the token of the chat's read-only web list, creating
+// one on first use. rotate replaces it, so links shared earlier stop working.
+func (s *Store) PublicToken(ctx context.Context, id int64, rotate bool) string {
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	c, ok := s.chats[id]
+	if !ok {
+		c = Chat{ID: id, FirstSeen: time.Now()}
+	}
+	if c.PublicToken != "" && !rotate {
+		return c.PublicToken
+	}
+
+	var b [16]byte
+	rand.Read(b[:])
+	c.PublicToken = hex.EncodeToString(b[:])
+	s.chats[id] = c
+	s.chatsFile.markDirty()
+	trace.Logf(ctx, "[STORE] New public list token for chat %d", id)
+	return c.PublicToken
+}
+
+// ChatByPublicToken finds the chat a public list token belongs to.
+func (s *Store) ChatByPublicToken(token string) (Chat, bool) {
+	if token == "" {
+		return Chat{}, false
+	}
+
+	s.chatMu.RLock()
+	defer s.chatMu.RUnlock()
+	for _, c := range s.chats {
+		if subtle.ConstantTimeCompare([]byte(c.PublicToken), []byte(token)) == 1 {
+			return c, true
+		}
+	}
+	return Chat{}, false
+}
+
+// GetChat returns a registry entry.
+func (s *Store) GetChat(id int64) (Chat, bool) {
+	s.chatMu.RLock()
+	defer s.chatMu.RUnlock()
+	c, ok := s.chats[id]
+	c.Settings = maps.Clone(c.Settings)
+	return c, ok
+}
+
+// GetChats returns the registry, most recently active first.
+func (s *Store) GetChats() []Chat {
+	s.chatMu.RLock()
+	defer s.chatMu.RUnlock()
+
+	out := make([]Chat, 0, len(s.chats))
+	for _, c := range s.chats {
+		c.Settings = maps.Clone(c.Settings)
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].LastActivity.After(out[j].LastActivity) })
+	return out
+}
@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- COMPACTION --------------------
+//
+
+// CompactReport is what Compact changed, with entry counts and the size of
+// the data files before and after.
+type CompactReport struct {
+	MoviesBefore, MoviesAfter int
+	RefsBefore, RefsAfter     int
+	Merged                    int // movies folded into an earlier copy with the same IMDb ID
+	DroppedRefs               int // message refs of movies or lists that are gone, or invalid
+	NilMaps                   int // movies that had no vote or watched map
+	BytesBefore, BytesAfter   int64
+}
+
+func (r CompactReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Movies: %d → %d (duplicates merged: %d)\n", r.MoviesBefore, r.MoviesAfter, r.Merged)
+	fmt.Fprintf(&sb, "Message refs: %d → %d (dropped: %d)\n", r.RefsBefore, r.RefsAfter, r.DroppedRefs)
+	if r.NilMaps > 0 {
+		fmt.Fprintf(&sb, "Vote maps restored: %d\n", r.NilMaps)
+	}
+	fmt.Fprintf(&sb, "Data files: %s → %s", byteSize(r.BytesBefore), byteSize(r.BytesAfter))
+	return sb.String()
+}
+
+// byteSize formats n as "812 B", "14.2 KB" or "3.1 MB".
+func byteSize(n int64) string {
+	switch {
+	case n < 1<<10:
+		return fmt.Sprintf("%d B", n)
+	case n < 1<<20:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
+
+// Compact tidies the store up and rewrites every data file: movies on the
+// same list with the same IMDb ID are merged into the one added first,
+// nights, polls, elections, rounds and tonight polls are pointed at the
+// merged movie, message refs of movies and lists that are gone are dropped
+// and empty maps are cleared. Take a Backup first.
+func (s *Store) Compact(ctx context.Context) CompactReport {
+	s.Flush()
+	r := CompactReport{BytesBefore: s.dataSize()}
+
+	s.mu.Lock()
+	r.MoviesBefore = len(s.movies)
+	replaced := make(map[string]string) // merged movie ID -> the one kept
+	first := make(map[string]int)       // "<library>|<list>|<IMDb ID>" -> index in kept
+	kept := s.movies[:0]
+	for _, m := range s.movies {
+		if m.Votes == nil || m.Watched == nil {
+			r.NilMaps++
+		}
+		m.normalizeMaps()
+
+		if m.ImdbID == "" || m.ImdbID == "N/A" {
+			kept = append(kept, m)
+			continue
+		}
+		key := fmt.Sprintf("%d|%s|%s", m.ChatID, m.List, m.ImdbID)
+		i, dup := first[key]
+		if !dup {
+			first[key] = len(kept)
+			kept = append(kept, m)
+			continue
+		}
+		if m.AddedAt.Before(kept[i].AddedAt) {
+			m, kept[i] = kept[i], m
+		}
+		kept[i].fold(m)
+		replaced[m.ID] = kept[i].ID
+		for from, to := range replaced {
+			if to == m.ID {
+				replaced[from] = kept[i].ID
+			}
+		}
+		trace.Logf(ctx, "[STORE] Compact: merged %s (%d) [%s] into [%s]", m.Title, m.Year, m.ID, kept[i].ID)
+		r.Merged++
+	}
+	s.movies = kept
+	r.MoviesAfter = len(kept)
+
+	movieIDs := make(map[string]bool, len(kept))
+	listKeys := map[string]bool{ListKey(""): true}
+	for _, m := range kept {
+		movieIDs[m.ID] = true
+		listKeys[ListKey(m.List)] = true
+	}
+	s.mu.Unlock()
+
+	s.msgMu.Lock()
+	for key, refs := range s.index {
+		r.RefsBefore += len(refs)
+		if to, ok := replaced[key]; ok {
+			delete(s.index, key)
+			s.index[to] = append(s.index[to], refs...)
+		}
+	}
+	for key, refs := range s.index {
+		if !movieIDs[key] && !listKeys[key] {
+			r.DroppedRefs += len(refs)
+			delete(s.index, key)
+			continue
+		}
+		valid := refs[:0]
+		for _, ref := range refs {
+			if ref.InlineID == "" && (ref.ChatID == 0 || ref.MessageID <= 0) {
+				r.DroppedRefs++
+				continue
+			}
+			if slices.ContainsFunc(valid, func(v MessageRef) bool {
+				return v.ChatID == ref.ChatID && v.MessageID == ref.MessageID && v.InlineID == ref.InlineID
+			}) {
+				r.DroppedRefs++
+				continue
+			}
+			valid = append(valid, ref)
+		}
+		if len(valid) == 0 {
+			delete(s.index, key)
+			continue
+		}
+		s.index[key] = valid
+	}
+	for _, refs := range s.index {
+		r.RefsAfter += len(refs)
+	}
+	s.msgMu.Unlock()
+
+	if len(replaced) > 0 {
+		s.renameMovieIDs(replaced)
+	}
+
+	for _, f := range s.persist.files {
+		f.lock.Lock()
+		f.markDirty()
+		f.lock.Unlock()
+	}
+	s.Flush()
+	r.BytesAfter = s.dataSize()
+
+	trace.Logf(ctx, "[STORE] Compacted: %d → %d movies, %d → %d message refs, %d → %d bytes",
+		r.MoviesBefore, r.MoviesAfter, r.RefsBefore, r.RefsAfter, r.BytesBefore, r.BytesAfter)
+	return r
+}
+
+// normalizeMaps gives m the vote maps every movie has and drops the
+// optional ones that are empty.
+func (m *Movie) normalizeMaps() {
+	if m.Votes == nil {
+		m.Votes = make(map[string]bool)
+	}
+	if m.Watched == nil {
+		m.Watched = make(map[string]bool)
+	}
+	if len(m.WatchedAt) == 0 {
+		m.WatchedAt = nil
+	}
+	if len(m.Ratings) == 0 {
+		m.Ratings = nil
+	}
+	if len(m.Reactions) == 0 {
+		m.Reactions = nil
+	}
+	if len(m.CriticRatings) == 0 {
+		m.CriticRatings = nil
+	}
+	if len(m.Titles) == 0 {
+		m.Titles = nil
+	}
+}
+
+// fold adds what o has and m lacks to m: voters, watchers, ratings,
+// reactions, tags, discussions and earlier viewings.
+func (m *Movie) fold(o Movie) {
+	for u := range o.Votes {
+		m.Votes[u] = true
+	}
+	for u := range o.Watched {
+		m.Watched[u] = true
+		if at, ok := o.WatchedAt[u]; ok && m.WatchedAt[u].IsZero() {
+			m.setWatchedAt(u, at)
+		}
+	}
+	if m.WatchedOn.IsZero() || (!o.WatchedOn.IsZero() && o.WatchedOn.Before(m.WatchedOn)) {
+		m.WatchedOn = o.WatchedOn
+	}
+	for u, score := range o.Ratings {
+		if _, ok := m.Ratings[u]; !ok {
+			if m.Ratings == nil {
+				m.Ratings = make(map[string]int)
+			}
+			m.Ratings[u] = score
+		}
+	}
+	for u, emoji := range o.Reactions {
+		if _, ok := m.Reactions[u]; !ok {
+			if m.Reactions == nil {
+				m.Reactions = make(map[string]string)
+			}
+			m.Reactions[u] = emoji
+		}
+	}
+	for _, t := range o.Tags {
+		if !slices.Contains(m.Tags, t) {
+			m.Tags = append(m.Tags, t)
+		}
+	}
+	for _, t := range o.NotTags {
+		if !slices.Contains(m.NotTags, t) {
+			m.NotTags = append(m.NotTags, t)
+		}
+	}
+	m.Discussions = append(m.Discussions, o.Discussions...)
+	m.History = append(m.History, o.History...)
+	if m.Poster == "" || m.Poster == "N/A" {
+		m.Poster = o.Poster
+	}
+}
+
+// dataSize is the combined size of the data files on disk.
+func (s *Store) dataSize() int64 {
+	var n int64
+	for _, f := range s.persist.files {
+		if fi, err := os.Stat(f.path); err == nil {
+			n += fi.Size()
+		}
+	}
+	return n
+}
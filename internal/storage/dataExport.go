@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportJSON serializes the movie list in the movies.json format, so an
+// export can be merged back into any instance with /merge or -merge.
+func ExportJSON(movies []Movie) ([]byte, error) {
+	if movies == nil {
+		movies = []Movie{}
+	}
+	return json.MarshalIndent(movies, "", "  ")
+}
+
+// csvHeader is the first row of ExportCSV.
+var csvHeader = []string{
+	"title", "year", "list", "imdb_id", "added_at", "added_by",
+	"votes", "voters", "watched", "watched_by", "seen", "rewatch",
+	"runtime", "genre", "imdb_rating", "group_rating", "ratings",
+}
+
+// ExportCSV flattens the movie list into a spreadsheet, one movie per row,
+// most voted first. User IDs are ;-separated; times are RFC 3339 in UTC.
+func ExportCSV(movies []Movie) ([]byte, error) {
+	movies = append([]Movie(nil), movies...)
+	sortMoviesByVotes(movies)
+
+	ids := func(set map[string]bool) string {
+		return strings.Join(slices.Sorted(maps.Keys(set)), ";")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(csvHeader)
+	for _, m := range movies {
+		added := ""
+		if !m.AddedAt.IsZero() {
+			added = m.AddedAt.UTC().Format(time.RFC3339)
+		}
+		groupRating := ""
+		if avg, n := AverageRating(m); n > 0 {
+			groupRating = strconv.FormatFloat(avg, 'f', 1, 64)
+		}
+		w.Write([]string{
+			m.Title,
+			strconv.Itoa(m.Year),
+			m.List,
+			m.ImdbID,
+			added,
+			m.AddedBy,
+			strconv.Itoa(len(m.Votes)),
+			ids(m.Votes),
+			strconv.Itoa(len(m.Watched)),
+			ids(m.Watched),
+			strconv.FormatBool(IsWatched(m)),
+			strconv.FormatBool(m.Rewatch),
+			m.Runtime,
+			m.Genre,
+			m.ImdbRating,
+			groupRating,
+			strconv.Itoa(len(m.Ratings)),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("write csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// diaryHeader is the first row of ExportDiary, in the column names of
+// Letterboxd's importer.
+var diaryHeader = []string{"imdbID", "Title", "Year", "WatchedDate", "Rating", "Rating10", "Rewatch"}
+
+// diaryEntry is one viewing in ExportDiary.
+type diaryEntry struct {
+	movie   Movie
+	at      time.Time // zero when the date is unknown
+	rating  int       // 1..10, 0 for none
+	rewatch bool
+}
+
+// ExportDiary writes userID's viewings as a Letterboxd diary CSV, to import
+// at letterboxd.com/import: one row per round the user watched a movie in,
+// oldest first, dated in loc. Marks from before watched dates were kept are
+// dated by the night the user attended (from nights), or left undated,
+// which Letterboxd logs as watched without a diary entry. Only the current
+// round carries the user's rating.
+func ExportDiary(movies []Movie, nights []Night, userID string, loc *time.Location) ([]byte, error) {
+	// attended is when the user was at a night of a movie, latest last
+	attended := make(map[string][]time.Time)
+	for _, n := range nights {
+		if at, ok := n.Attended[userID]; ok {
+			attended[n.MovieID] = append(attended[n.MovieID], at)
+		}
+	}
+	for _, ats := range attended {
+		slices.SortFunc(ats, func(a, b time.Time) int { return a.Compare(b) })
+	}
+	// dated picks the night attended within a round, from after start up to
+	// end (zero for the current round)
+	dated := func(movieID string, start, end time.Time) time.Time {
+		var at time.Time
+		for _, t := range attended[movieID] {
+			if t.After(start) && (end.IsZero() || t.Before(end)) {
+				at = t
+			}
+		}
+		return at
+	}
+
+	var entries []diaryEntry
+	for _, m := range movies {
+		var start time.Time
+		for round, v := range m.History {
+			if v.Watched[userID] {
+				at := v.WatchedAt[userID]
+				if at.IsZero() {
+					at = dated(m.ID, start, v.EndedAt)
+				}
+				entries = append(entries, diaryEntry{movie: m, at: at, rewatch: round > 0})
+			}
+			start = v.EndedAt
+		}
+		if m.Watched[userID] {
+			at := m.WatchedAt[userID]
+			if at.IsZero() {
+				at = dated(m.ID, start, time.Time{})
+			}
+			entries = append(entries, diaryEntry{movie: m, at: at, rating: m.Ratings[userID], rewatch: len(m.History) > 0})
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b diaryEntry) int {
+		if a.at.IsZero() != b.at.IsZero() {
+			if a.at.IsZero() {
+				return 1
+			}
+			return -1
+		}
+		return a.at.Compare(b.at)
+	})
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(diaryHeader)
+	for _, e := range entries {
+		date, rating, rating10 := "", "", ""
+		if !e.at.IsZero() {
+			date = e.at.In(loc).Format("2006-01-02")
+		}
+		if e.rating > 0 {
+			rating = strconv.FormatFloat(float64(e.rating)/2, 'f', -1, 64)
+			rating10 = strconv.Itoa(e.rating)
+		}
+		year := ""
+		if e.movie.Year > 0 {
+			year = strconv.Itoa(e.movie.Year)
+		}
+		w.Write([]string{e.movie.ImdbID, e.movie.Title, year, date, rating, rating10, strconv.FormatBool(e.rewatch)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("write csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
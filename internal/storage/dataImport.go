@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+// ImportReport summarizes what ImportMovies did with a file.
+type ImportReport struct {
+	Added   []string // "Title (Year)"
+	Skipped []string // duplicates and unusable rows, with the reason
+}
+
+// maxReportLines caps the skipped entries ImportReport.String lists.
+const maxReportLines = 15
+
+func (r ImportReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Added: %d, skipped: %d", len(r.Added), len(r.Skipped))
+	for i, s := range r.Skipped {
+		if i == maxReportLines {
+			fmt.Fprintf(&sb, "\n… and %d more", len(r.Skipped)-i)
+			break
+		}
+		sb.WriteString("\n⏭ " + s)
+	}
+	return sb.String()
+}
+
+// ParseImport reads movies from a JSON file in the movies.json format (as
+// /export sends it) or from a CSV file. CSV files name their columns in a
+// header row, as ExportCSV writes them; only "title" is required. Without a
+// header the columns are taken as title and year.
+func ParseImport(data []byte) ([]Movie, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var movies []Movie
+		if err := json.Unmarshal(trimmed, &movies); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return movies, nil
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the file is empty")
+	}
+
+	cols := map[string]int{"title": 0, "year": 1}
+	if header := rows[0]; containsFold(header, "title") {
+		cols = make(map[string]int, len(header))
+		for i, name := range header {
+			cols[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		rows = rows[1:]
+	}
+	field := func(row []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	users := func(v string) map[string]bool {
+		set := make(map[string]bool)
+		for _, id := range strings.Split(v, ";") {
+			if id = strings.TrimSpace(id); id != "" {
+				set[id] = true
+			}
+		}
+		return set
+	}
+
+	movies := make([]Movie, 0, len(rows))
+	for _, row := range rows {
+		year, _ := strconv.Atoi(field(row, "year"))
+		added, _ := time.Parse(time.RFC3339, field(row, "added_at"))
+		movies = append(movies, Movie{
+			Title:      field(row, "title"),
+			Year:       year,
+			List:       field(row, "list"),
+			ImdbID:     field(row, "imdb_id"),
+			AddedAt:    added,
+			AddedBy:    field(row, "added_by"),
+			Votes:      users(field(row, "voters")),
+			Watched:    users(field(row, "watched_by")),
+			Runtime:    field(row, "runtime"),
+			Genre:      field(row, "genre"),
+			ImdbRating: field(row, "imdb_rating"),
+		})
+	}
+	return movies, nil
+}
+
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportMovies adds the movies of an import to a library unless they are in
+// it already. A movie already on its list with the same title and year
+// (ignoring case) is skipped, as are repeats within the import; unlike
+// MergeMovies, nothing already stored is changed.
+func (s *Store) ImportMovies(ctx context.Context, library int64, incoming []Movie) ImportReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var r ImportReport
+	for i, m := range incoming {
+		m.Title = strings.TrimSpace(m.Title)
+		if m.Title == "" {
+			r.Skipped = append(r.Skipped, fmt.Sprintf("entry %d has no title", i+1))
+			continue
+		}
+		m.ChatID = library
+		label := fmt.Sprintf("%s (%d)", m.Title, m.Year)
+		if s.indexOfTitleLocked(library, m.List, m.Title, m.Year) >= 0 {
+			r.Skipped = append(r.Skipped, label+" is already on "+listName(m.List))
+			continue
+		}
+
+		m.ID = generateMovieID(library, m.List, m.Title, m.Year)
+		if m.Votes == nil {
+			m.Votes = make(map[string]bool)
+		}
+		if m.Watched == nil {
+			m.Watched = make(map[string]bool)
+		}
+		if m.AddedAt.IsZero() {
+			m.AddedAt = time.Now()
+		}
+		s.movies = append(s.movies, m)
+		r.Added = append(r.Added, label)
+	}
+
+	trace.Logf(ctx, "[STORE] Import: %d added, %d skipped", len(r.Added), len(r.Skipped))
+	if len(r.Added) > 0 {
+		s.markDirty()
+	}
+	return r
+}
+
+// indexOfTitleLocked finds a movie on a library's list by title (ignoring
+// case) and year.
+func (s *Store) indexOfTitleLocked(library int64, list, title string, year int) int {
+	for i := range s.movies {
+		if m := s.movies[i]; m.ChatID == library && m.List == list && m.Year == year && strings.EqualFold(m.Title, title) {
+			return i
+		}
+	}
+	return -1
+}
+
+func listName(list string) string {
+	if list == "" {
+		return "the watchlist"
+	}
+	return "the " + list + " list"
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- DISCUSSIONS & RATINGS --------------------
+//
+
+// Discussion is where a chat talks about a movie after watching it: a forum
+// topic (MessageID is the topic's thread ID) or a reply chain under a bot
+// message.
+type Discussion struct {
+	ChatID    int64 `json:"chat_id"`
+	MessageID int   `json:"message_id"`
+	Topic     bool  `json:"topic,omitempty"`
+}
+
+// DiscussionIn returns the movie's discussion in chatID, if there is one.
+func (m Movie) DiscussionIn(chatID int64) (Discussion, bool) {
+	for _, d := range m.Discussions {
+		if d.ChatID == chatID {
+			return d, true
+		}
+	}
+	return Discussion{}, false
+}
+
+// AddDiscussion links a discussion thread to a movie.
+func (s *Store) AddDiscussion(ctx context.Context, movieID string, d Discussion) (Movie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOfID(movieID)
+	if i < 0 {
+		return Movie{}, fmt.Errorf("movie not found")
+	}
+	s.movies[i].Discussions = append(s.movies[i].Discussions, d)
+	s.markDirty()
+
+	trace.Logf(ctx, "[STORE] Discussion for %s in chat %d (message %d)", s.movies[i].Title, d.ChatID, d.MessageID)
+	return s.movies[i].clone(), nil
+}
+
+// MovieByDiscussion finds the movie whose discussion in chatID starts at
+// messageID.
+func (s *Store) MovieByDiscussion(chatID int64, messageID int) (Movie, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, m := range s.movies {
+		if d, ok := m.DiscussionIn(chatID); ok && d.MessageID == messageID {
+			return m.clone(), true
+		}
+	}
+	return Movie{}, false
+}
+
+// SetRating stores a user's 1-10 rating of a movie, replacing an earlier one.
+func (s *Store) SetRating(ctx context.Context, movieID, userID string, rating int) (Movie, error) {
+	if rating < 1 || rating > 10 {
+		return Movie{}, fmt.Errorf("ratings go from 1 to 10")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOfID(movieID)
+	if i < 0 {
+		return Movie{}, fmt.Errorf("movie not found")
+	}
+	if s.movies[i].Ratings == nil {
+		s.movies[i].Ratings = make(map[string]int)
+	}
+	s.movies[i].Ratings[userID] = rating
+	s.markDirty()
+
+	trace.Logf(ctx, "[STORE] User %s rated %s %d/10", userID, s.movies[i].Title, rating)
+	return s.movies[i].clone(), nil
+}
+
+// AverageRating is the mean of a movie's ratings and how many there are.
+func AverageRating(m Movie) (float64, int) {
+	if len(m.Ratings) == 0 {
+		return 0, 0
+	}
+	sum := 0
+	for _, r := range m.Ratings {
+		sum += r
+	}
+	return float64(sum) / float64(len(m.Ratings)), len(m.Ratings)
+}
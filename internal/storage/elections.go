@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+// MaxRanks is how many movies a ranked-choice ballot orders.
+const MaxRanks = 3
+
+//
+// -------------------- ELECTIONS --------------------
+//
+
+// Election is a ranked-choice vote over some movies: everyone orders up to
+// MaxRanks of them and the winner is found by instant-runoff counting.
+type Election struct {
+	ID        string              `json:"id"`
+	ChatID    int64               `json:"chat_id"`
+	MessageID int                 `json:"message_id"`
+	MovieIDs  []string            `json:"movie_ids"`
+	Ballots   map[string][]string `json:"ballots,omitempty"` // user ID -> movie IDs, first choice first
+	CreatedAt time.Time           `json:"created_at"`
+	Closed    bool                `json:"closed,omitempty"`
+	Winner    string              `json:"winner,omitempty"` // movie ID, set on close
+}
+
+// AddElection opens an election and returns it with its ID set.
+func (s *Store) AddElection(ctx context.Context, e Election) Election {
+	s.electionMu.Lock()
+	defer s.electionMu.Unlock()
+
+	e.ID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	s.elections = append(s.elections, e)
+	s.electionsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Election %s in chat %d over %d movies", e.ID, e.ChatID, len(e.MovieIDs))
+	return e
+}
+
+// GetElection returns an election by ID.
+func (s *Store) GetElection(id string) (Election, bool) {
+	s.electionMu.RLock()
+	defer s.electionMu.RUnlock()
+
+	i := slices.IndexFunc(s.elections, func(e Election) bool { return e.ID == id })
+	if i < 0 {
+		return Election{}, false
+	}
+	return s.elections[i], true
+}
+
+// OpenElection returns the chat's latest election that is still open.
+func (s *Store) OpenElection(chatID int64) (Election, bool) {
+	s.electionMu.RLock()
+	defer s.electionMu.RUnlock()
+
+	for i := len(s.elections) - 1; i >= 0; i-- {
+		if e := s.elections[i]; e.ChatID == chatID && !e.Closed {
+			return e, true
+		}
+	}
+	return Election{}, false
+}
+
+// SetElectionMessage records the message holding an election's ballot.
+func (s *Store) SetElectionMessage(ctx context.Context, id string, messageID int) {
+	s.updateElection(ctx, id, func(e *Election) error {
+		e.MessageID = messageID
+		return nil
+	})
+}
+
+// RankMovie puts a movie next on a user's ballot. Ranking a movie already
+// on it again takes it (and everything ranked below it) off, so a mis-tap
+// can be undone. It returns the ballot as it stands.
+func (s *Store) RankMovie(ctx context.Context, id, userID, movieID string) ([]string, error) {
+	var ballot []string
+	err := s.updateElection(ctx, id, func(e *Election) error {
+		if !slices.Contains(e.MovieIDs, movieID) {
+			return fmt.Errorf("that movie is not on the ballot")
+		}
+		ballot = e.Ballots[userID]
+		if i := slices.Index(ballot, movieID); i >= 0 {
+			ballot = ballot[:i]
+		} else if len(ballot) >= MaxRanks {
+			return fmt.Errorf("you already ranked %d movies, clear your ballot to start over", MaxRanks)
+		} else {
+			ballot = append(slices.Clone(ballot), movieID)
+		}
+		e.setBallot(userID, ballot)
+		return nil
+	})
+	if err == nil {
+		trace.Logf(ctx, "[STORE] User %s ranked %d movies in election %s", userID, len(ballot), id)
+	}
+	return ballot, err
+}
+
+// ClearBallot throws away a user's ballot.
+func (s *Store) ClearBallot(ctx context.Context, id, userID string) error {
+	return s.updateElection(ctx, id, func(e *Election) error {
+		e.setBallot(userID, nil)
+		return nil
+	})
+}
+
+// CloseElection counts the ballots and closes the election. The winner is
+// "" when nobody voted.
+func (s *Store) CloseElection(ctx context.Context, id string) (Election, []RunoffRound, error) {
+	var (
+		closed Election
+		rounds []RunoffRound
+	)
+	err := s.updateElection(ctx, id, func(e *Election) error {
+		ballots := make([][]string, 0, len(e.Ballots))
+		for _, b := range e.Ballots {
+			ballots = append(ballots, b)
+		}
+		e.Winner, rounds = InstantRunoff(e.MovieIDs, ballots)
+		e.Closed = true
+		closed = *e
+		return nil
+	})
+	if err == nil {
+		trace.Logf(ctx, "[STORE] Election %s closed after %d rounds, winner %q", id, len(rounds), closed.Winner)
+	}
+	return closed, rounds, err
+}
+
+func (e *Election) setBallot(userID string, ballot []string) {
+	if len(ballot) == 0 {
+		delete(e.Ballots, userID)
+		return
+	}
+	if e.Ballots == nil {
+		e.Ballots = make(map[string][]string)
+	}
+	e.Ballots[userID] = ballot
+}
+
+// updateElection applies fn to an open election and saves the result.
+func (s *Store) updateElection(ctx context.Context, id string, fn func(*Election) error) error {
+	s.electionMu.Lock()
+	defer s.electionMu.Unlock()
+
+	i := slices.IndexFunc(s.elections, func(e Election) bool { return e.ID == id })
+	if i < 0 || s.elections[i].Closed {
+		return fmt.Errorf("this election is over")
+	}
+	if err := fn(&s.elections[i]); err != nil {
+		return err
+	}
+	s.electionsFile.markDirty()
+	return nil
+}
+
+//
+// -------------------- INSTANT RUNOFF --------------------
+//
+
+// RunoffRound is one counting round: each remaining candidate's votes and
+// who dropped out after it ("" in the deciding round).
+type RunoffRound struct {
+	Counts     map[string]int
+	Eliminated string
+}
+
+// InstantRunoff counts ranked ballots: every ballot goes to its highest
+// ranked candidate still in the race, and while no one holds a majority of
+// the ballots still counting, the last candidate drops out. Ties for last
+// place knock out the candidate with fewer first choices, then the one
+// listed later in candidates. It returns the winner, "" without ballots, and
+// the rounds for showing how it was decided.
+func InstantRunoff(candidates []string, ballots [][]string) (string, []RunoffRound) {
+	firsts := make(map[string]int)
+	for _, b := range ballots {
+		if len(b) > 0 {
+			firsts[b[0]]++
+		}
+	}
+
+	remaining := slices.Clone(candidates)
+	var rounds []RunoffRound
+	for len(remaining) > 0 {
+		counts := make(map[string]int, len(remaining))
+		for _, c := range remaining {
+			counts[c] = 0
+		}
+		active := 0
+		for _, b := range ballots {
+			for _, c := range b {
+				if _, in := counts[c]; in {
+					counts[c]++
+					active++
+					break
+				}
+			}
+		}
+		if active == 0 {
+			return "", rounds
+		}
+
+		leader := remaining[0]
+		for _, c := range remaining {
+			if counts[c] > counts[leader] {
+				leader = c
+			}
+		}
+		if 2*counts[leader] > active || len(remaining) == 1 {
+			rounds = append(rounds, RunoffRound{Counts: counts})
+			return leader, rounds
+		}
+
+		last := remaining[len(remaining)-1]
+		for i := len(remaining) - 1; i >= 0; i-- {
+			c := remaining[i]
+			if counts[c] < counts[last] || (counts[c] == counts[last] && firsts[c] < firsts[last]) {
+				last = c
+			}
+		}
+		rounds = append(rounds, RunoffRound{Counts: counts, Eliminated: last})
+		remaining = slices.DeleteFunc(remaining, func(c string) bool { return c == last })
+	}
+	return "", rounds
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+var htmlExportTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"poster": func(p string) string {
+		if p == "" || p == "N/A" {
+			return ""
+		}
+		return p
+	},
+	"imdb": func(m Movie) string {
+		if m.ImdbRating == "N/A" {
+			return ""
+		}
+		return m.ImdbRating
+	},
+	"group": func(m Movie) string {
+		avg, n := AverageRating(m)
+		if n == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%.1f/10 (%d)", avg, n)
+	},
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "—"
+		}
+		return t.Format("2 Jan 2006")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body{font-family:system-ui,sans-serif;background:#14161a;color:#e8e8e8;margin:0;padding:1.5rem}
+h1{margin-top:0}h2{border-bottom:1px solid #333;padding-bottom:.3rem}
+.grid{display:grid;grid-template-columns:repeat(auto-fill,minmax(160px,1fr));gap:1rem}
+.card{background:#1f2228;border-radius:8px;overflow:hidden}
+.card img,.card .noposter{width:100%;aspect-ratio:2/3;object-fit:cover;display:block;background:#2a2e36}
+.card .body{padding:.5rem .6rem}
+.title{font-weight:600}.meta{color:#9aa;font-size:.85rem}
+footer{margin-top:2rem;color:#777;font-size:.8rem}
+</style>
+</head>
+<body>
+<h1>🎬 {{.Title}}</h1>
+{{define "cards"}}<div class="grid">
+{{range .}}<div class="card">
+{{with poster .Poster}}<img src="{{.}}" alt="" loading="lazy">{{else}}<div class="noposter"></div>{{end}}
+<div class="body">
+<div class="title">{{.Title}}{{if gt .Year 0}} ({{.Year}}){{end}}</div>
+<div class="meta">👍 {{len .Votes}} · 👁 {{len .Watched}}{{with imdb .}} · ⭐ {{.}}{{end}}{{with group .}} · 🍿 {{.}}{{end}}</div>
+<div class="meta">Added {{date .AddedAt}}</div>
+</div>
+</div>
+{{end}}</div>{{end}}
+<h2>Up next</h2>
+{{if .Unwatched}}{{template "cards" .Unwatched}}{{else}}<p>Nothing left to watch.</p>{{end}}
+<h2>Watched</h2>
+{{if .Watched}}{{template "cards" .Watched}}{{else}}<p>Nothing watched yet.</p>{{end}}
+<footer>Exported {{date .Generated}}</footer>
+</body>
+</html>
+`))
+
+// ExportHTML renders the movie list as a single HTML page with inline
+// styles, suitable for any static host. Posters are not embedded: the page
+// links to the images where OMDb has them.
+func ExportHTML(movies []Movie, title string) ([]byte, error) {
+	movies = append([]Movie(nil), movies...)
+	sortMoviesByVotes(movies)
+
+	data := struct {
+		Title     string
+		Generated time.Time
+		Unwatched []Movie
+		Watched   []Movie
+	}{Title: title, Generated: time.Now()}
+
+	for _, m := range movies {
+		if IsWatched(m) {
+			data.Watched = append(data.Watched, m)
+		} else {
+			data.Unwatched = append(data.Unwatched, m)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := htmlExportTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
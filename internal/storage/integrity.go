@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//
+// -------------------- INTEGRITY CHECK --------------------
+//
+
+// quarantined is a movie record that was taken out of the store at load
+// because it could not be repaired. Such records are written to a
+// quarantine file next to movies.json so nothing is lost.
+type quarantined struct {
+	Reason string          `json:"reason"`
+	Record json.RawMessage `json:"record"`
+}
+
+// integrityReport counts what the load-time check repaired.
+type integrityReport struct {
+	nilMaps     int
+	zeroAddedAt int
+	newIDs      int
+	badRatings  int
+	mergedDups  int
+	orphanRefs  int
+	badRefs     int
+	quarantine  []quarantined
+}
+
+func (r integrityReport) repaired() bool {
+	return r.nilMaps+r.zeroAddedAt+r.newIDs+r.badRatings+r.mergedDups > 0
+}
+
+// decodeMovies parses movies.json record by record, so one malformed entry
+// is quarantined instead of failing the whole file.
+func (s *Store) decodeMovies(data []byte, r *integrityReport) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	s.movies = make([]Movie, 0, len(raw))
+	for _, rec := range raw {
+		var m Movie
+		if err := json.Unmarshal(rec, &m); err != nil {
+			r.quarantine = append(r.quarantine, quarantined{Reason: err.Error(), Record: rec})
+			continue
+		}
+		if strings.TrimSpace(m.Title) == "" {
+			r.quarantine = append(r.quarantine, quarantined{Reason: "no title", Record: rec})
+			continue
+		}
+		s.movies = append(s.movies, m)
+	}
+	return nil
+}
+
+// checkIntegrity repairs what is safe to repair in freshly loaded data: nil
+// vote maps, missing IDs and timestamps, out-of-range ratings, duplicate IDs
+// (merged into the first record) and message refs that point nowhere. It
+// runs before the store is shared, so it takes no locks.
+func (s *Store) checkIntegrity(r *integrityReport) {
+	addedFallback := time.Now()
+	if fi, err := os.Stat(s.moviesPath); err == nil {
+		addedFallback = fi.ModTime()
+	}
+
+	byID := make(map[string]int, len(s.movies))
+	kept := s.movies[:0]
+	for _, m := range s.movies {
+		if m.Votes == nil || m.Watched == nil {
+			r.nilMaps++
+			if m.Votes == nil {
+				m.Votes = make(map[string]bool)
+			}
+			if m.Watched == nil {
+				m.Watched = make(map[string]bool)
+			}
+		}
+		if m.AddedAt.IsZero() {
+			r.zeroAddedAt++
+			m.AddedAt = addedFallback
+		}
+		if m.ID == "" {
+			r.newIDs++
+			m.ID = generateMovieID(m.ChatID, m.List, m.Title, m.Year)
+		}
+		for user, score := range m.Ratings {
+			if score < 1 || score > 10 {
+				r.badRatings++
+				delete(m.Ratings, user)
+			}
+		}
+
+		if i, dup := byID[m.ID]; dup {
+			first := &kept[i]
+			for user := range m.Votes {
+				first.Votes[user] = true
+			}
+			for user := range m.Watched {
+				first.Watched[user] = true
+			}
+			r.mergedDups++
+			continue
+		}
+		byID[m.ID] = len(kept)
+		kept = append(kept, m)
+	}
+	s.movies = kept
+
+	for key, refs := range s.index {
+		if _, ok := byID[key]; !ok && key != "list" && !strings.HasPrefix(key, "list:") {
+			r.orphanRefs += len(refs)
+			delete(s.index, key)
+			continue
+		}
+		valid := refs[:0]
+		for _, ref := range refs {
+			if ref.InlineID == "" && (ref.ChatID == 0 || ref.MessageID <= 0) {
+				r.badRefs++
+				continue
+			}
+			valid = append(valid, ref)
+		}
+		s.index[key] = valid
+	}
+}
+
+// finishIntegrity writes the quarantine file, schedules rewrites of what
+// was repaired and logs a summary.
+func (s *Store) finishIntegrity(r integrityReport) {
+	if len(r.quarantine) > 0 {
+		path := filepath.Join(filepath.Dir(s.moviesPath), fmt.Sprintf("quarantine-%s.json", time.Now().Format("20060102-150405")))
+		data, _ := json.MarshalIndent(r.quarantine, "", "  ")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			log.Printf("[STORE] Failed to write quarantine file: %v", err)
+		} else {
+			log.Printf("[STORE] Quarantined %d unreadable movie records to %s", len(r.quarantine), path)
+		}
+	}
+	if r.repaired() || len(r.quarantine) > 0 {
+		s.markDirty()
+	}
+	if r.orphanRefs+r.badRefs > 0 {
+		s.markMsgDirty()
+	}
+
+	if !r.repaired() && len(r.quarantine) == 0 && r.orphanRefs+r.badRefs == 0 {
+		log.Printf("[STORE] Integrity check passed (%d movies)", len(s.movies))
+		return
+	}
+	log.Printf("[STORE] Integrity check: fixed %d nil vote maps, %d missing added dates, %d missing IDs, %d bad ratings; merged %d duplicate IDs; dropped %d orphaned and %d invalid message refs; quarantined %d records",
+		r.nilMaps, r.zeroAddedAt, r.newIDs, r.badRatings, r.mergedDups, r.orphanRefs, r.badRefs, len(r.quarantine))
+}
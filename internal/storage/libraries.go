@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- LIBRARIES --------------------
+//
+
+// Every movie is in one library: a chat's own, keyed by its chat ID, or the
+// shared one (0) that all chats used before they got their own and that
+// movies.json files from then are read into.
+
+// SetPerChatLibraries gives every chat a library of its own. Off, all chats
+// share one.
+func (s *Store) SetPerChatLibraries(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.perChat = on
+}
+
+// PerChatLibraries reports whether every chat has a library of its own.
+func (s *Store) PerChatLibraries() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.perChat
+}
+
+// SetPrivateLibraries gives private chats, whose IDs are the users' and
+// positive unlike those of groups, a library of their own even while the
+// groups share one.
+func (s *Store) SetPrivateLibraries(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.private = on
+}
+
+// LibraryOf returns the library chatID lists, votes and adds in.
+func (s *Store) LibraryOf(chatID int64) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.perChat && !(s.private && chatID > 0) {
+		return 0
+	}
+	return chatID
+}
+
+// AdoptReport counts what AdoptLibrary moved.
+type AdoptReport struct {
+	Moved   int
+	Skipped []string // already in the target library
+}
+
+// AdoptLibrary moves every movie of library from into library to, votes,
+// ratings and history included. The movies get the IDs matching their new
+// library; their tracked messages, nights, polls, elections and voting
+// rounds follow them. Movies the target already has on the same list stay
+// where they are.
+func (s *Store) AdoptLibrary(ctx context.Context, from, to int64) AdoptReport {
+	var r AdoptReport
+	if from == to {
+		return r
+	}
+
+	renamed := make(map[string]string)
+	s.mu.Lock()
+	for i := range s.movies {
+		m := &s.movies[i]
+		if m.ChatID != from {
+			continue
+		}
+		if s.onList(to, m.List, m.Title, m.Year) {
+			r.Skipped = append(r.Skipped, fmt.Sprintf("%s (%d)", m.Title, m.Year))
+			continue
+		}
+		id := generateMovieID(to, m.List, m.Title, m.Year)
+		renamed[m.ID] = id
+		m.ID, m.ChatID = id, to
+	}
+	r.Moved = len(renamed)
+	if r.Moved > 0 {
+		s.markDirty()
+	}
+	s.mu.Unlock()
+
+	s.renameMovieIDs(renamed)
+
+	trace.Logf(ctx, "[STORE] Library %d adopted into %d: %d moved, %d skipped", from, to, r.Moved, len(r.Skipped))
+	return r
+}
@@ -5,6 +5,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"moviebot/internal/i18n"
 )
 
 type fieldFormatter func(Movie) string
@@ -13,12 +15,51 @@ type MovieColumn struct {
 	Header string
 	Width  int
 	Format fieldFormatter
+	// FormatTime replaces Format for columns that show a time, so they can
+	// follow the table's TimeStyle.
+	FormatTime func(Movie, TimeStyle) string
 }
 
 type TableFormat struct {
-	Columns         []MovieColumn
-	SortBy          sortMethod
-	SeparateWatched bool
+	Columns          []MovieColumn
+	SortBy           sortMethod
+	SeparateWatched  bool
+	GroupCollections bool      // keep movies of one franchise together under a header
+	Time             TimeStyle // language, zone and mode of time columns
+	Numbered         bool      // prefix each movie with its position, for /vote 7
+	Render           renderMode
+	Limit            int // keep only the top Limit unwatched movies, 0 = all
+	PageSize         int // movies per page, 0 = no paging
+	Page             int // page to render, from 0; numbering stays list-wide
+	Filter           ListFilter
+}
+
+// ListFilter narrows a list down before rendering. Zero value keeps all.
+type ListFilter struct {
+	Collection string // case-insensitive substring of Movie.Collection
+	Genre      string // one of MovieTags, case-insensitive
+}
+
+func (f ListFilter) IsZero() bool {
+	return f == ListFilter{}
+}
+
+// FilterMovies returns the movies matching f.
+func FilterMovies(movies []Movie, f ListFilter) []Movie {
+	if f.IsZero() {
+		return movies
+	}
+	var out []Movie
+	for _, m := range movies {
+		if f.Collection != "" && !strings.Contains(strings.ToLower(m.Collection), strings.ToLower(f.Collection)) {
+			continue
+		}
+		if f.Genre != "" && !HasTag(m, f.Genre) {
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
 }
 
 type sortMethod int
@@ -28,34 +69,86 @@ const (
 	SortByDateAdded
 )
 
+type renderMode int
+
+const (
+	RenderTable renderMode = iota // fixed-width table for a code block
+	RenderCards                   // Markdown lines with poster links
+)
+
+// DefaultTableFormat is the compact Title/Year/Votes/Seen table.
+func DefaultTableFormat() TableFormat {
+	return TableFormat{
+		Columns: []MovieColumn{
+			{Header: "Title", Width: 25, Format: FormatTitle},
+			{Header: "Year", Width: 4, Format: FormatYear},
+			{Header: "Votes", Width: 5, Format: FormatVotes},
+			{Header: "Seen", Width: 4, Format: FormatWatched},
+		},
+		SortBy:           SortByVotes,
+		SeparateWatched:  true,
+		GroupCollections: true,
+	}
+}
+
 
 
 
 func truncate(s string, max int) string {
-	if len(s) <= max {
+	r := []rune(s)
+	if len(r) <= max {
 		return s
 	}
 	if max <= 3 {
-		return s[:max]
+		return string(r[:max])
 	}
-	return s[:max-3] + "..."
+	return string(r[:max-3]) + "..."
+}
+
+// LocalTitle is the movie's title in lang with the original in
+// parentheses, "Alien – Das unheimliche Wesen (Alien)", or just the
+// original when there is no other title for lang.
+func LocalTitle(m Movie, lang string) string {
+	local := m.Titles[lang]
+	if local == "" || strings.EqualFold(local, m.Title) {
+		return m.Title
+	}
+	return fmt.Sprintf("%s (%s)", local, m.Title)
+}
+
+// localizeTitles returns copies of movies titled for lang.
+func localizeTitles(movies []Movie, lang string) []Movie {
+	out := make([]Movie, len(movies))
+	for i, m := range movies {
+		m.Title = LocalTitle(m, lang)
+		out[i] = m
+	}
+	return out
 }
 
 func FormatTitle(m Movie) string {
 	if m.Title == "" {
 		return "???"
 	}
-	return fmt.Sprintf("%s", m.Title)
+	if m.Rewatch {
+		return "↺ " + m.Title // single-width, keeps the table aligned
+	}
+	return m.Title
 }
 
 func FormatYear(m Movie) string {
-	if m.Year <= 0 || m.Year > time.Now().Year()+1 {
+	if m.Year <= 0 || m.Year > time.Now().Year()+5 {
 		return "???"
 	}
 	return fmt.Sprintf("%4d", m.Year)
 }
 
+// FormatVotes is the vote count, or the days until release of an upcoming
+// movie, which can't be voted on yet ("  23d").
 func FormatVotes(m Movie) string {
+	if m.Upcoming {
+		return fmt.Sprintf("%4dd", ReleaseDays(m, time.Now()))
+	}
 	return fmt.Sprintf("%5d", len(m.Votes))
 }
 
@@ -63,34 +156,46 @@ func FormatWatched(m Movie) string {
 	return fmt.Sprintf("%4d", len(m.Watched))
 }
 
-func FormatAdded(m Movie) string {
-	return timeAgo(m.AddedAt)
+func FormatImdbRating(m Movie) string {
+	if m.ImdbRating == "" {
+		return "  -"
+	}
+	return fmt.Sprintf("%4s", m.ImdbRating)
 }
 
-func timeAgo(addedAt time.Time) string {
-	now := time.Now()
-	diff := now.Sub(addedAt)
+// FormatGenre is OMDb's genre list, "-" until the movie's details are in.
+func FormatGenre(m Movie) string {
+	if m.Genre == "" || m.Genre == "N/A" {
+		return "-"
+	}
+	return m.Genre
+}
 
-	switch {
-	case diff < time.Minute:
-		return fmt.Sprintf("%ds ago", int(diff.Seconds()))
-	case diff < time.Hour:
-		return fmt.Sprintf("%dm ago", int(diff.Minutes()))
-	case diff < 24*time.Hour:
-		return fmt.Sprintf("%dh ago", int(diff.Hours()))
-	case diff < 30*24*time.Hour: // Rough estimate for a month
-		return fmt.Sprintf("%dd ago", int(diff.Hours()/24))
-	case diff < 365*24*time.Hour:
-		return fmt.Sprintf("%dM ago", int(diff.Hours()/(24*30))) // Assume 30 days in a month
-	default:
-		return fmt.Sprintf("%dY ago", int(diff.Hours()/(24*365))) // Assume 365 days in a year
+// FormatGroupRating is the average of the group's own 1-10 ratings.
+func FormatGroupRating(m Movie) string {
+	avg, n := AverageRating(m)
+	if n == 0 {
+		return "  -"
 	}
+	return fmt.Sprintf("%4.1f", avg)
+}
+
+// FormatAdded is the English relative "Added" column; see FormatAddedTime
+// for the per-chat variant.
+func FormatAdded(m Movie) string {
+	return RelativeTime(m.AddedAt, time.Now(), "en")
+}
+
+// isWatched reports whether a movie belongs in the watched section: at least
+// as many people have seen it as have voted for it.
+func IsWatched(m Movie) bool {
+	return len(m.Watched) > 0 && len(m.Watched) >= len(m.Votes)
 }
 
 // Helper functions for sorting
 func sortMoviesByVotes(movies []Movie) {
 	sort.Slice(movies, func(i, j int) bool {
-		return len(movies[i].Votes) > len(movies[j].Votes)
+		return Score(movies[i]) > Score(movies[j])
 	})
 }
 
@@ -100,14 +205,88 @@ func sortMoviesByDateAdded(movies []Movie) {
 	})
 }
 
+// listRow is one rendered line: either a collection header or a movie,
+// indented when it sits under a header.
+type listRow struct {
+	header string
+	movie  Movie
+	indent bool
+}
+
+// arrangeRows keeps the sorted order but, when grouping, pulls every member
+// of a collection up to where its best-ranked member sits, ordered by year
+// under a header. Collections with a single movie on the list are not grouped.
+func arrangeRows(movies []Movie, group bool) []listRow {
+	var rows []listRow
+	if !group {
+		for _, m := range movies {
+			rows = append(rows, listRow{movie: m})
+		}
+		return rows
+	}
+
+	members := make(map[string][]Movie)
+	for _, m := range movies {
+		if m.Collection != "" {
+			members[m.Collection] = append(members[m.Collection], m)
+		}
+	}
+
+	done := make(map[string]bool)
+	for _, m := range movies {
+		coll := members[m.Collection]
+		if m.Collection == "" || len(coll) < 2 {
+			rows = append(rows, listRow{movie: m})
+			continue
+		}
+		if done[m.Collection] {
+			continue
+		}
+		done[m.Collection] = true
+
+		sort.SliceStable(coll, func(i, j int) bool { return coll[i].Year < coll[j].Year })
+		rows = append(rows, listRow{header: m.Collection})
+		for _, gm := range coll {
+			rows = append(rows, listRow{movie: gm, indent: true})
+		}
+	}
+	return rows
+}
+
+// PageCount is how many pages of size n movies take, at least 1.
+func PageCount(n, size int) int {
+	if size <= 0 || n <= size {
+		return 1
+	}
+	return (n + size - 1) / size
+}
+
 func BuildListMessage(movies []Movie, format TableFormat) string {
+	text, _ := buildList(movies, format)
+	return text
+}
+
+// BuildNumberedList renders the list with a position in front of every
+// movie and returns the movie IDs in that order, so position n is ids[n-1].
+func BuildNumberedList(movies []Movie, format TableFormat) (string, []string) {
+	format.Numbered = true
+	return buildList(movies, format)
+}
+
+func buildList(movies []Movie, format TableFormat) (string, []string) {
 	// Extract the fields from the format struct
 	columns := format.Columns
 	sortBy := format.SortBy
 	separateWatched := format.SeparateWatched
 
+	lang := format.Time.Locale
 	if len(movies) == 0 {
-		return "No movies yet"
+		return i18n.T(lang, "No movies yet"), nil
+	}
+	if !format.Filter.IsZero() {
+		if movies = FilterMovies(movies, format.Filter); len(movies) == 0 {
+			return i18n.T(lang, "No movies match"), nil
+		}
 	}
 
 	// Sort movies based on the selected method
@@ -117,18 +296,34 @@ func BuildListMessage(movies []Movie, format TableFormat) string {
 	case SortByDateAdded:
 		sortMoviesByDateAdded(movies)
 	}
+	if format.Time.Locale != "" {
+		movies = localizeTitles(movies, format.Time.Locale)
+	}
+
+	if format.Render == RenderCards {
+		return buildCards(movies, format)
+	}
 
 	var sb strings.Builder
+	var ids []string
+
+	// Positions get a narrow column of their own, without a separator
+	numWidth := 0
+	if format.Numbered {
+		numWidth = len(fmt.Sprint(len(movies))) + 1
+		sb.WriteString(fmt.Sprintf("%-*s", numWidth, "#"))
+	}
 
 	// Print header with "|" separator between columns
 	for i, col := range columns {
 		if i > 0 {
 			sb.WriteString(" | ") // Add pipe separator
 		}
-		sb.WriteString(fmt.Sprintf("%-*s", col.Width, col.Header))
+		sb.WriteString(fmt.Sprintf("%-*s", col.Width, i18n.Column(lang, col.Header, col.Width)))
 	}
 	sb.WriteString("\n")
 
+	sb.WriteString(strings.Repeat("-", numWidth))
 	for i, col := range columns {
 		if i > 0 {
 			sb.WriteString("-+-") // Add pipe separator
@@ -141,52 +336,152 @@ func BuildListMessage(movies []Movie, format TableFormat) string {
 	var unwatched, watched []Movie
 	if separateWatched {
 		for _, m := range movies {
-			if len(m.Watched) > 0 && len(m.Watched) >= len(m.Votes) {
+			if IsWatched(m) {
 				watched = append(watched, m)
 			} else {
 				unwatched = append(unwatched, m)
 			}
 		}
 	} else {
-		watched = movies
+		unwatched = movies
+	}
+	if format.Limit > 0 {
+		unwatched, watched = limitMovies(unwatched, format.Limit), nil
+	}
+
+	// With paging, only positions from first up to last are written; the
+	// rest still count so the numbers match the whole list
+	first, last := 0, len(unwatched)+len(watched)
+	if format.PageSize > 0 {
+		first = format.Page * format.PageSize
+		last = min(first+format.PageSize, last)
 	}
+	pos := 0
+	pendingHeader := ""
 
 	// Function to write a movie's information to the string builder
-	writeMovie := func(m Movie) {
+	writeMovie := func(m Movie, indent bool) {
+		pos++
+		if !indent {
+			pendingHeader = ""
+		}
+		if format.Numbered {
+			ids = append(ids, m.ID)
+		}
+		if pos <= first || pos > last {
+			return
+		}
+		if pendingHeader != "" {
+			sb.WriteString("▸ " + pendingHeader + "\n")
+			pendingHeader = ""
+		}
+		if format.Numbered {
+			sb.WriteString(fmt.Sprintf("%-*d", numWidth, len(ids)))
+		}
 		for i, col := range columns {
 			if i > 0 {
 				sb.WriteString(" | ") // Add pipe separator
 			}
-			sb.WriteString(truncate(fmt.Sprintf("%-*s", col.Width, col.Format(m)),col.Width))
+			var value string
+			if col.FormatTime != nil {
+				value = col.FormatTime(m, format.Time)
+			} else {
+				value = col.Format(m)
+			}
+			if i == 0 && indent {
+				value = "  " + value
+			}
+			sb.WriteString(truncate(fmt.Sprintf("%-*s", col.Width, value), col.Width))
 		}
 		sb.WriteString("\n")
 	}
 
+	writeRows := func(movies []Movie) {
+		for _, row := range arrangeRows(movies, format.GroupCollections) {
+			if row.header != "" {
+				// Written with the first member that makes it onto the page
+				pendingHeader = row.header
+				continue
+			}
+			writeMovie(row.movie, row.indent)
+		}
+	}
+
 	// Write unwatched movies
 	//if separateWatched {
 	//	sb.WriteString("Unwatched:\n")
 	//}
-	for _, m := range unwatched {
-		writeMovie(m)
-	}
+	writeRows(unwatched)
 
-if separateWatched && len(watched) > 0 {
+if separateWatched && len(watched) > 0 && last > len(unwatched) {
 	sb.WriteString("\n")
 	// Compute table width
-	width := 0
+	width := numWidth
 	for _, col := range columns {
 		width += col.Width
 	}
 	width += (len(columns) - 1) * 3 // account for " | "
-	text := "Watched"
+	text := i18n.T(lang, "Watched")
 	padding := width - len(text)
 	sb.WriteString(strings.Repeat("-", padding/2) + text + strings.Repeat("-", padding-padding/2) + "\n")
 
-	for _, m := range watched {
-		writeMovie(m)
-	}
+	writeRows(watched)
 }
 
-	return sb.String()
+	return sb.String(), ids
 }
 
+func limitMovies(movies []Movie, n int) []Movie {
+	if len(movies) > n {
+		return movies[:n]
+	}
+	return movies
+}
+
+// cardMedals mark the podium in card mode.
+var cardMedals = []string{"🥇", "🥈", "🥉"}
+
+// buildCards renders movies as one Markdown line each, the title linking to
+// the poster. Watched movies are left out. Movies must already be sorted.
+func buildCards(movies []Movie, format TableFormat) (string, []string) {
+	var unwatched []Movie
+	for _, m := range movies {
+		if !IsWatched(m) {
+			unwatched = append(unwatched, m)
+		}
+	}
+	if format.Limit > 0 {
+		unwatched = limitMovies(unwatched, format.Limit)
+	}
+	if len(unwatched) == 0 {
+		return i18n.T(format.Time.Locale, "Nothing left to watch"), nil
+	}
+
+	var sb strings.Builder
+	ids := make([]string, 0, len(unwatched))
+	for i, m := range unwatched {
+		ids = append(ids, m.ID)
+		if i < len(cardMedals) {
+			sb.WriteString(cardMedals[i] + " ")
+		} else {
+			fmt.Fprintf(&sb, "%d. ", i+1)
+		}
+
+		title := fmt.Sprintf("%s (%d)", FormatTitle(m), m.Year)
+		if strings.HasPrefix(m.Poster, "http") {
+			title = fmt.Sprintf("[%s](%s)", title, m.Poster)
+		}
+		fmt.Fprintf(&sb, "%s — 👍 %d", title, len(m.Votes))
+		if m.ImdbRating != "" && m.ImdbRating != "N/A" {
+			fmt.Fprintf(&sb, " · ⭐ %s", m.ImdbRating)
+		}
+		if m.Runtime != "" && m.Runtime != "N/A" {
+			fmt.Fprintf(&sb, " · ⏱ %s", m.Runtime)
+		}
+		if m.Genre != "" && m.Genre != "N/A" {
+			fmt.Fprintf(&sb, " · 🎭 %s", m.Genre)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), ids
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+// MergeReport summarizes what MergeMovies did with an imported movie list.
+type MergeReport struct {
+	Added     int
+	Merged    int
+	Skipped   int
+	Conflicts []string
+}
+
+func (r MergeReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Added: %d, merged: %d, skipped: %d", r.Added, r.Merged, r.Skipped)
+	for _, c := range r.Conflicts {
+		sb.WriteString("\n⚠️ " + c)
+	}
+	return sb.String()
+}
+
+// findMergeTarget returns the index of the local movie matching m: by IMDb ID
+// when both sides have one, otherwise by title+year.
+func (s *Store) findMergeTarget(m Movie) int {
+	if m.ImdbID != "" {
+		for i := range s.movies {
+			if s.movies[i].ChatID == m.ChatID && s.movies[i].List == m.List && s.movies[i].ImdbID == m.ImdbID {
+				return i
+			}
+		}
+	}
+	for i := range s.movies {
+		if s.movies[i].ChatID == m.ChatID && s.movies[i].List == m.List && strings.EqualFold(s.movies[i].Title, m.Title) && s.movies[i].Year == m.Year {
+			return i
+		}
+	}
+	return -1
+}
+
+// MergeMovies folds movies exported by another moviebot instance into this
+// store. Matching entries get the union of voters and watchers and keep the
+// earliest AddedAt; entries that match on title+year but carry different IMDb
+// IDs are left alone and reported as conflicts.
+func (s *Store) MergeMovies(ctx context.Context, incoming []Movie) MergeReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var r MergeReport
+	for _, m := range incoming {
+		if m.Title == "" {
+			r.Skipped++
+			continue
+		}
+
+		i := s.findMergeTarget(m)
+		if i < 0 {
+			if m.ID == "" || s.indexOfID(m.ID) >= 0 {
+				m.ID = generateMovieID(m.ChatID, m.List, m.Title, m.Year)
+			}
+			if m.Votes == nil {
+				m.Votes = make(map[string]bool)
+			}
+			if m.Watched == nil {
+				m.Watched = make(map[string]bool)
+			}
+			s.movies = append(s.movies, m)
+			r.Added++
+			continue
+		}
+
+		local := &s.movies[i]
+		if local.ImdbID != "" && m.ImdbID != "" && local.ImdbID != m.ImdbID {
+			r.Conflicts = append(r.Conflicts, fmt.Sprintf("%s (%d): IMDb ID %s here, %s in import — kept local entry",
+				local.Title, local.Year, local.ImdbID, m.ImdbID))
+			r.Skipped++
+			continue
+		}
+		if local.Title != m.Title || local.Year != m.Year {
+			r.Conflicts = append(r.Conflicts, fmt.Sprintf("%s (%d) is %s (%d) in import — kept local title",
+				local.Title, local.Year, m.Title, m.Year))
+		}
+
+		if local.Votes == nil {
+			local.Votes = make(map[string]bool)
+		}
+		for u := range m.Votes {
+			local.Votes[u] = true
+		}
+		for u, emoji := range m.Reactions {
+			if _, ok := local.Reactions[u]; !ok {
+				if local.Reactions == nil {
+					local.Reactions = make(map[string]string)
+				}
+				local.Reactions[u] = emoji
+			}
+		}
+		if local.Watched == nil {
+			local.Watched = make(map[string]bool)
+		}
+		for u := range m.Watched {
+			local.Watched[u] = true
+			if at, ok := m.WatchedAt[u]; ok && local.WatchedAt[u].IsZero() {
+				local.setWatchedAt(u, at)
+			}
+		}
+		if local.WatchedOn.IsZero() {
+			local.WatchedOn = m.WatchedOn
+		}
+		local.noteWatchedOn(time.Now())
+		if !m.AddedAt.IsZero() && (local.AddedAt.IsZero() || m.AddedAt.Before(local.AddedAt)) {
+			local.AddedAt = m.AddedAt
+		}
+		if local.ImdbID == "" {
+			local.ImdbID = m.ImdbID
+		}
+		if local.Poster == "" || local.Poster == "N/A" {
+			local.Poster = m.Poster
+		}
+		r.Merged++
+	}
+
+	trace.Logf(ctx, "[STORE] Merge import: %d added, %d merged, %d skipped, %d conflicts",
+		r.Added, r.Merged, r.Skipped, len(r.Conflicts))
+	if r.Added > 0 || r.Merged > 0 {
+		s.markDirty()
+	}
+	return r
+}
+
+func (s *Store) indexOfID(id string) int {
+	for i := range s.movies {
+		if s.movies[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
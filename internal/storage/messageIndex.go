@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- MESSAGE INDEX LIMITS --------------------
+//
+
+// agePruneInterval is how often RegisterMessage sweeps refs older than the
+// configured max age.
+const agePruneInterval = time.Hour
+
+// SetIndexLimits caps the message index as a whole: at most maxRefs refs
+// (oldest evicted first) and none older than maxAge. Zero disables a
+// limit. The index is pruned right away.
+func (s *Store) SetIndexLimits(maxRefs int, maxAge time.Duration) {
+	s.msgMu.Lock()
+	defer s.msgMu.Unlock()
+
+	s.maxIndexRefs = maxRefs
+	s.maxIndexAge = maxAge
+	if n := s.pruneIndexLocked(time.Now()); n > 0 {
+		log.Printf("[STORE] Evicted %d message refs over the index limits", n)
+	}
+}
+
+// DropMessages forgets every message ref under key, e.g. for a movie that
+// was removed.
+func (s *Store) DropMessages(ctx context.Context, key string) []MessageRef {
+	s.msgMu.Lock()
+	defer s.msgMu.Unlock()
+
+	refs, ok := s.index[key]
+	if !ok {
+		return nil
+	}
+	delete(s.index, key)
+	s.markMsgDirty()
+	trace.Logf(ctx, "[STORE] Dropped %d message refs of %s", len(refs), key)
+	return refs
+}
+
+// MovieByMessage finds the movie whose vote card is messageID in chatID.
+func (s *Store) MovieByMessage(chatID int64, messageID int) (Movie, bool) {
+	s.msgMu.RLock()
+	var key string
+	for k, refs := range s.index {
+		for _, ref := range refs {
+			if ref.ChatID == chatID && ref.MessageID == messageID && ref.InlineID == "" {
+				key = k
+			}
+		}
+	}
+	s.msgMu.RUnlock()
+
+	if key == "" {
+		return Movie{}, false
+	}
+	return s.GetMovieByID(key) // list keys match no movie
+}
+
+// RegisterInlineMessage tracks a message sent through inline mode under
+// key. Such messages can be edited but carry no chat or message ID.
+func (s *Store) RegisterInlineMessage(ctx context.Context, key, inlineID string) {
+	s.msgMu.Lock()
+	defer s.msgMu.Unlock()
+
+	for _, ref := range s.index[key] {
+		if ref.InlineID == inlineID {
+			return
+		}
+	}
+	s.index[key] = append(s.index[key], MessageRef{InlineID: inlineID, At: time.Now()})
+	s.markMsgDirty()
+	trace.Logf(ctx, "[STORE] Registered inline message for %s", key)
+}
+
+// SetMessagePage records which page a tracked list message shows, so syncs
+// keep it there. It reports whether the message is tracked under key.
+func (s *Store) SetMessagePage(ctx context.Context, key string, chatID int64, messageID, page int) bool {
+	return s.updateRef(key, chatID, messageID, func(ref *MessageRef) bool {
+		if ref.Page == page {
+			return false
+		}
+		ref.Page = page
+		trace.Logf(ctx, "[STORE] Message %d of %s now shows page %d", messageID, key, page+1)
+		return true
+	})
+}
+
+// SetMessageLinks records whether a vote card shows its links block, so
+// syncs keep it open or closed. It reports whether the card is tracked.
+func (s *Store) SetMessageLinks(ctx context.Context, key string, chatID int64, messageID int, on bool) bool {
+	return s.updateRef(key, chatID, messageID, func(ref *MessageRef) bool {
+		if ref.Links == on {
+			return false
+		}
+		ref.Links = on
+		trace.Logf(ctx, "[STORE] Card %d of %s in chat %d shows links: %v", messageID, key, chatID, on)
+		return true
+	})
+}
+
+// SetMessagePhoto records that a vote card was sent as its poster, so syncs
+// edit its caption rather than its text. It reports whether the card is
+// tracked.
+func (s *Store) SetMessagePhoto(ctx context.Context, key string, chatID int64, messageID int) bool {
+	return s.updateRef(key, chatID, messageID, func(ref *MessageRef) bool {
+		if ref.Photo {
+			return false
+		}
+		ref.Photo = true
+		return true
+	})
+}
+
+// PinMessage marks a list message as its chat's pinned copy, exempt from
+// every eviction. It reports whether the message is tracked under key.
+func (s *Store) PinMessage(ctx context.Context, key string, chatID int64, messageID int) bool {
+	return s.updateRef(key, chatID, messageID, func(ref *MessageRef) bool {
+		if ref.Pinned {
+			return false
+		}
+		ref.Pinned = true
+		trace.Logf(ctx, "[STORE] Message %d of %s pinned in chat %d", messageID, key, chatID)
+		return true
+	})
+}
+
+// SetMessageHash records a hash of what a tracked message now shows, so
+// syncs can skip edits that would change nothing.
+func (s *Store) SetMessageHash(ctx context.Context, key string, chatID int64, messageID int, hash string) {
+	s.updateRef(key, chatID, messageID, func(ref *MessageRef) bool {
+		if ref.Hash == hash {
+			return false
+		}
+		ref.Hash = hash
+		return true
+	})
+}
+
+// updateRef applies change to one tracked message and saves the index if
+// change reports a difference. It reports whether the message is tracked.
+func (s *Store) updateRef(key string, chatID int64, messageID int, change func(*MessageRef) bool) bool {
+	s.msgMu.Lock()
+	defer s.msgMu.Unlock()
+
+	for i, ref := range s.index[key] {
+		if ref.ChatID == chatID && ref.MessageID == messageID {
+			if change(&s.index[key][i]) {
+				s.markMsgDirty()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// UnregisterMessage forgets one message ref under key, e.g. a card that
+// now shows another movie.
+func (s *Store) UnregisterMessage(ctx context.Context, key string, chatID int64, messageID int) {
+	s.msgMu.Lock()
+	defer s.msgMu.Unlock()
+
+	for _, ref := range s.index[key] {
+		if ref.ChatID == chatID && ref.MessageID == messageID {
+			s.index[key] = removeRef(s.index[key], ref)
+			if len(s.index[key]) == 0 {
+				delete(s.index, key)
+			}
+			s.markMsgDirty()
+			trace.Logf(ctx, "[STORE] Unregistered message %d of %s", messageID, key)
+			return
+		}
+	}
+}
+
+// pruneIndexLocked applies the index limits and returns how many refs it
+// evicted. Callers hold msgMu.
+func (s *Store) pruneIndexLocked(now time.Time) int {
+	evicted := 0
+
+	if s.maxIndexAge > 0 {
+		cutoff := now.Add(-s.maxIndexAge)
+		for key, refs := range s.index {
+			kept := refs[:0]
+			for _, ref := range refs {
+				// Refs from before timestamps were recorded have a zero
+				// At and are only evicted by the size cap.
+				if !ref.Pinned && !ref.At.IsZero() && ref.At.Before(cutoff) {
+					evicted++
+					continue
+				}
+				kept = append(kept, ref)
+			}
+			if len(kept) == 0 {
+				delete(s.index, key)
+			} else {
+				s.index[key] = kept
+			}
+		}
+		s.lastAgePrune = now
+	}
+
+	if s.maxIndexRefs > 0 {
+		type keyed struct {
+			key string
+			ref MessageRef
+		}
+		var all []keyed
+		for key, refs := range s.index {
+			for _, ref := range refs {
+				if !ref.Pinned {
+					all = append(all, keyed{key, ref})
+				}
+			}
+		}
+
+		if len(all) > s.maxIndexRefs {
+			// Trim to 90% so the sort doesn't run on every new message.
+			drop := len(all) - s.maxIndexRefs*9/10
+			sort.Slice(all, func(i, j int) bool { return all[i].ref.At.Before(all[j].ref.At) })
+			for _, k := range all[:drop] {
+				s.index[k.key] = removeRef(s.index[k.key], k.ref)
+				if len(s.index[k.key]) == 0 {
+					delete(s.index, k.key)
+				}
+			}
+			evicted += drop
+		}
+	}
+
+	if evicted > 0 {
+		s.evictedRefs += evicted
+		s.markMsgDirty()
+	}
+	return evicted
+}
+
+func removeRef(refs []MessageRef, ref MessageRef) []MessageRef {
+	for i, r := range refs {
+		if r == ref {
+			return append(refs[:i], refs[i+1:]...)
+		}
+	}
+	return refs
+}
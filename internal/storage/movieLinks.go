@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ExternalLink is a page about a movie on another site.
+type ExternalLink struct {
+	Name string
+	URL  string
+}
+
+// MovieLinks builds a movie's external links from its stored IDs: IMDb and
+// TMDB when the IDs are known, Letterboxd by ID (or a search without one),
+// and trailer and JustWatch searches by title.
+func MovieLinks(m Movie) []ExternalLink {
+	title := m.Title
+	if m.Year > 0 {
+		title = fmt.Sprintf("%s %d", m.Title, m.Year)
+	}
+
+	var links []ExternalLink
+	if m.ImdbID != "" {
+		links = append(links, ExternalLink{"IMDb", "https://www.imdb.com/title/" + m.ImdbID + "/"})
+	}
+	if m.TmdbID > 0 {
+		links = append(links, ExternalLink{"TMDB", fmt.Sprintf("https://www.themoviedb.org/movie/%d", m.TmdbID)})
+	}
+	switch {
+	case m.ImdbID != "":
+		links = append(links, ExternalLink{"Letterboxd", "https://letterboxd.com/imdb/" + m.ImdbID + "/"})
+	case m.TmdbID > 0:
+		links = append(links, ExternalLink{"Letterboxd", fmt.Sprintf("https://letterboxd.com/tmdb/%d/", m.TmdbID)})
+	default:
+		links = append(links, ExternalLink{"Letterboxd", "https://letterboxd.com/search/films/" + url.PathEscape(m.Title) + "/"})
+	}
+	links = append(links,
+		ExternalLink{"Trailer", "https://www.youtube.com/results?search_query=" + url.QueryEscape(title+" trailer")},
+		ExternalLink{"JustWatch", "https://www.justwatch.com/us/search?q=" + url.QueryEscape(m.Title)},
+	)
+	return links
+}
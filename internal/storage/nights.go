@@ -0,0 +1,288 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- MOVIE NIGHTS --------------------
+//
+
+// Night is a scheduled viewing of one movie in one chat. Nights live in
+// nights.json next to the movies file.
+type Night struct {
+	ID        string    `json:"id"`
+	ChatID    int64     `json:"chat_id"`
+	MovieID   string    `json:"movie_id"`
+	Title     string    `json:"title"`
+	At        time.Time `json:"at"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	WatchLink string    `json:"watch_link,omitempty"` // watch-together URL for remote members
+
+	// Attended holds who marked the movie watched around the night, and
+	// when; see Store.Attendance.
+	Attended map[string]time.Time `json:"attended,omitempty"`
+
+	// RSVP holds who answered the announcement card, user ID -> one of
+	// RSVPGoing, RSVPMaybe or RSVPNo. MessageID is that card.
+	RSVP      map[string]string `json:"rsvp,omitempty"`
+	MessageID int               `json:"message_id,omitempty"`
+
+	// Reminded is the shortest lead time a reminder went out for, 0 before
+	// the first one; see Store.MarkReminded.
+	Reminded time.Duration `json:"reminded,omitempty"`
+
+	Recap *Recap `json:"recap,omitempty"` // set once the night is marked complete
+}
+
+// Recap is the minutes of a completed movie night, kept with the night for
+// the year in review.
+type Recap struct {
+	CompletedAt time.Time `json:"completed_at"`
+	CompletedBy string    `json:"completed_by"`
+	Attendees   []string  `json:"attendees,omitempty"` // user IDs
+	Rating      float64   `json:"rating,omitempty"`    // group average at completion
+	Ratings     int       `json:"ratings,omitempty"`
+	Quote       string    `json:"quote,omitempty"`
+	MessageID   int       `json:"message_id,omitempty"` // recap message, replies to it set the quote
+}
+
+// Answers to a movie night's announcement.
+const (
+	RSVPGoing = "going"
+	RSVPMaybe = "maybe"
+	RSVPNo    = "no"
+)
+
+// AddNight stores a new movie night and returns it with its ID set.
+func (s *Store) AddNight(ctx context.Context, n Night) Night {
+	s.nightMu.Lock()
+	defer s.nightMu.Unlock()
+
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	h := sha1.Sum([]byte(fmt.Sprintf("%d|%s|%d", n.ChatID, n.MovieID, n.CreatedAt.UnixNano())))
+	n.ID = hex.EncodeToString(h[:])[:12]
+
+	s.nights = append(s.nights, n)
+	s.nightsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Scheduled %s for %s in chat %d [%s]", n.Title, n.At.Format(time.RFC3339), n.ChatID, n.ID)
+	return n
+}
+
+// GetNight returns the night with the given ID.
+func (s *Store) GetNight(id string) (Night, bool) {
+	s.nightMu.RLock()
+	defer s.nightMu.RUnlock()
+
+	for _, n := range s.nights {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return Night{}, false
+}
+
+// Nights returns all nights of a chat, past ones included, soonest first.
+func (s *Store) Nights(chatID int64) []Night {
+	s.nightMu.RLock()
+	defer s.nightMu.RUnlock()
+
+	var out []Night
+	for _, n := range s.nights {
+		if n.ChatID == chatID {
+			out = append(out, n)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out
+}
+
+// UpcomingNights returns the chat's nights that start after now, soonest
+// first.
+func (s *Store) UpcomingNights(chatID int64, now time.Time) []Night {
+	s.nightMu.RLock()
+	defer s.nightMu.RUnlock()
+
+	var out []Night
+	for _, n := range s.nights {
+		if n.ChatID == chatID && n.At.After(now) {
+			out = append(out, n)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out
+}
+
+// PendingNights returns every chat's nights that start after now and were
+// not wrapped up, soonest first.
+func (s *Store) PendingNights(now time.Time) []Night {
+	s.nightMu.RLock()
+	defer s.nightMu.RUnlock()
+
+	var out []Night
+	for _, n := range s.nights {
+		if n.At.After(now) && n.Recap == nil {
+			out = append(out, n)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out
+}
+
+// MarkReminded records a reminder sent lead before a night. It reports
+// false when one for that lead or a shorter one went out already, so each
+// reminder is sent once even across restarts.
+func (s *Store) MarkReminded(ctx context.Context, id string, lead time.Duration) bool {
+	s.nightMu.Lock()
+	defer s.nightMu.Unlock()
+
+	n := s.nightByIDLocked(id)
+	if n == nil || (n.Reminded > 0 && n.Reminded <= lead) {
+		return false
+	}
+	n.Reminded = lead
+	s.nightsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Reminder %s before night %s", lead, n.ID)
+	return true
+}
+
+// CompleteNight marks a night complete with its recap.
+func (s *Store) CompleteNight(ctx context.Context, id string, r Recap) (Night, error) {
+	s.nightMu.Lock()
+	defer s.nightMu.Unlock()
+
+	n := s.nightByIDLocked(id)
+	if n == nil {
+		return Night{}, fmt.Errorf("night not found")
+	}
+	if n.Recap != nil {
+		return Night{}, fmt.Errorf("%s was already wrapped up", n.Title)
+	}
+	if r.CompletedAt.IsZero() {
+		r.CompletedAt = time.Now()
+	}
+	n.Recap = &r
+	s.nightsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Completed night %s (%s), %d attendees", n.ID, n.Title, len(r.Attendees))
+	return *n, nil
+}
+
+// SetNightMessage remembers which message announces a night.
+func (s *Store) SetNightMessage(ctx context.Context, id string, messageID int) {
+	s.nightMu.Lock()
+	defer s.nightMu.Unlock()
+
+	if n := s.nightByIDLocked(id); n != nil {
+		n.MessageID = messageID
+		s.nightsFile.markDirty()
+	}
+}
+
+// SetRSVP records a user's answer to a night's announcement. Giving the
+// same answer again takes it back.
+func (s *Store) SetRSVP(ctx context.Context, id, userID, answer string) (Night, error) {
+	switch answer {
+	case RSVPGoing, RSVPMaybe, RSVPNo:
+	default:
+		return Night{}, fmt.Errorf("unknown answer %q", answer)
+	}
+
+	s.nightMu.Lock()
+	defer s.nightMu.Unlock()
+
+	n := s.nightByIDLocked(id)
+	if n == nil {
+		return Night{}, fmt.Errorf("night not found")
+	}
+	if n.Recap != nil {
+		return Night{}, fmt.Errorf("%s was already wrapped up", n.Title)
+	}
+
+	rsvp := maps.Clone(n.RSVP) // copies handed out share the old map
+	if rsvp == nil {
+		rsvp = make(map[string]string)
+	}
+	if rsvp[userID] == answer {
+		delete(rsvp, userID)
+	} else {
+		rsvp[userID] = answer
+	}
+	n.RSVP = rsvp
+	s.nightsFile.markDirty()
+	trace.Logf(ctx, "[STORE] User %s answers %q to night %s", userID, rsvp[userID], n.ID)
+	return *n, nil
+}
+
+// SetRecapMessage remembers which message shows a night's recap.
+func (s *Store) SetRecapMessage(ctx context.Context, id string, messageID int) {
+	s.nightMu.Lock()
+	defer s.nightMu.Unlock()
+
+	if n := s.nightByIDLocked(id); n != nil && n.Recap != nil {
+		r := *n.Recap // copies handed out share the old one
+		r.MessageID = messageID
+		n.Recap = &r
+		s.nightsFile.markDirty()
+	}
+}
+
+// SetRecapQuote stores the best quote of a completed night.
+func (s *Store) SetRecapQuote(ctx context.Context, id, quote string) (Night, error) {
+	s.nightMu.Lock()
+	defer s.nightMu.Unlock()
+
+	n := s.nightByIDLocked(id)
+	if n == nil || n.Recap == nil {
+		return Night{}, fmt.Errorf("night not completed")
+	}
+	r := *n.Recap
+	r.Quote = quote
+	n.Recap = &r
+	s.nightsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Quote of night %s set", n.ID)
+	return *n, nil
+}
+
+// NightByRecapMessage finds the night whose recap is the given message.
+func (s *Store) NightByRecapMessage(chatID int64, messageID int) (Night, bool) {
+	s.nightMu.RLock()
+	defer s.nightMu.RUnlock()
+
+	for _, n := range s.nights {
+		if n.ChatID == chatID && n.Recap != nil && n.Recap.MessageID == messageID {
+			return n, true
+		}
+	}
+	return Night{}, false
+}
+
+// Recaps returns the chat's completed nights of a year, oldest first.
+func (s *Store) Recaps(chatID int64, year int) []Night {
+	var out []Night
+	for _, n := range s.Nights(chatID) {
+		if n.Recap != nil && n.At.Year() == year {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func (s *Store) nightByIDLocked(id string) *Night {
+	for i := range s.nights {
+		if s.nights[i].ID == id {
+			return &s.nights[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"moviebot/internal/alerts"
+)
+
+//
+// -------------------- PERSISTENCE --------------------
+//
+
+// Durability is how hard the store works to get changes onto disk.
+type Durability string
+
+const (
+	// DurabilityDefault writes changed files after the save delay and
+	// leaves syncing to the OS: a crash can lose the last few seconds.
+	DurabilityDefault Durability = ""
+	// DurabilityFsync batches like the default but fsyncs each file and
+	// replaces it atomically, so a crash never leaves a torn file.
+	DurabilityFsync Durability = "fsync"
+	// DurabilityStrict writes and fsyncs the file before a mutation
+	// returns. Safest, and slowest on busy chats.
+	DurabilityStrict Durability = "fsync-per-mutation"
+)
+
+// ParseDurability validates a configured durability level.
+func ParseDurability(s string) (Durability, bool) {
+	switch d := Durability(s); d {
+	case DurabilityDefault, DurabilityFsync, DurabilityStrict:
+		return d, true
+	}
+	return DurabilityDefault, false
+}
+
+// persister saves every data file of the store (movies, message index and
+// the sidecars next to them) on one debounce timer, so a burst of changes
+// across files ends up in a single batch of writes.
+type persister struct {
+	delay time.Duration
+
+	mu         sync.Mutex
+	durability Durability
+	alerts     *alerts.Alerter
+	timer      *time.Timer
+	files      []*dataFile
+}
+
+// dataFile is one JSON file managed by a persister. lock guards the data
+// value returns; it is read-locked while the file is encoded.
+type dataFile struct {
+	p     *persister
+	path  string
+	lock  sync.Locker
+	value func() any
+	dirty bool // guarded by p.mu
+}
+
+func newPersister(delay time.Duration) *persister {
+	return &persister{delay: delay}
+}
+
+// file registers a data file at path.
+func (p *persister) file(path string, lock sync.Locker, value func() any) *dataFile {
+	f := &dataFile{p: p, path: path, lock: lock, value: value}
+	p.files = append(p.files, f)
+	return f
+}
+
+// sidecar registers a file kept next to movies.json (nights, users, ...).
+func (p *persister) sidecar(moviesPath, name string, lock sync.Locker, value func() any) *dataFile {
+	return p.file(filepath.Join(filepath.Dir(moviesPath), name), lock, value)
+}
+
+func (p *persister) setDurability(d Durability) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.durability = d
+}
+
+func (p *persister) setAlerts(a *alerts.Alerter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.alerts = a
+}
+
+// load decodes the file into v; a missing file leaves v untouched.
+func (f *dataFile) load(v any) {
+	data, err := os.ReadFile(f.path)
+	if err == nil && len(data) > 0 {
+		if err := json.Unmarshal(data, v); err != nil {
+			log.Printf("[STORE] Failed to parse %s: %v", filepath.Base(f.path), err)
+		}
+	}
+}
+
+// markDirty records a change. Callers hold f.lock (usually for writing), so
+// in strict mode the file is encoded right here, before the lock is given
+// up; otherwise the write is left to the next batch.
+func (f *dataFile) markDirty() {
+	p := f.p
+	p.mu.Lock()
+	strict := p.durability == DurabilityStrict
+	p.mu.Unlock()
+
+	if strict {
+		if f.write(f.value(), true) {
+			return
+		}
+		// Fall through: retry with the next batch.
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f.dirty = true
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.timer = time.AfterFunc(p.delay, p.flush)
+}
+
+// flush writes every dirty file.
+func (p *persister) flush() {
+	p.mu.Lock()
+	var dirty []*dataFile
+	for _, f := range p.files {
+		if f.dirty {
+			dirty = append(dirty, f)
+			f.dirty = false
+		}
+	}
+	fsync := p.durability != DurabilityDefault
+	p.mu.Unlock()
+
+	for _, f := range dirty {
+		f.lock.Lock()
+		ok := f.write(f.value(), fsync)
+		f.lock.Unlock()
+
+		if !ok {
+			p.mu.Lock()
+			f.dirty = true
+			p.mu.Unlock()
+		}
+	}
+}
+
+// write encodes v into the file. With fsync it goes through a temp file
+// that is fsynced and renamed over the old one.
+func (f *dataFile) write(v any, fsync bool) bool {
+	start := time.Now()
+	name := filepath.Base(f.path)
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Printf("[STORE] Failed to marshal %s: %v", name, err)
+		return false
+	}
+
+	if !fsync {
+		err = os.WriteFile(f.path, data, 0644)
+	} else {
+		err = writeFileSync(f.path, data)
+	}
+	if err != nil {
+		log.Printf("[STORE] Failed to write %s: %v", name, err)
+		f.p.mu.Lock()
+		a := f.p.alerts
+		f.p.mu.Unlock()
+		a.Failure("Storage writes", fmt.Errorf("%s: %w", name, err))
+		return false
+	}
+
+	log.Printf("[STORE] Saved %s in %v", name, time.Since(start))
+	return true
+}
+
+func writeFileSync(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Make the rename itself durable.
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- POLLS --------------------
+//
+
+// Poll is a native Telegram poll over some movies. Option i stands for
+// MovieIDs[i]; answers are merged into the movies' votes.
+type Poll struct {
+	ID        string           `json:"id"` // Telegram's poll ID
+	ChatID    int64            `json:"chat_id"`
+	MessageID int              `json:"message_id"`
+	MovieIDs  []string         `json:"movie_ids"`
+	Answers   map[string][]int `json:"answers,omitempty"` // user ID -> options picked
+	CreatedAt time.Time        `json:"created_at"`
+	Closed    bool             `json:"closed,omitempty"`
+}
+
+// AddPoll starts tracking a poll the bot sent.
+func (s *Store) AddPoll(ctx context.Context, p Poll) {
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+
+	s.polls = append(s.polls, p)
+	s.pollsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Poll %s in chat %d over %d movies", p.ID, p.ChatID, len(p.MovieIDs))
+}
+
+// OpenPoll returns the chat's latest poll that is still open.
+func (s *Store) OpenPoll(chatID int64) (Poll, bool) {
+	s.pollMu.RLock()
+	defer s.pollMu.RUnlock()
+
+	for i := len(s.polls) - 1; i >= 0; i-- {
+		if p := s.polls[i]; p.ChatID == chatID && !p.Closed {
+			return p, true
+		}
+	}
+	return Poll{}, false
+}
+
+// ClosePoll marks a poll closed; later answers are ignored.
+func (s *Store) ClosePoll(ctx context.Context, pollID string) {
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+
+	for i := range s.polls {
+		if s.polls[i].ID == pollID && !s.polls[i].Closed {
+			s.polls[i].Closed = true
+			s.pollsFile.markDirty()
+			trace.Logf(ctx, "[STORE] Poll %s closed", pollID)
+		}
+	}
+}
+
+// RecordPollAnswer stores a user's (new) answer to an open poll and brings
+// the votes in line: options picked now get the user's vote, options they
+// dropped lose it. Votes on movies outside the poll are left alone. It
+// returns the movies whose votes changed.
+func (s *Store) RecordPollAnswer(ctx context.Context, pollID, userID string, options []int) ([]Movie, error) {
+	s.pollMu.Lock()
+	i := slices.IndexFunc(s.polls, func(p Poll) bool { return p.ID == pollID })
+	if i < 0 || s.polls[i].Closed {
+		s.pollMu.Unlock()
+		return nil, fmt.Errorf("poll %s is not open", pollID)
+	}
+	p := &s.polls[i]
+	before := p.Answers[userID]
+	if p.Answers == nil {
+		p.Answers = make(map[string][]int)
+	}
+	if len(options) == 0 {
+		delete(p.Answers, userID)
+	} else {
+		p.Answers[userID] = options
+	}
+	movieIDs := p.MovieIDs
+	s.pollsFile.markDirty()
+	s.pollMu.Unlock()
+
+	var changed []Movie
+	for opt, id := range movieIDs {
+		now, was := slices.Contains(options, opt), slices.Contains(before, opt)
+		if now == was {
+			continue
+		}
+		if m, ok := s.SetVote(ctx, id, userID, now); ok {
+			changed = append(changed, m)
+		}
+	}
+	return changed, nil
+}
+
+// SetVote gives or takes a user's vote, reporting whether it changed.
+// Unlike ToggleVoteByID it leaves a vote that is already as asked alone;
+// movies that aren't out yet get none.
+func (s *Store) SetVote(ctx context.Context, movieID, userID string, on bool) (Movie, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOfID(movieID)
+	if i < 0 || s.movies[i].Votes[userID] == on || (on && s.movies[i].Upcoming) {
+		return Movie{}, false
+	}
+	if on {
+		if s.movies[i].Votes == nil {
+			s.movies[i].Votes = make(map[string]bool)
+		}
+		s.movies[i].Votes[userID] = true
+		trace.Logf(ctx, "[STORE] User %s voted for %s", userID, s.movies[i].Title)
+	} else {
+		delete(s.movies[i].Votes, userID)
+		delete(s.movies[i].Reactions, userID)
+		trace.Logf(ctx, "[STORE] User %s took back their vote for %s", userID, s.movies[i].Title)
+	}
+	s.markDirty()
+	return s.movies[i].clone(), true
+}
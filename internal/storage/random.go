@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+//
+// -------------------- RANDOM PICK --------------------
+//
+
+// PickWeighted draws one unwatched movie at random, each weighted by its
+// score plus one so movies nobody voted for yet still get a chance. Movies
+// in skip (e.g. the one just rerolled) and ones not out yet are left out.
+// It reports false when nothing is left to draw.
+func PickWeighted(movies []Movie, skip ...string) (Movie, bool) {
+	var pool []Movie
+	total := 0.0
+	for _, m := range movies {
+		if IsWatched(m) || m.Upcoming || slices.Contains(skip, m.ID) {
+			continue
+		}
+		pool = append(pool, m)
+		total += pickWeight(m)
+	}
+	if len(pool) == 0 {
+		return Movie{}, false
+	}
+
+	n := rand.Float64() * total
+	for _, m := range pool {
+		n -= pickWeight(m)
+		if n < 0 {
+			return m, true
+		}
+	}
+	return pool[len(pool)-1], true
+}
+
+// pickWeight is a movie's chance in PickWeighted; reactions worth less than
+// nothing don't take its chance away.
+func pickWeight(m Movie) float64 {
+	return max(Score(m), 0) + 1
+}
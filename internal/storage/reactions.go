@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- REACTION VOTES --------------------
+//
+
+// VoteOption is one way to react to a movie instead of a plain vote, e.g.
+// 😍 "must watch" worth 2, 🙂 "fine" worth 1 and 😴 "pass" worth 0. A
+// reaction worth more than nothing also counts as the user's vote.
+type VoteOption struct {
+	Emoji  string
+	Label  string
+	Weight float64
+}
+
+// voteOptions are the reactions on offer, none for plain votes. They are
+// set once at startup.
+var voteOptions []VoteOption
+
+// SetVoteOptions turns on reaction voting with opts; none keeps plain votes.
+// Call it before the store is used.
+func SetVoteOptions(opts []VoteOption) {
+	voteOptions = slices.DeleteFunc(slices.Clone(opts), func(o VoteOption) bool { return o.Emoji == "" })
+}
+
+// VoteOptions returns the reactions on offer, none with plain votes.
+func VoteOptions() []VoteOption {
+	return voteOptions
+}
+
+// VoteOptionOf returns the option of emoji.
+func VoteOptionOf(emoji string) (VoteOption, bool) {
+	i := slices.IndexFunc(voteOptions, func(o VoteOption) bool { return o.Emoji == emoji })
+	if i < 0 {
+		return VoteOption{}, false
+	}
+	return voteOptions[i], true
+}
+
+// Score is the movie's weighted votes: each reaction's weight, and 1 for a
+// plain vote. Without reactions it is the vote count.
+func Score(m Movie) float64 {
+	var score float64
+	for userID, emoji := range m.Reactions {
+		if o, ok := VoteOptionOf(emoji); ok {
+			score += o.Weight
+		} else if m.Votes[userID] {
+			score++ // an option since taken out of the config
+		}
+	}
+	for userID := range m.Votes {
+		if _, reacted := m.Reactions[userID]; !reacted {
+			score++
+		}
+	}
+	return score
+}
+
+// ReactionCounts returns how many users gave each reaction.
+func ReactionCounts(m Movie) map[string]int {
+	counts := make(map[string]int, len(voteOptions))
+	for _, emoji := range m.Reactions {
+		counts[emoji]++
+	}
+	return counts
+}
+
+// SetReaction records a user's reaction to a movie; giving the same one
+// again takes it back. The user's vote follows: on for a reaction worth
+// more than nothing, off otherwise.
+func (s *Store) SetReaction(ctx context.Context, movieID, userID, emoji string) (Movie, error) {
+	o, ok := VoteOptionOf(emoji)
+	if !ok {
+		return Movie{}, fmt.Errorf("unknown reaction %q", emoji)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.indexOfID(movieID)
+	if i < 0 {
+		return Movie{}, fmt.Errorf("movie not found")
+	}
+	m := &s.movies[i]
+	if m.Upcoming && m.Reactions[userID] != emoji {
+		return m.clone(), fmt.Errorf("%s isn't out yet, voting opens on its release", m.Title)
+	}
+	if m.Reactions == nil {
+		m.Reactions = make(map[string]string)
+	}
+	if m.Votes == nil {
+		m.Votes = make(map[string]bool)
+	}
+	if m.Reactions[userID] == emoji {
+		delete(m.Reactions, userID)
+		delete(m.Votes, userID)
+		trace.Logf(ctx, "[STORE] User %s took back %s on %s", userID, emoji, m.Title)
+	} else {
+		m.Reactions[userID] = emoji
+		if o.Weight > 0 {
+			m.Votes[userID] = true
+		} else {
+			delete(m.Votes, userID)
+		}
+		trace.Logf(ctx, "[STORE] User %s reacted %s to %s", userID, emoji, m.Title)
+	}
+	s.markDirty()
+	return m.clone(), nil
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- RELEASES --------------------
+//
+
+// ReleaseDays is how many days until an upcoming movie comes out, at least
+// one: a movie is out from the start of its release day (UTC).
+func ReleaseDays(m Movie, now time.Time) int {
+	return max(1, int(math.Ceil(m.Released.Sub(now).Hours()/24)))
+}
+
+// UpcomingMovies returns the movies waiting for their release, in every
+// library.
+func (s *Store) UpcomingMovies() []Movie {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []Movie
+	for _, m := range s.movies {
+		if m.Upcoming {
+			out = append(out, m.clone())
+		}
+	}
+	return out
+}
+
+// MarkReleased opens voting on an upcoming movie. It reports false when
+// the movie is gone or was marked already, so each release is announced
+// once.
+func (s *Store) MarkReleased(ctx context.Context, movieID string) (Movie, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOfID(movieID)
+	if i < 0 || !s.movies[i].Upcoming {
+		return Movie{}, false
+	}
+	s.movies[i].Upcoming = false
+	trace.Logf(ctx, "[STORE] %s is out, voting is open", s.movies[i].Title)
+	s.markDirty()
+	return s.movies[i].clone(), true
+}
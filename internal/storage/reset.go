@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- CHAT RESET --------------------
+//
+
+// ResetReport counts what ResetChat wiped.
+type ResetReport struct {
+	Movies int
+	Refs   int
+	Nights int
+}
+
+// ResetChat wipes a chat for a fresh start: its settings and blocklist, its
+// nights, polls, elections, voting rounds and tonight polls and every
+// tracked message in it. The movies of the chat's library are only cleared too when withMovies
+// is set; the caller decides whether the chat has the library to itself.
+// Take a Backup first.
+func (s *Store) ResetChat(ctx context.Context, chatID, library int64, withMovies bool) ResetReport {
+	var r ResetReport
+
+	removed := make(map[string]bool)
+	if withMovies {
+		s.mu.Lock()
+		kept := s.movies[:0]
+		for _, m := range s.movies {
+			if m.ChatID == library {
+				removed[m.ID] = true
+				continue
+			}
+			kept = append(kept, m)
+		}
+		r.Movies = len(removed)
+		s.movies = kept
+		s.markDirty()
+		s.mu.Unlock()
+	}
+
+	s.msgMu.Lock()
+	for key, refs := range s.index {
+		kept := refs[:0]
+		for _, ref := range refs {
+			if ref.ChatID == chatID {
+				r.Refs++
+				continue
+			}
+			kept = append(kept, ref)
+		}
+		if len(kept) == 0 || removed[key] {
+			delete(s.index, key)
+		} else {
+			s.index[key] = kept
+		}
+	}
+	s.markMsgDirty()
+	s.msgMu.Unlock()
+
+	s.nightMu.Lock()
+	kept := s.nights[:0]
+	for _, n := range s.nights {
+		if n.ChatID == chatID {
+			r.Nights++
+			continue
+		}
+		kept = append(kept, n)
+	}
+	s.nights = kept
+	s.nightsFile.markDirty()
+	s.nightMu.Unlock()
+
+	s.pollMu.Lock()
+	polls := s.polls[:0]
+	for _, p := range s.polls {
+		if p.ChatID != chatID {
+			polls = append(polls, p)
+		}
+	}
+	s.polls = polls
+	s.pollsFile.markDirty()
+	s.pollMu.Unlock()
+
+	s.electionMu.Lock()
+	elections := s.elections[:0]
+	for _, e := range s.elections {
+		if e.ChatID != chatID {
+			elections = append(elections, e)
+		}
+	}
+	s.elections = elections
+	s.electionsFile.markDirty()
+	s.electionMu.Unlock()
+
+	s.roundMu.Lock()
+	rounds := s.rounds[:0]
+	for _, vr := range s.rounds {
+		if vr.ChatID != chatID {
+			rounds = append(rounds, vr)
+		}
+	}
+	s.rounds = rounds
+	s.roundsFile.markDirty()
+	s.roundMu.Unlock()
+
+	s.tonightMu.Lock()
+	tonight := s.tonight[:0]
+	for _, p := range s.tonight {
+		if p.ChatID != chatID {
+			tonight = append(tonight, p)
+		}
+	}
+	s.tonight = tonight
+	s.tonightFile.markDirty()
+	s.tonightMu.Unlock()
+
+	s.chatMu.Lock()
+	if c, ok := s.chats[chatID]; ok {
+		c.Settings = nil
+		c.Blocklist = nil
+		c.VoteDeadline, c.VotingClosed = time.Time{}, false
+		s.chats[chatID] = c
+		s.chatsFile.markDirty()
+	}
+	s.chatMu.Unlock()
+
+	trace.Logf(ctx, "[STORE] Reset chat %d: %d movies, %d message refs, %d nights", chatID, r.Movies, r.Refs, r.Nights)
+	return r
+}
@@ -1,14 +1,20 @@
 package storage
 
 import (
+	"context"
 	"crypto/sha1"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 	"time"
+
+	"moviebot/internal/alerts"
+	"moviebot/internal/trace"
 )
 
 //
@@ -16,19 +22,88 @@ import (
 //
 
 type Movie struct {
-	ID      string          `json:"id"`
-	Title   string          `json:"title"`
-	Year    int             `json:"year"`
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Year  int    `json:"year"`
 
-	AddedAt time.Time     `json:"added_at"` 
+	AddedAt time.Time       `json:"added_at"`
 	Votes   map[string]bool `json:"votes"`
 	Watched map[string]bool `json:"watched"`
 	Poster  string          `json:"poster"`
+	ImdbID  string          `json:"imdb_id,omitempty"`
+	List    string          `json:"list,omitempty"`    // named list, "" for the main watchlist
+	ChatID  int64           `json:"chat_id,omitempty"` // library the movie is in, 0 for the shared one
+
+	Runtime     string    `json:"runtime,omitempty"`
+	ImdbRating  string    `json:"imdb_rating,omitempty"`
+	TmdbID      int       `json:"tmdb_id,omitempty"`
+	Collection  string    `json:"collection,omitempty"`
+	RefreshedAt time.Time `json:"refreshed_at,omitzero"`
+	Released    time.Time `json:"released,omitzero"`  // release date, midnight UTC
+	Upcoming    bool      `json:"upcoming,omitempty"` // not out yet, voting opens on Released
+
+	Plot          string            `json:"plot,omitempty"`
+	Genre         string            `json:"genre,omitempty"` // comma separated, as OMDb has it
+	Director      string            `json:"director,omitempty"`
+	Rated         string            `json:"rated,omitempty"`          // MPAA rating, "PG-13"
+	CriticRatings map[string]string `json:"critic_ratings,omitempty"` // outlet -> score, "Rotten Tomatoes" -> "94%"
+	Titles        map[string]string `json:"titles,omitempty"`         // language -> localized title, from TMDB
+	Tags          []string          `json:"tags,omitempty"`           // added with /tag, lowercase
+	NotTags       []string          `json:"not_tags,omitempty"`       // genres taken off with /tag, lowercase
+
+	Rewatch bool      `json:"rewatch,omitempty"` // back on the list after being watched
+	History []Viewing `json:"history,omitempty"` // earlier rounds, oldest first
+
+	WatchedAt map[string]time.Time `json:"watched_at,omitempty"` // userID -> when they marked it watched, for the diary export
+	WatchedOn time.Time            `json:"watched_on,omitzero"`  // when it first counted as watched by the group, for /history
+
+	Ratings     map[string]int    `json:"ratings,omitempty"`   // userID -> 1..10
+	Reactions   map[string]string `json:"reactions,omitempty"` // userID -> emoji of a VoteOption
+	Discussions []Discussion      `json:"discussions,omitempty"`
+
+	Manual   bool     `json:"manual,omitempty"`   // typed in while OMDb was down, details still missing
+	AddedBy  string   `json:"added_by,omitempty"` // user ID of the suggester, as in Votes
+	Notified []string `json:"notified,omitempty"` // milestones the suggester was told about
+}
+
+// Viewing is a closed voting round of a movie that was watched and then put
+// up for a rewatch.
+type Viewing struct {
+	Votes     map[string]bool      `json:"votes"`
+	Watched   map[string]bool      `json:"watched"`
+	WatchedAt map[string]time.Time `json:"watched_at,omitempty"`
+	WatchedOn time.Time            `json:"watched_on,omitzero"`
+	EndedAt   time.Time            `json:"ended_at"`
+}
+
+// Metadata is provider data that can drift after a movie was added and is
+// refreshed periodically. Empty fields are left untouched.
+type Metadata struct {
+	ImdbID        string
+	Poster        string
+	Runtime       string
+	ImdbRating    string
+	TmdbID        int
+	Collection    string
+	Released      time.Time
+	Plot          string
+	Genre         string
+	Director      string
+	Rated         string
+	CriticRatings map[string]string
+	Titles        map[string]string
 }
 
 type MessageRef struct {
-	ChatID    int64 `json:"chat_id"`
-	MessageID int   `json:"message_id"`
+	ChatID    int64     `json:"chat_id"`
+	MessageID int       `json:"message_id"`
+	InlineID  string    `json:"inline_id,omitempty"` // message sent through inline mode, no chat or message ID
+	Page      int       `json:"page,omitempty"`      // page a list message shows, from 0
+	Hash      string    `json:"hash,omitempty"`      // of what a list message last showed, to skip identical edits
+	Pinned    bool      `json:"pinned,omitempty"`    // a chat's pinned list message, never evicted
+	Links     bool      `json:"links,omitempty"`     // a vote card showing its links block
+	Photo     bool      `json:"photo,omitempty"`     // a vote card sent as its poster, the card in the caption
+	At        time.Time `json:"at,omitzero"`         // when it was sent, for age-based eviction
 }
 
 //
@@ -39,35 +114,81 @@ type Store struct {
 	moviesPath string
 	indexPath  string
 
-	saveDelay   time.Duration
 	maxMessages int // max messages per movie/list
 
-	mu       sync.RWMutex
-	msgMu    sync.RWMutex
-	movies   []Movie
-	index    map[string][]MessageRef
-	dirty    bool
-	msgDirty bool
-
-	saveTimer   *time.Timer
-	msgSaveTimer *time.Timer
-	timerMu     sync.Mutex
-	msgTimerMu  sync.Mutex
+	attendanceWindow time.Duration // how close to a night a watched mark must be
+	perChat          bool          // chats have their own libraries, see LibraryOf
+	private          bool          // private chats have their own libraries, see LibraryOf
+
+	maxIndexRefs int           // max refs in the whole index, 0 = unlimited
+	maxIndexAge  time.Duration // refs older than this are evicted, 0 = never
+	lastAgePrune time.Time
+	evictedRefs  int
+
+	persist    *persister
+	mu         sync.RWMutex
+	movies     []Movie
+	moviesFile *dataFile
+	msgMu      sync.RWMutex
+	index      map[string][]MessageRef
+	indexFile  *dataFile
+
+	nightMu    sync.RWMutex
+	nights     []Night
+	nightsFile *dataFile
+
+	userMu    sync.RWMutex
+	users     map[string]User
+	usersFile *dataFile
+
+	chatMu    sync.RWMutex
+	chats     map[int64]Chat
+	chatsFile *dataFile
+
+	pollMu    sync.RWMutex
+	polls     []Poll
+	pollsFile *dataFile
+
+	electionMu    sync.RWMutex
+	elections     []Election
+	electionsFile *dataFile
+
+	roundMu    sync.RWMutex
+	rounds     []VotingRound
+	roundsFile *dataFile
+
+	tonightMu   sync.RWMutex
+	tonight     []TonightPoll
+	tonightFile *dataFile
 }
 
 //
 // -------------------- INITIALIZATION --------------------
 //
 
-// NewStore creates a store and loads everything into memory.
+// NewStore creates a store and loads everything into memory. Changes are
+// written saveDelay after the last one, see SetDurability.
 func NewStore(moviesPath, indexPath string, saveDelay time.Duration, maxMessages int) *Store {
 	s := &Store{
-		moviesPath: moviesPath,
-		indexPath:  indexPath,
-		saveDelay:  saveDelay,
+		moviesPath:  moviesPath,
+		indexPath:   indexPath,
+		persist:     newPersister(saveDelay),
+		users:       make(map[string]User),
+		chats:       make(map[int64]Chat),
 		maxMessages: maxMessages,
-		index:      make(map[string][]MessageRef),
+		index:       make(map[string][]MessageRef),
+
+		attendanceWindow: DefaultAttendanceWindow,
 	}
+	s.moviesFile = s.persist.file(moviesPath, s.mu.RLocker(), func() any { return s.movies })
+	s.indexFile = s.persist.file(indexPath, s.msgMu.RLocker(), func() any { return s.index })
+	s.nightsFile = s.persist.sidecar(moviesPath, "nights.json", s.nightMu.RLocker(), func() any { return s.nights })
+	s.usersFile = s.persist.sidecar(moviesPath, "users.json", s.userMu.RLocker(), func() any { return s.users })
+	s.chatsFile = s.persist.sidecar(moviesPath, "chats.json", s.chatMu.RLocker(), func() any { return s.chats })
+	s.pollsFile = s.persist.sidecar(moviesPath, "polls.json", s.pollMu.RLocker(), func() any { return s.polls })
+	s.electionsFile = s.persist.sidecar(moviesPath, "elections.json", s.electionMu.RLocker(), func() any { return s.elections })
+	s.roundsFile = s.persist.sidecar(moviesPath, "rounds.json", s.roundMu.RLocker(), func() any { return s.rounds })
+	s.tonightFile = s.persist.sidecar(moviesPath, "tonight.json", s.tonightMu.RLocker(), func() any { return s.tonight })
 
 	log.Printf("[STORE] Initializing store...")
 	s.loadAll()
@@ -75,24 +196,47 @@ func NewStore(moviesPath, indexPath string, saveDelay time.Duration, maxMessages
 	return s
 }
 
+// SetDurability picks how changes reach the disk.
+func (s *Store) SetDurability(d Durability) {
+	s.persist.setDurability(d)
+}
+
+// SetAlerts reports failed writes to a.
+func (s *Store) SetAlerts(a *alerts.Alerter) {
+	s.persist.setAlerts(a)
+}
+
 func (s *Store) loadAll() {
 	start := time.Now()
 
+	var report integrityReport
+
 	// Load movies
 	data, err := os.ReadFile(s.moviesPath)
 	if err == nil && len(data) > 0 {
-		if err := json.Unmarshal(data, &s.movies); err != nil {
+		if err := s.decodeMovies(data, &report); err != nil {
 			log.Printf("[STORE] Failed to parse movies: %v", err)
+			// Keep the unreadable file: the next save would overwrite it.
+			corrupt := fmt.Sprintf("%s.corrupt-%s", s.moviesPath, time.Now().Format("20060102-150405"))
+			if err := os.WriteFile(corrupt, data, 0644); err == nil {
+				log.Printf("[STORE] Unreadable movies file kept as %s", corrupt)
+			}
 		}
 	}
 
 	// Load index
-	idxData, err := os.ReadFile(s.indexPath)
-	if err == nil && len(idxData) > 0 {
-		if err := json.Unmarshal(idxData, &s.index); err != nil {
-			log.Printf("[STORE] Failed to parse index: %v", err)
-		}
-	}
+	s.indexFile.load(&s.index)
+
+	s.checkIntegrity(&report)
+	s.finishIntegrity(report)
+
+	s.nightsFile.load(&s.nights)
+	s.usersFile.load(&s.users)
+	s.chatsFile.load(&s.chats)
+	s.pollsFile.load(&s.polls)
+	s.electionsFile.load(&s.elections)
+	s.roundsFile.load(&s.rounds)
+	s.tonightFile.load(&s.tonight)
 
 	log.Printf("[STORE] Loaded data from disk in %v", time.Since(start))
 }
@@ -101,114 +245,66 @@ func (s *Store) loadAll() {
 // -------------------- BULK SAVE LOGIC --------------------
 //
 
+// markDirty schedules a save of the movies. Callers hold s.mu.
 func (s *Store) markDirty() {
-	s.timerMu.Lock()
-	defer s.timerMu.Unlock()
-
-	s.dirty = true
-	if s.saveTimer != nil {
-		s.saveTimer.Stop()
-	}
-
-	s.saveTimer = time.AfterFunc(s.saveDelay, s.flushMovies)
-}
-
-func (s *Store) flushMovies() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if !s.dirty {
-		return
-	}
-
-	start := time.Now()
-	data, err := json.MarshalIndent(s.movies, "", "  ")
-	if err != nil {
-		log.Printf("[STORE] Failed to marshal movies: %v", err)
-		return
-	}
-
-	if err := os.WriteFile(s.moviesPath, data, 0644); err != nil {
-		log.Printf("[STORE] Failed to write movies: %v", err)
-		return
-	}
-
-	s.dirty = false
-	log.Printf("[STORE] Saved movies in %v", time.Since(start))
+	s.moviesFile.markDirty()
 }
 
+// markMsgDirty schedules a save of the message index. Callers hold s.msgMu.
 func (s *Store) markMsgDirty() {
-	s.msgTimerMu.Lock()
-	defer s.msgTimerMu.Unlock()
-
-	s.msgDirty = true
-	if s.msgSaveTimer != nil {
-		s.msgSaveTimer.Stop()
-	}
-
-	s.msgSaveTimer = time.AfterFunc(s.saveDelay, s.flushMessages)
+	s.indexFile.markDirty()
 }
 
-func (s *Store) flushMessages() {
-	s.msgMu.Lock()
-	defer s.msgMu.Unlock()
-
-	if !s.msgDirty {
-		return
-	}
-
-	start := time.Now()
-	data, err := json.MarshalIndent(s.index, "", "  ")
-	if err != nil {
-		log.Printf("[STORE] Failed to marshal message index: %v", err)
-		return
-	}
-
-	if err := os.WriteFile(s.indexPath, data, 0644); err != nil {
-		log.Printf("[STORE] Failed to write message index: %v", err)
-		return
-	}
-
-	s.msgDirty = false
-	log.Printf("[STORE] Saved message index in %v", time.Since(start))
+// Flush writes any pending changes to disk immediately instead of waiting for
+// the debounce timer. Used by one-shot CLI operations before exiting.
+func (s *Store) Flush() {
+	s.persist.flush()
 }
 
 //
 // -------------------- MOVIE HELPERS --------------------
 //
 
-func generateMovieID(title string, year int) string {
+// generateMovieID derives a stable ID. Movies on the main list keep the
+// original title|year scheme; named lists are namespaced so the same film can
+// sit on several lists with separate votes, and so are chats' own libraries.
+func generateMovieID(chatID int64, list, title string, year int) string {
 	h := sha1.New()
-	h.Write([]byte(fmt.Sprintf("%s|%d", title, year)))
+	if chatID != 0 {
+		fmt.Fprintf(h, "%d|", chatID)
+	}
+	if list == "" {
+		h.Write([]byte(fmt.Sprintf("%s|%d", title, year)))
+	} else {
+		h.Write([]byte(fmt.Sprintf("%s|%s|%d", list, title, year)))
+	}
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-func (s *Store) NotifyNewMovie(title string, year int, poster string) string {
+// NotifyNewMovie adds a movie unless it is already on its list. Only the
+// descriptive fields of m are used (Title, Year, Poster, ImdbID, List,
+// ChatID) and AddedBy; the ID, timestamps and vote maps are set here. It
+// returns the movie's ID and whether it was newly created.
+func (s *Store) NotifyNewMovie(ctx context.Context, m Movie) (string, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for _, m := range s.movies {
-		if m.Title == title && m.Year == year {
-			log.Printf("[STORE] Movie already exists: %s (%d)", title, year)
-			return m.ID
+	for _, existing := range s.movies {
+		if existing.ChatID == m.ChatID && existing.List == m.List && existing.Title == m.Title && existing.Year == m.Year {
+			trace.Logf(ctx, "[STORE] Movie already exists: %s (%d)", m.Title, m.Year)
+			return existing.ID, false
 		}
 	}
 
-	id := generateMovieID(title, year)
-	m := Movie{
-		ID:      id,
-		Title:   title,
-		Year:    year,
-		AddedAt: time.Now(),
-		Poster:  poster,
-		Votes:   make(map[string]bool),
-		Watched: make(map[string]bool),
-	}
+	m.ID = generateMovieID(m.ChatID, m.List, m.Title, m.Year)
+	m.AddedAt = time.Now()
+	m.Votes = make(map[string]bool)
+	m.Watched = make(map[string]bool)
 
 	s.movies = append(s.movies, m)
-	log.Printf("[STORE] Added movie: %s (%d) [%s]", title, year, id)
+	trace.Logf(ctx, "[STORE] Added movie: %s (%d) [%s] list=%q chat=%d", m.Title, m.Year, m.ID, m.List, m.ChatID)
 	s.markDirty()
-	return id
+	return m.ID, true
 }
 
 func (s *Store) GetMovieByID(id string) (Movie, bool) {
@@ -216,13 +312,13 @@ func (s *Store) GetMovieByID(id string) (Movie, bool) {
 	defer s.mu.RUnlock()
 	for _, m := range s.movies {
 		if m.ID == id {
-			return m, true
+			return m.clone(), true
 		}
 	}
 	return Movie{}, false
 }
 
-func (s *Store) ToggleVoteByID(movieID, userID string) (Movie, error) {
+func (s *Store) ToggleVoteByID(ctx context.Context, movieID, userID string) (Movie, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	for i := range s.movies {
@@ -230,46 +326,556 @@ func (s *Store) ToggleVoteByID(movieID, userID string) (Movie, error) {
 			if s.movies[i].Votes == nil {
 				s.movies[i].Votes = make(map[string]bool)
 			}
+			if s.movies[i].Upcoming && !s.movies[i].Votes[userID] {
+				return s.movies[i].clone(), fmt.Errorf("%s isn't out yet, voting opens on its release", s.movies[i].Title)
+			}
+			delete(s.movies[i].Reactions, userID) // a plain vote replaces a reaction
 			if s.movies[i].Votes[userID] {
 				delete(s.movies[i].Votes, userID)
-				log.Printf("[STORE] User %s removed vote for %s", userID, s.movies[i].Title)
+				trace.Logf(ctx, "[STORE] User %s removed vote for %s", userID, s.movies[i].Title)
 			} else {
 				s.movies[i].Votes[userID] = true
-				log.Printf("[STORE] User %s voted for %s", userID, s.movies[i].Title)
+				trace.Logf(ctx, "[STORE] User %s voted for %s", userID, s.movies[i].Title)
 			}
 			s.markDirty()
-			return s.movies[i], nil
+			return s.movies[i].clone(), nil
 		}
 	}
 	return Movie{}, fmt.Errorf("movie not found")
 }
 
-func (s *Store) ToggleWatchedByID(movieID, userID string) (Movie, error) {
+// ToggleWatchedByID flips a user's watched mark. Marks set around a
+// scheduled night of the movie also count as attending it.
+func (s *Store) ToggleWatchedByID(ctx context.Context, movieID, userID string) (Movie, error) {
+	s.mu.Lock()
+	i := s.indexOfID(movieID)
+	if i < 0 {
+		s.mu.Unlock()
+		return Movie{}, fmt.Errorf("movie not found")
+	}
+	if s.movies[i].Watched == nil {
+		s.movies[i].Watched = make(map[string]bool)
+	}
+	if s.movies[i].Watched[userID] {
+		delete(s.movies[i].Watched, userID)
+		delete(s.movies[i].WatchedAt, userID)
+		trace.Logf(ctx, "[STORE] User %s marked %s as unwatched", userID, s.movies[i].Title)
+	} else {
+		s.movies[i].Watched[userID] = true
+		s.movies[i].setWatchedAt(userID, time.Now())
+		trace.Logf(ctx, "[STORE] User %s marked %s as watched", userID, s.movies[i].Title)
+	}
+	s.movies[i].noteWatchedOn(time.Now())
+	s.markDirty()
+	m := s.movies[i].clone()
+	s.mu.Unlock()
+
+	s.recordAttendance(ctx, movieID, userID, m.Watched[userID], time.Now())
+	return m, nil
+}
+
+// MarkWatched marks a movie watched by a user who watched it elsewhere at
+// the given time, e.g. on a media server. It reports false when they already
+// had, or when the play predates the movie's current rewatch round.
+func (s *Store) MarkWatched(ctx context.Context, movieID, userID string, at time.Time) (Movie, bool) {
+	s.mu.Lock()
+	i := s.indexOfID(movieID)
+	if i < 0 || s.movies[i].Watched[userID] {
+		s.mu.Unlock()
+		return Movie{}, false
+	}
+	if h := s.movies[i].History; len(h) > 0 && at.Before(h[len(h)-1].EndedAt) {
+		s.mu.Unlock()
+		return Movie{}, false
+	}
+	if s.movies[i].Watched == nil {
+		s.movies[i].Watched = make(map[string]bool)
+	}
+	s.movies[i].Watched[userID] = true
+	s.movies[i].setWatchedAt(userID, at)
+	s.movies[i].noteWatchedOn(at)
+	trace.Logf(ctx, "[STORE] User %s watched %s on %v", userID, s.movies[i].Title, at)
+	s.markDirty()
+	m := s.movies[i].clone()
+	s.mu.Unlock()
+
+	s.recordAttendance(ctx, movieID, userID, true, at)
+	return m, true
+}
+
+func (m *Movie) setWatchedAt(userID string, at time.Time) {
+	if m.WatchedAt == nil {
+		m.WatchedAt = make(map[string]time.Time)
+	}
+	m.WatchedAt[userID] = at
+}
+
+// noteWatchedOn keeps WatchedOn in step with IsWatched after a watched mark
+// changed at at: set the first time the movie counts as watched, cleared
+// when it no longer does.
+func (m *Movie) noteWatchedOn(at time.Time) {
+	switch {
+	case !IsWatched(*m):
+		m.WatchedOn = time.Time{}
+	case m.WatchedOn.IsZero():
+		m.WatchedOn = at
+	}
+}
+
+// ReopenForRewatch archives a watched movie's votes and watched marks in its
+// History and puts it back up for voting with a fresh tally.
+func (s *Store) ReopenForRewatch(ctx context.Context, movieID string) (Movie, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	for i := range s.movies {
-		if s.movies[i].ID == movieID {
-			if s.movies[i].Watched == nil {
-				s.movies[i].Watched = make(map[string]bool)
+
+	i := s.indexOfID(movieID)
+	if i < 0 {
+		return Movie{}, fmt.Errorf("movie not found")
+	}
+	m := &s.movies[i]
+	if len(m.Watched) == 0 {
+		return Movie{}, fmt.Errorf("nobody has watched %s yet", m.Title)
+	}
+
+	m.History = append(m.History, Viewing{Votes: m.Votes, Watched: m.Watched, WatchedAt: m.WatchedAt, WatchedOn: m.WatchedOn, EndedAt: time.Now()})
+	m.Votes = make(map[string]bool)
+	m.Watched = make(map[string]bool)
+	m.WatchedAt = nil
+	m.WatchedOn = time.Time{}
+	m.Reactions = nil
+	m.Rewatch = true
+	m.Notified = nil
+	s.markDirty()
+
+	trace.Logf(ctx, "[STORE] %s reopened for rewatch (round %d)", m.Title, len(m.History)+1)
+	return m.clone(), nil
+}
+
+// MarkNotified records that the suggester of a movie was told about a
+// milestone. It reports false if they already were, so each one is sent once.
+func (s *Store) MarkNotified(ctx context.Context, movieID, milestone string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOfID(movieID)
+	if i < 0 || slices.Contains(s.movies[i].Notified, milestone) {
+		return false
+	}
+	s.movies[i].Notified = append(s.movies[i].Notified, milestone)
+	s.markDirty()
+	trace.Logf(ctx, "[STORE] Milestone %s of %s noted", milestone, s.movies[i].Title)
+	return true
+}
+
+// FindMovie resolves a free-text reference (exact or partial title) to
+// exactly one of movies.
+func FindMovie(movies []Movie, ref string) (Movie, error) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if ref == "" {
+		return Movie{}, fmt.Errorf("which movie?")
+	}
+
+	var matches []Movie
+	for _, m := range movies {
+		title := strings.ToLower(m.Title)
+		if title == ref {
+			matches = append(matches, m)
+		}
+	}
+	if len(matches) == 0 {
+		for _, m := range movies {
+			if strings.Contains(strings.ToLower(m.Title), ref) {
+				matches = append(matches, m)
 			}
-			if s.movies[i].Watched[userID] {
-				delete(s.movies[i].Watched, userID)
-				log.Printf("[STORE] User %s marked %s as unwatched", userID, s.movies[i].Title)
-			} else {
-				s.movies[i].Watched[userID] = true
-				log.Printf("[STORE] User %s marked %s as watched", userID, s.movies[i].Title)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Movie{}, fmt.Errorf("no movie matches %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return Movie{}, fmt.Errorf("%d movies match %q, be more specific", len(matches), ref)
+	}
+}
+
+// MoveMovie relocates a movie to another list, keeping votes and history.
+// The movie gets the ID matching its new list; its tracked messages, nights,
+// polls, elections and voting rounds follow it.
+func (s *Store) MoveMovie(ctx context.Context, movieID, toList string) (Movie, error) {
+	s.mu.Lock()
+	i := s.indexOfID(movieID)
+	if i < 0 {
+		s.mu.Unlock()
+		return Movie{}, fmt.Errorf("movie not found")
+	}
+	m := s.movies[i]
+	if m.List == toList {
+		s.mu.Unlock()
+		return Movie{}, fmt.Errorf("%s is already on that list", m.Title)
+	}
+	if s.onList(m.ChatID, toList, m.Title, m.Year) {
+		s.mu.Unlock()
+		return Movie{}, fmt.Errorf("%s is already on that list", m.Title)
+	}
+
+	m.List = toList
+	m.ID = generateMovieID(m.ChatID, toList, m.Title, m.Year)
+	s.movies[i] = m
+	m = m.clone()
+	s.markDirty()
+	s.mu.Unlock()
+
+	s.renameMovieID(movieID, m.ID)
+	trace.Logf(ctx, "[STORE] Moved %s to list %q [%s -> %s]", m.Title, toList, movieID, m.ID)
+	return m, nil
+}
+
+// renameMovieID points everything that refers to a movie by ID at the new
+// ID it got, see renameMovieIDs.
+func (s *Store) renameMovieID(from, to string) {
+	s.renameMovieIDs(map[string]string{from: to})
+}
+
+// renameMovieIDs points everything that refers to a movie by ID (tracked
+// messages, nights, polls, elections, voting rounds and tonight polls) at
+// the ID in to, for movies that got a new one or were merged into another.
+// Ratings, reactions and discussions live on the movie and need nothing.
+func (s *Store) renameMovieIDs(to map[string]string) {
+	// remap returns ids with the renamed ones replaced, in a new slice so
+	// copies handed out earlier stay as they were.
+	remap := func(ids []string, changed *bool) []string {
+		if !slices.ContainsFunc(ids, func(id string) bool { _, ok := to[id]; return ok }) {
+			return ids
+		}
+		out := slices.Clone(ids)
+		for i, id := range out {
+			if n, ok := to[id]; ok {
+				out[i] = n
 			}
-			s.markDirty()
-			return s.movies[i], nil
 		}
+		*changed = true
+		return out
 	}
-	return Movie{}, fmt.Errorf("movie not found")
+	// remapEach does the same for every user's IDs, in a new map.
+	remapEach := func(byUser map[string][]string, changed *bool) map[string][]string {
+		moved := false
+		out := make(map[string][]string, len(byUser))
+		for user, ids := range byUser {
+			out[user] = remap(ids, &moved)
+		}
+		if !moved {
+			return byUser
+		}
+		*changed = true
+		return out
+	}
+
+	for from, id := range to {
+		s.renameMessages(from, id)
+	}
+
+	s.nightMu.Lock()
+	changed := false
+	for i := range s.nights {
+		if n, ok := to[s.nights[i].MovieID]; ok {
+			s.nights[i].MovieID = n
+			changed = true
+		}
+	}
+	if changed {
+		s.nightsFile.markDirty()
+	}
+	s.nightMu.Unlock()
+
+	s.pollMu.Lock()
+	changed = false
+	for i := range s.polls {
+		s.polls[i].MovieIDs = remap(s.polls[i].MovieIDs, &changed)
+	}
+	if changed {
+		s.pollsFile.markDirty()
+	}
+	s.pollMu.Unlock()
+
+	s.electionMu.Lock()
+	changed = false
+	for i := range s.elections {
+		e := &s.elections[i]
+		e.MovieIDs = remap(e.MovieIDs, &changed)
+		e.Ballots = remapEach(e.Ballots, &changed)
+		if n, ok := to[e.Winner]; ok {
+			e.Winner = n
+			changed = true
+		}
+	}
+	if changed {
+		s.electionsFile.markDirty()
+	}
+	s.electionMu.Unlock()
+
+	s.roundMu.Lock()
+	changed = false
+	for i := range s.rounds {
+		vr := &s.rounds[i]
+		vr.MovieIDs = remap(vr.MovieIDs, &changed)
+		vr.Winners = remap(vr.Winners, &changed)
+		vr.Votes = remapEach(vr.Votes, &changed)
+	}
+	if changed {
+		s.roundsFile.markDirty()
+	}
+	s.roundMu.Unlock()
+
+	s.tonightMu.Lock()
+	changed = false
+	for i := range s.tonight {
+		s.tonight[i].MovieIDs = remap(s.tonight[i].MovieIDs, &changed)
+	}
+	if changed {
+		s.tonightFile.markDirty()
+	}
+	s.tonightMu.Unlock()
+}
+
+// CopyMovie puts an independent copy of a movie, votes and history
+// included, on another list.
+func (s *Store) CopyMovie(ctx context.Context, movieID, toList string) (Movie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOfID(movieID)
+	if i < 0 {
+		return Movie{}, fmt.Errorf("movie not found")
+	}
+	m := s.movies[i]
+	if s.onList(m.ChatID, toList, m.Title, m.Year) {
+		return Movie{}, fmt.Errorf("%s is already on that list", m.Title)
+	}
+
+	m = m.clone()
+	m.List = toList
+	m.ID = generateMovieID(m.ChatID, toList, m.Title, m.Year)
+	m.Votes = copySet(m.Votes)
+	m.Watched = copySet(m.Watched)
+
+	s.movies = append(s.movies, m)
+	s.markDirty()
+	trace.Logf(ctx, "[STORE] Copied %s to list %q [%s]", m.Title, toList, m.ID)
+	return m.clone(), nil
+}
+
+// RemoveMovieByID deletes a movie for good and forgets its tracked
+// messages. It returns the removed movie and the refs of its vote cards so
+// the caller can take them down.
+func (s *Store) RemoveMovieByID(ctx context.Context, movieID string) (Movie, []MessageRef, error) {
+	s.mu.Lock()
+	i := s.indexOfID(movieID)
+	if i < 0 {
+		s.mu.Unlock()
+		return Movie{}, nil, fmt.Errorf("movie not found")
+	}
+	m := s.movies[i]
+	s.movies = append(s.movies[:i], s.movies[i+1:]...)
+	s.markDirty()
+	s.mu.Unlock()
+
+	refs := s.DropMessages(ctx, movieID)
+	trace.Logf(ctx, "[STORE] Removed %s (%d) [%s]", m.Title, m.Year, m.ID)
+	return m, refs, nil
+}
+
+func (s *Store) onList(chatID int64, list, title string, year int) bool {
+	for _, m := range s.movies {
+		if m.ChatID == chatID && m.List == list && m.Title == title && m.Year == year {
+			return true
+		}
+	}
+	return false
+}
+
+func copySet(in map[string]bool) map[string]bool {
+	out := make(map[string]bool, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+// clone returns a copy of m that shares no maps or slices with it. Movies
+// leave the store only as clones: the copies are read without the lock while
+// votes and marks keep changing the originals.
+func (m Movie) clone() Movie {
+	m.Votes = maps.Clone(m.Votes)
+	m.Watched = maps.Clone(m.Watched)
+	m.WatchedAt = maps.Clone(m.WatchedAt)
+	m.Ratings = maps.Clone(m.Ratings)
+	m.Reactions = maps.Clone(m.Reactions)
+	m.CriticRatings = maps.Clone(m.CriticRatings)
+	m.Titles = maps.Clone(m.Titles)
+	m.Tags = slices.Clone(m.Tags)
+	m.NotTags = slices.Clone(m.NotTags)
+	m.Notified = slices.Clone(m.Notified)
+	m.Discussions = slices.Clone(m.Discussions)
+	m.History = slices.Clone(m.History)
+	for i, v := range m.History {
+		m.History[i].Votes = maps.Clone(v.Votes)
+		m.History[i].Watched = maps.Clone(v.Watched)
+		m.History[i].WatchedAt = maps.Clone(v.WatchedAt)
+	}
+	return m
+}
+
+// cloneMovies clones every movie of ms, see clone.
+func cloneMovies(ms []Movie) []Movie {
+	out := make([]Movie, len(ms))
+	for i, m := range ms {
+		out[i] = m.clone()
+	}
+	return out
+}
+
+// UpdateMetadata applies refreshed provider data to a movie and stamps
+// RefreshedAt. It reports whether any visible field actually changed.
+func (s *Store) UpdateMetadata(ctx context.Context, movieID string, md Metadata) (Movie, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOfID(movieID)
+	if i < 0 {
+		return Movie{}, false, fmt.Errorf("movie not found")
+	}
+	m := &s.movies[i]
+
+	changed := false
+	set := func(dst *string, v string) {
+		if v != "" && v != "N/A" && *dst != v {
+			*dst = v
+			changed = true
+		}
+	}
+	set(&m.ImdbID, md.ImdbID)
+	if m.Manual && m.ImdbID != "" {
+		m.Manual = false
+		changed = true
+	}
+	set(&m.Poster, md.Poster)
+	set(&m.Runtime, md.Runtime)
+	set(&m.ImdbRating, md.ImdbRating)
+	set(&m.Collection, md.Collection)
+	set(&m.Plot, md.Plot)
+	set(&m.Genre, md.Genre)
+	set(&m.Director, md.Director)
+	set(&m.Rated, md.Rated)
+	if md.TmdbID != 0 && m.TmdbID != md.TmdbID {
+		m.TmdbID = md.TmdbID
+		changed = true
+	}
+	if len(md.CriticRatings) > 0 && !maps.Equal(m.CriticRatings, md.CriticRatings) {
+		m.CriticRatings = maps.Clone(md.CriticRatings)
+		changed = true
+	}
+	if len(md.Titles) > 0 && !maps.Equal(m.Titles, md.Titles) {
+		m.Titles = maps.Clone(md.Titles)
+		changed = true
+	}
+	if !md.Released.IsZero() && !m.Released.Equal(md.Released) {
+		m.Released = md.Released
+		changed = true
+	}
+	// Only the release scheduler clears Upcoming, so the opening of voting
+	// is announced even when a refresh finds the date already passed.
+	if !m.Upcoming && m.Released.After(time.Now()) && !IsWatched(*m) {
+		m.Upcoming = true
+		changed = true
+	}
+
+	m.RefreshedAt = time.Now()
+	if changed {
+		trace.Logf(ctx, "[STORE] Refreshed metadata for %s", m.Title)
+	}
+	s.markDirty()
+	return m.clone(), changed, nil
+}
+
+// GetMovies returns the movies on one list ("" for the main watchlist) of
+// a library (0 for the shared one).
+func (s *Store) GetMovies(library int64, list string) []Movie {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []Movie
+	for _, m := range s.movies {
+		if m.ChatID == library && m.List == list {
+			out = append(out, m.clone())
+		}
+	}
+	return out
+}
+
+// GetLibrary returns every movie of a library, on all its lists.
+func (s *Store) GetLibrary(library int64) []Movie {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []Movie
+	for _, m := range s.movies {
+		if m.ChatID == library {
+			out = append(out, m.clone())
+		}
+	}
+	return out
+}
+
+// ListNames returns every named list of a library with its movie count.
+// The main watchlist is reported under "".
+func (s *Store) ListNames(library int64) map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := map[string]int{"": 0}
+	for _, m := range s.movies {
+		if m.ChatID == library {
+			out[m.List]++
+		}
+	}
+	return out
+}
+
+// AllListNames returns the names of the lists in any library, "" included.
+func (s *Store) AllListNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := []string{""}
+	for _, m := range s.movies {
+		if !slices.Contains(names, m.List) {
+			names = append(names, m.List)
+		}
+	}
+	return names
+}
+
+// NormalizeListName turns user input into a list key: lower case, spaces
+// collapsed to dashes.
+func NormalizeListName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
+
+// ListKey is the message index key under which rendered copies of a list
+// are tracked.
+func ListKey(list string) string {
+	if list == "" {
+		return "list"
+	}
+	return "list:" + list
 }
 
 func (s *Store) GetAllMovies() []Movie {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return append([]Movie(nil), s.movies...)
+	return cloneMovies(s.movies)
 }
 
 //
@@ -278,19 +884,44 @@ func (s *Store) GetAllMovies() []Movie {
 
 // RegisterMessage adds a message ref for a movie or list.
 // Keeps only last `maxMessages` messages per movie.
-func (s *Store) RegisterMessage(movieID string, chatID int64, msgID int) {
+func (s *Store) RegisterMessage(ctx context.Context, movieID string, chatID int64, msgID int) {
 	s.msgMu.Lock()
 	defer s.msgMu.Unlock()
 
-	msgs := append(s.index[movieID], MessageRef{ChatID: chatID, MessageID: msgID})
-	if len(msgs) > s.maxMessages {
-		msgs = msgs[len(msgs)-s.maxMessages:]
+	now := time.Now()
+	msgs := append(s.index[movieID], MessageRef{ChatID: chatID, MessageID: msgID, At: now})
+	for i := 0; len(msgs) > s.maxMessages && i < len(msgs); {
+		if msgs[i].Pinned {
+			i++
+			continue
+		}
+		msgs = append(msgs[:i], msgs[i+1:]...)
+		s.evictedRefs++
 	}
 	s.index[movieID] = msgs
 
-	log.Printf("[STORE] Registered message %d for movie %s (total stored: %d)", msgID, movieID, len(msgs))
+	trace.Logf(ctx, "[STORE] Registered message %d for movie %s (total stored: %d)", msgID, movieID, len(msgs))
 
 	s.markMsgDirty()
+	if s.maxIndexRefs > 0 || (s.maxIndexAge > 0 && now.Sub(s.lastAgePrune) > agePruneInterval) {
+		if n := s.pruneIndexLocked(now); n > 0 {
+			trace.Logf(ctx, "[STORE] Evicted %d message refs over the index limits", n)
+		}
+	}
+}
+
+// renameMessages moves tracked message refs from one key to another.
+func (s *Store) renameMessages(from, to string) {
+	s.msgMu.Lock()
+	defer s.msgMu.Unlock()
+
+	refs, ok := s.index[from]
+	if !ok {
+		return
+	}
+	s.index[to] = append(s.index[to], refs...)
+	delete(s.index, from)
+	s.markMsgDirty()
 }
 
 // GetMessages returns the last N messages for a movie/list.
@@ -312,4 +943,4 @@ func (s *Store) GetAllMessages() map[string][]MessageRef {
 	}
 
 	return out
-}
\ No newline at end of file
+}
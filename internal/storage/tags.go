@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- TAGS --------------------
+//
+
+// NormalizeTag is the stored form of a tag: lowercase, without a leading
+// "#", words joined by "-" ("Science Fiction" -> "science-fiction").
+func NormalizeTag(s string) string {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	return strings.ToLower(strings.Join(strings.Fields(s), "-"))
+}
+
+// MovieTags are the labels a list can be filtered by: the movie's OMDb
+// genres less the ones taken off with /tag, then the ones added by hand.
+func MovieTags(m Movie) []string {
+	var tags []string
+	for _, g := range genres(m) {
+		if !slices.Contains(m.NotTags, g) {
+			tags = append(tags, g)
+		}
+	}
+	for _, t := range m.Tags {
+		if !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// HasTag reports whether tag is one of MovieTags, ignoring case.
+func HasTag(m Movie, tag string) bool {
+	return slices.Contains(MovieTags(m), NormalizeTag(tag))
+}
+
+func genres(m Movie) []string {
+	if m.Genre == "" || m.Genre == "N/A" {
+		return nil
+	}
+	var out []string
+	for _, g := range strings.Split(m.Genre, ",") {
+		if g = NormalizeTag(g); g != "" {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// TagMovie adds and removes tags of a movie. Removing one of its genres
+// hides it from MovieTags; adding it back undoes that.
+func (s *Store) TagMovie(ctx context.Context, movieID string, add, remove []string) (Movie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOfID(movieID)
+	if i < 0 {
+		return Movie{}, fmt.Errorf("movie not found")
+	}
+	m := &s.movies[i]
+	for _, t := range add {
+		if t = NormalizeTag(t); t == "" {
+			continue
+		}
+		m.NotTags = slices.DeleteFunc(m.NotTags, func(n string) bool { return n == t })
+		if !slices.Contains(genres(*m), t) && !slices.Contains(m.Tags, t) {
+			m.Tags = append(m.Tags, t)
+		}
+	}
+	for _, t := range remove {
+		if t = NormalizeTag(t); t == "" {
+			continue
+		}
+		m.Tags = slices.DeleteFunc(m.Tags, func(n string) bool { return n == t })
+		if slices.Contains(genres(*m), t) && !slices.Contains(m.NotTags, t) {
+			m.NotTags = append(m.NotTags, t)
+		}
+	}
+	trace.Logf(ctx, "[STORE] Tags of %s: %v", m.Title, MovieTags(*m))
+	s.markDirty()
+	return m.clone(), nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+//
+// -------------------- TIME FORMATTING --------------------
+//
+
+// TimeStyle is how a chat wants times in lists: the language of relative
+// times, the time zone, and whether to show exact dates instead.
+type TimeStyle struct {
+	Locale   string
+	Location *time.Location // nil means the server's local time
+	Exact    bool
+}
+
+// relativeUnits are the compact "3d ago" patterns of a locale, from seconds
+// to years. They stay short enough for a 10-wide table column.
+var relativeUnits = map[string][6]string{
+	"en": {"%ds ago", "%dm ago", "%dh ago", "%dd ago", "%dM ago", "%dY ago"},
+	"de": {"vor %ds", "vor %dmin", "vor %dh", "vor %dT", "vor %dMo", "vor %dJ"},
+	"es": {"hace %ds", "hace %dm", "hace %dh", "hace %dd", "hace %dM", "hace %da"},
+	"fr": {"il y a %ds", "il y a %dm", "il y a %dh", "il y a %dj", "il y a %dM", "il y a %da"},
+	"it": {"%ds fa", "%dm fa", "%dh fa", "%dg fa", "%dM fa", "%da fa"},
+}
+
+// TimeLocales lists the locales RelativeTime knows.
+func TimeLocales() []string {
+	return slices.Sorted(maps.Keys(relativeUnits))
+}
+
+// RelativeTime renders how long before now t was, e.g. "3d ago". Months and
+// years are counted on the calendar rather than as 30/365-day blocks.
+// Unknown locales fall back to English.
+func RelativeTime(t, now time.Time, locale string) string {
+	units, ok := relativeUnits[locale]
+	if !ok {
+		units = relativeUnits["en"]
+	}
+
+	diff := now.Sub(t)
+	switch {
+	case diff < time.Minute:
+		return fmt.Sprintf(units[0], max(int(diff.Seconds()), 0))
+	case diff < time.Hour:
+		return fmt.Sprintf(units[1], int(diff.Minutes()))
+	case diff < 24*time.Hour:
+		return fmt.Sprintf(units[2], int(diff.Hours()))
+	}
+
+	months := (now.Year()-t.Year())*12 + int(now.Month()-t.Month())
+	if now.Day() < t.Day() {
+		months--
+	}
+	switch {
+	case months < 1:
+		return fmt.Sprintf(units[3], int(diff.Hours()/24))
+	case months < 12:
+		return fmt.Sprintf(units[4], months)
+	default:
+		return fmt.Sprintf(units[5], months/12)
+	}
+}
+
+// FormatAddedTime is the "Added" column in a chat's style: relative in its
+// language, or the exact date in its time zone.
+func FormatAddedTime(m Movie, ts TimeStyle) string {
+	if m.AddedAt.IsZero() {
+		return "-"
+	}
+	if ts.Exact {
+		t := m.AddedAt
+		if ts.Location != nil {
+			t = t.In(ts.Location)
+		}
+		return t.Format("2006-01-02")
+	}
+	return RelativeTime(m.AddedAt, time.Now(), ts.Locale)
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- TONIGHT POLLS --------------------
+//
+
+// TonightPoll asks a chat whether to watch something tonight. Whoever is in
+// when it ends decides between the top movies, and with enough of them a
+// night is scheduled. Polls live in tonight.json next to the movies file.
+type TonightPoll struct {
+	ID        string          `json:"id"`
+	ChatID    int64           `json:"chat_id"`
+	MessageID int             `json:"message_id"`
+	MovieIDs  []string        `json:"movie_ids"`         // the candidates, top first
+	At        time.Time       `json:"at"`                // when the night would start
+	Need      int             `json:"need"`              // how many must be in
+	Answers   map[string]bool `json:"answers,omitempty"` // user ID -> in (true) or can't
+	StartedBy string          `json:"started_by"`        // username, becomes the night's CreatedBy
+	StartedAt time.Time       `json:"started_at"`
+	EndsAt    time.Time       `json:"ends_at"`
+	Closed    bool            `json:"closed,omitempty"`
+	NightID   string          `json:"night_id,omitempty"` // the night it scheduled, if any
+}
+
+// Answered returns who answered in (or can't), sorted by user ID.
+func (p TonightPoll) Answered(in bool) []string {
+	var ids []string
+	for _, id := range slices.Sorted(maps.Keys(p.Answers)) {
+		if p.Answers[id] == in {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// AddTonight starts a tonight poll and returns it with its ID set.
+func (s *Store) AddTonight(ctx context.Context, p TonightPoll) TonightPoll {
+	s.tonightMu.Lock()
+	defer s.tonightMu.Unlock()
+
+	p.ID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	s.tonight = append(s.tonight, p)
+	s.tonightFile.markDirty()
+	trace.Logf(ctx, "[STORE] Tonight poll %s in chat %d for %v, needs %d", p.ID, p.ChatID, p.At, p.Need)
+	return p
+}
+
+// GetTonight returns a tonight poll by ID.
+func (s *Store) GetTonight(id string) (TonightPoll, bool) {
+	s.tonightMu.RLock()
+	defer s.tonightMu.RUnlock()
+
+	i := slices.IndexFunc(s.tonight, func(p TonightPoll) bool { return p.ID == id })
+	if i < 0 {
+		return TonightPoll{}, false
+	}
+	return s.tonight[i], true
+}
+
+// OpenTonight returns the chat's tonight poll that is still open.
+func (s *Store) OpenTonight(chatID int64) (TonightPoll, bool) {
+	s.tonightMu.RLock()
+	defer s.tonightMu.RUnlock()
+
+	for i := len(s.tonight) - 1; i >= 0; i-- {
+		if p := s.tonight[i]; p.ChatID == chatID && !p.Closed {
+			return p, true
+		}
+	}
+	return TonightPoll{}, false
+}
+
+// OpenTonights returns every open tonight poll, for the scheduler.
+func (s *Store) OpenTonights() []TonightPoll {
+	s.tonightMu.RLock()
+	defer s.tonightMu.RUnlock()
+
+	var out []TonightPoll
+	for _, p := range s.tonight {
+		if !p.Closed {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// SetTonightMessage records the message holding a tonight poll's buttons.
+func (s *Store) SetTonightMessage(ctx context.Context, id string, messageID int) {
+	s.updateTonight(ctx, id, func(p *TonightPoll) error {
+		p.MessageID = messageID
+		return nil
+	})
+}
+
+// TonightAnswer records whether a user is in. Giving the same answer again
+// takes it back; answers after EndsAt are refused.
+func (s *Store) TonightAnswer(ctx context.Context, id, userID string, in bool, now time.Time) (TonightPoll, error) {
+	var answered TonightPoll
+	err := s.updateTonight(ctx, id, func(p *TonightPoll) error {
+		if !now.Before(p.EndsAt) {
+			return fmt.Errorf("this poll has ended")
+		}
+		answers := maps.Clone(p.Answers) // copies handed out share the old map
+		if answers == nil {
+			answers = make(map[string]bool)
+		}
+		if had, ok := answers[userID]; ok && had == in {
+			delete(answers, userID)
+		} else {
+			answers[userID] = in
+		}
+		p.Answers = answers
+		answered = *p
+		return nil
+	})
+	if err == nil {
+		trace.Logf(ctx, "[STORE] User %s answers %v to tonight poll %s", userID, in, id)
+	}
+	return answered, err
+}
+
+// CloseTonight ends a tonight poll, recording the night it scheduled ("" for
+// none).
+func (s *Store) CloseTonight(ctx context.Context, id, nightID string) (TonightPoll, error) {
+	var closed TonightPoll
+	err := s.updateTonight(ctx, id, func(p *TonightPoll) error {
+		p.Closed, p.NightID = true, nightID
+		closed = *p
+		return nil
+	})
+	if err == nil {
+		trace.Logf(ctx, "[STORE] Tonight poll %s closed, night %q", id, nightID)
+	}
+	return closed, err
+}
+
+// updateTonight applies fn to an open tonight poll and saves the result.
+func (s *Store) updateTonight(ctx context.Context, id string, fn func(*TonightPoll) error) error {
+	s.tonightMu.Lock()
+	defer s.tonightMu.Unlock()
+
+	i := slices.IndexFunc(s.tonight, func(p TonightPoll) bool { return p.ID == id })
+	if i < 0 || s.tonight[i].Closed {
+		return fmt.Errorf("this poll is over")
+	}
+	if err := fn(&s.tonight[i]); err != nil {
+		return err
+	}
+	s.tonightFile.markDirty()
+	return nil
+}
@@ -0,0 +1,70 @@
+package storage
+
+//
+// -------------------- USER STATS --------------------
+//
+
+// UserStats is what one user did in a library. Votes and watched marks of
+// earlier rounds of rewatched movies count too.
+type UserStats struct {
+	UserID    string
+	Nominated int // movies they added
+	Won       int // of those, the ones the group went on to watch
+	Voted     int // movies they voted for
+	Watched   int // movies they watched
+}
+
+// WinRate is the share of their nominations the group watched, 0 for
+// users who never nominated anything.
+func (st UserStats) WinRate() float64 {
+	if st.Nominated == 0 {
+		return 0
+	}
+	return float64(st.Won) / float64(st.Nominated)
+}
+
+// LibraryStats aggregates the stats of everyone who nominated, voted for
+// or watched a movie of the library, by user ID.
+func (s *Store) LibraryStats(library int64) map[string]UserStats {
+	stats := make(map[string]UserStats)
+	update := func(id string, change func(*UserStats)) {
+		st := stats[id]
+		st.UserID = id
+		change(&st)
+		stats[id] = st
+	}
+
+	for _, m := range s.GetLibrary(library) {
+		if m.AddedBy != "" {
+			update(m.AddedBy, func(st *UserStats) {
+				st.Nominated++
+				if IsWatched(m) || len(m.History) > 0 {
+					st.Won++
+				}
+			})
+		}
+		rounds := append([]Viewing{{Votes: m.Votes, Watched: m.Watched}}, m.History...)
+		for _, v := range rounds {
+			for id, voted := range v.Votes {
+				if voted {
+					update(id, func(st *UserStats) { st.Voted++ })
+				}
+			}
+			for id, watched := range v.Watched {
+				if watched {
+					update(id, func(st *UserStats) { st.Watched++ })
+				}
+			}
+		}
+	}
+	return stats
+}
+
+// UserStats returns one user's stats in a library; all zero for users who
+// did nothing there.
+func (s *Store) UserStats(library int64, userID string) UserStats {
+	if st, ok := s.LibraryStats(library)[userID]; ok {
+		return st
+	}
+	return UserStats{UserID: userID}
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- USERS --------------------
+//
+
+// User is what the bot remembers about someone it has seen, so votes (keyed
+// by user ID) can be matched to @usernames.
+type User struct {
+	ID       string    `json:"id"`
+	Username string    `json:"username,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	LastSeen time.Time `json:"last_seen"`
+	Mute     bool      `json:"mute,omitempty"` // no DMs about their suggestions
+
+	Karma  map[int64]int      `json:"karma,omitempty"`  // chat ID -> points won in /quiz
+	Badges map[int64][]string `json:"badges,omitempty"` // library -> achievement IDs unlocked there
+}
+
+// SeenUser records that a user interacted with the bot. Only name changes and
+// the first sighting of the day mark the store dirty.
+func (s *Store) SeenUser(ctx context.Context, u User) {
+	s.userMu.Lock()
+	defer s.userMu.Unlock()
+
+	now := time.Now()
+	old, known := s.users[u.ID]
+	if known && old.Username == u.Username && old.Name == u.Name &&
+		now.Sub(old.LastSeen) < 24*time.Hour {
+		return
+	}
+
+	u.LastSeen = now
+	u.Mute = old.Mute
+	u.Karma = old.Karma
+	u.Badges = old.Badges
+	s.users[u.ID] = u
+	s.usersFile.markDirty()
+	if !known {
+		trace.Logf(ctx, "[STORE] New user %s (@%s)", u.ID, u.Username)
+	}
+}
+
+// SetUserMute turns a user's suggestion notifications off or back on.
+func (s *Store) SetUserMute(ctx context.Context, id string, mute bool) {
+	s.userMu.Lock()
+	defer s.userMu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		u = User{ID: id, LastSeen: time.Now()}
+	}
+	u.Mute = mute
+	s.users[id] = u
+	s.usersFile.markDirty()
+	trace.Logf(ctx, "[STORE] User %s mute=%v", id, mute)
+}
+
+// GetUser returns a known user by ID.
+func (s *Store) GetUser(id string) (User, bool) {
+	s.userMu.RLock()
+	defer s.userMu.RUnlock()
+	u, ok := s.users[id]
+	return u, ok
+}
+
+// UserByUsername finds a known user by @username, case-insensitively and
+// with or without the @.
+func (s *Store) UserByUsername(username string) (User, bool) {
+	username = strings.TrimPrefix(username, "@")
+
+	s.userMu.RLock()
+	defer s.userMu.RUnlock()
+	for _, u := range s.users {
+		if u.Username != "" && strings.EqualFold(u.Username, username) {
+			return u, true
+		}
+	}
+	return User{}, false
+}
+
+//
+// -------------------- KARMA --------------------
+//
+
+// KarmaScore is one user's karma in a chat.
+type KarmaScore struct {
+	UserID string
+	Points int
+}
+
+// AddKarma gives a user points in a chat and returns their new total.
+func (s *Store) AddKarma(ctx context.Context, chatID int64, userID string, points int) int {
+	s.userMu.Lock()
+	defer s.userMu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		u = User{ID: userID, LastSeen: time.Now()}
+	}
+	u.Karma = maps.Clone(u.Karma) // copies handed out by GetUser stay as they were
+	if u.Karma == nil {
+		u.Karma = make(map[int64]int)
+	}
+	u.Karma[chatID] += points
+	s.users[userID] = u
+	s.usersFile.markDirty()
+	trace.Logf(ctx, "[STORE] User %s +%d karma in chat %d (%d)", userID, points, chatID, u.Karma[chatID])
+	return u.Karma[chatID]
+}
+
+// KarmaBoard returns the users with karma in a chat, most first.
+func (s *Store) KarmaBoard(chatID int64) []KarmaScore {
+	s.userMu.RLock()
+	defer s.userMu.RUnlock()
+
+	var out []KarmaScore
+	for id, u := range s.users {
+		if p := u.Karma[chatID]; p > 0 {
+			out = append(out, KarmaScore{UserID: id, Points: p})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Points != out[j].Points {
+			return out[i].Points > out[j].Points
+		}
+		return out[i].UserID < out[j].UserID
+	})
+	return out
+}
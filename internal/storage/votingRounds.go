@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+//
+// -------------------- VOTING ROUNDS --------------------
+//
+
+// VotingRound is a time-boxed vote over a fixed set of movies: the
+// candidates are picked when it starts and only votes cast before EndsAt
+// count. Closed rounds stay as the chat's history. Rounds live in
+// rounds.json next to the movies file.
+type VotingRound struct {
+	ID          string              `json:"id"`
+	ChatID      int64               `json:"chat_id"`
+	MessageID   int                 `json:"message_id"` // the standings with the vote buttons
+	MovieIDs    []string            `json:"movie_ids"`
+	Titles      []string            `json:"titles"`          // "Title (Year)" of MovieIDs, kept for the history
+	Votes       map[string][]string `json:"votes,omitempty"` // user ID -> movie IDs voted for
+	StartedBy   string              `json:"started_by"`
+	StartedAt   time.Time           `json:"started_at"`
+	EndsAt      time.Time           `json:"ends_at"`
+	StandingsAt time.Time           `json:"standings_at,omitzero"` // last standings posted, see Store.MarkStandings
+	Closed      bool                `json:"closed,omitempty"`
+	Winners     []string            `json:"winners,omitempty"` // movie IDs tied for the most votes, set on close
+
+	Guests map[string]GuestPass `json:"guests,omitempty"` // one-time link token -> pass
+}
+
+// GuestPass lets someone outside Telegram cast one vote in a round through
+// a web link. The vote goes into Votes under GuestVoter(token).
+type GuestPass struct {
+	Name      string    `json:"name,omitempty"` // who it was made for, if anyone said
+	CreatedBy string    `json:"created_by"`
+	UsedAt    time.Time `json:"used_at,omitzero"`
+}
+
+// GuestVoter is the Votes key of the guest holding token.
+func GuestVoter(token string) string {
+	return "guest:" + token
+}
+
+// Counts returns each candidate's votes.
+func (r VotingRound) Counts() map[string]int {
+	counts := make(map[string]int, len(r.MovieIDs))
+	for _, ids := range r.Votes {
+		for _, id := range ids {
+			counts[id]++
+		}
+	}
+	return counts
+}
+
+// Standings returns the candidates by votes, most first; ties keep the
+// order they were put up in.
+func (r VotingRound) Standings() []string {
+	counts := r.Counts()
+	ids := slices.Clone(r.MovieIDs)
+	sort.SliceStable(ids, func(i, j int) bool { return counts[ids[i]] > counts[ids[j]] })
+	return ids
+}
+
+// Title is the label a candidate was put up with.
+func (r VotingRound) Title(movieID string) string {
+	if i := slices.Index(r.MovieIDs, movieID); i >= 0 && i < len(r.Titles) {
+		return r.Titles[i]
+	}
+	return "(removed movie)"
+}
+
+// leaders returns the candidates tied for the most votes, none without votes.
+func (r VotingRound) leaders() []string {
+	counts := r.Counts()
+	var top []string
+	for _, id := range r.MovieIDs {
+		switch {
+		case counts[id] == 0:
+		case len(top) == 0 || counts[id] > counts[top[0]]:
+			top = []string{id}
+		case counts[id] == counts[top[0]]:
+			top = append(top, id)
+		}
+	}
+	return top
+}
+
+// AddRound starts a voting round and returns it with its ID set.
+func (s *Store) AddRound(ctx context.Context, r VotingRound) VotingRound {
+	s.roundMu.Lock()
+	defer s.roundMu.Unlock()
+
+	r.ID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	s.rounds = append(s.rounds, r)
+	s.roundsFile.markDirty()
+	trace.Logf(ctx, "[STORE] Voting round %s in chat %d over %d movies until %v", r.ID, r.ChatID, len(r.MovieIDs), r.EndsAt)
+	return r
+}
+
+// GetRound returns a voting round by ID.
+func (s *Store) GetRound(id string) (VotingRound, bool) {
+	s.roundMu.RLock()
+	defer s.roundMu.RUnlock()
+
+	i := slices.IndexFunc(s.rounds, func(r VotingRound) bool { return r.ID == id })
+	if i < 0 {
+		return VotingRound{}, false
+	}
+	return s.rounds[i], true
+}
+
+// OpenRound returns the chat's voting round that is still open.
+func (s *Store) OpenRound(chatID int64) (VotingRound, bool) {
+	s.roundMu.RLock()
+	defer s.roundMu.RUnlock()
+
+	for i := len(s.rounds) - 1; i >= 0; i-- {
+		if r := s.rounds[i]; r.ChatID == chatID && !r.Closed {
+			return r, true
+		}
+	}
+	return VotingRound{}, false
+}
+
+// OpenRounds returns every open voting round, for the scheduler.
+func (s *Store) OpenRounds() []VotingRound {
+	s.roundMu.RLock()
+	defer s.roundMu.RUnlock()
+
+	var out []VotingRound
+	for _, r := range s.rounds {
+		if !r.Closed {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// PastRounds returns a chat's closed voting rounds, latest first.
+func (s *Store) PastRounds(chatID int64) []VotingRound {
+	s.roundMu.RLock()
+	defer s.roundMu.RUnlock()
+
+	var out []VotingRound
+	for i := len(s.rounds) - 1; i >= 0; i-- {
+		if r := s.rounds[i]; r.ChatID == chatID && r.Closed {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// SetRoundMessage records the message holding a round's vote buttons.
+func (s *Store) SetRoundMessage(ctx context.Context, id string, messageID int) {
+	s.updateRound(ctx, id, func(r *VotingRound) error {
+		r.MessageID = messageID
+		return nil
+	})
+}
+
+// MarkStandings records that a round's standings were posted at at.
+func (s *Store) MarkStandings(ctx context.Context, id string, at time.Time) {
+	s.updateRound(ctx, id, func(r *VotingRound) error {
+		r.StandingsAt = at
+		return nil
+	})
+}
+
+// RoundVote gives or takes back a user's vote for a candidate of an open
+// round. Votes after EndsAt are refused even before the round is closed.
+// It reports whether the user now votes for the movie.
+func (s *Store) RoundVote(ctx context.Context, id, userID, movieID string, now time.Time) (bool, error) {
+	var on bool
+	err := s.updateRound(ctx, id, func(r *VotingRound) error {
+		if !now.Before(r.EndsAt) {
+			return fmt.Errorf("voting in this round has ended")
+		}
+		if !slices.Contains(r.MovieIDs, movieID) {
+			return fmt.Errorf("that movie is not in this round")
+		}
+		votes := r.Votes[userID]
+		if i := slices.Index(votes, movieID); i >= 0 {
+			votes = slices.Delete(slices.Clone(votes), i, i+1)
+		} else {
+			votes, on = append(slices.Clone(votes), movieID), true
+		}
+		if r.Votes == nil {
+			r.Votes = make(map[string][]string)
+		}
+		if len(votes) == 0 {
+			delete(r.Votes, userID)
+		} else {
+			r.Votes[userID] = votes
+		}
+		return nil
+	})
+	if err == nil {
+		trace.Logf(ctx, "[STORE] User %s voted %v for %s in round %s", userID, on, movieID, id)
+	}
+	return on, err
+}
+
+// AddGuestPass creates a one-time voting link for an open round and
+// returns its token.
+func (s *Store) AddGuestPass(ctx context.Context, id, name, createdBy string) (string, error) {
+	var b [16]byte
+	rand.Read(b[:])
+	token := hex.EncodeToString(b[:])
+	err := s.updateRound(ctx, id, func(r *VotingRound) error {
+		if r.Guests == nil {
+			r.Guests = make(map[string]GuestPass)
+		}
+		r.Guests[token] = GuestPass{Name: name, CreatedBy: createdBy}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	trace.Logf(ctx, "[STORE] Guest pass for round %s created by %s", id, createdBy)
+	return token, nil
+}
+
+// RoundByGuestToken finds the round a guest pass belongs to, open or not.
+func (s *Store) RoundByGuestToken(token string) (VotingRound, GuestPass, bool) {
+	if token == "" {
+		return VotingRound{}, GuestPass{}, false
+	}
+
+	s.roundMu.RLock()
+	defer s.roundMu.RUnlock()
+	for _, r := range s.rounds {
+		for t, pass := range r.Guests {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				return r, pass, true
+			}
+		}
+	}
+	return VotingRound{}, GuestPass{}, false
+}
+
+// GuestVote spends a guest pass on a vote for one of its round's
+// candidates. Each pass votes once, and only while the round runs.
+func (s *Store) GuestVote(ctx context.Context, token, movieID string, now time.Time) (VotingRound, error) {
+	r, _, ok := s.RoundByGuestToken(token)
+	if !ok {
+		return VotingRound{}, fmt.Errorf("unknown voting link")
+	}
+	var voted VotingRound
+	err := s.updateRound(ctx, r.ID, func(r *VotingRound) error {
+		pass := r.Guests[token]
+		switch {
+		case !pass.UsedAt.IsZero():
+			return fmt.Errorf("this link was already used")
+		case !now.Before(r.EndsAt):
+			return fmt.Errorf("voting in this round has ended")
+		case !slices.Contains(r.MovieIDs, movieID):
+			return fmt.Errorf("that movie is not in this round")
+		}
+		pass.UsedAt = now
+		r.Guests[token] = pass
+		if r.Votes == nil {
+			r.Votes = make(map[string][]string)
+		}
+		r.Votes[GuestVoter(token)] = []string{movieID}
+		voted = *r
+		return nil
+	})
+	if err == nil {
+		trace.Logf(ctx, "[STORE] Guest voted for %s in round %s", movieID, r.ID)
+	}
+	return voted, err
+}
+
+// CloseRound ends a round and settles its winners.
+func (s *Store) CloseRound(ctx context.Context, id string) (VotingRound, error) {
+	var closed VotingRound
+	err := s.updateRound(ctx, id, func(r *VotingRound) error {
+		r.Winners = r.leaders()
+		r.Closed = true
+		closed = *r
+		return nil
+	})
+	if err == nil {
+		trace.Logf(ctx, "[STORE] Voting round %s closed, winners %v", id, closed.Winners)
+	}
+	return closed, err
+}
+
+// updateRound applies fn to an open round and saves the result.
+func (s *Store) updateRound(ctx context.Context, id string, fn func(*VotingRound) error) error {
+	s.roundMu.Lock()
+	defer s.roundMu.Unlock()
+
+	i := slices.IndexFunc(s.rounds, func(r VotingRound) bool { return r.ID == id })
+	if i < 0 || s.rounds[i].Closed {
+		return fmt.Errorf("this voting round is over")
+	}
+	if err := fn(&s.rounds[i]); err != nil {
+		return err
+	}
+	s.roundsFile.markDirty()
+	return nil
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"slices"
+	"time"
+)
+
+//
+// -------------------- WATCH HISTORY --------------------
+//
+
+// WatchEntry is one viewing of a movie in a library's watch history.
+type WatchEntry struct {
+	Movie   Movie
+	At      time.Time // the movie night it was watched at, else when it counted as watched
+	Rewatch bool
+	Night   bool // At is a movie night's start
+}
+
+// WatchHistory lists every viewing of the library's movies, earlier rounds
+// of rewatched ones included, oldest first. A viewing is dated by the last
+// movie night of the movie someone attended during its round, else by when
+// the movie counted as watched, else by the last watched mark; viewings
+// with none of these are left out.
+func (s *Store) WatchHistory(library int64) []WatchEntry {
+	movies := s.GetLibrary(library)
+
+	// shown is when each movie was on at a night, with when its attendees
+	// marked it watched, which places the night in a round
+	type showing struct{ at, marked time.Time }
+	s.nightMu.RLock()
+	shown := make(map[string][]showing)
+	for _, n := range s.nights {
+		for _, marked := range n.Attended {
+			shown[n.MovieID] = append(shown[n.MovieID], showing{n.At, marked})
+		}
+	}
+	s.nightMu.RUnlock()
+
+	var entries []WatchEntry
+	add := func(m Movie, v Viewing, start, end time.Time, rewatch bool) {
+		if len(v.Watched) == 0 {
+			return
+		}
+		e := WatchEntry{Movie: m, At: v.WatchedOn, Rewatch: rewatch}
+		for _, sh := range shown[m.ID] {
+			if sh.marked.After(start) && (end.IsZero() || sh.marked.Before(end)) && (!e.Night || sh.at.After(e.At)) {
+				e.At, e.Night = sh.at, true
+			}
+		}
+		if e.At.IsZero() {
+			for _, at := range v.WatchedAt {
+				if at.After(e.At) {
+					e.At = at
+				}
+			}
+		}
+		if !e.At.IsZero() {
+			entries = append(entries, e)
+		}
+	}
+
+	for _, m := range movies {
+		var start time.Time
+		for round, v := range m.History {
+			add(m, v, start, v.EndedAt, round > 0)
+			start = v.EndedAt
+		}
+		if IsWatched(m) {
+			add(m, Viewing{Watched: m.Watched, WatchedAt: m.WatchedAt, WatchedOn: m.WatchedOn}, start, time.Time{}, len(m.History) > 0)
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b WatchEntry) int { return a.At.Compare(b.At) })
+	return entries
+}
@@ -0,0 +1,125 @@
+// Package streaming looks up which services carry a movie in one country,
+// from TMDB's watch providers (data by JustWatch).
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/storage"
+	"moviebot/internal/tmdb"
+)
+
+// cacheTTL is how long a movie's availability is reused; catalogues
+// change monthly at most, and lookups cost two TMDB calls.
+const cacheTTL = 12 * time.Hour
+
+// ErrNotFound is returned when TMDB doesn't know the movie.
+var ErrNotFound = errors.New("movie not on TMDB")
+
+// Availability is where a movie can be watched in Region, provider names
+// in the order JustWatch ranks them. All lists are empty when it can't.
+type Availability struct {
+	Region string
+	Link   string // page with the offers: TMDB's, or a JustWatch search
+	Stream []string
+	Free   []string // free, with or without ads
+	Rent   []string
+	Buy    []string
+}
+
+// Available reports whether any service carries the movie.
+func (a Availability) Available() bool {
+	return len(a.Stream)+len(a.Free)+len(a.Rent)+len(a.Buy) > 0
+}
+
+// Client looks availability up and keeps it for cacheTTL.
+type Client struct {
+	region string
+	tmdb   *tmdb.Client
+
+	mu    sync.Mutex
+	found map[int]cached // by TMDB ID
+}
+
+type cached struct {
+	providers map[string]tmdb.WatchOptions
+	at        time.Time
+}
+
+// NewClient lists services for cfg's region, looking them up through
+// tmdbClient.
+func NewClient(cfg config.StreamingConfig, tmdbClient *tmdb.Client) *Client {
+	region := strings.ToUpper(cfg.Region)
+	if region == "" {
+		region = "US"
+	}
+	return &Client{region: region, tmdb: tmdbClient, found: make(map[int]cached)}
+}
+
+// Region is the country services are listed for.
+func (c *Client) Region() string {
+	return c.region
+}
+
+// Find returns where m can be watched in the client's region.
+func (c *Client) Find(ctx context.Context, m storage.Movie) (Availability, error) {
+	id := m.TmdbID
+	if id == 0 && m.ImdbID != "" {
+		var err error
+		if id, err = c.tmdb.FindByImdbID(ctx, m.ImdbID); err != nil {
+			return Availability{}, err
+		}
+	}
+	if id == 0 {
+		return Availability{}, ErrNotFound
+	}
+
+	c.mu.Lock()
+	hit, ok := c.found[id]
+	c.mu.Unlock()
+	if !ok || time.Since(hit.at) >= cacheTTL {
+		providers, err := c.tmdb.WatchProviders(ctx, id)
+		if err != nil {
+			return Availability{}, err
+		}
+		hit = cached{providers: providers, at: time.Now()}
+		c.mu.Lock()
+		c.found[id] = hit
+		c.mu.Unlock()
+	}
+
+	opts := hit.providers[c.region]
+	a := Availability{
+		Region: c.region,
+		Link:   opts.Link,
+		Stream: names(opts.Flatrate),
+		Free:   names(append(slices.Clone(opts.Free), opts.Ads...)),
+		Rent:   names(opts.Rent),
+		Buy:    names(opts.Buy),
+	}
+	if a.Link == "" {
+		a.Link = fmt.Sprintf("https://www.justwatch.com/%s/search?q=%s", strings.ToLower(c.region), url.QueryEscape(m.Title))
+	}
+	return a, nil
+}
+
+// names lists providers by display priority, each once.
+func names(providers []tmdb.Provider) []string {
+	providers = slices.Clone(providers)
+	slices.SortStableFunc(providers, func(a, b tmdb.Provider) int { return a.Priority - b.Priority })
+	var out []string
+	for _, p := range providers {
+		if !slices.Contains(out, p.Name) {
+			out = append(out, p.Name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/omdb"
+	"moviebot/internal/trace"
+)
+
+// usageReporter is implemented by metadata clients that count their calls.
+type usageReporter interface {
+	CallsToday() int
+}
+
+// =====================================================
+// /admin — owner control panel, private chat only
+// =====================================================
+
+func (b *Bot) handleAdmin(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isOwner(msg.From.ID) || msg.Chat.Type != "private" {
+		trace.Logf(ctx, "[BOT] /admin denied for %s in chat %d", msg.From.UserName, msg.Chat.ID)
+		return
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, "🛠 Admin panel")
+	reply.ReplyMarkup = b.adminKeyboard()
+	b.send(ctx, reply)
+}
+
+func (b *Bot) adminKeyboard() tgbotapi.InlineKeyboardMarkup {
+	maintenance := "🛠 Maintenance: off"
+	if b.InMaintenance() {
+		maintenance = "🛠 Maintenance: ON"
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💬 Chats", "admin|chats"),
+			tgbotapi.NewInlineKeyboardButtonData("📊 Store", "admin|stats"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔑 OMDb quota", "admin|quota"),
+			tgbotapi.NewInlineKeyboardButtonData("⏱ Latency", "admin|latency"),
+			tgbotapi.NewInlineKeyboardButtonData("ℹ️ Version", "admin|version"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💾 Backup", "admin|backup"),
+			tgbotapi.NewInlineKeyboardButtonData("🧹 Compact", "admin|compact"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Force sync", "admin|sync"),
+			tgbotapi.NewInlineKeyboardButtonData(maintenance, "admin|maintenance"),
+		),
+	)
+}
+
+// handleAdminCallback runs a panel button and shows the result in the panel.
+func (b *Bot) handleAdminCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if !b.isOwner(cb.From.ID) || cb.Message == nil {
+		b.answerToast(ctx, cb, "⛔ Owners only")
+		return
+	}
+
+	action := strings.TrimPrefix(cb.Data, "admin|")
+	trace.Logf(ctx, "[BOT] Admin action %s by %s", action, cb.From.UserName)
+
+	var text string
+	switch action {
+	case "chats":
+		text = b.chatsText()
+
+	case "stats":
+		st := b.Store.Stats()
+		text = fmt.Sprintf("📊 Store\n\n🎬 %d movies (%d watched) on %d lists\n✉️ %d tracked messages (%d evicted)\n👤 %d users\n💬 %d chats\n🍿 %d movie nights",
+			st.Movies, st.Watched, st.Lists, st.Messages, st.Evicted, st.Users, st.Chats, st.Nights)
+		text += "\n" + b.outageText() + "\n" + b.alertsText()
+
+	case "quota":
+		if u, ok := b.OMDb.(usageReporter); ok {
+			text = fmt.Sprintf("🔑 OMDb: %d of %d calls used today (UTC)", u.CallsToday(), omdb.DailyLimit)
+		} else {
+			text = "🔑 OMDb usage is not tracked for this provider"
+		}
+
+	case "latency":
+		text = b.latencyText()
+
+	case "version":
+		text = b.versionText()
+
+	case "backup":
+		dir, err := b.Store.Backup(ctx)
+		if err != nil {
+			text = "❌ Backup failed: " + err.Error()
+		} else {
+			text = "💾 Backup written to " + dir
+		}
+
+	case "compact":
+		text = b.compactText(ctx)
+
+	case "sync":
+		b.syncListMessages(ctx)
+		for _, run := range b.FullSyncs {
+			go run()
+		}
+		text = fmt.Sprintf("🔄 Lists re-synced, %d integrations resyncing", len(b.FullSyncs))
+
+	case "maintenance":
+		b.SetMaintenance(ctx, !b.InMaintenance(), "")
+		text = "🛠 Maintenance mode off"
+		if b.InMaintenance() {
+			text = "🛠 Maintenance mode ON, only owners get answers"
+		}
+
+	default:
+		b.answerToast(ctx, cb, "Unknown action")
+		return
+	}
+
+	b.answerToast(ctx, cb, "")
+	edit := tgbotapi.NewEditMessageTextAndMarkup(cb.Message.Chat.ID, cb.Message.MessageID,
+		"🛠 Admin panel\n\n"+text, b.adminKeyboard())
+	b.send(ctx, edit)
+}
@@ -0,0 +1,47 @@
+package telegram
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// API is the slice of the Telegram Bot API the bot talks to. Handlers only
+// ever go through this interface so it can be swapped for a mock, a logger
+// or a rate-limited queue without touching them.
+type API interface {
+	// Send delivers anything that produces a message (texts, photos, edits).
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	// Request performs calls whose result is not a message (deletes,
+	// callback answers, markup edits, pins).
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+	// GetFileDirectURL resolves a file ID to a downloadable URL.
+	GetFileDirectURL(fileID string) (string, error)
+	// Call invokes a Bot API method the library has no config type for yet
+	// (forum topics, ...).
+	Call(method string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+}
+
+// tgAPI is the real implementation backed by go-telegram-bot-api.
+type tgAPI struct {
+	bot *tgbotapi.BotAPI
+}
+
+// NewAPI wraps an authorized *tgbotapi.BotAPI.
+func NewAPI(bot *tgbotapi.BotAPI) API {
+	return &tgAPI{bot: bot}
+}
+
+func (a *tgAPI) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return a.bot.Send(c)
+}
+
+func (a *tgAPI) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return a.bot.Request(c)
+}
+
+func (a *tgAPI) GetFileDirectURL(fileID string) (string, error) {
+	return a.bot.GetFileDirectURL(fileID)
+}
+
+func (a *tgAPI) Call(method string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	return a.bot.MakeRequest(method, params)
+}
@@ -0,0 +1,45 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// =====================================================
+// /me — movie night attendance
+// =====================================================
+
+// handleMe shows the sender's attendance at this chat's movie nights.
+func (b *Bot) handleMe(ctx context.Context, msg *tgbotapi.Message) {
+	st := b.Store.UserAttendance(msg.Chat.ID, strconv.FormatInt(msg.From.ID, 10), time.Now())
+	if st.Nights == 0 {
+		b.replyText(ctx, msg, "📅 No movie nights here yet. Set one up with /schedule.")
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🎟 %s, you came to %d of %d movie nights here.\n", msg.From.FirstName, st.Attended, st.Nights)
+	fmt.Fprintf(&sb, "🔥 Streak: %d in a row (best %d)", st.Streak, st.Best)
+	if st.Streak == 0 {
+		sb.WriteString("\nMark the movie 👁 watched after the next night to start one.")
+	}
+	b.replyText(ctx, msg, sb.String())
+}
+
+// userLabel names a user for chat messages: @username, their name, or their
+// ID for users the bot never saw.
+func (b *Bot) userLabel(id string) string {
+	u, ok := b.Store.GetUser(id)
+	switch {
+	case ok && u.Username != "":
+		return "@" + u.Username
+	case ok && u.Name != "":
+		return u.Name
+	}
+	return "user " + id
+}
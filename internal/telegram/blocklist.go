@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/trace"
+)
+
+// refusals answer a pick of a blocked movie; %s is the title.
+var refusals = []string{
+	"🙅 We are NOT watching %s. It's on this chat's blocklist.",
+	"🚫 Nice try. %s is banned here, and for good reason.",
+	"🙈 %s? The group has spoken: blocked, forever.",
+	"⛔ %s was blocked by the admins. Pick something else, please.",
+}
+
+func refusal(title string) string {
+	return fmt.Sprintf(refusals[rand.IntN(len(refusals))], title)
+}
+
+// =====================================================
+// /block and /unblock — admins only
+// =====================================================
+
+// handleBlock shows the chat's blocklist, or adds a title or keyword to it.
+func (b *Bot) handleBlock(ctx context.Context, msg *tgbotapi.Message) {
+	term := strings.TrimSpace(msg.CommandArguments())
+	if term == "" {
+		list := b.Store.Blocklist(msg.Chat.ID)
+		if len(list) == 0 {
+			b.replyText(ctx, msg, "🚫 Nothing is blocked here. Admins: /block <title or keyword>")
+			return
+		}
+		b.replyText(ctx, msg, "🚫 Blocked here:\n• "+strings.Join(list, "\n• ")+"\n\nAdmins: /unblock <term>")
+		return
+	}
+
+	if !b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+		b.replyText(ctx, msg, "⛔ Only chat admins can change the blocklist.")
+		return
+	}
+	if !b.Store.BlockTerm(ctx, msg.Chat.ID, term) {
+		b.replyText(ctx, msg, fmt.Sprintf("%q is already blocked.", term))
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s blocked %q in chat %d", msg.From.UserName, term, msg.Chat.ID)
+	b.replyText(ctx, msg, fmt.Sprintf("🚫 Blocked %q. Movies with it in the title can't be added here anymore.", term))
+}
+
+// handleUnblock takes a term off the chat's blocklist.
+func (b *Bot) handleUnblock(ctx context.Context, msg *tgbotapi.Message) {
+	term := strings.TrimSpace(msg.CommandArguments())
+	if term == "" {
+		b.replyText(ctx, msg, "Usage: /unblock <term>")
+		return
+	}
+	if !b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+		b.replyText(ctx, msg, "⛔ Only chat admins can change the blocklist.")
+		return
+	}
+	if !b.Store.UnblockTerm(ctx, msg.Chat.ID, term) {
+		b.replyText(ctx, msg, fmt.Sprintf("%q isn't blocked.", term))
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s unblocked %q in chat %d", msg.From.UserName, term, msg.Chat.ID)
+	b.replyText(ctx, msg, fmt.Sprintf("✅ Unblocked %q.", term))
+}
@@ -0,0 +1,181 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/omdb"
+	"moviebot/internal/search"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// /movie bulk — one title per line
+// =====================================================
+
+const (
+	// maxBulkTitles caps one bulk add, each line costs an OMDb search.
+	maxBulkTitles = 30
+	// bulkConfidence is the title similarity above which the top search
+	// result is added without asking.
+	bulkConfidence = 0.85
+)
+
+// queuedSearch is an ambiguous bulk line waiting for the user to pick.
+type queuedSearch struct {
+	Query   string
+	Results []omdb.SearchResult
+}
+
+// bulkLines returns the titles of a bulk add: the lines after "/movie bulk",
+// or those of the replied-to message.
+func bulkLines(msg *tgbotapi.Message) []string {
+	text := msg.CommandArguments()
+	_, rest, found := strings.Cut(text, "\n")
+	if !found && msg.ReplyToMessage != nil {
+		rest = msg.ReplyToMessage.Text
+	}
+
+	var lines []string
+	for _, l := range strings.Split(rest, "\n") {
+		l = strings.TrimSpace(strings.TrimLeft(l, "-•*0123456789.) \t"))
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// pickConfident returns the result a line clearly means: the best match
+// scores above bulkConfidence and, without a year to tell them apart, no
+// other result (a remake, say) scores as well.
+func pickConfident(title string, year int, results []omdb.SearchResult) (omdb.SearchResult, bool) {
+	best, bestScore, runnerUp := -1, 0.0, 0.0
+	for i, r := range results {
+		score := search.Similarity(title, r.Title)
+		if year > 0 && !strings.HasPrefix(r.Year, strconv.Itoa(year)) {
+			score /= 2
+		}
+		if score > bestScore {
+			best, bestScore, runnerUp = i, score, bestScore
+		} else if score > runnerUp {
+			runnerUp = score
+		}
+	}
+	if best < 0 || bestScore < bulkConfidence || runnerUp >= bestScore {
+		return omdb.SearchResult{}, false
+	}
+	return results[best], true
+}
+
+func (b *Bot) handleBulkAdd(ctx context.Context, msg *tgbotapi.Message, list string) {
+	lines := bulkLines(msg)
+	if len(lines) == 0 {
+		b.replyText(ctx, msg, "Send one title per line after /movie bulk, or reply to a list of titles with /movie bulk.")
+		return
+	}
+	if len(lines) > maxBulkTitles {
+		b.replyText(ctx, msg, fmt.Sprintf("That's %d titles, please send at most %d at a time.", len(lines), maxBulkTitles))
+		return
+	}
+	trace.Logf(ctx, "[BOT] Bulk add of %d titles by %s", len(lines), msg.From.UserName)
+
+	var added, existing, blocked, notFound []string
+	var queue []queuedSearch
+	for _, line := range lines {
+		title, year := search.NormalizeQuery(line)
+		results, err := search.Query(ctx, b.OMDb, b.TMDB, line)
+		if err != nil || len(results) == 0 {
+			notFound = append(notFound, line)
+			continue
+		}
+
+		r, ok := pickConfident(title, year, results)
+		if !ok {
+			queue = append(queue, queuedSearch{Query: line, Results: results})
+			continue
+		}
+
+		label := fmt.Sprintf("%s (%s)", r.Title, r.Year)
+		if _, isBlocked := b.Store.BlockedTerm(msg.Chat.ID, r.Title); isBlocked {
+			blocked = append(blocked, label)
+			continue
+		}
+
+		_, created := b.addSearchResult(ctx, msg.Chat.ID, msg.From, r, list)
+		if created {
+			added = append(added, label)
+		} else {
+			existing = append(existing, label)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📥 Bulk add: %d added, %d already listed, %d blocked, %d to pick, %d not found\n",
+		len(added), len(existing), len(blocked), len(queue), len(notFound))
+	for _, t := range added {
+		fmt.Fprintf(&sb, "\n✅ %s", t)
+	}
+	for _, t := range existing {
+		fmt.Fprintf(&sb, "\n☑️ %s", t)
+	}
+	for _, t := range blocked {
+		fmt.Fprintf(&sb, "\n🚫 %s", t)
+	}
+	for _, q := range queue {
+		fmt.Fprintf(&sb, "\n❓ %s", q.Query)
+	}
+	for _, t := range notFound {
+		fmt.Fprintf(&sb, "\n❌ %s", t)
+	}
+	b.replyText(ctx, msg, sb.String())
+
+	if len(added) > 0 {
+		b.syncListMessages(ctx)
+	}
+
+	b.resumeQueue(ctx, &userSession{
+		UserID:        msg.From.ID,
+		ChatID:        msg.Chat.ID,
+		OrigMessageID: msg.MessageID,
+		List:          list,
+		Lang:          b.userLanguage(msg.Chat.ID, msg.From),
+		Queue:         queue,
+	})
+}
+
+// resumeQueue starts the next queued search of a finished selection, if
+// any.
+func (b *Bot) resumeQueue(ctx context.Context, done *userSession) {
+	if len(done.Queue) == 0 {
+		return
+	}
+	next := done.Queue[0]
+
+	sess := &userSession{
+		ID:            fmt.Sprintf("%d:%d", done.UserID, time.Now().UnixNano()),
+		UserID:        done.UserID,
+		ChatID:        done.ChatID,
+		Query:         next.Query,
+		Results:       next.Results,
+		OrigMessageID: done.OrigMessageID,
+		List:          done.List,
+		Lang:          done.Lang,
+		Queue:         done.Queue[1:],
+	}
+
+	b.sessMu.Lock()
+	b.sessions[sess.ID] = sess
+	b.sessMu.Unlock()
+
+	prompt := fmt.Sprintf("❓ Which one is \"%s\"?", next.Query)
+	if len(sess.Queue) > 0 {
+		prompt += fmt.Sprintf(" (%d more after this)", len(sess.Queue))
+	}
+	b.send(ctx, tgbotapi.NewMessage(sess.ChatID, prompt))
+	b.sendMovieSelection(ctx, sess, 0)
+}
@@ -0,0 +1,105 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/i18n"
+	"moviebot/internal/trace"
+)
+
+// defaultSessionTTL is how long a /movie prompt waits for a title when
+// neither the chat's autodelete setting nor SessionTTL say otherwise, and
+// searchResultsTTL how long search results stay.
+const (
+	defaultSessionTTL = 5 * time.Minute
+	searchResultsTTL  = 5 * time.Minute
+)
+
+// =====================================================
+// /cancel — withdraw the /movie prompt
+// =====================================================
+
+// waitSessionID is the session of the /movie prompt waiting for userID's
+// title in chatID; chat-scoped so prompts in different groups don't mix.
+func waitSessionID(chatID, userID int64) string {
+	return fmt.Sprintf("wait:%d:%d", chatID, userID)
+}
+
+// cancelKeyboard is the prompt's cancel button, "cancel|<user>".
+func cancelKeyboard(lang string, userID int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✖️ "+i18n.T(lang, "Cancel"), fmt.Sprintf("cancel|%d", userID)),
+	))
+}
+
+// handleCancel withdraws the sender's /movie prompt in this chat.
+func (b *Bot) handleCancel(ctx context.Context, msg *tgbotapi.Message) {
+	lang := b.userLanguage(msg.Chat.ID, msg.From)
+	if !b.withdrawPrompt(ctx, waitSessionID(msg.Chat.ID, msg.From.ID)) {
+		b.replyText(ctx, msg, i18n.T(lang, "Nothing to cancel."))
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s cancelled their search", msg.From.UserName)
+	b.replyText(ctx, msg, "✖️ "+i18n.T(lang, "Search cancelled."))
+}
+
+// handleCancelCallback handles the prompt's cancel button, which only works
+// for whoever ran /movie.
+func (b *Bot) handleCancelCallback(ctx context.Context, cb *tgbotapi.CallbackQuery, lang string) {
+	if strings.TrimPrefix(cb.Data, "cancel|") != strconv.FormatInt(cb.From.ID, 10) {
+		b.answerToast(ctx, cb, "⛔ "+i18n.T(lang, "This movie selection isn’t for you"))
+		return
+	}
+	if cb.Message == nil || !b.withdrawPrompt(ctx, waitSessionID(cb.Message.Chat.ID, cb.From.ID)) {
+		b.answerToast(ctx, cb, i18n.T(lang, "Nothing to cancel."))
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s cancelled their search", cb.From.UserName)
+	b.answerToast(ctx, cb, "✖️ "+i18n.T(lang, "Search cancelled."))
+}
+
+// withdrawPrompt ends a waiting /movie session and deletes its prompt,
+// reporting whether there was one.
+func (b *Bot) withdrawPrompt(ctx context.Context, sessionID string) bool {
+	b.sessMu.Lock()
+	sess, ok := b.sessions[sessionID]
+	ok = ok && sess.WaitingForQuery
+	if ok {
+		delete(b.sessions, sessionID)
+	}
+	b.sessMu.Unlock()
+
+	if ok {
+		b.request(ctx, tgbotapi.NewDeleteMessage(sess.ChatID, sess.PromptMessageID))
+	}
+	return ok
+}
+
+// expirePrompts withdraws the /movie prompts left unanswered for the chat's
+// autodelete time or SessionTTL.
+func (b *Bot) expirePrompts(ctx context.Context, now time.Time) {
+	ttl := b.SessionTTL
+	if ttl <= 0 {
+		ttl = defaultSessionTTL
+	}
+
+	var expired []*userSession
+	b.sessMu.Lock()
+	for id, sess := range b.sessions {
+		if sess.WaitingForQuery && now.Sub(sess.PromptedAt) >= b.autoDelete(sess.ChatID, ttl) {
+			delete(b.sessions, id)
+			expired = append(expired, sess)
+		}
+	}
+	b.sessMu.Unlock()
+
+	for _, sess := range expired {
+		b.request(ctx, tgbotapi.NewDeleteMessage(sess.ChatID, sess.PromptMessageID))
+		trace.Logf(ctx, "[BOT] Search prompt %s expired", sess.ID)
+	}
+}
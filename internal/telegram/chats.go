@@ -0,0 +1,93 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/trace"
+)
+
+// memberCountTTL is how long a chat's member count estimate is trusted.
+const memberCountTTL = 24 * time.Hour
+
+// seeChat keeps the chat registry current and refreshes the member count
+// estimate once a day.
+func (b *Bot) seeChat(ctx context.Context, chat *tgbotapi.Chat) {
+	if chat == nil {
+		return
+	}
+	b.Store.TouchChat(ctx, chat.ID, chatTitle(chat), chat.Type)
+
+	if c, ok := b.Store.GetChat(chat.ID); ok && time.Since(c.MembersAt) > memberCountTTL {
+		resp, err := b.request(ctx, tgbotapi.ChatMemberCountConfig{
+			ChatConfig: tgbotapi.ChatConfig{ChatID: chat.ID},
+		})
+		if err != nil {
+			return
+		}
+		var n int
+		if err := json.Unmarshal(resp.Result, &n); err == nil {
+			b.Store.SetChatMembers(ctx, chat.ID, n)
+		}
+	}
+}
+
+func chatTitle(chat *tgbotapi.Chat) string {
+	if chat.Title != "" {
+		return chat.Title
+	}
+	if chat.UserName != "" {
+		return "@" + chat.UserName
+	}
+	if name := strings.TrimSpace(chat.FirstName + " " + chat.LastName); name != "" {
+		return name
+	}
+	return fmt.Sprintf("chat %d", chat.ID)
+}
+
+// handleMyChatMember tracks the bot being added to, removed from or blocked
+// in a chat.
+func (b *Bot) handleMyChatMember(ctx context.Context, u *tgbotapi.ChatMemberUpdated) {
+	switch u.NewChatMember.Status {
+	case "left", "kicked":
+		b.Store.MarkChatLeft(ctx, u.Chat.ID)
+	default:
+		b.seeChat(ctx, &u.Chat)
+	}
+}
+
+// =====================================================
+// /chats — owner only
+// =====================================================
+
+func (b *Bot) handleChats(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isOwner(msg.From.ID) {
+		trace.Logf(ctx, "[BOT] /chats denied for %s", msg.From.UserName)
+		return
+	}
+
+	b.replyText(ctx, msg, b.chatsText())
+}
+
+// chatsText renders the chat registry.
+func (b *Bot) chatsText() string {
+	chats := b.Store.GetChats()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "💬 %d chats\n\n", len(chats))
+	for _, c := range chats {
+		icon := "🟢"
+		if c.Left {
+			icon = "⚪️"
+		}
+		fmt.Fprintf(&sb, "%s %s (%s", icon, c.Title, c.Type)
+		if c.Members > 0 {
+			fmt.Fprintf(&sb, ", ~%d members", c.Members)
+		}
+		fmt.Fprintf(&sb, ")\n    id %d, active %s ago\n", c.ID, time.Since(c.LastActivity).Round(time.Minute))
+	}
+	return sb.String()
+}
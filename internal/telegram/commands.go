@@ -0,0 +1,212 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/trace"
+)
+
+// commandScope says where a command is offered. Handlers still check
+// permissions themselves; scopes only shape /help and the "/" menu.
+type commandScope uint8
+
+const (
+	inPrivate commandScope = 1 << iota // private chats with the bot
+	inGroups                           // group chats, for everyone
+	forAdmins                          // group chats, for admins only
+	forOwners                          // the bot owners' private chats
+
+	everywhere = inPrivate | inGroups
+)
+
+// command is one entry of the registry: how /help and the menu describe it
+// and what runs it.
+type command struct {
+	name  string
+	args  string // usage hint shown in /help, "" for none
+	desc  string
+	scope commandScope
+	run   func(b *Bot, ctx context.Context, msg *tgbotapi.Message)
+}
+
+// commands is the command registry, in /help order. It is filled in init
+// because /help itself reads it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"movie", "[--list name] [title | add-manual Title;Year]", "Search a movie and put it up for a vote", everywhere, (*Bot).handleMovie},
+		{"cancel", "", "Cancel your /movie prompt", everywhere, (*Bot).handleCancel},
+		{"list", "[format | list | genre=...]", "Show the watchlist", everywhere, (*Bot).handleList},
+		{"lists", "", "Show the named lists", everywhere, (*Bot).handleLists},
+		{"tag", "<movie> [+tag -tag]", "Show, add or take off a movie's genre tags", everywhere, (*Bot).handleTag},
+		{"info", "<title | IMDb ID>", "Plot, genre, director and ratings of a movie", everywhere, (*Bot).handleInfo},
+		{"trailer", "<title | IMDb ID>", "Link the official trailer of a movie", everywhere, (*Bot).handleTrailer},
+		{"where", "<title | IMDb ID>", "Which streaming services carry a movie", everywhere, (*Bot).handleWhere},
+		{"top", "[n]", "The highest-voted unwatched movies", everywhere, (*Bot).handleTop},
+		{"vote", "<n>", "Vote for row n of the last /list", everywhere, func(b *Bot, ctx context.Context, msg *tgbotapi.Message) {
+			b.handleQuickToggle(ctx, msg, false)
+		}},
+		{"seen", "<n>", "Mark row n of the last /list as watched", everywhere, func(b *Bot, ctx context.Context, msg *tgbotapi.Message) {
+			b.handleQuickToggle(ctx, msg, true)
+		}},
+		{"poll", "[n | close]", "Vote with a Telegram poll", inGroups, (*Bot).handlePoll},
+		{"election", "[n | close]", "Rank your favourites, instant-runoff style", inGroups, (*Bot).handleElection},
+		{"voteround", "<48h> [n] | close | guest [name] | history", "Vote on a fixed set of movies until time is up", inGroups, (*Bot).handleVoteRound},
+		{"deadline", "[<when> | off]", "When voting closes", everywhere, (*Bot).handleDeadline},
+		{"suggest_from_mine", "[title]", "Put a movie from your own watchlist up for a vote", inGroups, (*Bot).handleSuggestFromMine},
+		{"random", "", "Let the dice pick, weighted by votes", everywhere, (*Bot).handleRandom},
+		{"pickfor", "@people", "Pick what everyone present wants most", inGroups, (*Bot).handlePickFor},
+		{"schedule", "<when> <movie>", "Announce a movie night", everywhere, (*Bot).handleSchedule},
+		{"tonight", "[20:00] [people]", "Ask who's in for a movie tonight", inGroups, (*Bot).handleTonight},
+		{"rewatch", "<movie>", "Put a watched movie back up for a vote", everywhere, (*Bot).handleRewatch},
+		{"history", "[n]", "The movies watched so far, with dates and ratings", everywhere, (*Bot).handleHistory},
+		{"me", "", "Your movie night attendance", everywhere, (*Bot).handleMe},
+		{"stats", "[@user]", "What someone nominated, voted for and watched", everywhere, (*Bot).handleStats},
+		{"leaderboard", "[picks | votes | winrate | nights]", "Who picks, votes and shows up the most", inGroups, (*Bot).handleLeaderboard},
+		{"quiz", "[top]", "Guess the movie from a scrap of its poster", everywhere, (*Bot).handleQuiz},
+		{"notify", "on | off", "DMs about your suggestions and movie nights", everywhere, (*Bot).handleNotify},
+		{"block", "[term]", "Show or extend the blocklist", everywhere, (*Bot).handleBlock},
+		{"publiclist", "[revoke]", "Link to a read-only web list", everywhere, (*Bot).handlePublicList},
+		{"export", "[json | csv | html | diary]", "Download the whole library, or your Letterboxd diary, as a file", everywhere, (*Bot).handleExport},
+		{"help", "", "What the bot can do", everywhere, (*Bot).handleHelp},
+		{"start", "", "Show the quick keyboard", inPrivate, (*Bot).handleStart},
+
+		{"recap", "[quote]", "Wrap up the movie night", inPrivate | forAdmins, (*Bot).handleRecap},
+		{"settings", "[set <key> <value> | unset <key>]", "Chat settings", inPrivate | forAdmins, (*Bot).handleSettings},
+		{"unblock", "<term>", "Take a term off the blocklist", inPrivate | forAdmins, (*Bot).handleUnblock},
+		{"move", "<movie> <list>", "Move a movie to another list", inPrivate | forAdmins, func(b *Bot, ctx context.Context, msg *tgbotapi.Message) {
+			b.handleMoveCopy(ctx, msg, false)
+		}},
+		{"copy", "<movie> <list>", "Copy a movie to another list", inPrivate | forAdmins, func(b *Bot, ctx context.Context, msg *tgbotapi.Message) {
+			b.handleMoveCopy(ctx, msg, true)
+		}},
+		{"remove", "[title]", "Remove a movie from the list", inPrivate | forAdmins, (*Bot).handleRemove},
+		{"import", "", "Reply to a JSON or CSV file to add its movies", inPrivate | forAdmins, (*Bot).handleImport},
+		{"reset", "[confirm]", "Start a fresh season", inPrivate | forAdmins, (*Bot).handleReset},
+
+		{"admin", "", "Owner control panel", forOwners, (*Bot).handleAdmin},
+		{"chats", "", "Chats the bot is in", forOwners, (*Bot).handleChats},
+		{"maintenance", "on [message] | off", "Pause the bot for everyone else", forOwners, (*Bot).handleMaintenance},
+		{"merge", "", "Reply to a movies.json to merge it", forOwners, (*Bot).handleMerge},
+		{"adopt", "", "Move the shared library into this chat", forOwners, (*Bot).handleAdopt},
+		{"compact", "", "Back up, then merge duplicates and drop stale data", forOwners, (*Bot).handleCompact},
+		{"version", "", "Build, uptime, load and switched-on features", forOwners, (*Bot).handleVersion},
+	}
+}
+
+func commandByName(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// Features that can be switched off in the config, by their names there.
+const (
+	featureInline    = "inline_mode"
+	featureWeb       = "web_dashboard"
+	featureScheduler = "scheduler"
+	featureGames     = "games"
+)
+
+// commandFeatures are the commands that belong to a feature; they are gone
+// from /help and the menu while it is off.
+var commandFeatures = map[string]string{
+	"schedule":   featureScheduler,
+	"tonight":    featureScheduler,
+	"recap":      featureScheduler,
+	"me":         featureScheduler,
+	"quiz":       featureGames,
+	"publiclist": featureWeb,
+}
+
+// callbackFeatures are the buttons, by the prefix of their data ("rsvp" of
+// "rsvp|<night>|going"), that belong to a feature. Ones still around from
+// before it was switched off do nothing while it is.
+var callbackFeatures = map[string]string{
+	"rsvp":    featureScheduler,
+	"tonight": featureScheduler,
+	"quiz":    featureGames,
+	"inline":  featureInline,
+}
+
+// commandsIn returns the commands offered in any of scope.
+func (b *Bot) commandsIn(scope commandScope) []command {
+	var out []command
+	for _, c := range commands {
+		if c.scope&scope != 0 && !b.Disabled[commandFeatures[c.name]] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// =====================================================
+// /help and the "/" menu
+// =====================================================
+
+// handleHelp lists the commands the sender can use where they asked.
+func (b *Bot) handleHelp(ctx context.Context, msg *tgbotapi.Message) {
+	scope := inGroups
+	if msg.Chat.Type == "private" {
+		scope = inPrivate
+		if b.isOwner(msg.From.ID) {
+			scope |= forOwners
+		}
+	} else if b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+		scope |= forAdmins
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🎬 Here's what I can do:\n")
+	for _, c := range b.commandsIn(scope) {
+		sb.WriteString("\n/" + c.name)
+		if c.args != "" {
+			sb.WriteString(" " + c.args)
+		}
+		sb.WriteString(" — " + c.desc)
+	}
+	if !b.Disabled[featureInline] {
+		sb.WriteString("\n\nMention me with a title in any chat to share a vote card.")
+	}
+	b.replyText(ctx, msg, sb.String())
+}
+
+func (b *Bot) handleStart(ctx context.Context, msg *tgbotapi.Message) {
+	trace.Logf(ctx, "[BOT] /start from %s", msg.From.UserName)
+	b.sendKeyboard(ctx, msg.Chat.ID)
+}
+
+// RegisterCommands fills the Telegram "/" menu from the registry: one set
+// for private chats, one for groups, a longer one for group admins and the
+// owner commands in the owners' private chats.
+func (b *Bot) RegisterCommands(ctx context.Context) {
+	type menu struct {
+		scope tgbotapi.BotCommandScope
+		cmds  commandScope
+	}
+	menus := []menu{
+		{tgbotapi.NewBotCommandScopeAllPrivateChats(), inPrivate},
+		{tgbotapi.NewBotCommandScopeAllGroupChats(), inGroups},
+		{tgbotapi.NewBotCommandScopeAllChatAdministrators(), inGroups | forAdmins},
+	}
+	for _, id := range b.OwnerIDs {
+		menus = append(menus, menu{tgbotapi.NewBotCommandScopeChat(id), inPrivate | forOwners})
+	}
+
+	for _, m := range menus {
+		var list []tgbotapi.BotCommand
+		for _, c := range b.commandsIn(m.cmds) {
+			list = append(list, tgbotapi.BotCommand{Command: c.name, Description: c.desc})
+		}
+		if _, err := b.request(ctx, tgbotapi.NewSetMyCommandsWithScope(m.scope, list...)); err != nil {
+			log.Printf("[BOT] Setting the %s command menu failed: %v", m.scope.Type, err)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// deadlineTick is how often the deadline scheduler looks at the chats.
+const deadlineTick = time.Minute
+
+// countdowns remembers the countdown vote cards show, per chat for voting
+// deadlines and per movie for releases, so they are only edited when it
+// changes.
+type countdowns[K comparable] struct {
+	mu    sync.Mutex
+	shown map[K]string
+}
+
+// changed records label for key and reports whether it differs from the
+// one shown so far.
+func (c *countdowns[K]) changed(key K, label string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shown == nil {
+		c.shown = make(map[K]string)
+	}
+	if c.shown[key] == label {
+		return false
+	}
+	c.shown[key] = label
+	return true
+}
+
+// =====================================================
+// /deadline — voting closes at a set time
+// =====================================================
+
+// handleDeadline shows, sets ("/deadline fri 20:00") or drops ("/deadline
+// off") the time voting in the chat closes. Changing it is for admins.
+func (b *Bot) handleDeadline(ctx context.Context, msg *tgbotapi.Message) {
+	const usage = "Usage: /deadline <today|tomorrow|fri|2006-01-02> [20:00] or /deadline off"
+	fields := strings.Fields(msg.CommandArguments())
+	chatID := msg.Chat.ID
+
+	if len(fields) == 0 {
+		chat, _ := b.Store.GetChat(chatID)
+		switch {
+		case chat.VoteDeadline.IsZero():
+			b.replyText(ctx, msg, "No voting deadline is set.\n"+usage)
+		case chat.VotingClosed:
+			b.replyText(ctx, msg, "🔒 Voting closed "+b.deadlineTime(chatID, chat.VoteDeadline)+". Set a new deadline or /deadline off to reopen it.")
+		default:
+			b.replyText(ctx, msg, "⏳ Voting closes "+b.deadlineText(chatID, chat.VoteDeadline, time.Now())+".")
+		}
+		return
+	}
+
+	if !b.isAdmin(ctx, chatID, msg.From.ID) {
+		b.replyText(ctx, msg, "⛔ Only chat admins can set the voting deadline.")
+		return
+	}
+
+	if fields[0] == "off" {
+		b.Store.SetVoteDeadline(ctx, chatID, time.Time{})
+		trace.Logf(ctx, "[BOT] %s dropped the voting deadline", msg.From.UserName)
+		b.replyText(ctx, msg, "Voting deadline dropped, votes stay open.")
+		b.syncCardsIn(ctx, chatID)
+		return
+	}
+
+	at, used, err := parseWhen(fields, time.Now().In(b.chatLocation(chatID)))
+	if err != nil || used != len(fields) {
+		text := usage
+		if err != nil {
+			text = "❌ " + err.Error() + "\n" + usage
+		}
+		b.replyText(ctx, msg, text)
+		return
+	}
+	b.setDeadline(ctx, msg, at)
+}
+
+// setDeadline makes voting in msg's chat close at at and shows it on the
+// chat's vote cards.
+func (b *Bot) setDeadline(ctx context.Context, msg *tgbotapi.Message, at time.Time) {
+	b.Store.SetVoteDeadline(ctx, msg.Chat.ID, at)
+	trace.Logf(ctx, "[BOT] %s set the voting deadline to %v", msg.From.UserName, at)
+	b.replyText(ctx, msg, "⏳ Voting closes "+b.deadlineText(msg.Chat.ID, at, time.Now())+". The movie with the most votes then wins.")
+	b.countdowns.changed(msg.Chat.ID, countdown(time.Until(at)))
+	b.syncCardsIn(ctx, msg.Chat.ID)
+}
+
+// attachDeadline sets the deadline given with /movie --until. Anyone may
+// start one; moving one that is already running is for admins.
+func (b *Bot) attachDeadline(ctx context.Context, msg *tgbotapi.Message, at time.Time) bool {
+	chat, _ := b.Store.GetChat(msg.Chat.ID)
+	running := !chat.VoteDeadline.IsZero() && !chat.VotingClosed
+	if running && !b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+		b.replyText(ctx, msg, "⏳ Voting already closes "+b.deadlineTime(msg.Chat.ID, chat.VoteDeadline)+", only admins can move it.")
+		return false
+	}
+	b.setDeadline(ctx, msg, at)
+	return true
+}
+
+// cutUntil takes "--until <when>" out of /movie arguments, e.g. "/movie
+// --until fri 20:00 the thing".
+func cutUntil(args string, now time.Time) (rest string, at time.Time, found bool, err error) {
+	fields := strings.Fields(args)
+	for i, f := range fields {
+		if f != "--until" {
+			continue
+		}
+		at, used, err := parseWhen(fields[i+1:], now)
+		if err != nil {
+			return args, time.Time{}, true, err
+		}
+		fields = append(fields[:i], fields[i+1+used:]...)
+		return strings.Join(fields, " "), at, true, nil
+	}
+	return args, time.Time{}, false, nil
+}
+
+// deadlineTime is when voting closes, in the chat's time zone.
+func (b *Bot) deadlineTime(chatID int64, at time.Time) string {
+	return at.In(b.chatLocation(chatID)).Format("Mon 2 Jan 15:04")
+}
+
+// deadlineText is when voting closes and how long that is from now.
+func (b *Bot) deadlineText(chatID int64, at, now time.Time) string {
+	return fmt.Sprintf("%s (in %s)", b.deadlineTime(chatID, at), countdown(at.Sub(now)))
+}
+
+// countdown renders the time left coarsely, so vote cards need editing at
+// most hourly until the last two hours, then every ten minutes.
+func countdown(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%d days", int(d.Hours()/24))
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("%d h", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d min", max(10, int(math.Ceil(d.Minutes()/10))*10))
+	}
+}
+
+// votingClosed reports whether chatID's deadline has passed.
+func (b *Bot) votingClosed(chatID int64) bool {
+	chat, ok := b.Store.GetChat(chatID)
+	return ok && chat.VotingClosed
+}
+
+// =====================================================
+// DEADLINE SCHEDULER
+// =====================================================
+
+// RunDeadlines closes voting in chats whose deadline is up and keeps the
+// countdown on their vote cards current, until ctx is done. It runs the
+// voting rounds, the tonight polls (unless the scheduler is off) and opens
+// voting on movies that came out too. Deadlines live in the chat registry
+// and rounds in theirs, so ones that passed while the bot was down are
+// closed on start.
+func (b *Bot) RunDeadlines(ctx context.Context) {
+	ticker := time.NewTicker(deadlineTick)
+	defer ticker.Stop()
+	for {
+		if !b.InMaintenance() {
+			b.checkDeadlines(trace.NewContext(ctx), time.Now())
+			b.checkRounds(trace.NewContext(ctx), time.Now())
+			if !b.Disabled[featureScheduler] {
+				b.checkTonight(trace.NewContext(ctx), time.Now())
+			}
+			b.checkReleases(trace.NewContext(ctx), time.Now())
+			b.expirePrompts(trace.NewContext(ctx), time.Now())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Bot) checkDeadlines(ctx context.Context, now time.Time) {
+	for _, chat := range b.Store.GetChats() {
+		if chat.VoteDeadline.IsZero() || chat.VotingClosed || chat.Left {
+			continue
+		}
+		if now.Before(chat.VoteDeadline) {
+			if b.countdowns.changed(chat.ID, countdown(chat.VoteDeadline.Sub(now))) {
+				b.syncCardsIn(ctx, chat.ID)
+			}
+			continue
+		}
+		if b.Store.CloseVoting(ctx, chat.ID) {
+			trace.Logf(ctx, "[BOT] Voting deadline reached in chat %d", chat.ID)
+			b.syncCardsIn(ctx, chat.ID)
+			b.announceWinner(ctx, chat.ID)
+		}
+	}
+}
+
+// announceWinner names the unwatched movie with the best score, or the
+// ones tied for it.
+func (b *Bot) announceWinner(ctx context.Context, chatID int64) {
+	var top []storage.Movie
+	for _, m := range b.Store.GetMovies(b.library(chatID), "") {
+		if storage.IsWatched(m) || len(m.Votes) == 0 {
+			continue
+		}
+		switch score := storage.Score(m); {
+		case len(top) == 0 || score > storage.Score(top[0]):
+			top = []storage.Movie{m}
+		case score == storage.Score(top[0]):
+			top = append(top, m)
+		}
+	}
+
+	var text string
+	switch len(top) {
+	case 0:
+		text = "⏰ Voting is closed, but nobody voted."
+	case 1:
+		text = fmt.Sprintf("⏰ Voting is closed!\n🏆 %s (%d) wins with %s.", top[0].Title, top[0].Year, scoreText(top[0]))
+	default:
+		names := make([]string, len(top))
+		for i, m := range top {
+			names[i] = fmt.Sprintf("%s (%d)", m.Title, m.Year)
+		}
+		text = fmt.Sprintf("⏰ Voting is closed!\n🤝 It's a tie at %s: %s. Try /election to settle it.", scoreText(top[0]), strings.Join(names, ", "))
+	}
+	text += "\nAdmins reopen voting with /deadline <when> or /deadline off."
+	b.send(ctx, tgbotapi.NewMessage(chatID, text))
+}
+
+// syncCardsIn re-renders every vote card in chatID, e.g. for a new
+// countdown.
+func (b *Bot) syncCardsIn(ctx context.Context, chatID int64) {
+	for _, m := range b.Store.GetLibrary(b.library(chatID)) {
+		for _, ref := range b.Store.GetMessages(m.ID) {
+			if ref.ChatID != chatID || ref.InlineID != "" {
+				continue
+			}
+			text, keyboard := b.voteCard(m, chatID, ref.Links)
+			if _, err := b.send(ctx, editCard(chatID, ref.MessageID, ref.Photo, text, keyboard)); err != nil && !notModified(err) {
+				trace.Logf(ctx, "[BOT] Updating card of %s in chat %d failed: %v", m.Title, chatID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"sync"
+	"time"
+
+	"moviebot/internal/i18n"
+)
+
+// toggleDebounce is how long after a vote or watched toggle another tap on
+// the same button by the same user counts as a double tap and is ignored.
+const toggleDebounce = time.Second
+
+// debouncer remembers recent toggles per user and button so that racing
+// callbacks from a double tap don't switch a vote on and straight off again.
+type debouncer struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// tooSoon reports whether key fired less than toggleDebounce ago, and
+// records it as firing now otherwise. Check and record are one step, so of
+// two concurrent taps exactly one gets through.
+func (d *debouncer) tooSoon(key string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if t, ok := d.last[key]; ok && now.Sub(t) < toggleDebounce {
+		return true
+	}
+	if d.last == nil {
+		d.last = make(map[string]time.Time)
+	}
+	if len(d.last) > 256 {
+		for k, t := range d.last {
+			if now.Sub(t) >= toggleDebounce {
+				delete(d.last, k)
+			}
+		}
+	}
+	d.last[key] = now
+	return false
+}
+
+func voteToast(lang string, on bool) string {
+	if on {
+		return "👍 " + i18n.T(lang, "Vote added")
+	}
+	return i18n.T(lang, "Vote removed")
+}
+
+func watchedToast(lang string, on bool) string {
+	if on {
+		return "👁 " + i18n.T(lang, "Marked as watched")
+	}
+	return i18n.T(lang, "No longer marked as watched")
+}
@@ -0,0 +1,137 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// DISCUSSION THREADS
+// =====================================================
+
+// supergroupIDOffset turns a supergroup chat ID into the ID used in
+// t.me/c/ links.
+const supergroupIDOffset = -1000000000000
+
+// discussionLink links to a discussion, or returns "" in chats without
+// message links (basic groups, private chats).
+func discussionLink(chatID int64, d storage.Discussion) string {
+	if chatID > supergroupIDOffset {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/c/%d/%d", supergroupIDOffset-chatID, d.MessageID)
+}
+
+func discussionPrompt(movie storage.Movie) string {
+	return fmt.Sprintf("💬 How was %s (%d)? Talk about it here and reply with a rating like 8/10.", movie.Title, movie.Year)
+}
+
+// maybeOpenDiscussion starts a discussion in chatID once a movie counts as
+// watched there: a forum topic when enabled and possible, otherwise a reply
+// chain under the vote card.
+func (b *Bot) maybeOpenDiscussion(ctx context.Context, chatID int64, cardID int, movie storage.Movie) storage.Movie {
+	if !b.Discussions || !storage.IsWatched(movie) {
+		return movie
+	}
+	if _, ok := movie.DiscussionIn(chatID); ok {
+		return movie
+	}
+
+	d, ok := b.openTopic(ctx, chatID, movie)
+	if !ok {
+		reply := tgbotapi.NewMessage(chatID, discussionPrompt(movie))
+		reply.ReplyToMessageID = cardID
+		sent, err := b.send(ctx, reply)
+		if err != nil {
+			return movie
+		}
+		d = storage.Discussion{ChatID: chatID, MessageID: sent.MessageID}
+	}
+
+	updated, err := b.Store.AddDiscussion(ctx, movie.ID, d)
+	if err != nil {
+		return movie
+	}
+	return updated
+}
+
+// openTopic creates a forum topic for the movie. It fails in chats that are
+// not forums or where the bot may not manage topics.
+func (b *Bot) openTopic(ctx context.Context, chatID int64, movie storage.Movie) (storage.Discussion, bool) {
+	if !b.DiscussionTopics {
+		return storage.Discussion{}, false
+	}
+
+	params := tgbotapi.Params{}
+	params.AddFirstValid("chat_id", chatID)
+	params["name"] = fmt.Sprintf("🎬 %s (%d)", movie.Title, movie.Year)
+	resp, err := b.call(ctx, "createForumTopic", params)
+	if err != nil {
+		return storage.Discussion{}, false
+	}
+
+	var topic struct {
+		MessageThreadID int `json:"message_thread_id"`
+	}
+	if err := json.Unmarshal(resp.Result, &topic); err != nil || topic.MessageThreadID == 0 {
+		trace.Logf(ctx, "[BOT] Unexpected createForumTopic result: %s", resp.Result)
+		return storage.Discussion{}, false
+	}
+
+	params = tgbotapi.Params{}
+	params.AddFirstValid("chat_id", chatID)
+	params.AddNonZero("message_thread_id", topic.MessageThreadID)
+	params["text"] = discussionPrompt(movie)
+	b.call(ctx, "sendMessage", params)
+
+	return storage.Discussion{ChatID: chatID, MessageID: topic.MessageThreadID, Topic: true}, true
+}
+
+// ratingPattern matches "8", "8/10" or "... 8 / 10 ...".
+var ratingPattern = regexp.MustCompile(`^\s*(10|[1-9])\s*(?:/\s*10)?\s*$|\b(10|[1-9])\s*/\s*10\b`)
+
+func parseRating(text string) (int, bool) {
+	m := ratingPattern.FindStringSubmatch(text)
+	if m == nil {
+		return 0, false
+	}
+	n := m[1]
+	if n == "" {
+		n = m[2]
+	}
+	r, err := strconv.Atoi(n)
+	return r, err == nil
+}
+
+// collectRating stores ratings posted in a discussion. In a forum topic,
+// messages without an explicit reply point at the topic's first message, so
+// both flavours are matched the same way. It reports whether msg was one.
+func (b *Bot) collectRating(ctx context.Context, msg *tgbotapi.Message) bool {
+	if msg.ReplyToMessage == nil {
+		return false
+	}
+	movie, ok := b.Store.MovieByDiscussion(msg.Chat.ID, msg.ReplyToMessage.MessageID)
+	if !ok {
+		return false
+	}
+	rating, ok := parseRating(msg.Text)
+	if !ok {
+		return true // just chatting
+	}
+
+	movie, err := b.Store.SetRating(ctx, movie.ID, strconv.FormatInt(msg.From.ID, 10), rating)
+	if err != nil {
+		return true
+	}
+	avg, n := storage.AverageRating(movie)
+	b.replyText(ctx, msg, fmt.Sprintf("⭐ Noted %d/10. %s averages %.1f from %d ratings.", rating, movie.Title, avg, n))
+	b.syncMovie(ctx, movie)
+	return true
+}
@@ -0,0 +1,231 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// maxElectionCandidates keeps the ballot keyboard readable.
+const maxElectionCandidates = 8
+
+// =====================================================
+// /election — ranked-choice vote with instant-runoff counting
+// =====================================================
+
+// handleElection opens a ranked-choice vote over the top unwatched movies.
+// Everyone taps up to storage.MaxRanks of them in order of preference;
+// "/election close" (admins only) counts the ballots and names the winner.
+func (b *Bot) handleElection(ctx context.Context, msg *tgbotapi.Message) {
+	arg := strings.TrimSpace(msg.CommandArguments())
+	open, hasOpen := b.Store.OpenElection(msg.Chat.ID)
+
+	if arg == "close" {
+		if !b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+			b.replyText(ctx, msg, "⛔ Only chat admins can close the election.")
+			return
+		}
+		if !hasOpen {
+			b.replyText(ctx, msg, "There is no open election here.")
+			return
+		}
+		b.closeElection(ctx, open)
+		return
+	}
+
+	if hasOpen {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, "🗳 There is already an election running, rank your picks here. Admins can count it with /election close.")
+		reply.ReplyToMessageID = open.MessageID
+		if _, err := b.send(ctx, reply); err == nil {
+			return
+		}
+		// The ballot message is gone; count what there is and start over.
+		b.Store.CloseElection(ctx, open.ID)
+	}
+
+	n := 5
+	if arg != "" {
+		v, err := strconv.Atoi(arg)
+		if err != nil || v < 2 {
+			b.replyText(ctx, msg, fmt.Sprintf("Usage: /election [candidates, 2-%d] or /election close", maxElectionCandidates))
+			return
+		}
+		n = min(v, maxElectionCandidates)
+	}
+
+	format := b.listFormat(msg.Chat.ID)
+	format.SortBy = storage.SortByVotes
+	format.Render = storage.RenderCards
+	format.Limit = n
+	_, ids := storage.BuildNumberedList(b.Store.GetMovies(b.library(msg.Chat.ID), ""), format)
+	if len(ids) < 2 {
+		b.replyText(ctx, msg, "🗳 An election needs at least two unwatched movies, add some with /movie.")
+		return
+	}
+
+	e := b.Store.AddElection(ctx, storage.Election{
+		ChatID:    msg.Chat.ID,
+		MovieIDs:  ids,
+		CreatedAt: time.Now(),
+	})
+	ballot := tgbotapi.NewMessage(msg.Chat.ID, b.electionText(e))
+	ballot.ReplyMarkup = b.electionKeyboard(e)
+	sent, err := b.send(ctx, ballot)
+	if err != nil {
+		b.Store.CloseElection(ctx, e.ID)
+		b.replyText(ctx, msg, "❌ Couldn't start the election.")
+		return
+	}
+	b.Store.SetElectionMessage(ctx, e.ID, sent.MessageID)
+	trace.Logf(ctx, "[BOT] %s started election %s over %d movies", msg.From.UserName, e.ID, len(ids))
+}
+
+func (b *Bot) electionText(e storage.Election) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🗳 Ranked-choice vote! Tap up to %d movies, your favourite first. Tap a movie again to take it back.\n\n", storage.MaxRanks)
+	for i, id := range e.MovieIDs {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, b.movieLabel(id))
+	}
+	fmt.Fprintf(&sb, "\nBallots cast: %d", len(e.Ballots))
+	return sb.String()
+}
+
+func (b *Bot) electionKeyboard(e storage.Election) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, id := range e.MovieIDs {
+		label := truncateRunes(fmt.Sprintf("%d. %s", i+1, b.movieLabel(id)), 60)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("rank|%s|%d", e.ID, i)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🧹 Clear my ballot", fmt.Sprintf("rank|%s|clear", e.ID)),
+		tgbotapi.NewInlineKeyboardButtonData("🏁 Count", fmt.Sprintf("rank|%s|close", e.ID)),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// movieLabel is "Title (Year)", or a placeholder for a movie removed since.
+func (b *Bot) movieLabel(id string) string {
+	m, ok := b.Store.GetMovieByID(id)
+	if !ok {
+		return "(removed movie)"
+	}
+	return fmt.Sprintf("%s (%d)", m.Title, m.Year)
+}
+
+// handleRankCallback handles the ballot buttons: "rank|<election>|<n>" ranks
+// candidate n next, "clear" empties the ballot and "close" counts (admins).
+func (b *Bot) handleRankCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cb.Data, "|")
+	if len(parts) != 3 {
+		return
+	}
+	e, ok := b.Store.GetElection(parts[1])
+	if !ok || e.Closed {
+		b.answerToast(ctx, cb, "This election is over.")
+		return
+	}
+	userID := strconv.FormatInt(cb.From.ID, 10)
+
+	switch parts[2] {
+	case "close":
+		if cb.Message == nil || !b.isAdmin(ctx, cb.Message.Chat.ID, cb.From.ID) {
+			b.answerToast(ctx, cb, "⛔ Only chat admins can count the votes.")
+			return
+		}
+		b.closeElection(ctx, e)
+		return
+
+	case "clear":
+		if err := b.Store.ClearBallot(ctx, e.ID, userID); err != nil {
+			b.answerToast(ctx, cb, "❌ "+err.Error())
+			return
+		}
+		b.answerToast(ctx, cb, "🧹 Ballot cleared.")
+
+	default:
+		i, err := strconv.Atoi(parts[2])
+		if err != nil || i < 0 || i >= len(e.MovieIDs) {
+			return
+		}
+		ballot, err := b.Store.RankMovie(ctx, e.ID, userID, e.MovieIDs[i])
+		if err != nil {
+			b.answerToast(ctx, cb, "❌ "+err.Error())
+			return
+		}
+		b.answerToast(ctx, cb, b.ballotText(ballot))
+	}
+
+	if e, ok = b.Store.GetElection(e.ID); ok {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(e.ChatID, e.MessageID, b.electionText(e), b.electionKeyboard(e))
+		if _, err := b.send(ctx, edit); err != nil && !notModified(err) {
+			trace.Logf(ctx, "[BOT] Updating election %s failed: %v", e.ID, err)
+		}
+	}
+}
+
+// ballotText tells a voter how their ballot stands.
+func (b *Bot) ballotText(ballot []string) string {
+	if len(ballot) == 0 {
+		return "Your ballot is empty."
+	}
+	picks := make([]string, len(ballot))
+	for i, id := range ballot {
+		picks[i] = fmt.Sprintf("%d. %s", i+1, b.movieLabel(id))
+	}
+	return "Your ranking: " + strings.Join(picks, ", ")
+}
+
+// closeElection counts the ballots, turns the ballot message into the
+// result and announces the winner.
+func (b *Bot) closeElection(ctx context.Context, e storage.Election) {
+	e, rounds, err := b.Store.CloseElection(ctx, e.ID)
+	if err != nil {
+		return
+	}
+
+	var sb strings.Builder
+	if e.Winner == "" {
+		sb.WriteString("🗳 The election closed without a single ballot.")
+	} else {
+		fmt.Fprintf(&sb, "🏆 The group has chosen: %s\n", b.movieLabel(e.Winner))
+		fmt.Fprintf(&sb, "\nBallots: %d, counted by instant runoff:\n", len(e.Ballots))
+		for i, r := range rounds {
+			fmt.Fprintf(&sb, "\nRound %d: %s", i+1, b.roundText(e, r))
+		}
+	}
+	text := sb.String()
+
+	edit := tgbotapi.NewEditMessageText(e.ChatID, e.MessageID, text)
+	if _, err := b.send(ctx, edit); err != nil {
+		// The ballot message is gone; announce the result on its own.
+		b.send(ctx, tgbotapi.NewMessage(e.ChatID, text))
+	} else if e.Winner != "" {
+		reply := tgbotapi.NewMessage(e.ChatID, fmt.Sprintf("🍿 %s wins the election!", b.movieLabel(e.Winner)))
+		reply.ReplyToMessageID = e.MessageID
+		b.send(ctx, reply)
+	}
+	trace.Logf(ctx, "[BOT] Election %s closed, winner %q", e.ID, e.Winner)
+}
+
+// roundText lists a round's counts in ballot order and who dropped out.
+func (b *Bot) roundText(e storage.Election, r storage.RunoffRound) string {
+	var counts []string
+	for _, id := range e.MovieIDs {
+		if n, in := r.Counts[id]; in {
+			counts = append(counts, fmt.Sprintf("%s %d", b.movieLabel(id), n))
+		}
+	}
+	text := strings.Join(counts, " · ")
+	if r.Eliminated != "" {
+		text += fmt.Sprintf(" — %s is out", b.movieLabel(r.Eliminated))
+	}
+	return text
+}
@@ -0,0 +1,137 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// /export
+// =====================================================
+
+// handleExport sends the whole library as a document: JSON (the default, in
+// the movies.json format), CSV for spreadsheets or a static HTML page;
+// "diary" sends the sender's own viewings for Letterboxd instead.
+func (b *Bot) handleExport(ctx context.Context, msg *tgbotapi.Message) {
+	format := strings.ToLower(strings.TrimSpace(msg.CommandArguments()))
+	trace.Logf(ctx, "[BOT] /export %s from %s", format, msg.From.UserName)
+
+	var (
+		data []byte
+		err  error
+	)
+	movies := b.Store.GetLibrary(b.library(msg.Chat.ID))
+	switch format {
+	case "", "json":
+		format = "json"
+		data, err = storage.ExportJSON(movies)
+	case "csv":
+		data, err = storage.ExportCSV(movies)
+	case "html":
+		data, err = storage.ExportHTML(movies, "Movie night watchlist")
+	case "diary":
+		b.sendDiary(ctx, msg, movies)
+		return
+	default:
+		reply := tgbotapi.NewMessage(msg.Chat.ID, "Usage: /export [json | csv | html | diary]")
+		reply.ReplyToMessageID = msg.MessageID
+		b.send(ctx, reply)
+		return
+	}
+
+	if err != nil {
+		trace.Logf(ctx, "[BOT] Export failed: %v", err)
+		b.send(ctx, tgbotapi.NewMessage(msg.Chat.ID, "❌ Export failed."))
+		return
+	}
+
+	name := fmt.Sprintf("watchlist-%s.%s", time.Now().Format("2006-01-02"), format)
+	doc := tgbotapi.NewDocument(msg.Chat.ID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	doc.ReplyToMessageID = msg.MessageID
+	if _, err := b.send(ctx, doc); err != nil {
+		b.send(ctx, tgbotapi.NewMessage(msg.Chat.ID, "❌ Couldn't upload the export."))
+	}
+}
+
+// =====================================================
+// /import — admins only
+// =====================================================
+
+// handleImport adds the movies of a JSON or CSV file (an /export from
+// another chat or instance, or a spreadsheet) that aren't on the lists yet.
+// Admins reply to the file with /import.
+func (b *Bot) handleImport(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isManager(ctx, msg.Chat.ID, msg.From.ID) {
+		b.replyText(ctx, msg, "⛔ Only chat admins can import movies.")
+		return
+	}
+
+	doc := msg.Document
+	if msg.ReplyToMessage != nil && msg.ReplyToMessage.Document != nil {
+		doc = msg.ReplyToMessage.Document
+	}
+	if doc == nil {
+		b.replyText(ctx, msg, "Reply to a JSON or CSV file with /import. It needs a title column; year, list, imdb_id, voters and watched_by are read too.")
+		return
+	}
+	if doc.FileSize > maxDownloadSize {
+		b.replyText(ctx, msg, "❌ That file is too big to import.")
+		return
+	}
+
+	data, err := b.downloadDocument(ctx, doc)
+	if err != nil {
+		trace.Logf(ctx, "[BOT] /import download failed: %v", err)
+		b.replyText(ctx, msg, "❌ Could not download the file.")
+		return
+	}
+	movies, err := storage.ParseImport(data)
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+doc.FileName+": "+err.Error())
+		return
+	}
+
+	report := b.Store.ImportMovies(ctx, b.library(msg.Chat.ID), movies)
+	trace.Logf(ctx, "[BOT] /import of %s by %s: %d added, %d skipped", doc.FileName, msg.From.UserName, len(report.Added), len(report.Skipped))
+	b.replyText(ctx, msg, "📥 Import complete\n"+report.String())
+	if len(report.Added) > 0 {
+		b.syncListMessages(ctx)
+	}
+}
+
+// sendDiary sends the sender's own viewings and ratings as a Letterboxd
+// diary CSV, so every member can import their history into their account.
+func (b *Bot) sendDiary(ctx context.Context, msg *tgbotapi.Message, movies []storage.Movie) {
+	userID := strconv.FormatInt(msg.From.ID, 10)
+	if !slices.ContainsFunc(movies, func(m storage.Movie) bool { return m.Watched[userID] || watchedBefore(m, userID) }) {
+		b.replyText(ctx, msg, "📔 You haven't marked anything on the list as watched yet.")
+		return
+	}
+	data, err := storage.ExportDiary(movies, b.Store.Nights(msg.Chat.ID), userID, b.chatLocation(msg.Chat.ID))
+	if err != nil {
+		trace.Logf(ctx, "[BOT] Diary export failed: %v", err)
+		b.send(ctx, tgbotapi.NewMessage(msg.Chat.ID, "❌ Export failed."))
+		return
+	}
+
+	name := fmt.Sprintf("letterboxd-diary-%s.csv", time.Now().Format("2006-01-02"))
+	doc := tgbotapi.NewDocument(msg.Chat.ID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	doc.Caption = "📔 Your diary, " + msg.From.FirstName + ". Import it at letterboxd.com/import"
+	doc.ReplyToMessageID = msg.MessageID
+	if _, err := b.send(ctx, doc); err != nil {
+		b.send(ctx, tgbotapi.NewMessage(msg.Chat.ID, "❌ Couldn't upload the export."))
+	}
+}
+
+// watchedBefore reports whether userID watched m in an earlier round.
+func watchedBefore(m storage.Movie, userID string) bool {
+	return slices.ContainsFunc(m.History, func(v storage.Viewing) bool { return v.Watched[userID] })
+}
@@ -0,0 +1,70 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+const (
+	defaultHistory = 15
+	// maxHistory keeps the log well within a message (4096 characters).
+	maxHistory = 50
+)
+
+// =====================================================
+// 📜 /history [n]
+// =====================================================
+
+// handleHistory sends the last n movies the chat watched, oldest first,
+// with the date and the group's rating.
+func (b *Bot) handleHistory(ctx context.Context, msg *tgbotapi.Message) {
+	n := defaultHistory
+	if arg := strings.TrimSpace(msg.CommandArguments()); arg != "" {
+		v, err := strconv.Atoi(arg)
+		if err != nil || v < 1 {
+			b.replyText(ctx, msg, "Usage: /history [n]")
+			return
+		}
+		n = min(v, maxHistory)
+	}
+	trace.Logf(ctx, "[BOT] /history %d from %s", n, msg.From.UserName)
+
+	entries := b.Store.WatchHistory(b.library(msg.Chat.ID))
+	if len(entries) == 0 {
+		b.replyText(ctx, msg, "📜 Nothing watched yet. Mark a movie watched on its card, or with /seen.")
+		return
+	}
+	b.replyText(ctx, msg, historyText(entries, n, b.chatLocation(msg.Chat.ID)))
+}
+
+// historyText renders the last n entries, one line each:
+// "Sat 3 Oct 2026 🍿 Alien (1979) · ⭐ 8.2 (4) 🔁".
+func historyText(entries []storage.WatchEntry, n int, loc *time.Location) string {
+	var sb strings.Builder
+	sb.WriteString("📜 Watch history\n")
+	if len(entries) > n {
+		fmt.Fprintf(&sb, "\n… and %d earlier", len(entries)-n)
+		entries = entries[len(entries)-n:]
+	}
+	for _, e := range entries {
+		icon := "👀"
+		if e.Night {
+			icon = "🍿"
+		}
+		fmt.Fprintf(&sb, "\n%s %s %s (%d)", e.At.In(loc).Format("Mon 2 Jan 2006"), icon, e.Movie.Title, e.Movie.Year)
+		if avg, raters := storage.AverageRating(e.Movie); raters > 0 {
+			fmt.Fprintf(&sb, " · ⭐ %.1f (%d)", avg, raters)
+		}
+		if e.Rewatch {
+			sb.WriteString(" 🔁")
+		}
+	}
+	return sb.String()
+}
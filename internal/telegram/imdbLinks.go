@@ -0,0 +1,158 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/omdb"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// imdbLinkPattern finds the IMDb ID in a title URL, localized ones
+// ("imdb.com/de/title/...") and the mobile site included.
+var imdbLinkPattern = regexp.MustCompile(`(?i)imdb\.com/(?:[a-z]{2}(?:-[a-z]{2})?/)?title/(tt\d{7,})`)
+
+// maxLinkOffers caps the cards one message with several links gets.
+const maxLinkOffers = 3
+
+// =====================================================
+// IMDb LINKS — offer to add pasted movies
+// =====================================================
+
+// imdbLinks returns the IMDb IDs of the title links in msg, in order and
+// without repeats.
+func imdbLinks(msg *tgbotapi.Message) []string {
+	var ids []string
+	for _, e := range msg.Entities {
+		var url string
+		switch e.Type {
+		case "url":
+			url = entityText(msg.Text, e)
+		case "text_link":
+			url = e.URL
+		}
+		m := imdbLinkPattern.FindStringSubmatch(url)
+		if m == nil {
+			continue
+		}
+		if id := strings.ToLower(m[1]); !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// entityText is the part of text an entity covers; Telegram counts its
+// offsets in UTF-16 code units.
+func entityText(text string, e tgbotapi.MessageEntity) string {
+	units := utf16.Encode([]rune(text))
+	if e.Offset < 0 || e.Length < 0 || e.Offset+e.Length > len(units) {
+		return ""
+	}
+	return string(utf16.Decode(units[e.Offset : e.Offset+e.Length]))
+}
+
+// offerImdbLinks answers IMDb title links in msg with a card offering to
+// add the movie, or with its vote card when it is on the list already. It
+// reports whether msg had any.
+func (b *Bot) offerImdbLinks(ctx context.Context, msg *tgbotapi.Message) bool {
+	ids := imdbLinks(msg)
+	if len(ids) > maxLinkOffers {
+		ids = ids[:maxLinkOffers]
+	}
+	for _, id := range ids {
+		if movie, ok := b.onMainList(msg.Chat.ID, id); ok {
+			trace.Logf(ctx, "[BOT] %s linked %s, already listed", msg.From.UserName, movie.Title)
+			b.createOrUpdateVoteMessage(ctx, msg.Chat.ID, movie.ID)
+			continue
+		}
+		d, err := b.OMDb.GetByID(ctx, id)
+		if err != nil || d == nil {
+			trace.Logf(ctx, "[OMDb] Looking up linked %s failed: %v", id, err)
+			continue
+		}
+		trace.Logf(ctx, "[BOT] %s linked %s (%s)", msg.From.UserName, d.Title, id)
+		b.sendCard(ctx, msg.Chat.ID, msg.MessageID, d.Poster, linkOfferText(d), tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("➕ Add to the watchlist", "imdb|add|"+id),
+				tgbotapi.NewInlineKeyboardButtonData("✖️ No thanks", "imdb|no|"+id),
+			),
+		))
+	}
+	return len(ids) > 0
+}
+
+// linkOfferText describes a linked movie above the add buttons.
+func linkOfferText(d *omdb.Details) string {
+	known := func(s string) bool { return s != "" && s != "N/A" }
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%s* (%s)\n", d.Title, d.Year)
+	if known(d.Runtime) {
+		fmt.Fprintf(&sb, "⏱ %s\n", d.Runtime)
+	}
+	if known(d.ImdbRating) {
+		fmt.Fprintf(&sb, "⭐ IMDb %s/10\n", d.ImdbRating)
+	}
+	if known(d.Genre) {
+		fmt.Fprintf(&sb, "🎭 %s\n", d.Genre)
+	}
+	sb.WriteString("\nAdd it to the watchlist?")
+	return sb.String()
+}
+
+// onMainList finds a movie on chatID's main list by its IMDb ID.
+func (b *Bot) onMainList(chatID int64, imdbID string) (storage.Movie, bool) {
+	for _, m := range b.Store.GetMovies(b.library(chatID), "") {
+		if strings.EqualFold(m.ImdbID, imdbID) {
+			return m, true
+		}
+	}
+	return storage.Movie{}, false
+}
+
+// handleImdbCallback adds a linked movie, "imdb|add|<id>", or withdraws the
+// offer, "imdb|no|<id>".
+func (b *Bot) handleImdbCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cb.Data, "|")
+	if len(parts) != 3 || cb.Message == nil {
+		return
+	}
+	chatID, id := cb.Message.Chat.ID, parts[2]
+	b.request(ctx, tgbotapi.NewDeleteMessage(chatID, cb.Message.MessageID))
+	if parts[1] != "add" {
+		return
+	}
+
+	if movie, ok := b.onMainList(chatID, id); ok {
+		b.createOrUpdateVoteMessage(ctx, chatID, movie.ID)
+		return
+	}
+	d, err := b.OMDb.GetByID(ctx, id)
+	if err != nil || d == nil {
+		b.answerToast(ctx, cb, "⚠️ "+searchDownText)
+		return
+	}
+	if term, blocked := b.Store.BlockedTerm(chatID, d.Title); blocked {
+		trace.Logf(ctx, "[BOT] '%s' refused, blocked by %q", d.Title, term)
+		b.send(ctx, tgbotapi.NewMessage(chatID, refusal(d.Title)))
+		return
+	}
+	movieID, _ := b.addSearchResult(ctx, chatID, cb.From, omdb.SearchResult{
+		Title:  d.Title,
+		Year:   d.Year,
+		ImdbID: d.ImdbID,
+		Poster: d.Poster,
+	}, "")
+	if movieID == "" {
+		b.answerToast(ctx, cb, "❌ Couldn't add that movie")
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s added linked %s", cb.From.UserName, d.Title)
+	b.createOrUpdateVoteMessage(ctx, chatID, movieID)
+}
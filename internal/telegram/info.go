@@ -0,0 +1,189 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/omdb"
+	"moviebot/internal/refresh"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+var imdbIDPattern = regexp.MustCompile(`^tt\d{7,}$`)
+
+// =====================================================
+// /info — full details of a movie
+// =====================================================
+
+// handleInfo shows everything known about a movie: plot, genre, director,
+// runtime and ratings. Movies on a list that still lack details are looked
+// up and keep them; other titles are looked up without being added.
+func (b *Bot) handleInfo(ctx context.Context, msg *tgbotapi.Message) {
+	ref := strings.TrimSpace(msg.CommandArguments())
+	if ref == "" {
+		b.replyText(ctx, msg, "Usage: /info <title or IMDb ID>")
+		return
+	}
+	trace.Logf(ctx, "[BOT] /info %q from %s", ref, msg.From.UserName)
+
+	movie, listed, err := b.infoMovie(ctx, msg.Chat.ID, ref)
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error())
+		return
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, infoText(movie, listed))
+	reply.ParseMode = "Markdown"
+	reply.ReplyToMessageID = msg.MessageID
+	b.send(ctx, reply)
+}
+
+// infoMovie resolves ref to a movie with its details, reporting whether it
+// is on a list of chatID.
+func (b *Bot) infoMovie(ctx context.Context, chatID int64, ref string) (storage.Movie, bool, error) {
+	all := b.Store.GetLibrary(b.library(chatID))
+
+	var (
+		movie storage.Movie
+		found bool
+	)
+	if id := strings.ToLower(ref); imdbIDPattern.MatchString(id) {
+		i := slices.IndexFunc(all, func(m storage.Movie) bool { return m.ImdbID == id })
+		if i >= 0 {
+			movie, found = all[i], true
+		} else {
+			d, err := b.OMDb.GetByID(ctx, id)
+			if err != nil || d == nil {
+				return storage.Movie{}, false, fmt.Errorf("OMDb doesn't know %s", id)
+			}
+			return movieFromDetails(d), false, nil
+		}
+	} else {
+		m, err := storage.FindMovie(all, ref)
+		switch {
+		case err == nil:
+			movie, found = m, true
+		case strings.HasPrefix(err.Error(), "no movie matches"):
+			title, year := splitTitleYear(ref)
+			d, err := b.OMDb.GetByTitle(ctx, title, year)
+			if err != nil || d == nil {
+				return storage.Movie{}, false, fmt.Errorf("no movie matches %q, on the lists or on OMDb", ref)
+			}
+			return movieFromDetails(d), false, nil
+		default:
+			return storage.Movie{}, false, err
+		}
+	}
+
+	if found && movie.Plot == "" {
+		b.enrichMovie(ctx, movie.ID)
+		if m, ok := b.Store.GetMovieByID(movie.ID); ok {
+			movie = m
+		}
+	}
+	return movie, found, nil
+}
+
+// splitTitleYear takes a trailing year off "Alien 1979".
+func splitTitleYear(ref string) (string, int) {
+	i := strings.LastIndex(ref, " ")
+	if i < 0 {
+		return ref, 0
+	}
+	year, err := strconv.Atoi(strings.Trim(ref[i+1:], "()"))
+	if err != nil || year < 1870 || year > 2200 {
+		return ref, 0
+	}
+	return ref[:i], year
+}
+
+// movieFromDetails holds an OMDb record that isn't on any list, for
+// rendering only.
+func movieFromDetails(d *omdb.Details) storage.Movie {
+	year, _ := strconv.Atoi(d.Year[:min(4, len(d.Year))])
+	md := refresh.MetadataFrom(d)
+	return storage.Movie{
+		Title:         d.Title,
+		Year:          year,
+		ImdbID:        md.ImdbID,
+		Poster:        md.Poster,
+		Runtime:       md.Runtime,
+		ImdbRating:    md.ImdbRating,
+		Plot:          md.Plot,
+		Genre:         md.Genre,
+		Director:      md.Director,
+		Rated:         md.Rated,
+		CriticRatings: md.CriticRatings,
+	}
+}
+
+// infoText renders the /info card.
+func infoText(m storage.Movie, listed bool) string {
+	known := func(s string) bool { return s != "" && s != "N/A" }
+	esc := func(s string) string { return tgbotapi.EscapeText("Markdown", s) }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🎬 *%s* (%d)\n", esc(m.Title), m.Year)
+
+	var facts []string
+	for _, f := range []string{m.Rated, m.Runtime, m.Genre} {
+		if known(f) {
+			facts = append(facts, f)
+		}
+	}
+	if len(facts) > 0 {
+		sb.WriteString(esc(strings.Join(facts, " · ")) + "\n")
+	}
+	if known(m.Director) {
+		fmt.Fprintf(&sb, "🎥 %s\n", esc(m.Director))
+	}
+	if len(m.Tags)+len(m.NotTags) > 0 {
+		fmt.Fprintf(&sb, "🏷 %s\n", esc(strings.Join(storage.MovieTags(m), ", ")))
+	}
+	if known(m.Plot) {
+		fmt.Fprintf(&sb, "\n%s\n", esc(m.Plot))
+	}
+
+	var ratings []string
+	if known(m.ImdbRating) {
+		ratings = append(ratings, fmt.Sprintf("⭐ IMDb %s/10", m.ImdbRating))
+	}
+	for _, source := range slices.Sorted(maps.Keys(m.CriticRatings)) {
+		if source != "Internet Movie Database" {
+			ratings = append(ratings, fmt.Sprintf("%s %s", esc(source), esc(m.CriticRatings[source])))
+		}
+	}
+	if avg, n := storage.AverageRating(m); n > 0 {
+		ratings = append(ratings, fmt.Sprintf("🍿 Group %.1f/10 (%d)", avg, n))
+	}
+	if len(ratings) > 0 {
+		sb.WriteString("\n" + strings.Join(ratings, "\n") + "\n")
+	}
+
+	sb.WriteString("\n")
+	switch {
+	case !listed:
+		sb.WriteString("Not on any list yet, add it with /movie.\n")
+	case storage.IsWatched(m):
+		fmt.Fprintf(&sb, "👁 Watched by %d\n", len(m.Watched))
+	default:
+		fmt.Fprintf(&sb, "👍 %d votes on %s\n", len(m.Votes), listLabel(m.List))
+	}
+	if known(m.Poster) {
+		fmt.Fprintf(&sb, "[Poster](%s)", m.Poster)
+	}
+	if m.ImdbID != "" {
+		if known(m.Poster) {
+			sb.WriteString(" · ")
+		}
+		fmt.Fprintf(&sb, "[IMDb](https://www.imdb.com/title/%s/)", m.ImdbID)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
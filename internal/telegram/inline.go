@@ -0,0 +1,161 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/omdb"
+	"moviebot/internal/search"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// inlineResults caps the articles shown for one inline query.
+const inlineResults = 10
+
+// =====================================================
+// INLINE MODE — @moviebot dune, in any chat
+// =====================================================
+
+// handleInlineQuery answers "@bot <title>" with OMDb results. Movies already
+// on the list come as their live vote card; others as a card with an add
+// button. Picking one posts it to whichever chat the user is in.
+//
+// Inline messages don't say which chat they are in, so they can only work
+// on the shared library: with per-chat libraries inline mode is refused.
+func (b *Bot) handleInlineQuery(ctx context.Context, q *tgbotapi.InlineQuery) {
+	query := strings.TrimSpace(q.Query)
+	answer := tgbotapi.InlineConfig{InlineQueryID: q.ID, CacheTime: 30, IsPersonal: true}
+
+	if b.Store.PerChatLibraries() {
+		trace.Logf(ctx, "[BOT] Inline query from %s refused, chats have their own libraries", q.From.UserName)
+		answer.SwitchPMText = "Every chat has its own list, add movies there"
+		answer.SwitchPMParameter = "inline"
+	} else if query != "" {
+		results, err := search.Query(ctx, b.OMDb, b.TMDB, query)
+		if err != nil {
+			trace.Logf(ctx, "[OMDb] Inline search for '%s' failed: %v", query, err)
+		}
+		if len(results) > inlineResults {
+			results = results[:inlineResults]
+		}
+		for _, r := range results {
+			answer.Results = append(answer.Results, b.inlineArticle(r))
+		}
+		trace.Logf(ctx, "[BOT] Inline query '%s' from %s: %d results", query, q.From.UserName, len(answer.Results))
+	}
+
+	if _, err := b.request(ctx, answer); err != nil {
+		trace.Logf(ctx, "[BOT] Answering inline query failed: %v", err)
+	}
+}
+
+// inlineArticle builds the inline result for one search hit. Its ID is the
+// IMDb ID, which is all a chosen result carries back.
+func (b *Bot) inlineArticle(r omdb.SearchResult) tgbotapi.InlineQueryResultArticle {
+	var text string
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	description := "➕ Not on the list yet"
+
+	if movie, ok := b.listedByImdbID(r.ImdbID); ok {
+		text, keyboard = b.buildVoteMessageConfig(movie, 0)
+		description = fmt.Sprintf("👍 %d votes, 👁 %d watched", len(movie.Votes), len(movie.Watched))
+	} else {
+		text = fmt.Sprintf("*%s* (%s)\n\n[Poster](%s)\n\nNot on the list yet.", r.Title, r.Year, r.Poster)
+		keyboard = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Add to the list", "inline|"+r.ImdbID),
+		))
+	}
+
+	article := tgbotapi.NewInlineQueryResultArticleMarkdown(r.ImdbID, fmt.Sprintf("%s (%s)", r.Title, r.Year), text)
+	article.Description = description
+	article.ReplyMarkup = &keyboard
+	if strings.HasPrefix(r.Poster, "http") {
+		article.ThumbURL = r.Poster
+	}
+	return article
+}
+
+// listedByImdbID finds a movie on the main list by its IMDb ID.
+func (b *Bot) listedByImdbID(imdbID string) (storage.Movie, bool) {
+	if imdbID == "" {
+		return storage.Movie{}, false
+	}
+	for _, m := range b.Store.GetMovies(0, "") {
+		if m.ImdbID == imdbID {
+			return m, true
+		}
+	}
+	return storage.Movie{}, false
+}
+
+// handleChosenInlineResult turns a posted inline result into a tracked vote
+// card, adding the movie first if needed. Telegram only sends these with
+// inline feedback enabled in BotFather; the add button covers the rest.
+func (b *Bot) handleChosenInlineResult(ctx context.Context, res *tgbotapi.ChosenInlineResult) {
+	if res.InlineMessageID == "" {
+		return
+	}
+	if _, err := b.inlineCard(ctx, res.From, res.ResultID, res.InlineMessageID); err != nil {
+		trace.Logf(ctx, "[BOT] Chosen inline result %s: %v", res.ResultID, err)
+	}
+}
+
+// handleInlineCallback is the "Add to the list" button of a posted inline
+// result.
+func (b *Bot) handleInlineCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if cb.InlineMessageID == "" {
+		return
+	}
+	movie, err := b.inlineCard(ctx, cb.From, strings.TrimPrefix(cb.Data, "inline|"), cb.InlineMessageID)
+	if err != nil {
+		b.answerToast(ctx, cb, "❌ "+err.Error())
+		return
+	}
+	b.answerToast(ctx, cb, fmt.Sprintf("➕ %s is on the list", movie.Title))
+}
+
+// inlineCard makes sure the movie is on the main list and turns the inline
+// message into its vote card, kept in sync like any other.
+func (b *Bot) inlineCard(ctx context.Context, user *tgbotapi.User, imdbID, inlineID string) (storage.Movie, error) {
+	if b.Store.PerChatLibraries() {
+		return storage.Movie{}, fmt.Errorf("every chat has its own list now, add it there")
+	}
+	movie, ok := b.listedByImdbID(imdbID)
+	if !ok {
+		details, err := b.OMDb.GetByID(ctx, imdbID)
+		if err != nil || details == nil {
+			return storage.Movie{}, fmt.Errorf("couldn't look that movie up")
+		}
+		movieID, created := b.addSearchResult(ctx, 0, user, omdb.SearchResult{
+			Title:  details.Title,
+			Year:   details.Year,
+			ImdbID: details.ImdbID,
+			Poster: details.Poster,
+		}, "")
+		if movieID == "" {
+			return storage.Movie{}, fmt.Errorf("couldn't add that movie")
+		}
+		if created {
+			trace.Logf(ctx, "[BOT] %s added %s inline", user.UserName, details.Title)
+		}
+		if movie, ok = b.Store.GetMovieByID(movieID); !ok {
+			return storage.Movie{}, fmt.Errorf("couldn't add that movie")
+		}
+	}
+
+	b.Store.RegisterInlineMessage(ctx, movie.ID, inlineID)
+	b.syncMovie(ctx, movie)
+	return movie, nil
+}
+
+// inlineEdit builds an edit of a message sent through inline mode. Telegram answers
+// such edits with true rather than the message, so use request, not send.
+func inlineEdit(inlineID, text string, keyboard *tgbotapi.InlineKeyboardMarkup) tgbotapi.EditMessageTextConfig {
+	return tgbotapi.EditMessageTextConfig{
+		BaseEdit: tgbotapi.BaseEdit{InlineMessageID: inlineID, ReplyMarkup: keyboard},
+		Text:     text,
+	}
+}
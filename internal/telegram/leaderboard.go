@@ -0,0 +1,153 @@
+package telegram
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+const (
+	leaderboardSize = 10 // people a single board lists
+	leaderboardTop  = 3  // people each board shows in the overview
+)
+
+// minWinRateNominations is how many movies someone must have nominated to
+// be ranked by win rate, so one lucky pick doesn't top the board.
+const minWinRateNominations = 3
+
+// board is one ranking of /leaderboard, picked by its key.
+type board struct{ key, title string }
+
+var leaderboards = []board{
+	{"picks", "🍿 Picks the group watched"},
+	{"votes", "👍 Votes cast"},
+	{"winrate", fmt.Sprintf("🎯 Win rate (%d+ nominations)", minWinRateNominations)},
+	{"nights", "🔥 Movie night streaks"},
+}
+
+// =====================================================
+// 🏆 /leaderboard [picks | votes | winrate | nights]
+// =====================================================
+
+// handleLeaderboard ranks the chat's members on every board, the top few
+// each, or on the one board asked for.
+func (b *Bot) handleLeaderboard(ctx context.Context, msg *tgbotapi.Message) {
+	boards, size := leaderboards, leaderboardTop
+	if arg := strings.ToLower(strings.TrimSpace(msg.CommandArguments())); arg != "" {
+		i := slices.IndexFunc(leaderboards, func(l board) bool { return l.key == arg })
+		if i < 0 {
+			b.replyText(ctx, msg, "Usage: /leaderboard [picks | votes | winrate | nights]")
+			return
+		}
+		boards, size = leaderboards[i:i+1], leaderboardSize
+	}
+	trace.Logf(ctx, "[BOT] /leaderboard %q from %s", msg.CommandArguments(), msg.From.UserName)
+
+	stats := slices.Collect(maps.Values(b.Store.LibraryStats(b.library(msg.Chat.ID))))
+	attendance := b.Store.Attendance(msg.Chat.ID, time.Now())
+
+	var sb strings.Builder
+	for _, l := range boards {
+		if lines := b.rankLines(l.key, stats, attendance, size); len(lines) > 0 {
+			fmt.Fprintf(&sb, "\n\n%s\n%s", l.title, strings.Join(lines, "\n"))
+		}
+	}
+	if sb.Len() == 0 {
+		b.replyText(ctx, msg, "🏆 Nobody is on the board here yet. Nominate, vote and watch some movies first.")
+		return
+	}
+	text := "🏆 Leaderboard" + sb.String()
+	if len(boards) > 1 {
+		text += fmt.Sprintf("\n\nTop %d of one: /leaderboard picks, votes, winrate or nights", leaderboardSize)
+	}
+	b.replyText(ctx, msg, text)
+}
+
+// rankLines ranks people on one board, at most n of them, as
+// "1. @alice — 7". People with nothing to show aren't listed.
+func (b *Bot) rankLines(key string, stats []storage.UserStats, attendance []storage.AttendanceStats, n int) []string {
+	var lines []string
+	add := func(userID, score string) {
+		lines = append(lines, fmt.Sprintf("%d. %s — %s", len(lines)+1, b.userLabel(userID), score))
+	}
+
+	if key == "nights" {
+		for _, st := range attendance[:min(n, len(attendance))] {
+			add(st.UserID, fmt.Sprintf("🔥 %d (best %d), %d/%d nights", st.Streak, st.Best, st.Attended, st.Nights))
+		}
+		return lines
+	}
+
+	var value func(storage.UserStats) float64
+	score := func(st storage.UserStats) string { return fmt.Sprint(value(st)) }
+	switch key {
+	case "picks":
+		value = func(st storage.UserStats) float64 { return float64(st.Won) }
+	case "votes":
+		value = func(st storage.UserStats) float64 { return float64(st.Voted) }
+	case "winrate":
+		value = func(st storage.UserStats) float64 {
+			if st.Nominated < minWinRateNominations {
+				return 0
+			}
+			return st.WinRate()
+		}
+		score = func(st storage.UserStats) string {
+			return fmt.Sprintf("%.0f%% (%d/%d)", st.WinRate()*100, st.Won, st.Nominated)
+		}
+	}
+
+	ranked := slices.DeleteFunc(slices.Clone(stats), func(st storage.UserStats) bool { return value(st) == 0 })
+	slices.SortFunc(ranked, func(a, c storage.UserStats) int {
+		return cmp.Or(cmp.Compare(value(c), value(a)), cmp.Compare(c.Won, a.Won), strings.Compare(a.UserID, c.UserID))
+	})
+	for _, st := range ranked[:min(n, len(ranked))] {
+		add(st.UserID, score(st))
+	}
+	return lines
+}
+
+// =====================================================
+// 🏅 ACHIEVEMENTS
+// =====================================================
+
+// awardBadges unlocks the achievements the users reached in the chat's
+// library and congratulates them in the chat. It is called after someone
+// adds a movie, votes, or marks one watched (for its suggester too, whose
+// pick may just have won). Badges belong to games, off with them.
+func (b *Bot) awardBadges(ctx context.Context, chatID int64, userIDs ...string) {
+	if chatID == 0 || b.Disabled[featureGames] {
+		return
+	}
+	library := b.library(chatID)
+	for _, id := range userIDs {
+		if id == "" {
+			continue
+		}
+		if unlocked := b.Store.UnlockAchievements(ctx, library, id); len(unlocked) > 0 {
+			b.send(ctx, tgbotapi.NewMessage(chatID, badgeText(b.userLabel(id), unlocked)))
+		}
+	}
+}
+
+// badgeText announces newly unlocked achievements.
+func badgeText(name string, unlocked []storage.Achievement) string {
+	var sb strings.Builder
+	if len(unlocked) == 1 {
+		fmt.Fprintf(&sb, "🏅 %s unlocked a badge!\n", name)
+	} else {
+		fmt.Fprintf(&sb, "🏅 %s unlocked %d badges!\n", name, len(unlocked))
+	}
+	for _, a := range unlocked {
+		fmt.Fprintf(&sb, "\n%s %s — %s", a.Badge, a.Name, a.Desc)
+	}
+	return sb.String()
+}
@@ -0,0 +1,178 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// library is the movie library chatID works with: its own with
+// per_chat_libraries set, the shared one otherwise. Inline mode has no chat
+// and always uses the shared one.
+func (b *Bot) library(chatID int64) int64 {
+	return b.Store.LibraryOf(chatID)
+}
+
+// chatMovie returns the movie a button in chatID acts on (chatID 0 for
+// inline messages), provided it is in that chat's library. Buttons carry
+// bare movie IDs, so a forged or stale one could otherwise reach a movie of
+// another chat.
+func (b *Bot) chatMovie(chatID int64, movieID string) (storage.Movie, bool) {
+	movie, ok := b.Store.GetMovieByID(movieID)
+	if !ok || movie.ChatID != b.library(chatID) {
+		return storage.Movie{}, false
+	}
+	return movie, true
+}
+
+// =====================================================
+// /adopt — owner only
+// =====================================================
+
+// handleAdopt moves the shared library, which holds every movie added
+// before chats got their own, into the chat it is sent in.
+func (b *Bot) handleAdopt(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isOwner(msg.From.ID) {
+		trace.Logf(ctx, "[BOT] /adopt denied for %s", msg.From.UserName)
+		return
+	}
+	chatID := msg.Chat.ID
+	if b.library(chatID) == 0 {
+		b.replyText(ctx, msg, "📚 All chats share one library. Set per_chat_libraries in the config to give each its own.")
+		return
+	}
+
+	r := b.Store.AdoptLibrary(ctx, 0, chatID)
+	trace.Logf(ctx, "[BOT] %s adopted the shared library into chat %d: %d moved", msg.From.UserName, chatID, r.Moved)
+
+	text := fmt.Sprintf("📚 Moved %d movies from the shared library into this chat's.", r.Moved)
+	if len(r.Skipped) > 0 {
+		text += fmt.Sprintf("\nAlready here, left behind: %s", strings.Join(r.Skipped, ", "))
+	}
+	b.replyText(ctx, msg, text)
+	if r.Moved > 0 {
+		b.syncListMessages(ctx)
+	}
+}
+
+// =====================================================
+// /suggest_from_mine — nominate from your own watchlist
+// =====================================================
+
+// mineChoices caps the buttons /suggest_from_mine offers.
+const mineChoices = 20
+
+// handleSuggestFromMine offers the unwatched movies of the sender's own
+// watchlist, the one in their private chat with the bot, for nomination
+// into this chat's list; with a title that matches one, it is nominated
+// right away.
+func (b *Bot) handleSuggestFromMine(ctx context.Context, msg *tgbotapi.Message) {
+	userID := msg.From.ID
+	if msg.Chat.ID == userID {
+		b.replyText(ctx, msg, "🙋 Use this in a group to put a movie from this list up for a vote there.")
+		return
+	}
+	if b.library(userID) != userID {
+		b.replyText(ctx, msg, "🙋 There are no personal watchlists here, our private chat shares this list.")
+		return
+	}
+
+	query := strings.ToLower(strings.TrimSpace(msg.CommandArguments()))
+	var choices []storage.Movie
+	for _, m := range b.Store.GetMovies(userID, "") {
+		if !storage.IsWatched(m) && strings.Contains(strings.ToLower(m.Title), query) {
+			choices = append(choices, m)
+		}
+	}
+	switch {
+	case len(choices) == 0 && query == "":
+		b.replyText(ctx, msg, "🙋 Your own watchlist is empty. Add movies with /movie in our private chat.")
+		return
+	case len(choices) == 0:
+		b.replyText(ctx, msg, "🙋 Nothing on your own watchlist matches that.")
+		return
+	case len(choices) == 1 && query != "":
+		b.nominate(ctx, msg.Chat.ID, msg.From, choices[0])
+		return
+	}
+
+	sort.Slice(choices, func(i, j int) bool { return choices[i].Title < choices[j].Title })
+	text := "🙋 Which of your movies should go up for a vote here?"
+	if len(choices) > mineChoices {
+		text = fmt.Sprintf("🙋 %d movies, showing the first %d. Use /suggest_from_mine <title> to narrow it down.", len(choices), mineChoices)
+		choices = choices[:mineChoices]
+	}
+	prefix := fmt.Sprintf("mine|%d|", userID)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, m := range choices {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s (%d)", m.Title, m.Year), prefix+m.ID),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✖️ Cancel", prefix),
+	))
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.ReplyToMessageID = msg.MessageID
+	reply.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.send(ctx, reply)
+}
+
+// handleMineCallback nominates the picked movie, "mine|<user>|<id>", or
+// cancels for an empty ID; only the user the panel is for may pick.
+func (b *Bot) handleMineCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cb.Data, "|")
+	if len(parts) != 3 || cb.Message == nil {
+		return
+	}
+	if parts[1] != strconv.FormatInt(cb.From.ID, 10) {
+		b.answerToast(ctx, cb, "⛔ That's someone else's list")
+		return
+	}
+	chatID, panelID := cb.Message.Chat.ID, cb.Message.MessageID
+	b.request(ctx, tgbotapi.NewDeleteMessage(chatID, panelID))
+	if parts[2] == "" {
+		return
+	}
+	movie, ok := b.Store.GetMovieByID(parts[2])
+	if !ok || movie.ChatID != cb.From.ID {
+		b.answerToast(ctx, cb, "This movie is gone from your list")
+		return
+	}
+	b.nominate(ctx, chatID, cb.From, movie)
+}
+
+// nominate puts a movie from a personal watchlist on chatID's main list,
+// as suggested by user, and shows its vote card.
+func (b *Bot) nominate(ctx context.Context, chatID int64, user *tgbotapi.User, m storage.Movie) {
+	if term, blocked := b.Store.BlockedTerm(chatID, m.Title); blocked {
+		trace.Logf(ctx, "[BOT] '%s' refused, blocked by %q", m.Title, term)
+		b.send(ctx, tgbotapi.NewMessage(chatID, refusal(m.Title)))
+		return
+	}
+	movieID, created := b.Store.NotifyNewMovie(ctx, storage.Movie{
+		Title:   m.Title,
+		Year:    m.Year,
+		Poster:  m.Poster,
+		ImdbID:  m.ImdbID,
+		ChatID:  b.library(chatID),
+		AddedBy: strconv.FormatInt(user.ID, 10),
+	})
+	trace.Logf(ctx, "[BOT] %s nominated %s from their own list (new: %v)", user.UserName, m.Title, created)
+	if created {
+		if movie, ok := b.Store.GetMovieByID(movieID); ok {
+			b.publish(ctx, events.MovieAdded, user, movie, true)
+		}
+		b.awardBadges(ctx, chatID, strconv.FormatInt(user.ID, 10))
+		go b.enrichMovie(ctx, movieID)
+	}
+	b.createOrUpdateVoteMessage(ctx, chatID, movieID)
+}
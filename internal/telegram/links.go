@@ -0,0 +1,66 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// 🔗 LINKS — a vote card's external links, on demand
+// =====================================================
+
+// linksText is the links block of a vote card, in Markdown.
+func linksText(movie storage.Movie) string {
+	var parts []string
+	for _, l := range storage.MovieLinks(movie) {
+		parts = append(parts, fmt.Sprintf("[%s](%s)", l.Name, l.URL))
+	}
+	return "🔗 " + strings.Join(parts, " · ")
+}
+
+// linksButton opens or closes the links block: "links|<movie>|1" shows it,
+// "|0" hides it. The state travels in the button so inline cards, which
+// aren't tracked per chat, can toggle too.
+func linksButton(movie storage.Movie, open bool) tgbotapi.InlineKeyboardButton {
+	if open {
+		return tgbotapi.NewInlineKeyboardButtonData("🔗 Hide links", "links|"+movie.ID+"|0")
+	}
+	return tgbotapi.NewInlineKeyboardButtonData("🔗 Links", "links|"+movie.ID+"|1")
+}
+
+// handleLinksCallback opens or closes a card's links block. Chat cards
+// remember the choice, so later syncs keep it.
+func (b *Bot) handleLinksCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cb.Data, "|")
+	if len(parts) != 3 {
+		return
+	}
+	movie, ok := b.Store.GetMovieByID(parts[1])
+	if !ok {
+		b.answerToast(ctx, cb, "This movie is no longer on the list.")
+		return
+	}
+	open := parts[2] == "1"
+
+	if cb.Message == nil {
+		text, keyboard := b.voteCard(movie, 0, open)
+		edit := inlineEdit(cb.InlineMessageID, text, &keyboard)
+		edit.ParseMode = "Markdown"
+		if _, err := b.request(ctx, edit); err != nil && !notModified(err) {
+			trace.Logf(ctx, "[BOT] Toggling links of inline card %s failed: %v", movie.Title, err)
+		}
+		return
+	}
+
+	chatID, messageID := cb.Message.Chat.ID, cb.Message.MessageID
+	b.Store.SetMessageLinks(ctx, movie.ID, chatID, messageID, open)
+	text, keyboard := b.voteCard(movie, chatID, open)
+	if _, err := b.send(ctx, editCard(chatID, messageID, len(cb.Message.Photo) > 0, text, keyboard)); err != nil && !notModified(err) {
+		trace.Logf(ctx, "[BOT] Toggling links of %s in chat %d failed: %v", movie.Title, chatID, err)
+	}
+}
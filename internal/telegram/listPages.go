@@ -0,0 +1,120 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// jumpPages is how many pages the jump row lists before it only offers the
+// first, the last and those next to the current one.
+const jumpPages = 7
+
+// =====================================================
+// LIST PAGES — Prev/Next under long lists
+// =====================================================
+
+// listPageKeyboard is the pager under a list message, nil for a list that
+// fits one page. Page numbers in callbacks start at 0.
+func listPageKeyboard(page, pages int) *tgbotapi.InlineKeyboardMarkup {
+	if pages <= 1 {
+		return nil
+	}
+	button := func(label string, to int) tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("listpage|%d", to))
+	}
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		nav = append(nav, button("◀️ Prev", page-1))
+	}
+	nav = append(nav, button(fmt.Sprintf("%d/%d", page+1, pages), page))
+	if page < pages-1 {
+		nav = append(nav, button("Next ▶️", page+1))
+	}
+	rows := [][]tgbotapi.InlineKeyboardButton{nav}
+
+	if pages > 2 {
+		var jump []tgbotapi.InlineKeyboardButton
+		for _, p := range jumpTargets(page, pages) {
+			label := strconv.Itoa(p + 1)
+			if p == page {
+				label = "· " + label + " ·"
+			}
+			jump = append(jump, button(label, p))
+		}
+		rows = append(rows, jump)
+	}
+
+	kb := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &kb
+}
+
+// jumpTargets lists every page of a short list, or the first, the last and
+// the neighbours of page for a long one.
+func jumpTargets(page, pages int) []int {
+	var out []int
+	for p := range pages {
+		if pages <= jumpPages || p == 0 || p == pages-1 || (p >= page-1 && p <= page+1) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// listPageEdit shows r in an existing list message. Without a pager the
+// edit drops any buttons left from when the list was longer.
+func listPageEdit(chatID int64, messageID int, r renderedList) tgbotapi.EditMessageTextConfig {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, r.text)
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = listPageKeyboard(r.page, r.pages)
+	return edit
+}
+
+// handleListPageCallback turns a tracked list message to another page and
+// remembers it there for later syncs.
+func (b *Bot) handleListPageCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if cb.Message == nil {
+		return
+	}
+	page, err := strconv.Atoi(strings.TrimPrefix(cb.Data, "listpage|"))
+	if err != nil {
+		return
+	}
+	chatID, messageID := cb.Message.Chat.ID, cb.Message.MessageID
+
+	for list := range b.Store.ListNames(b.library(chatID)) {
+		if !tracks(b.Store.GetMessages(storage.ListKey(list)), chatID, messageID) {
+			continue
+		}
+		r := b.renderList(list, chatID, page)
+		b.Store.SetMessagePage(ctx, storage.ListKey(list), chatID, messageID, r.page)
+		trace.Logf(ctx, "[BOT] %s turned list %q to page %d/%d", cb.From.UserName, list, r.page+1, r.pages)
+		if _, err := b.send(ctx, listPageEdit(chatID, messageID, r)); err == nil || notModified(err) {
+			b.Store.SetMessageHash(ctx, storage.ListKey(list), chatID, messageID, r.hash)
+		}
+		return
+	}
+	b.answerToast(ctx, cb, "This list is no longer kept up to date, send /list again")
+}
+
+// notModified reports whether Telegram refused an edit because the message
+// already looks like that.
+func notModified(err error) bool {
+	return strings.Contains(err.Error(), "message is not modified")
+}
+
+// tracks reports whether refs include the message.
+func tracks(refs []storage.MessageRef, chatID int64, messageID int) bool {
+	for _, ref := range refs {
+		if ref.ChatID == chatID && ref.MessageID == messageID {
+			return true
+		}
+	}
+	return false
+}
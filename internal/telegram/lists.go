@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/i18n"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// NAMED LISTS
+// =====================================================
+
+// parseMovieArgs pulls an optional "--list <name>" out of /movie arguments,
+// e.g. "/movie --list halloween the thing".
+func parseMovieArgs(args string) (list, query string) {
+	fields := strings.Fields(args)
+	var rest []string
+	for i := 0; i < len(fields); i++ {
+		if fields[i] == "--list" && i+1 < len(fields) {
+			list = storage.NormalizeListName(fields[i+1])
+			i++
+			continue
+		}
+		rest = append(rest, fields[i])
+	}
+	return list, strings.Join(rest, " ")
+}
+
+func (b *Bot) handleLists(ctx context.Context, msg *tgbotapi.Message) {
+	trace.Logf(ctx, "[BOT] /lists from %s", msg.From.UserName)
+
+	counts := b.Store.ListNames(b.library(msg.Chat.ID))
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	lang := b.chatLanguage(msg.Chat.ID)
+	var sb strings.Builder
+	sb.WriteString("📋 " + i18n.Tf(lang, "Watchlist — %d movies (/list)", counts[""]) + "\n")
+	for _, name := range names {
+		sb.WriteString("📂 " + i18n.Tf(lang, "%s — %d movies (/list %s)", name, counts[name], name) + "\n")
+	}
+	sb.WriteString("\n" + i18n.T(lang, "Add to a named list with /movie --list <name> <title>"))
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, sb.String())
+	reply.ReplyToMessageID = msg.MessageID
+	b.send(ctx, reply)
+}
@@ -0,0 +1,103 @@
+package telegram
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// LogAPI is an API that never contacts Telegram. Every call is logged and
+// answered with a synthetic message, so handlers run unchanged in replay or
+// demo setups.
+type LogAPI struct {
+	mu     sync.Mutex
+	nextID int
+
+	// Echo, when set, receives every synthetic message together with its
+	// inline buttons instead of the raw log line (used by demo mode).
+	Echo func(msg tgbotapi.Message, buttons []tgbotapi.InlineKeyboardButton)
+}
+
+func NewLogAPI() *LogAPI {
+	return &LogAPI{nextID: 1000}
+}
+
+// loggedCall is the part of a config struct we care about; go-telegram-bot-api
+// configs embed BaseChat/BaseEdit, so these fields flatten to the top level.
+type loggedCall struct {
+	ChatID      int64
+	MessageID   int
+	Text        string
+	Caption     string
+	ReplyMarkup json.RawMessage
+}
+
+// buttons flattens an inline keyboard, if the call carried one.
+func (c loggedCall) buttons() []tgbotapi.InlineKeyboardButton {
+	var markup tgbotapi.InlineKeyboardMarkup
+	if err := json.Unmarshal(c.ReplyMarkup, &markup); err != nil {
+		return nil
+	}
+	var out []tgbotapi.InlineKeyboardButton
+	for _, row := range markup.InlineKeyboard {
+		out = append(out, row...)
+	}
+	return out
+}
+
+func describe(c tgbotapi.Chattable) (loggedCall, string) {
+	var call loggedCall
+	data, _ := json.Marshal(c)
+	_ = json.Unmarshal(data, &call)
+
+	raw := string(data)
+	if len(raw) > 300 {
+		raw = raw[:300] + "..."
+	}
+	return call, raw
+}
+
+func (a *LogAPI) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	call, raw := describe(c)
+
+	a.mu.Lock()
+	id := call.MessageID // edits keep the ID of the message they change
+	if id == 0 {
+		a.nextID++
+		id = a.nextID
+	}
+	a.mu.Unlock()
+
+	msg := tgbotapi.Message{
+		MessageID: id,
+		Chat:      &tgbotapi.Chat{ID: call.ChatID},
+		Text:      call.Text,
+		Caption:   call.Caption,
+	}
+
+	if a.Echo != nil {
+		a.Echo(msg, call.buttons())
+	} else {
+		log.Printf("[LOGAPI] Send %T -> message %d %s", c, id, raw)
+	}
+	return msg, nil
+}
+
+func (a *LogAPI) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	_, raw := describe(c)
+	log.Printf("[LOGAPI] Request %T %s", c, raw)
+	return &tgbotapi.APIResponse{Ok: true, Result: json.RawMessage("true")}, nil
+}
+
+func (a *LogAPI) GetFileDirectURL(fileID string) (string, error) {
+	log.Printf("[LOGAPI] GetFileDirectURL %s", fileID)
+	return "", errors.New("file downloads are not available offline")
+}
+
+func (a *LogAPI) Call(method string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	log.Printf("[LOGAPI] Call %s %v", method, params)
+	return nil, errors.New(method + " is not available offline")
+}
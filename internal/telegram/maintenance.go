@@ -0,0 +1,77 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/trace"
+)
+
+// InMaintenance reports whether the bot only serves its owners.
+func (b *Bot) InMaintenance() bool {
+	return b.Maintenance.Active()
+}
+
+// SetMaintenance switches maintenance mode on or off. Entering it flushes
+// the store so the data files on disk are complete.
+func (b *Bot) SetMaintenance(ctx context.Context, on bool, message string) {
+	b.Maintenance.Set(on, message)
+	if on {
+		b.Store.Flush()
+	}
+	trace.Logf(ctx, "[BOT] Maintenance mode: %v", on)
+}
+
+// holdForMaintenance answers update with the maintenance notice if it must
+// not be handled now. Owners are always let through.
+func (b *Bot) holdForMaintenance(ctx context.Context, update Update) bool {
+	if !b.InMaintenance() {
+		return false
+	}
+
+	switch {
+	case update.CallbackQuery != nil:
+		if b.isOwner(update.CallbackQuery.From.ID) {
+			return false
+		}
+		b.answerToast(ctx, update.CallbackQuery, b.Maintenance.Message())
+	case update.Message != nil && update.Message.From != nil:
+		if b.isOwner(update.Message.From.ID) {
+			return false
+		}
+		if update.Message.IsCommand() {
+			b.replyText(ctx, update.Message, b.Maintenance.Message())
+		}
+	}
+	return true
+}
+
+// =====================================================
+// /maintenance on [message] | off — owner only
+// =====================================================
+
+func (b *Bot) handleMaintenance(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isOwner(msg.From.ID) {
+		trace.Logf(ctx, "[BOT] /maintenance denied for %s", msg.From.UserName)
+		return
+	}
+
+	args := strings.TrimSpace(msg.CommandArguments())
+	mode, message, _ := strings.Cut(args, " ")
+
+	switch strings.ToLower(mode) {
+	case "on":
+		b.SetMaintenance(ctx, true, strings.TrimSpace(message))
+		b.replyText(ctx, msg, "🛠 Maintenance mode ON. Storage flushed, background jobs paused. Users see:\n"+b.Maintenance.Message())
+	case "off":
+		b.SetMaintenance(ctx, false, "")
+		b.replyText(ctx, msg, "✅ Maintenance mode off.")
+	default:
+		state := "off"
+		if b.InMaintenance() {
+			state = "ON"
+		}
+		b.replyText(ctx, msg, "🛠 Maintenance mode is "+state+".\nUsage: /maintenance on [message] | off")
+	}
+}
@@ -0,0 +1,79 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/omdb"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// searchDownText answers a search that failed rather than found nothing.
+// It is a message key; prefix it with "⚠️ " when sending.
+const searchDownText = "OMDb can't be reached right now. You can still add the movie by hand:\n/movie add-manual Title;Year"
+
+// searchFailed tells a search that failed, e.g. OMDb being down or out of
+// quota, from one that just found nothing.
+func searchFailed(err error) bool {
+	return err != nil && !errors.Is(err, omdb.ErrNoResults)
+}
+
+// =====================================================
+// /movie add-manual — adding without OMDb
+// =====================================================
+
+// handleManualAdd adds "Title;Year" as typed, for when OMDb is down. The
+// movie is marked manual so the refresh job fills in its details later.
+func (b *Bot) handleManualAdd(ctx context.Context, msg *tgbotapi.Message, list, args string) {
+	title, year, ok := parseManualMovie(args)
+	if !ok {
+		b.replyText(ctx, msg, "Usage: /movie add-manual Title;Year\ne.g. /movie add-manual The Thing;1982")
+		return
+	}
+
+	if term, blocked := b.Store.BlockedTerm(msg.Chat.ID, title); blocked {
+		trace.Logf(ctx, "[BOT] '%s' refused, blocked by %q", title, term)
+		b.replyText(ctx, msg, refusal(title))
+		return
+	}
+
+	movieID, created := b.Store.NotifyNewMovie(ctx, storage.Movie{
+		Title:   title,
+		Year:    year,
+		Manual:  true,
+		List:    list,
+		ChatID:  b.library(msg.Chat.ID),
+		AddedBy: strconv.FormatInt(msg.From.ID, 10),
+	})
+	trace.Logf(ctx, "[BOT] %s added %s (%d) by hand", msg.From.UserName, title, year)
+	if created {
+		if movie, ok := b.Store.GetMovieByID(movieID); ok {
+			b.publish(ctx, events.MovieAdded, msg.From, movie, true)
+		}
+		b.awardBadges(ctx, msg.Chat.ID, strconv.FormatInt(msg.From.ID, 10))
+		// OMDb may only have failed the search; try the details right away.
+		go b.enrichMovie(ctx, movieID)
+	}
+	b.createOrUpdateVoteMessage(ctx, msg.Chat.ID, movieID)
+}
+
+// parseManualMovie splits "Title;Year". The year must be plausible for a
+// film, up to a few years ahead for announced ones.
+func parseManualMovie(args string) (title string, year int, ok bool) {
+	title, yearText, found := strings.Cut(args, ";")
+	title = strings.TrimSpace(title)
+	if !found || title == "" {
+		return "", 0, false
+	}
+	year, err := strconv.Atoi(strings.TrimSpace(yearText))
+	if err != nil || year < 1870 || year > time.Now().Year()+5 {
+		return "", 0, false
+	}
+	return title, year, true
+}
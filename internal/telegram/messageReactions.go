@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// MESSAGE REACTIONS
+// =====================================================
+
+// Update is a Telegram update with the fields the tgbotapi version in use
+// predates.
+type Update struct {
+	tgbotapi.Update
+	MessageReaction *MessageReactionUpdated `json:"message_reaction,omitempty"`
+}
+
+// MessageReactionUpdated is a user changing their reactions to a message.
+// Telegram only sends it to bots that are admins of the chat.
+type MessageReactionUpdated struct {
+	Chat        tgbotapi.Chat  `json:"chat"`
+	MessageID   int            `json:"message_id"`
+	User        *tgbotapi.User `json:"user,omitempty"` // nil for anonymous admins
+	Date        int            `json:"date"`
+	OldReaction []ReactionType `json:"old_reaction"`
+	NewReaction []ReactionType `json:"new_reaction"`
+}
+
+// ReactionType is one reaction: an emoji, or a custom emoji by ID.
+type ReactionType struct {
+	Type          string `json:"type"` // "emoji" or "custom_emoji"
+	Emoji         string `json:"emoji,omitempty"`
+	CustomEmojiID string `json:"custom_emoji_id,omitempty"`
+}
+
+// AllowedUpdates are the update types the bot asks Telegram for. Reactions
+// only come when asked for by name, which means listing the rest as well.
+var AllowedUpdates = []string{
+	"message", "callback_query", "inline_query", "chosen_inline_result",
+	"poll", "poll_answer", "my_chat_member", "message_reaction",
+}
+
+// GetUpdates is getUpdates for PollUpdates, keeping the fields Update adds.
+func GetUpdates(api *tgbotapi.BotAPI) func(tgbotapi.UpdateConfig) ([]Update, error) {
+	return func(cfg tgbotapi.UpdateConfig) ([]Update, error) {
+		resp, err := api.Request(cfg)
+		if err != nil {
+			return nil, err
+		}
+		var updates []Update
+		err = json.Unmarshal(resp.Result, &updates)
+		return updates, err
+	}
+}
+
+// voteReactionEmoji is the reaction that counts as a plain vote.
+const voteReactionEmoji = "👍"
+
+// voteReaction is the reaction among reactions that votes: a configured
+// vote option first, then 👍; "" for none.
+func voteReaction(reactions []ReactionType) string {
+	thumb := false
+	for _, r := range reactions {
+		if r.Type != "emoji" {
+			continue
+		}
+		if _, ok := storage.VoteOptionOf(r.Emoji); ok {
+			return r.Emoji
+		}
+		thumb = thumb || r.Emoji == voteReactionEmoji
+	}
+	if thumb {
+		return voteReactionEmoji
+	}
+	return ""
+}
+
+// handleMessageReaction turns reactions to vote cards into votes: 👍 (or a
+// vote option) votes, taking it away takes the vote back. Votes are set
+// rather than toggled, so a reaction agrees with a vote already given by
+// button, and the buttons keep working next to reactions.
+func (b *Bot) handleMessageReaction(ctx context.Context, r *MessageReactionUpdated) {
+	if r.User == nil || r.User.IsBot {
+		return
+	}
+	was, now := voteReaction(r.OldReaction), voteReaction(r.NewReaction)
+	if was == now {
+		return
+	}
+	movie, ok := b.Store.MovieByMessage(r.Chat.ID, r.MessageID)
+	if !ok {
+		return
+	}
+	if b.votingClosed(r.Chat.ID) {
+		trace.Logf(ctx, "[BOT] Ignoring %s's reaction to %s, voting is closed", r.User.UserName, movie.Title)
+		return
+	}
+
+	userID := strconv.FormatInt(r.User.ID, 10)
+	movie, changed := b.reactionVote(ctx, movie, userID, was, now)
+	if !changed {
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s reacted %q (was %q) to %s", r.User.UserName, now, was, movie.Title)
+	b.syncMovie(ctx, movie)
+	b.publish(ctx, events.VoteChanged, r.User, movie, movie.Votes[userID])
+	b.awardBadges(ctx, r.Chat.ID, userID)
+}
+
+// reactionVote applies a user's vote reaction changing from was to now,
+// reporting whether their vote changed.
+func (b *Bot) reactionVote(ctx context.Context, movie storage.Movie, userID, was, now string) (storage.Movie, bool) {
+	current, reacted := movie.Reactions[userID]
+	_, nowOption := storage.VoteOptionOf(now)
+	_, wasOption := storage.VoteOptionOf(was)
+	switch {
+	case nowOption:
+		if current == now {
+			return movie, false
+		}
+		m, err := b.Store.SetReaction(ctx, movie.ID, userID, now)
+		return m, err == nil
+	case now != "":
+		return b.Store.SetVote(ctx, movie.ID, userID, true)
+	case wasOption:
+		if current != was {
+			return movie, false // already changed by button
+		}
+		m, err := b.Store.SetReaction(ctx, movie.ID, userID, was) // the same one again takes it back
+		return m, err == nil
+	case !reacted:
+		return b.Store.SetVote(ctx, movie.ID, userID, false)
+	}
+	return movie, false
+}
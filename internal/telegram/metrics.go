@@ -0,0 +1,151 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+// latencySamples is how many recent durations are kept per handler for the
+// percentiles.
+const latencySamples = 512
+
+// handlerMetrics records how long each command and callback takes.
+type handlerMetrics struct {
+	mu       sync.Mutex
+	handlers map[string]*latencies
+}
+
+// latencies is a ring of recent durations plus lifetime counters.
+type latencies struct {
+	samples []time.Duration
+	next    int
+	count   int
+	slow    int
+	max     time.Duration
+}
+
+// HandlerStats is the latency summary of one handler.
+type HandlerStats struct {
+	Name  string        `json:"name"`
+	Count int           `json:"count"`
+	Slow  int           `json:"slow"`
+	P50   time.Duration `json:"p50"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+// handlerName labels an update for the metrics: "/movie", "cb:vote", "text".
+func handlerName(update Update) string {
+	switch {
+	case update.CallbackQuery != nil:
+		action, _, _ := strings.Cut(update.CallbackQuery.Data, "|")
+		return "cb:" + action
+	case update.Message != nil && update.Message.IsCommand():
+		return "/" + strings.ToLower(update.Message.Command())
+	case update.Message != nil:
+		return "text"
+	case update.MyChatMember != nil:
+		return "my_chat_member"
+	case update.InlineQuery != nil:
+		return "inline_query"
+	case update.ChosenInlineResult != nil:
+		return "chosen_inline_result"
+	case update.PollAnswer != nil:
+		return "poll_answer"
+	case update.Poll != nil:
+		return "poll"
+	case update.MessageReaction != nil:
+		return "message_reaction"
+	}
+	return "other"
+}
+
+// observe records one handled update and warns when it was slow.
+func (b *Bot) observe(ctx context.Context, name string, d time.Duration) {
+	slow := b.SlowHandler > 0 && d >= b.SlowHandler
+	if slow {
+		trace.Logf(ctx, "[BOT][WARN] Slow handler %s took %s", name, d.Round(time.Millisecond))
+	}
+
+	b.metrics.mu.Lock()
+	defer b.metrics.mu.Unlock()
+	if b.metrics.handlers == nil {
+		b.metrics.handlers = make(map[string]*latencies)
+	}
+	l, ok := b.metrics.handlers[name]
+	if !ok {
+		l = &latencies{samples: make([]time.Duration, 0, latencySamples)}
+		b.metrics.handlers[name] = l
+	}
+
+	if len(l.samples) < latencySamples {
+		l.samples = append(l.samples, d)
+	} else {
+		l.samples[l.next] = d
+		l.next = (l.next + 1) % latencySamples
+	}
+	l.count++
+	l.max = max(l.max, d)
+	if slow {
+		l.slow++
+	}
+}
+
+// HandlerStats returns the latency summary of every handler seen so far,
+// busiest first.
+func (b *Bot) HandlerStats() []HandlerStats {
+	b.metrics.mu.Lock()
+	defer b.metrics.mu.Unlock()
+
+	out := make([]HandlerStats, 0, len(b.metrics.handlers))
+	for name, l := range b.metrics.handlers {
+		sorted := slices.Clone(l.samples)
+		slices.Sort(sorted)
+		out = append(out, HandlerStats{
+			Name:  name,
+			Count: l.count,
+			Slow:  l.slow,
+			P50:   percentile(sorted, 50),
+			P99:   percentile(sorted, 99),
+			Max:   l.max,
+		})
+	}
+	slices.SortFunc(out, func(a, b HandlerStats) int { return b.Count - a.Count })
+	return out
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[(len(sorted)-1)*p/100]
+}
+
+// latencyText renders HandlerStats for the admin panel.
+func (b *Bot) latencyText() string {
+	stats := b.HandlerStats()
+	if len(stats) == 0 {
+		return "⏱ No updates handled yet"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("⏱ Handler latency (p50 / p99 / max)\n")
+	for i, st := range stats {
+		if i == 15 {
+			fmt.Fprintf(&sb, "… and %d more", len(stats)-i)
+			break
+		}
+		fmt.Fprintf(&sb, "\n%s ×%d: %s / %s / %s", st.Name, st.Count,
+			st.P50.Round(time.Millisecond), st.P99.Round(time.Millisecond), st.Max.Round(time.Millisecond))
+		if st.Slow > 0 {
+			fmt.Fprintf(&sb, " (%d slow)", st.Slow)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// /move and /copy — admins only
+// =====================================================
+
+// parseMoveArgs splits "/move <movie> <list>" arguments; the last word is
+// the target list, where "main" means the main watchlist.
+func parseMoveArgs(args string) (ref, list string, ok bool) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		return "", "", false
+	}
+	list = storage.NormalizeListName(fields[len(fields)-1])
+	if list == "main" {
+		list = ""
+	}
+	return strings.Join(fields[:len(fields)-1], " "), list, true
+}
+
+// handleMoveCopy relocates a movie onto another list, or puts a copy there
+// when duplicate is set.
+func (b *Bot) handleMoveCopy(ctx context.Context, msg *tgbotapi.Message, duplicate bool) {
+	cmd := "/" + msg.Command()
+	if !b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+		trace.Logf(ctx, "[BOT] %s denied for %s", cmd, msg.From.UserName)
+		b.replyText(ctx, msg, "⛔ Only chat admins can do that.")
+		return
+	}
+
+	ref, list, ok := parseMoveArgs(msg.CommandArguments())
+	if !ok {
+		b.replyText(ctx, msg, fmt.Sprintf("Usage: %s <movie> <list> (use \"main\" for the main watchlist)", cmd))
+		return
+	}
+
+	// Only consider movies that are not already on the target list.
+	var candidates []storage.Movie
+	for _, m := range b.Store.GetLibrary(b.library(msg.Chat.ID)) {
+		if m.List != list {
+			candidates = append(candidates, m)
+		}
+	}
+	movie, err := storage.FindMovie(candidates, ref)
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error())
+		return
+	}
+
+	from := movie.List
+	if duplicate {
+		movie, err = b.Store.CopyMovie(ctx, movie.ID, list)
+	} else {
+		movie, err = b.Store.MoveMovie(ctx, movie.ID, list)
+	}
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error())
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s %s %q -> %q by %s", cmd, movie.Title, from, list, msg.From.UserName)
+
+	verb := "Moved"
+	if duplicate {
+		verb = "Copied"
+	}
+	b.replyText(ctx, msg, fmt.Sprintf("📂 %s %s to %s.", verb, movie.Title, listLabel(list)))
+
+	b.syncMovie(ctx, movie)
+	b.publish(ctx, events.MovieUpdated, msg.From, movie, true)
+}
+
+// listLabel names a list for humans.
+func listLabel(list string) string {
+	if list == "" {
+		return "the main watchlist"
+	}
+	return "list " + list
+}
+
+// replyText answers msg with plain text.
+func (b *Bot) replyText(ctx context.Context, msg *tgbotapi.Message, text string) {
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.ReplyToMessageID = msg.MessageID
+	b.send(ctx, reply)
+}
@@ -0,0 +1,186 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// defaultNightHour is the start time used when /schedule gets a day only.
+const defaultNightHour = 20
+
+// =====================================================
+// /schedule — movie nights
+// =====================================================
+
+// parseWhen reads a day ("today", "tonight", "tomorrow", a weekday or
+// 2006-01-02) and an optional 15:04 time off the front of fields. It returns
+// the time and how many fields it used.
+func parseWhen(fields []string, now time.Time) (time.Time, int, error) {
+	if len(fields) == 0 {
+		return time.Time{}, 0, fmt.Errorf("when?")
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	word := strings.ToLower(fields[0])
+
+	var day time.Time
+	switch word {
+	case "today", "tonight":
+		day = today
+	case "tomorrow":
+		day = today.AddDate(0, 0, 1)
+	default:
+		if wd, ok := parseWeekday(word); ok {
+			day = today.AddDate(0, 0, (int(wd)-int(now.Weekday())+7)%7)
+		} else if d, err := time.ParseInLocation("2006-01-02", word, now.Location()); err == nil {
+			day = d
+		} else {
+			return time.Time{}, 0, fmt.Errorf("unknown day %q", fields[0])
+		}
+	}
+
+	used := 1
+	at := day.Add(defaultNightHour * time.Hour)
+	if len(fields) > 1 {
+		if t, err := time.Parse("15:04", fields[1]); err == nil {
+			at = day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute)
+			used = 2
+		}
+	}
+
+	if !at.After(now) {
+		return time.Time{}, 0, fmt.Errorf("%s is in the past", at.Format("Mon 2 Jan 15:04"))
+	}
+	return at, used, nil
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] {
+			return wd, true
+		}
+	}
+	return 0, false
+}
+
+// checkNightCooldown refuses a night at the given time while another night
+// in the chat is still pending (its movie not watched yet) or lies within
+// the chat's cooldown of it.
+func (b *Bot) checkNightCooldown(chatID int64, at time.Time, loc *time.Location) error {
+	now := time.Now()
+	cooldown := b.nightCooldown(chatID)
+	for _, n := range b.Store.Nights(chatID) {
+		gap := n.At.Sub(at)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap < cooldown {
+			return fmt.Errorf("that's too close to %s on %s", n.Title, n.At.In(loc).Format("Mon 2 Jan 15:04"))
+		}
+
+		movie, ok := b.Store.GetMovieByID(n.MovieID)
+		if ok && !storage.IsWatched(movie) && n.At.After(now.Add(-cooldown)) {
+			return fmt.Errorf("%s (%s) is still pending, watch it first", n.Title, n.At.In(loc).Format("Mon 2 Jan 15:04"))
+		}
+	}
+	return nil
+}
+
+// handleSchedule announces a movie night: /schedule [--force] <when> <movie>.
+// Admins may --force past the cooldown.
+func (b *Bot) handleSchedule(ctx context.Context, msg *tgbotapi.Message) {
+	var fields []string
+	force := false
+	for _, f := range strings.Fields(msg.CommandArguments()) {
+		if f == "--force" {
+			force = true
+			continue
+		}
+		fields = append(fields, f)
+	}
+
+	loc := b.chatLocation(msg.Chat.ID)
+	at, used, err := parseWhen(fields, time.Now().In(loc))
+	if err != nil || used == len(fields) {
+		text := "Usage: /schedule [--force] <today|tomorrow|fri|2006-01-02> [20:00] <movie>"
+		if err != nil && len(fields) > 0 {
+			text = "❌ " + err.Error() + "\n" + text
+		}
+		b.replyText(ctx, msg, text)
+		return
+	}
+
+	movie, err := storage.FindMovie(b.Store.GetLibrary(b.library(msg.Chat.ID)), strings.Join(fields[used:], " "))
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error())
+		return
+	}
+
+	if err := b.checkNightCooldown(msg.Chat.ID, at, loc); err != nil {
+		if !force {
+			b.replyText(ctx, msg, "⏳ "+err.Error()+". An admin can override with /schedule --force ...")
+			return
+		}
+		if !b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+			b.replyText(ctx, msg, "⛔ Only chat admins can override the cooldown.")
+			return
+		}
+		trace.Logf(ctx, "[BOT] Cooldown overridden by %s: %v", msg.From.UserName, err)
+	}
+
+	night := storage.Night{
+		ChatID:    msg.Chat.ID,
+		MovieID:   movie.ID,
+		Title:     movie.Title,
+		At:        at,
+		CreatedBy: msg.From.UserName,
+	}
+
+	if b.WatchParty != nil {
+		link, err := b.WatchParty.Link(ctx, movie, at)
+		if err != nil {
+			trace.Logf(ctx, "[BOT] Watch-party link for %s failed: %v", movie.Title, err)
+		}
+		night.WatchLink = link
+	}
+
+	night = b.Store.AddNight(ctx, night)
+	trace.Logf(ctx, "[BOT] /schedule %s at %s by %s", movie.Title, at.Format(time.RFC3339), msg.From.UserName)
+
+	b.announceNight(ctx, msg.MessageID, night, movie)
+
+	b.Events.Publish(ctx, events.Event{
+		Type:     events.NightScheduled,
+		Source:   "telegram",
+		ChatID:   msg.Chat.ID,
+		UserID:   msg.From.ID,
+		Username: msg.From.UserName,
+		Active:   true,
+		Movie:    &movie,
+		Night:    &night,
+	})
+}
+
+// nightText renders the announcement card of a movie night in the chat's
+// time zone.
+func nightText(n storage.Night, movie storage.Movie, loc *time.Location) string {
+	var sb strings.Builder
+	sb.WriteString("🍿 Movie night!\n\n")
+	fmt.Fprintf(&sb, "🎬 %s (%d)\n", movie.Title, movie.Year)
+	fmt.Fprintf(&sb, "📅 %s\n", n.At.In(loc).Format("Mon 2 Jan, 15:04"))
+	if movie.Runtime != "" && movie.Runtime != "N/A" {
+		fmt.Fprintf(&sb, "⏱ %s\n", movie.Runtime)
+	}
+	if n.WatchLink != "" {
+		fmt.Fprintf(&sb, "\n📺 Watch together: %s\n", n.WatchLink)
+	}
+	return sb.String()
+}
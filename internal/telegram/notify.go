@@ -0,0 +1,111 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// SUGGESTER NOTIFICATIONS
+// =====================================================
+
+// suggesterMilestone returns the milestone e marks for the movie's
+// suggester and the message to send, "" if none. Milestone keys are stored
+// on the movie so each is sent only once.
+func (b *Bot) suggesterMilestone(e events.Event) (string, string) {
+	m := e.Movie
+	switch {
+	case e.Type == events.VoteChanged && e.Active:
+		votes := len(m.Votes)
+		if b.Quorum > 0 && votes >= b.Quorum {
+			return "quorum", fmt.Sprintf("🎉 %s (%d), your suggestion, reached %d votes!", m.Title, m.Year, votes)
+		}
+		// The suggester's own vote doesn't count as interest.
+		if others := votes - boolInt(m.Votes[m.AddedBy]); others == 1 && e.UserID != suggesterID(m) {
+			who := "Someone"
+			switch {
+			case e.Username != "" && e.Source == "telegram":
+				who = "@" + e.Username
+			case e.Username != "":
+				who = e.Username
+			}
+			return "first_vote", fmt.Sprintf("👍 %s voted for %s (%d), the movie you suggested.", who, m.Title, m.Year)
+		}
+
+	case e.Type == events.NightScheduled && e.Night != nil:
+		return "scheduled:" + e.Night.ID, fmt.Sprintf("📅 %s (%d), your suggestion, is on for %s.",
+			m.Title, m.Year, e.Night.At.In(b.chatLocation(e.Night.ChatID)).Format("Mon 2 Jan, 15:04"))
+	}
+	return "", ""
+}
+
+// notifySuggester DMs the user who added e's movie when it crosses a vote
+// milestone or gets scheduled, unless they muted it with /notify off. Only
+// Telegram suggesters can be reached.
+func (b *Bot) notifySuggester(ctx context.Context, e events.Event) {
+	if !b.NotifySuggesters {
+		return
+	}
+	id := suggesterID(e.Movie)
+	if id == 0 {
+		return
+	}
+	if u, ok := b.Store.GetUser(e.Movie.AddedBy); ok && u.Mute {
+		return
+	}
+
+	milestone, text := b.suggesterMilestone(e)
+	if milestone == "" || !b.Store.MarkNotified(ctx, e.Movie.ID, milestone) {
+		return
+	}
+
+	trace.Logf(ctx, "[BOT] Telling %d about %s of %s", id, milestone, e.Movie.Title)
+	b.send(ctx, tgbotapi.NewMessage(id, text+"\n\nTurn these off with /notify off."))
+}
+
+// suggesterID returns the Telegram user ID of a movie's suggester, 0 when it
+// was added elsewhere or before suggesters were recorded.
+func suggesterID(m *storage.Movie) int64 {
+	id, err := strconv.ParseInt(m.AddedBy, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func boolInt(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// =====================================================
+// /notify on|off
+// =====================================================
+
+func (b *Bot) handleNotify(ctx context.Context, msg *tgbotapi.Message) {
+	id := strconv.FormatInt(msg.From.ID, 10)
+
+	switch strings.ToLower(strings.TrimSpace(msg.CommandArguments())) {
+	case "on":
+		b.Store.SetUserMute(ctx, id, false)
+		b.replyText(ctx, msg, "🔔 You'll get a DM when your suggestions get votes or are scheduled.")
+	case "off":
+		b.Store.SetUserMute(ctx, id, true)
+		b.replyText(ctx, msg, "🔕 No more DMs about your suggestions.")
+	default:
+		state := "on"
+		if u, ok := b.Store.GetUser(id); ok && u.Mute {
+			state = "off"
+		}
+		b.replyText(ctx, msg, fmt.Sprintf("Suggestion notifications are %s. Use /notify on or /notify off.", state))
+	}
+}
@@ -0,0 +1,215 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/alerts"
+	"moviebot/internal/trace"
+)
+
+const (
+	// pollBackoffMax caps the wait between failed getUpdates calls.
+	pollBackoffMax = 2 * time.Minute
+	// outageAlertAfter is how long Telegram must have been unreachable
+	// before owners get a note once it is back.
+	outageAlertAfter = time.Minute
+)
+
+// outage tracks whether Telegram is reachable. While it is not, background
+// work (vote cards re-rendered for other frontends' changes) is parked in
+// pending instead of failing call after call, and replayed on recovery.
+type outage struct {
+	mu       sync.Mutex
+	since    time.Time // zero while Telegram is reachable
+	failures int       // failed calls in the current outage
+	count    int       // outages since start
+	longest  time.Duration
+	pending  map[string]bool // movie IDs whose cards need a re-sync
+}
+
+// unreachable reports whether err means Telegram could not be reached (or
+// failed server-side), as opposed to rejecting a single request.
+func unreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code >= 500
+	}
+	return true
+}
+
+// apiResult records the outcome of a Telegram call.
+func (b *Bot) apiResult(ctx context.Context, err error) {
+	if err == nil {
+		b.telegramBack(ctx)
+		return
+	}
+	if !unreachable(err) {
+		b.rejected(err)
+		return
+	}
+
+	b.outage.mu.Lock()
+	defer b.outage.mu.Unlock()
+	b.outage.failures++
+	if b.outage.since.IsZero() {
+		b.outage.since = time.Now()
+		b.outage.count++
+		trace.Logf(ctx, "[TG] Telegram unreachable, holding background work: %v", err)
+	}
+}
+
+// rejected reports requests Telegram refused (403: blocked or kicked, 400:
+// bad request) to the alerts. Edits that changed nothing are not failures.
+func (b *Bot) rejected(err error) {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) || notModified(err) {
+		return
+	}
+	switch apiErr.Code {
+	case 400:
+		b.Alerts.Failure("Telegram 400 Bad Request", err)
+	case 403:
+		b.Alerts.Failure("Telegram 403 Forbidden", err)
+	}
+}
+
+// telegramDown reports whether Telegram is currently unreachable.
+func (b *Bot) telegramDown() bool {
+	b.outage.mu.Lock()
+	defer b.outage.mu.Unlock()
+	return !b.outage.since.IsZero()
+}
+
+// deferSync parks a movie whose cards could not be synced during an outage.
+func (b *Bot) deferSync(movieID string) {
+	b.outage.mu.Lock()
+	defer b.outage.mu.Unlock()
+	if b.outage.pending == nil {
+		b.outage.pending = make(map[string]bool)
+	}
+	b.outage.pending[movieID] = true
+}
+
+// telegramBack ends an outage: parked syncs are replayed and, after a long
+// one, owners are told.
+func (b *Bot) telegramBack(ctx context.Context) {
+	b.outage.mu.Lock()
+	if b.outage.since.IsZero() {
+		b.outage.mu.Unlock()
+		return
+	}
+	down := time.Since(b.outage.since).Round(time.Second)
+	failures := b.outage.failures
+	pending := slices.Sorted(maps.Keys(b.outage.pending))
+	b.outage.since = time.Time{}
+	b.outage.failures = 0
+	b.outage.pending = nil
+	b.outage.longest = max(b.outage.longest, down)
+	b.outage.mu.Unlock()
+
+	trace.Logf(ctx, "[TG] Telegram reachable again after %s (%d failed calls, %d cards to re-sync)", down, failures, len(pending))
+
+	go func() {
+		for _, id := range pending {
+			if movie, ok := b.Store.GetMovieByID(id); ok {
+				b.syncMovie(ctx, movie)
+			}
+		}
+		if down >= outageAlertAfter {
+			b.alertOwners(ctx, fmt.Sprintf("⚠️ Telegram was unreachable for %s (%d failed calls). %d vote cards were re-synced.",
+				down, failures, len(pending)))
+		}
+	}()
+}
+
+// outageText summarises outages for the admin panel.
+func (b *Bot) outageText() string {
+	b.outage.mu.Lock()
+	defer b.outage.mu.Unlock()
+	if !b.outage.since.IsZero() {
+		return fmt.Sprintf("📡 Telegram unreachable since %s (%d failed calls)", b.outage.since.Format("15:04:05"), b.outage.failures)
+	}
+	return fmt.Sprintf("📡 Telegram: %d outages since start, longest %s", b.outage.count, b.outage.longest)
+}
+
+// alertsText lists the failures the alerts saw recently, for the admin panel.
+func (b *Bot) alertsText() string {
+	recent := b.Alerts.Recent()
+	if len(recent) == 0 {
+		return "🚨 No recent failures"
+	}
+	slices.SortFunc(recent, func(x, y alerts.Status) int { return strings.Compare(x.Kind, y.Kind) })
+	var sb strings.Builder
+	sb.WriteString("🚨 Recent failures")
+	for _, st := range recent {
+		fmt.Fprintf(&sb, "\n%s ×%d: %v", st.Kind, st.Recent, st.Last)
+	}
+	return sb.String()
+}
+
+// AlertOwners DMs every owner from outside an update, e.g. for the alerts.
+func (b *Bot) AlertOwners(text string) {
+	b.alertOwners(trace.NewContext(context.Background()), text)
+}
+
+// alertOwners DMs every owner.
+func (b *Bot) alertOwners(ctx context.Context, text string) {
+	for _, id := range b.OwnerIDs {
+		b.send(ctx, tgbotapi.NewMessage(id, text))
+	}
+}
+
+// =====================================================
+// LONG POLLING
+// =====================================================
+
+// PollUpdates is a replacement for BotAPI.GetUpdatesChan that backs off
+// exponentially while Telegram is unreachable and logs the outage once
+// instead of every few seconds. The channel closes when ctx is done.
+func (b *Bot) PollUpdates(ctx context.Context, getUpdates func(tgbotapi.UpdateConfig) ([]Update, error), cfg tgbotapi.UpdateConfig) <-chan Update {
+	ch := make(chan Update, 100)
+
+	go func() {
+		defer close(ch)
+		backoff := time.Second
+		for ctx.Err() == nil {
+			updates, err := getUpdates(cfg)
+			if err != nil {
+				b.apiResult(ctx, err)
+				if backoff == time.Second || backoff == pollBackoffMax {
+					log.Printf("[TG] getUpdates failed, retrying in %s: %v", backoff, err)
+				}
+				select {
+				case <-time.After(backoff):
+				case <-ctx.Done():
+					return
+				}
+				backoff = min(backoff*2, pollBackoffMax)
+				continue
+			}
+
+			b.apiResult(ctx, nil)
+			backoff = time.Second
+			for _, u := range updates {
+				if u.UpdateID >= cfg.Offset {
+					cfg.Offset = u.UpdateID + 1
+					ch <- u
+				}
+			}
+		}
+	}()
+
+	return ch
+}
@@ -0,0 +1,128 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// maxDownloadSize caps documents fetched from Telegram (imports, merges).
+const maxDownloadSize = 10 << 20
+
+func (b *Bot) isOwner(userID int64) bool {
+	for _, id := range b.OwnerIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// downloadDocument fetches the document attached to msg.
+func (b *Bot) downloadDocument(ctx context.Context, doc *tgbotapi.Document) ([]byte, error) {
+	return b.downloadFile(ctx, doc.FileID)
+}
+
+// downloadFile fetches any file sent to the bot by its file ID.
+func (b *Bot) downloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	url, err := b.API.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve file: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download file: HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize))
+}
+
+// =====================================================
+// /merge — owner only
+// =====================================================
+
+// handleMerge merges another instance's movies.json, sent as a document the
+// owner replies to with /merge. A /merge caption on the document itself does
+// nothing: Telegram doesn't mark captions as commands.
+func (b *Bot) handleMerge(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isOwner(msg.From.ID) {
+		trace.Logf(ctx, "[BOT] /merge denied for %s", msg.From.UserName)
+		return
+	}
+
+	var doc *tgbotapi.Document
+	if msg.ReplyToMessage != nil {
+		doc = msg.ReplyToMessage.Document
+	}
+	if doc == nil {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, "Reply to another instance's movies.json with /merge.")
+		reply.ReplyToMessageID = msg.MessageID
+		b.send(ctx, reply)
+		return
+	}
+
+	data, err := b.downloadDocument(ctx, doc)
+	if err != nil {
+		trace.Logf(ctx, "[BOT] /merge download failed: %v", err)
+		b.send(ctx, tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not download the file."))
+		return
+	}
+
+	var movies []storage.Movie
+	if err := json.Unmarshal(data, &movies); err != nil {
+		b.send(ctx, tgbotapi.NewMessage(msg.Chat.ID, "❌ That is not a moviebot movies.json file."))
+		return
+	}
+
+	report := b.Store.MergeMovies(ctx, movies)
+	reply := tgbotapi.NewMessage(msg.Chat.ID, "📥 Merge complete\n"+report.String())
+	reply.ReplyToMessageID = msg.MessageID
+	b.send(ctx, reply)
+
+	b.syncListMessages(ctx)
+}
+
+// =====================================================
+// /compact — owner only
+// =====================================================
+
+// handleCompact backs the store up, then compacts it and reports the
+// difference.
+func (b *Bot) handleCompact(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isOwner(msg.From.ID) {
+		trace.Logf(ctx, "[BOT] /compact denied for %s", msg.From.UserName)
+		return
+	}
+	reply := tgbotapi.NewMessage(msg.Chat.ID, b.compactText(ctx))
+	reply.ReplyToMessageID = msg.MessageID
+	b.send(ctx, reply)
+}
+
+// compactText runs a backup and a compaction, for /compact and the admin
+// panel.
+func (b *Bot) compactText(ctx context.Context) string {
+	dir, err := b.Store.Backup(ctx)
+	if err != nil {
+		return "❌ Backup failed, nothing compacted: " + err.Error()
+	}
+	report := b.Store.Compact(ctx)
+	if report.Merged > 0 || report.DroppedRefs > 0 {
+		b.syncListMessages(ctx)
+	}
+	return fmt.Sprintf("🧹 Store compacted\n%s\n\n💾 Backup in %s", report, dir)
+}
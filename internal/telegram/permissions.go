@@ -0,0 +1,100 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/trace"
+)
+
+// adminCacheTTL is how long a group's administrators are trusted before
+// Telegram is asked again.
+const adminCacheTTL = 5 * time.Minute
+
+// adminCache remembers who administers each group, so permission checks
+// don't cost a getChatAdministrators call each.
+type adminCache struct {
+	mu     sync.Mutex
+	chats  map[int64]map[int64]bool
+	expiry map[int64]time.Time
+}
+
+func (c *adminCache) get(chatID int64, now time.Time) (map[int64]bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.After(c.expiry[chatID]) {
+		return nil, false
+	}
+	return c.chats[chatID], true
+}
+
+func (c *adminCache) put(chatID int64, admins map[int64]bool, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.chats == nil {
+		c.chats = make(map[int64]map[int64]bool)
+		c.expiry = make(map[int64]time.Time)
+	}
+	c.chats[chatID] = admins
+	c.expiry[chatID] = now.Add(adminCacheTTL)
+}
+
+// =====================================================
+// PERMISSIONS
+// =====================================================
+
+// isAdmin reports whether userID may run curation commands in chatID:
+// owners and the configured AdminIDs always can, otherwise the user must
+// administer the group. In a private chat the user is its only member and
+// counts as admin.
+func (b *Bot) isAdmin(ctx context.Context, chatID, userID int64) bool {
+	if b.isOwner(userID) || slices.Contains(b.AdminIDs, userID) || chatID == userID {
+		return true
+	}
+	admins, ok := b.chatAdmins(ctx, chatID)
+	return ok && admins[userID]
+}
+
+// isManager reports whether userID may run destructive commands in chatID:
+// switch the table format, remove movies, reset the chat or import a file.
+// It takes an admin, but a private chat that shares the library with the
+// groups is not enough there; only owners and AdminIDs may then.
+func (b *Bot) isManager(ctx context.Context, chatID, userID int64) bool {
+	if chatID == userID && b.library(chatID) != chatID {
+		return b.isOwner(userID) || slices.Contains(b.AdminIDs, userID)
+	}
+	return b.isAdmin(ctx, chatID, userID)
+}
+
+// chatAdmins returns the user IDs administering chatID, cached for
+// adminCacheTTL. It reports false when Telegram can't tell.
+func (b *Bot) chatAdmins(ctx context.Context, chatID int64) (map[int64]bool, bool) {
+	now := time.Now()
+	if admins, ok := b.admins.get(chatID, now); ok {
+		return admins, true
+	}
+
+	resp, err := b.request(ctx, tgbotapi.ChatAdministratorsConfig{
+		ChatConfig: tgbotapi.ChatConfig{ChatID: chatID},
+	})
+	if err != nil {
+		return nil, false
+	}
+	var members []tgbotapi.ChatMember
+	if err := json.Unmarshal(resp.Result, &members); err != nil {
+		trace.Logf(ctx, "[BOT] Could not parse admins of chat %d: %v", chatID, err)
+		return nil, false
+	}
+	admins := make(map[int64]bool, len(members))
+	for _, m := range members {
+		if m.User != nil {
+			admins[m.User.ID] = true
+		}
+	}
+	b.admins.put(chatID, admins, now)
+	return admins, true
+}
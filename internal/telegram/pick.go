@@ -0,0 +1,117 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// seeUser remembers who u is so later @mentions can be resolved.
+func (b *Bot) seeUser(ctx context.Context, u *tgbotapi.User) {
+	if u == nil || u.IsBot {
+		return
+	}
+	b.Store.SeenUser(ctx, storage.User{
+		ID:       strconv.FormatInt(u.ID, 10),
+		Username: u.UserName,
+		Name:     strings.TrimSpace(u.FirstName + " " + u.LastName),
+	})
+}
+
+// =====================================================
+// /pickfor @alice @bob
+// =====================================================
+
+// pickFor returns the best movie for a subset of users: nobody in the group
+// has seen it, and it has the most votes from the group, then the best
+// score overall, then the longest wait on the list.
+func pickFor(movies []storage.Movie, userIDs []string) (storage.Movie, int, bool) {
+	var candidates []storage.Movie
+	groupVotes := make(map[string]int)
+
+	for _, m := range movies {
+		seen := false
+		for _, id := range userIDs {
+			if m.Watched[id] {
+				seen = true
+				break
+			}
+			if m.Votes[id] {
+				groupVotes[m.ID]++
+			}
+		}
+		if !seen {
+			candidates = append(candidates, m)
+		}
+	}
+	if len(candidates) == 0 {
+		return storage.Movie{}, 0, false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if groupVotes[a.ID] != groupVotes[b.ID] {
+			return groupVotes[a.ID] > groupVotes[b.ID]
+		}
+		if sa, sb := storage.Score(a), storage.Score(b); sa != sb {
+			return sa > sb
+		}
+		return a.AddedAt.Before(b.AddedAt)
+	})
+	return candidates[0], groupVotes[candidates[0].ID], true
+}
+
+func (b *Bot) handlePickFor(ctx context.Context, msg *tgbotapi.Message) {
+	var ids, names, unknown []string
+	add := func(id, name string) {
+		for _, have := range ids {
+			if have == id {
+				return
+			}
+		}
+		ids = append(ids, id)
+		names = append(names, name)
+	}
+
+	// Users without a username are mentioned by name and carry their ID.
+	for _, e := range msg.Entities {
+		if e.Type == "text_mention" && e.User != nil {
+			add(strconv.FormatInt(e.User.ID, 10), e.User.FirstName)
+		}
+	}
+	for _, f := range strings.Fields(msg.CommandArguments()) {
+		if !strings.HasPrefix(f, "@") {
+			continue
+		}
+		if u, ok := b.Store.UserByUsername(f); ok {
+			add(u.ID, "@"+u.Username)
+		} else {
+			unknown = append(unknown, f)
+		}
+	}
+
+	if len(unknown) > 0 {
+		b.replyText(ctx, msg, fmt.Sprintf("🤷 I don't know %s yet. They need to vote or use a command first.", strings.Join(unknown, ", ")))
+		return
+	}
+	if len(ids) == 0 {
+		b.replyText(ctx, msg, "Usage: /pickfor @alice @bob")
+		return
+	}
+
+	trace.Logf(ctx, "[BOT] /pickfor %s from %s", strings.Join(names, " "), msg.From.UserName)
+
+	movie, votes, ok := pickFor(b.Store.GetMovies(b.library(msg.Chat.ID), ""), ids)
+	if !ok {
+		b.replyText(ctx, msg, "🤷 Between "+strings.Join(names, ", ")+" you've seen everything on the list!")
+		return
+	}
+	b.replyText(ctx, msg, fmt.Sprintf("🎯 Pick for %s: %s (%d)\n👍 %d of you voted for it, %d votes overall.",
+		strings.Join(names, ", "), movie.Title, movie.Year, votes, len(movie.Votes)))
+}
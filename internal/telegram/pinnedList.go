@@ -0,0 +1,80 @@
+package telegram
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// PINNED LIST — one living list message per chat
+// =====================================================
+
+// pinnedLists reports whether chatID keeps one pinned list message (the
+// "list" setting) instead of a new copy per /list.
+func (b *Bot) pinnedLists(chatID int64) bool {
+	return b.Store.ChatSetting(chatID, "list") == "pinned"
+}
+
+// bumpPinnedList brings the chat's pinned copy of list up to date and
+// points at it. It reports false when there is none (anymore), so the
+// caller sends and pins a new one.
+func (b *Bot) bumpPinnedList(ctx context.Context, chatID int64, replyTo int, list string) bool {
+	key := storage.ListKey(list)
+	var pinned *storage.MessageRef
+	for _, ref := range b.Store.GetMessages(key) {
+		if ref.ChatID == chatID && ref.Pinned {
+			pinned = &ref
+			break
+		}
+	}
+	if pinned == nil {
+		return false
+	}
+
+	r := b.renderList(list, chatID, pinned.Page)
+	if _, err := b.send(ctx, listPageEdit(chatID, pinned.MessageID, r)); err != nil && !notModified(err) {
+		if unreachable(err) {
+			return true // try again once Telegram is back
+		}
+		trace.Logf(ctx, "[BOT] Pinned list %d in chat %d is gone: %v", pinned.MessageID, chatID, err)
+		b.Store.UnregisterMessage(ctx, key, chatID, pinned.MessageID)
+		return false
+	}
+	b.Store.SetMessageHash(ctx, key, chatID, pinned.MessageID, r.hash)
+	b.rememberList(chatID, listView{list: list, ids: r.ids})
+
+	reply := tgbotapi.NewMessage(chatID, "📌 The list is pinned here and kept up to date.")
+	reply.ReplyToMessageID = pinned.MessageID
+	if _, err := b.send(ctx, reply); err != nil {
+		// The reply target vanished between the edit and now; answer plainly.
+		reply.ReplyToMessageID = replyTo
+		b.send(ctx, reply)
+	}
+	return true
+}
+
+// pinList makes a just sent list message the chat's pinned copy and stops
+// tracking the chat's older copies, unpinning a previous pinned one.
+func (b *Bot) pinList(ctx context.Context, list string, sent tgbotapi.Message) {
+	key := storage.ListKey(list)
+	chatID := sent.Chat.ID
+	for _, ref := range b.Store.GetMessages(key) {
+		if ref.ChatID != chatID || ref.MessageID == sent.MessageID {
+			continue
+		}
+		if ref.Pinned {
+			b.request(ctx, tgbotapi.UnpinChatMessageConfig{ChatID: chatID, MessageID: ref.MessageID})
+		}
+		b.Store.UnregisterMessage(ctx, key, chatID, ref.MessageID)
+	}
+
+	b.Store.PinMessage(ctx, key, chatID, sent.MessageID)
+	pin := tgbotapi.PinChatMessageConfig{ChatID: chatID, MessageID: sent.MessageID, DisableNotification: true}
+	if _, err := b.request(ctx, pin); err != nil {
+		// Without the right to pin, the message is still the one kept current.
+		trace.Logf(ctx, "[BOT] Pinning the list in chat %d failed: %v", chatID, err)
+	}
+}
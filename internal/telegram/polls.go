@@ -0,0 +1,132 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// Telegram polls take 2 to 10 options of at most 100 characters.
+const (
+	maxPollOptions   = 10
+	maxPollOptionLen = 100
+)
+
+// =====================================================
+// /poll — native Telegram poll over the top nominations
+// =====================================================
+
+// handlePoll starts a poll over the top unwatched movies. Answers count as
+// votes, just like the vote card buttons. "/poll close" stops the chat's
+// open poll (admins only).
+func (b *Bot) handlePoll(ctx context.Context, msg *tgbotapi.Message) {
+	arg := strings.TrimSpace(msg.CommandArguments())
+	open, hasOpen := b.Store.OpenPoll(msg.Chat.ID)
+
+	if arg == "close" {
+		if !b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+			b.replyText(ctx, msg, "⛔ Only chat admins can close the poll.")
+			return
+		}
+		if !hasOpen {
+			b.replyText(ctx, msg, "There is no open poll here.")
+			return
+		}
+		b.request(ctx, tgbotapi.NewStopPoll(open.ChatID, open.MessageID))
+		b.Store.ClosePoll(ctx, open.ID)
+		b.replyText(ctx, msg, "🗳 Poll closed. The votes stay on the list.")
+		return
+	}
+
+	if hasOpen {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, "🗳 There is already a poll running, vote here. Admins can end it with /poll close.")
+		reply.ReplyToMessageID = open.MessageID
+		if _, err := b.send(ctx, reply); err != nil {
+			// The poll message is gone; don't let it block new polls.
+			b.Store.ClosePoll(ctx, open.ID)
+			b.replyText(ctx, msg, "The last poll disappeared, send /poll again to start a new one.")
+		}
+		return
+	}
+
+	n := maxPollOptions
+	if arg != "" {
+		v, err := strconv.Atoi(arg)
+		if err != nil || v < 2 {
+			b.replyText(ctx, msg, "Usage: /poll [options, 2-10] or /poll close")
+			return
+		}
+		n = min(v, maxPollOptions)
+	}
+
+	format := b.listFormat(msg.Chat.ID)
+	format.SortBy = storage.SortByVotes
+	format.Render = storage.RenderCards
+	format.Limit = n
+	_, ids := storage.BuildNumberedList(b.Store.GetMovies(b.library(msg.Chat.ID), ""), format)
+	if len(ids) < 2 {
+		b.replyText(ctx, msg, "🗳 A poll needs at least two unwatched movies, add some with /movie.")
+		return
+	}
+
+	var options []string
+	for _, id := range ids {
+		m, _ := b.Store.GetMovieByID(id)
+		options = append(options, truncateRunes(fmt.Sprintf("%s (%d)", m.Title, m.Year), maxPollOptionLen))
+	}
+
+	poll := tgbotapi.NewPoll(msg.Chat.ID, "🍿 What should we watch next? Pick all you'd enjoy.", options...)
+	poll.IsAnonymous = false // answers must name the voter to count as votes
+	poll.AllowsMultipleAnswers = true
+	sent, err := b.send(ctx, poll)
+	if err != nil || sent.Poll == nil {
+		b.replyText(ctx, msg, "❌ Couldn't start the poll.")
+		return
+	}
+
+	b.Store.AddPoll(ctx, storage.Poll{
+		ID:        sent.Poll.ID,
+		ChatID:    sent.Chat.ID,
+		MessageID: sent.MessageID,
+		MovieIDs:  ids,
+		CreatedAt: time.Now(),
+	})
+	trace.Logf(ctx, "[BOT] %s started poll %s over %d movies", msg.From.UserName, sent.Poll.ID, len(ids))
+}
+
+// handlePollAnswer merges a poll answer into the votes. Polls the bot
+// doesn't know, or closed ones, are ignored.
+func (b *Bot) handlePollAnswer(ctx context.Context, ans *tgbotapi.PollAnswer) {
+	userID := strconv.FormatInt(ans.User.ID, 10)
+	changed, err := b.Store.RecordPollAnswer(ctx, ans.PollID, userID, ans.OptionIDs)
+	if err != nil {
+		trace.Logf(ctx, "[BOT] Ignoring poll answer: %v", err)
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s answered poll %s: %d votes changed", ans.User.UserName, ans.PollID, len(changed))
+	for _, m := range changed {
+		b.syncMovie(ctx, m)
+		b.publish(ctx, events.VoteChanged, &ans.User, m, m.Votes[userID])
+	}
+}
+
+// handlePollUpdate notices polls that closed on their own.
+func (b *Bot) handlePollUpdate(ctx context.Context, p *tgbotapi.Poll) {
+	if p.IsClosed {
+		b.Store.ClosePoll(ctx, p.ID)
+	}
+}
+
+func truncateRunes(s string, max int) string {
+	if r := []rune(s); len(r) > max {
+		return string(r[:max-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/trace"
+	"moviebot/internal/web"
+)
+
+// =====================================================
+// /publiclist [revoke] — read-only web link
+// =====================================================
+
+// handlePublicList hands out the link to the chat's read-only web list.
+// Admins can revoke it, which replaces the token.
+func (b *Bot) handlePublicList(ctx context.Context, msg *tgbotapi.Message) {
+	if b.PublicBaseURL == "" {
+		b.replyText(ctx, msg, "The web list is not enabled on this bot.")
+		return
+	}
+
+	rotate := strings.EqualFold(strings.TrimSpace(msg.CommandArguments()), "revoke")
+	if rotate && !b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+		trace.Logf(ctx, "[BOT] /publiclist revoke denied for %s", msg.From.UserName)
+		b.replyText(ctx, msg, "⛔ Only chat admins can do that.")
+		return
+	}
+
+	url := web.ListURL(b.PublicBaseURL, b.Store.PublicToken(ctx, msg.Chat.ID, rotate))
+	trace.Logf(ctx, "[BOT] /publiclist (revoke=%v) from %s", rotate, msg.From.UserName)
+
+	text := "🔗 Read-only list for friends: " + url
+	if rotate {
+		text = "♻️ The old link no longer works. New one: " + url
+	}
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.ReplyToMessageID = msg.MessageID
+	reply.DisableWebPagePreview = true
+	b.send(ctx, reply)
+}
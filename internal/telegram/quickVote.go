@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// /vote 7, /seen 7
+// =====================================================
+
+// listViews remembers, per chat, the order of the list last sent there so
+// the numbers on its rows can be typed back.
+type listViews struct {
+	mu    sync.Mutex
+	chats map[int64]listView
+}
+
+type listView struct {
+	list     string   // named list, "" for the main one
+	filtered bool     // one-off list (filtered, /top), never re-rendered
+	ids      []string // movie IDs, row n is ids[n-1]
+}
+
+// rememberList records the list just sent to chatID.
+func (b *Bot) rememberList(chatID int64, view listView) {
+	b.views.mu.Lock()
+	defer b.views.mu.Unlock()
+	if b.views.chats == nil {
+		b.views.chats = make(map[int64]listView)
+	}
+	b.views.chats[chatID] = view
+}
+
+// relisted updates the order after list was re-rendered in chatID, if that
+// is the list the chat last asked for.
+func (b *Bot) relisted(chatID int64, list string, ids []string) {
+	b.views.mu.Lock()
+	defer b.views.mu.Unlock()
+	if v, ok := b.views.chats[chatID]; ok && !v.filtered && v.list == list {
+		v.ids = ids
+		b.views.chats[chatID] = v
+	}
+}
+
+// listedMovie resolves a row number of the last list sent to chatID.
+func (b *Bot) listedMovie(chatID int64, arg string) (storage.Movie, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(arg), "#"))
+	if err != nil {
+		return storage.Movie{}, fmt.Errorf("give the number of a row in /list")
+	}
+
+	b.views.mu.Lock()
+	v, ok := b.views.chats[chatID]
+	b.views.mu.Unlock()
+	if !ok {
+		return storage.Movie{}, fmt.Errorf("send /list first, then use its numbers")
+	}
+	if n < 1 || n > len(v.ids) {
+		return storage.Movie{}, fmt.Errorf("the list has no row %d", n)
+	}
+
+	movie, ok := b.Store.GetMovieByID(v.ids[n-1])
+	if !ok {
+		return storage.Movie{}, fmt.Errorf("that movie is gone, send /list again")
+	}
+	return movie, nil
+}
+
+// handleQuickToggle is /vote <n> and /seen <n>: the vote card buttons for
+// people who would rather type.
+func (b *Bot) handleQuickToggle(ctx context.Context, msg *tgbotapi.Message, watched bool) {
+	usage := "/vote <number>"
+	if watched {
+		usage = "/seen <number>"
+	}
+	movie, err := b.listedMovie(msg.Chat.ID, msg.CommandArguments())
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error()+"\nUsage: "+usage)
+		return
+	}
+
+	userID := strconv.FormatInt(msg.From.ID, 10)
+	if !watched {
+		if b.votingClosed(msg.Chat.ID) {
+			b.replyText(ctx, msg, "🔒 Voting is closed here. Admins reopen it with /deadline.")
+			return
+		}
+		movie, err = b.Store.ToggleVoteByID(ctx, movie.ID, userID)
+		if err != nil {
+			b.replyText(ctx, msg, "❌ "+err.Error())
+			return
+		}
+		trace.Logf(ctx, "[BOT] %s toggled their vote for %s by number", msg.From.UserName, movie.Title)
+		b.syncMovie(ctx, movie)
+		b.publish(ctx, events.VoteChanged, msg.From, movie, movie.Votes[userID])
+		b.awardBadges(ctx, msg.Chat.ID, userID)
+		if movie.Votes[userID] {
+			b.replyText(ctx, msg, fmt.Sprintf("👍 Voted for %s, now at %d 👍", movie.Title, len(movie.Votes)))
+		} else {
+			b.replyText(ctx, msg, fmt.Sprintf("Removed your vote for %s", movie.Title))
+		}
+		return
+	}
+
+	movie, err = b.Store.ToggleWatchedByID(ctx, movie.ID, userID)
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error())
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s toggled watched for %s by number", msg.From.UserName, movie.Title)
+	movie = b.maybeOpenDiscussion(ctx, msg.Chat.ID, b.cardIn(msg.Chat.ID, movie.ID, msg.MessageID), movie)
+	b.syncMovie(ctx, movie)
+	b.publish(ctx, events.MovieWatched, msg.From, movie, movie.Watched[userID])
+	b.awardBadges(ctx, msg.Chat.ID, userID, movie.AddedBy)
+	if movie.Watched[userID] {
+		b.replyText(ctx, msg, fmt.Sprintf("👁 Marked %s as seen", movie.Title))
+		b.offerRating(ctx, msg.Chat.ID, msg.From, movie)
+	} else {
+		b.replyText(ctx, msg, fmt.Sprintf("Marked %s as not seen", movie.Title))
+	}
+}
+
+// cardIn returns the latest vote card of a movie in chatID, or fallback if
+// the chat has none.
+func (b *Bot) cardIn(chatID int64, movieID string, fallback int) int {
+	card := fallback
+	for _, ref := range b.Store.GetMessages(movieID) {
+		if ref.ChatID == chatID {
+			card = ref.MessageID
+		}
+	}
+	return card
+}
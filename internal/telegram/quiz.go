@@ -0,0 +1,289 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/posters"
+	"moviebot/internal/search"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// Quiz rules: how long guesses are taken, how many titles the buttons
+// offer, and the karma for guessing by button or by typing the title.
+const (
+	quizTime       = time.Minute
+	quizChoices    = 4
+	quizKarma      = 1
+	quizTypedKarma = 2
+	quizTries      = 3 // posters tried before giving up
+)
+
+// quizzes are the running quizzes, one per chat.
+type quizzes struct {
+	mu    sync.Mutex
+	chats map[int64]*quiz
+}
+
+type quiz struct {
+	movie     storage.Movie
+	messageID int
+	guessed   map[int64]bool // users who tapped a wrong title
+}
+
+// =====================================================
+// /quiz — guess the movie from a bit of its poster
+// =====================================================
+
+// handleQuiz posts a pixelated crop of the poster of a movie the chat
+// watched, with titles to pick from; "/quiz top" shows the karma board.
+func (b *Bot) handleQuiz(ctx context.Context, msg *tgbotapi.Message) {
+	if strings.TrimSpace(msg.CommandArguments()) == "top" {
+		b.handleKarmaBoard(ctx, msg)
+		return
+	}
+	chatID := msg.Chat.ID
+	if b.Posters == nil {
+		b.replyText(ctx, msg, "🧩 The quiz is not available on this bot.")
+		return
+	}
+	if b.runningQuiz(chatID) != nil {
+		b.replyText(ctx, msg, "🧩 A quiz is already running, guess that one first.")
+		return
+	}
+
+	library := b.Store.GetLibrary(b.library(chatID))
+	var watched []storage.Movie
+	for _, m := range library {
+		if (len(m.Watched) > 0 || len(m.History) > 0) && m.Poster != "" && m.Poster != "N/A" {
+			watched = append(watched, m)
+		}
+	}
+	if len(watched) == 0 || len(library) < 2 {
+		b.replyText(ctx, msg, "🧩 Watch a few movies first, the quiz picks from the ones you saw.")
+		return
+	}
+
+	var (
+		movie  storage.Movie
+		teaser []byte
+		err    error
+	)
+	for i, n := range rand.Perm(len(watched)) {
+		if i == quizTries {
+			break
+		}
+		movie = watched[n]
+		if teaser, err = b.quizTeaser(ctx, movie); err == nil {
+			break
+		}
+		trace.Logf(ctx, "[BOT] No quiz poster for %s: %v", movie.Title, err)
+	}
+	if err != nil {
+		b.replyText(ctx, msg, "⚠️ Couldn't load a poster for the quiz, try again later.")
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: "quiz.jpg", Bytes: teaser})
+	photo.Caption = fmt.Sprintf("🧩 Which movie is this? Tap a title or reply with it, you have %d seconds.\n"+
+		"%d karma for a tap, %d for typing it.", int(quizTime.Seconds()), quizKarma, quizTypedKarma)
+	photo.ReplyMarkup = quizKeyboard(movie, library)
+	sent, err := b.send(ctx, photo)
+	if err != nil {
+		return
+	}
+	trace.Logf(ctx, "[BOT] Quiz on %s started by %s", movie.Title, msg.From.UserName)
+
+	b.quizzes.mu.Lock()
+	if b.quizzes.chats == nil {
+		b.quizzes.chats = make(map[int64]*quiz)
+	}
+	b.quizzes.chats[chatID] = &quiz{movie: movie, messageID: sent.MessageID, guessed: make(map[int64]bool)}
+	b.quizzes.mu.Unlock()
+
+	go func() {
+		time.Sleep(quizTime)
+		b.endQuiz(ctx, chatID, sent.MessageID, nil, 0)
+	}()
+}
+
+// quizTeaser is the obscured poster of movie.
+func (b *Bot) quizTeaser(ctx context.Context, movie storage.Movie) ([]byte, error) {
+	img, err := b.Posters.Get(ctx, movie)
+	if err != nil {
+		return nil, err
+	}
+	return posters.Teaser(img)
+}
+
+// quizKeyboard offers the answer among other titles of the library, one
+// per row, "quiz|<id>".
+func quizKeyboard(answer storage.Movie, library []storage.Movie) tgbotapi.InlineKeyboardMarkup {
+	choices := []storage.Movie{answer}
+	for _, n := range rand.Perm(len(library)) {
+		if len(choices) == quizChoices {
+			break
+		}
+		m := library[n]
+		if !strings.EqualFold(m.Title, answer.Title) {
+			choices = append(choices, m)
+		}
+	}
+	rand.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, m := range choices {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s (%d)", m.Title, m.Year), "quiz|"+m.ID),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// runningQuiz is the chat's quiz, nil if none is running.
+func (b *Bot) runningQuiz(chatID int64) *quiz {
+	b.quizzes.mu.Lock()
+	defer b.quizzes.mu.Unlock()
+	return b.quizzes.chats[chatID]
+}
+
+// handleQuizCallback takes a tapped title; everyone gets one guess.
+func (b *Bot) handleQuizCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if cb.Message == nil {
+		return
+	}
+	chatID := cb.Message.Chat.ID
+	id := strings.TrimPrefix(cb.Data, "quiz|")
+
+	b.quizzes.mu.Lock()
+	q := b.quizzes.chats[chatID]
+	var tried, right bool
+	if q != nil && q.messageID == cb.Message.MessageID {
+		tried = q.guessed[cb.From.ID]
+		right = !tried && id == q.movie.ID
+		if !tried && !right {
+			q.guessed[cb.From.ID] = true
+		}
+	}
+	b.quizzes.mu.Unlock()
+
+	switch {
+	case q == nil || q.messageID != cb.Message.MessageID:
+		b.removeInlineKeyboard(ctx, chatID, cb.Message.MessageID)
+		b.answerToast(ctx, cb, "🧩 This quiz is over")
+	case tried:
+		b.answerToast(ctx, cb, "🧩 You already had your guess")
+	case !right:
+		trace.Logf(ctx, "[BOT] %s guessed wrong in the quiz", cb.From.UserName)
+		b.answerToast(ctx, cb, "❌ Not that one")
+	default:
+		b.endQuiz(ctx, chatID, cb.Message.MessageID, cb.From, quizKarma)
+	}
+}
+
+// collectGuess checks replies to a running quiz for the title. Wrong ones
+// cost nothing. It reports whether msg was a reply to the quiz.
+func (b *Bot) collectGuess(ctx context.Context, msg *tgbotapi.Message) bool {
+	if msg.ReplyToMessage == nil {
+		return false
+	}
+	q := b.runningQuiz(msg.Chat.ID)
+	if q == nil || q.messageID != msg.ReplyToMessage.MessageID {
+		return false
+	}
+	if !quizMatch(q.movie, msg.Text) {
+		return true
+	}
+	b.endQuiz(ctx, msg.Chat.ID, q.messageID, msg.From, quizTypedKarma)
+	return true
+}
+
+// quizMatch reports whether guess names movie, in any language it has a
+// title in, ignoring case, punctuation and a year.
+func quizMatch(movie storage.Movie, guess string) bool {
+	guess, _ = search.NormalizeQuery(guess)
+	key := quizKey(guess)
+	if key == "" {
+		return false
+	}
+	if key == quizKey(movie.Title) {
+		return true
+	}
+	for _, title := range movie.Titles {
+		if key == quizKey(title) {
+			return true
+		}
+	}
+	return false
+}
+
+// quizKey lowercases s and keeps only its letters and digits, without a
+// leading "the".
+func quizKey(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	return strings.Join(words, "")
+}
+
+// endQuiz reveals the answer on the quiz message and awards the winner,
+// nil when time ran out. Only the first call for a quiz does anything.
+func (b *Bot) endQuiz(ctx context.Context, chatID int64, messageID int, winner *tgbotapi.User, karma int) {
+	b.quizzes.mu.Lock()
+	q := b.quizzes.chats[chatID]
+	if q == nil || q.messageID != messageID {
+		b.quizzes.mu.Unlock()
+		return
+	}
+	delete(b.quizzes.chats, chatID)
+	b.quizzes.mu.Unlock()
+
+	movie := q.movie
+	text := fmt.Sprintf("⏰ Time's up! It was %s (%d).", movie.Title, movie.Year)
+	if winner != nil {
+		total := b.Store.AddKarma(ctx, chatID, strconv.FormatInt(winner.ID, 10), karma)
+		text = fmt.Sprintf("🎉 %s got it: %s (%d)!\n🏆 +%d karma, %d in total. /quiz for another one.",
+			winner.FirstName, movie.Title, movie.Year, karma, total)
+		trace.Logf(ctx, "[BOT] %s won the quiz on %s", winner.UserName, movie.Title)
+	} else {
+		trace.Logf(ctx, "[BOT] Quiz on %s timed out", movie.Title)
+	}
+
+	// Show the whole poster; if Telegram can't fetch it, the caption will do.
+	media := tgbotapi.NewInputMediaPhoto(tgbotapi.FileURL(movie.Poster))
+	media.Caption = text
+	reveal := tgbotapi.EditMessageMediaConfig{BaseEdit: tgbotapi.BaseEdit{ChatID: chatID, MessageID: messageID}, Media: media}
+	if _, err := b.send(ctx, reveal); err != nil {
+		b.send(ctx, tgbotapi.NewEditMessageCaption(chatID, messageID, text))
+	}
+}
+
+// handleKarmaBoard ranks the chat's members by quiz karma.
+func (b *Bot) handleKarmaBoard(ctx context.Context, msg *tgbotapi.Message) {
+	board := b.Store.KarmaBoard(msg.Chat.ID)
+	if len(board) == 0 {
+		b.replyText(ctx, msg, "🏆 Nobody has won a /quiz here yet.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🏆 Quiz karma\n")
+	for i, k := range board {
+		if i == leaderboardSize {
+			break
+		}
+		fmt.Fprintf(&sb, "\n%d. %s — %d", i+1, b.userLabel(k.UserID), k.Points)
+	}
+	b.replyText(ctx, msg, sb.String())
+}
@@ -0,0 +1,91 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// /random — weighted pick with confirm / reroll
+// =====================================================
+
+// handleRandom posts the vote card of a random unwatched movie from the main
+// list, favouring ones with more votes, with buttons to settle on it or
+// roll again.
+func (b *Bot) handleRandom(ctx context.Context, msg *tgbotapi.Message) {
+	movie, ok := storage.PickWeighted(b.Store.GetMovies(b.library(msg.Chat.ID), ""))
+	if !ok {
+		b.replyText(ctx, msg, "🎲 Nothing left to pick, everything on the list was watched.")
+		return
+	}
+	trace.Logf(ctx, "[BOT] /random picked %s for %s", movie.Title, msg.From.UserName)
+	b.sendRandomCard(ctx, msg.Chat.ID, msg.MessageID, movie)
+}
+
+// sendRandomCard posts the randomCard of movie and tracks it.
+func (b *Bot) sendRandomCard(ctx context.Context, chatID int64, replyTo int, movie storage.Movie) {
+	text, keyboard := b.randomCard(movie, chatID)
+	sent, photo, err := b.sendCard(ctx, chatID, replyTo, movie.Poster, text, keyboard)
+	if err != nil {
+		return
+	}
+	b.Store.RegisterMessage(ctx, movie.ID, sent.Chat.ID, sent.MessageID)
+	if photo {
+		b.Store.SetMessagePhoto(ctx, movie.ID, sent.Chat.ID, sent.MessageID)
+	}
+}
+
+// randomCard is a vote card with the confirm and reroll buttons below. The
+// card is tracked like any other, so a vote re-renders it without them.
+func (b *Bot) randomCard(movie storage.Movie, chatID int64) (string, tgbotapi.InlineKeyboardMarkup) {
+	text, keyboard := b.buildVoteMessageConfig(movie, chatID)
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✅ Pick it", "random|ok|"+movie.ID),
+		tgbotapi.NewInlineKeyboardButtonData("🎲 Reroll", "random|again|"+movie.ID),
+	))
+	return "🎲 " + text, keyboard
+}
+
+// handleRandomCallback settles on the drawn movie or swaps the card for
+// another draw. A card can't swap its poster, so a reroll posts a new one.
+func (b *Bot) handleRandomCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cb.Data, "|")
+	if len(parts) != 3 || cb.Message == nil {
+		return
+	}
+	action, id := parts[1], parts[2]
+	chatID, cardID := cb.Message.Chat.ID, cb.Message.MessageID
+
+	current, ok := b.Store.GetMovieByID(id)
+	if !ok {
+		b.removeInlineKeyboard(ctx, chatID, cardID)
+		b.answerToast(ctx, cb, "This movie is gone")
+		return
+	}
+
+	switch action {
+	case "ok":
+		trace.Logf(ctx, "[BOT] %s settled on %s", cb.From.UserName, current.Title)
+		text, keyboard := b.buildVoteMessageConfig(current, chatID)
+		b.send(ctx, editCard(chatID, cardID, len(cb.Message.Photo) > 0, text, keyboard))
+		b.send(ctx, tgbotapi.NewMessage(chatID, fmt.Sprintf("🎬 The dice chose %s (%d)! Set it up with /schedule <day> [time] %s",
+			current.Title, current.Year, current.Title)))
+
+	case "again":
+		movie, ok := storage.PickWeighted(b.Store.GetMovies(b.library(chatID), ""), id)
+		if !ok {
+			b.answerToast(ctx, cb, "🎲 Nothing else to pick")
+			return
+		}
+		trace.Logf(ctx, "[BOT] %s rerolled %s -> %s", cb.From.UserName, current.Title, movie.Title)
+
+		b.Store.UnregisterMessage(ctx, id, chatID, cardID)
+		b.request(ctx, tgbotapi.NewDeleteMessage(chatID, cardID))
+		b.sendRandomCard(ctx, chatID, 0, movie)
+	}
+}
@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// RATINGS — 1-10 keyboard after marking a movie watched
+// =====================================================
+
+// offerRating asks someone who just marked a movie watched for a 1-10
+// rating, unless they already rated it. Others who saw it can use the same
+// keyboard.
+func (b *Bot) offerRating(ctx context.Context, chatID int64, user *tgbotapi.User, movie storage.Movie) {
+	userID := strconv.FormatInt(user.ID, 10)
+	if !movie.Watched[userID] {
+		return
+	}
+	if _, rated := movie.Ratings[userID]; rated {
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, ratingPrompt(movie, user.FirstName))
+	msg.ReplyMarkup = ratingKeyboard(movie.ID)
+	b.send(ctx, msg)
+}
+
+func ratingPrompt(movie storage.Movie, name string) string {
+	text := fmt.Sprintf("⭐ How was %s (%d), %s? Rate it 1-10.", movie.Title, movie.Year, name)
+	if avg, n := storage.AverageRating(movie); n > 0 {
+		text += fmt.Sprintf("\nGroup so far: %.1f from %d", avg, n)
+	}
+	return text
+}
+
+func ratingKeyboard(movieID string) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for start := 1; start <= 10; start += 5 {
+		var row []tgbotapi.InlineKeyboardButton
+		for n := start; n < start+5; n++ {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(strconv.Itoa(n), fmt.Sprintf("rate|%s|%d", movieID, n)))
+		}
+		rows = append(rows, row)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// handleRateCallback stores a rating from the keyboard. Only people who
+// marked the movie watched can rate it.
+func (b *Bot) handleRateCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cb.Data, "|")
+	if len(parts) != 3 {
+		return
+	}
+	rating, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return
+	}
+	userID := strconv.FormatInt(cb.From.ID, 10)
+
+	var chatID int64
+	if cb.Message != nil {
+		chatID = cb.Message.Chat.ID
+	}
+	movie, ok := b.chatMovie(chatID, parts[1])
+	if !ok {
+		b.answerToast(ctx, cb, "This movie is gone")
+		return
+	}
+	if !movie.Watched[userID] {
+		b.answerToast(ctx, cb, "👁 Mark it watched first")
+		return
+	}
+
+	movie, err = b.Store.SetRating(ctx, movie.ID, userID, rating)
+	if err != nil {
+		b.answerToast(ctx, cb, "❌ "+err.Error())
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s rated %s %d/10", cb.From.UserName, movie.Title, rating)
+	b.answerToast(ctx, cb, fmt.Sprintf("⭐ Noted %d/10", rating))
+
+	if cb.Message != nil {
+		avg, n := storage.AverageRating(movie)
+		text := fmt.Sprintf("⭐ %s (%d): %.1f/10 from %d ratings. Seen it too? Rate it 1-10.", movie.Title, movie.Year, avg, n)
+		b.send(ctx, tgbotapi.NewEditMessageTextAndMarkup(cb.Message.Chat.ID, cb.Message.MessageID, text, ratingKeyboard(movie.ID)))
+	}
+	b.syncMovie(ctx, movie)
+}
@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/i18n"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// REACTION VOTES
+// =====================================================
+
+// With vote_options configured, vote cards offer one button per reaction
+// instead of 👍, and movies are ranked by their weighted score.
+
+// reactionLine tallies a card's reactions, "😍 2 · 🙂 1 · 😴 0 — score 5".
+func reactionLine(movie storage.Movie) string {
+	counts := storage.ReactionCounts(movie)
+	var parts []string
+	for _, o := range storage.VoteOptions() {
+		parts = append(parts, fmt.Sprintf("%s %d", o.Emoji, counts[o.Emoji]))
+	}
+	return fmt.Sprintf("%s — score %g", strings.Join(parts, " · "), storage.Score(movie))
+}
+
+// reactionButtons are a card's reaction buttons, "react|<movie>|<n>" for
+// option n.
+func reactionButtons(movie storage.Movie) []tgbotapi.InlineKeyboardButton {
+	counts := storage.ReactionCounts(movie)
+	var row []tgbotapi.InlineKeyboardButton
+	for i, o := range storage.VoteOptions() {
+		label := o.Emoji
+		if o.Label != "" {
+			label += " " + o.Label
+		}
+		if c := counts[o.Emoji]; c > 0 {
+			label += fmt.Sprintf(" (%d)", c)
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("react|%s|%d", movie.ID, i)))
+	}
+	return row
+}
+
+// scoreText is how a movie did in the vote: "3 votes", or its score with
+// reactions on.
+func scoreText(m storage.Movie) string {
+	if len(storage.VoteOptions()) == 0 {
+		return votesText(len(m.Votes))
+	}
+	return fmt.Sprintf("a score of %g", storage.Score(m))
+}
+
+// handleReactCallback handles the reaction buttons. Tapping your reaction
+// again takes it back; another one replaces it.
+func (b *Bot) handleReactCallback(ctx context.Context, cb *tgbotapi.CallbackQuery, lang string) {
+	parts := strings.Split(cb.Data, "|")
+	if len(parts) != 3 {
+		return
+	}
+	options := storage.VoteOptions()
+	i, err := strconv.Atoi(parts[2])
+	var chatID int64
+	if cb.Message != nil {
+		chatID = cb.Message.Chat.ID
+	}
+	if _, ok := b.chatMovie(chatID, parts[1]); err != nil || i < 0 || i >= len(options) || !ok {
+		b.answerToast(ctx, cb, "❌ "+i18n.T(lang, "Sorry, this message is too old"))
+		return
+	}
+	if cb.Message != nil && b.votingClosed(cb.Message.Chat.ID) {
+		b.answerToast(ctx, cb, "🔒 "+i18n.T(lang, "Voting is closed here"))
+		return
+	}
+	userID := strconv.FormatInt(cb.From.ID, 10)
+	if movie, ok := b.Store.GetMovieByID(parts[1]); ok && movie.Upcoming && movie.Reactions[userID] == "" {
+		b.answerToast(ctx, cb, "📅 "+i18n.T(lang, "Voting opens once it's out"))
+		return
+	}
+	if b.debounce.tooSoon(userID+"|"+cb.Data, time.Now()) {
+		trace.Logf(ctx, "[CALLBACK] Ignoring double tap on %s", cb.Data)
+		if movie, ok := b.Store.GetMovieByID(parts[1]); ok {
+			b.answerToast(ctx, cb, reactionToast(lang, movie, userID))
+		}
+		return
+	}
+
+	movie, err := b.Store.SetReaction(ctx, parts[1], userID, options[i].Emoji)
+	if err != nil {
+		b.answerToast(ctx, cb, "❌ "+err.Error())
+		return
+	}
+	b.answerToast(ctx, cb, reactionToast(lang, movie, userID))
+	b.syncMovie(ctx, movie)
+	b.publish(ctx, events.VoteChanged, cb.From, movie, movie.Votes[userID])
+	if cb.Message != nil {
+		b.awardBadges(ctx, cb.Message.Chat.ID, userID)
+	}
+}
+
+// reactionToast confirms the user's reaction to movie, or that they took it
+// back.
+func reactionToast(lang string, movie storage.Movie, userID string) string {
+	emoji, ok := movie.Reactions[userID]
+	if !ok {
+		return i18n.T(lang, "Reaction taken back.")
+	}
+	if o, ok := storage.VoteOptionOf(emoji); ok && o.Label != "" {
+		return emoji + " " + o.Label
+	}
+	return emoji
+}
@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// /recap — wrap up a movie night, admins only
+// =====================================================
+
+// handleRecap marks the chat's latest movie night complete and posts its
+// minutes. The best quote can be given right away ("/recap I'll be back")
+// or later by replying to the recap.
+func (b *Bot) handleRecap(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+		b.replyText(ctx, msg, "⛔ Only chat admins can wrap up a movie night.")
+		return
+	}
+
+	night, ok := b.openNight(msg.Chat.ID, time.Now())
+	if !ok {
+		b.replyText(ctx, msg, "📅 No movie night to wrap up. Set one up with /schedule.")
+		return
+	}
+
+	recap := storage.Recap{
+		CompletedBy: msg.From.UserName,
+		Attendees:   slices.Sorted(maps.Keys(night.Attended)),
+		Quote:       strings.TrimSpace(msg.CommandArguments()),
+	}
+	if movie, ok := b.Store.GetMovieByID(night.MovieID); ok {
+		recap.Rating, recap.Ratings = storage.AverageRating(movie)
+	}
+
+	night, err := b.Store.CompleteNight(ctx, night.ID, recap)
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error())
+		return
+	}
+	trace.Logf(ctx, "[BOT] /recap of %s by %s", night.Title, msg.From.UserName)
+	b.retireNightCard(ctx, night)
+
+	sent, err := b.send(ctx, tgbotapi.NewMessage(msg.Chat.ID, b.recapText(night)))
+	if err != nil {
+		return
+	}
+	b.Store.SetRecapMessage(ctx, night.ID, sent.MessageID)
+}
+
+// openNight returns the chat's latest night that has started but was not
+// wrapped up yet.
+func (b *Bot) openNight(chatID int64, now time.Time) (storage.Night, bool) {
+	nights := b.Store.Nights(chatID)
+	for i := len(nights) - 1; i >= 0; i-- {
+		if n := nights[i]; n.At.Before(now) && n.Recap == nil {
+			return n, true
+		}
+	}
+	return storage.Night{}, false
+}
+
+// recapText renders the minutes of a completed night.
+func (b *Bot) recapText(n storage.Night) string {
+	r := n.Recap
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📝 Movie night recap, %s\n\n", n.At.In(b.chatLocation(n.ChatID)).Format("Mon 2 Jan 2006"))
+	fmt.Fprintf(&sb, "🎬 %s\n", n.Title)
+
+	if len(r.Attendees) > 0 {
+		names := make([]string, len(r.Attendees))
+		for i, id := range r.Attendees {
+			names[i] = b.userLabel(id)
+		}
+		fmt.Fprintf(&sb, "🎟 %d there: %s\n", len(names), strings.Join(names, ", "))
+	} else {
+		sb.WriteString("🎟 Nobody marked it watched in time\n")
+	}
+
+	if r.Ratings > 0 {
+		fmt.Fprintf(&sb, "⭐ %.1f/10 from %d ratings\n", r.Rating, r.Ratings)
+	}
+
+	if r.Quote != "" {
+		fmt.Fprintf(&sb, "\n💬 “%s”", r.Quote)
+	} else {
+		sb.WriteString("\n💬 Reply to this message with the best quote of the night.")
+	}
+	return sb.String()
+}
+
+// collectQuote takes the first reply to a recap as the night's best quote.
+// It reports whether msg was one.
+func (b *Bot) collectQuote(ctx context.Context, msg *tgbotapi.Message) bool {
+	if msg.ReplyToMessage == nil {
+		return false
+	}
+	night, ok := b.Store.NightByRecapMessage(msg.Chat.ID, msg.ReplyToMessage.MessageID)
+	if !ok {
+		return false
+	}
+	quote := strings.TrimSpace(msg.Text)
+	if night.Recap.Quote != "" || quote == "" {
+		return true
+	}
+
+	night, err := b.Store.SetRecapQuote(ctx, night.ID, quote)
+	if err != nil {
+		return true
+	}
+	b.send(ctx, tgbotapi.NewEditMessageText(msg.Chat.ID, night.Recap.MessageID, b.recapText(night)))
+	b.replyText(ctx, msg, "💬 Quote saved to the recap.")
+	return true
+}
@@ -0,0 +1,56 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/i18n"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// 📅 RELEASES — upcoming movies open for voting once out
+// =====================================================
+
+// releaseText is the countdown an upcoming movie's card shows instead of
+// its votes: "Releases in 23d (14 Nov 2026)".
+func releaseText(lang string, movie storage.Movie, now time.Time) string {
+	return i18n.Tf(lang, "Releases in %dd (%s)", storage.ReleaseDays(movie, now), movie.Released.Format("2 Jan 2006"))
+}
+
+// checkReleases keeps the countdown on the cards of upcoming movies
+// current and opens voting on the ones whose release day has come.
+func (b *Bot) checkReleases(ctx context.Context, now time.Time) {
+	for _, m := range b.Store.UpcomingMovies() {
+		if now.Before(m.Released) {
+			if b.releases.changed(m.ID, fmt.Sprint(storage.ReleaseDays(m, now))) {
+				b.syncMovie(ctx, m)
+			}
+			continue
+		}
+		if movie, ok := b.Store.MarkReleased(ctx, m.ID); ok {
+			b.announceRelease(ctx, movie)
+		}
+	}
+}
+
+// announceRelease tells the groups sharing movie's library, and the private
+// chat that owns it, that it is out, with a fresh vote card.
+func (b *Bot) announceRelease(ctx context.Context, movie storage.Movie) {
+	b.syncMovie(ctx, movie)
+	b.publish(ctx, events.MovieUpdated, nil, movie, true)
+
+	for _, chat := range b.Store.GetChats() {
+		if chat.Left || b.library(chat.ID) != movie.ChatID || (chat.Type == "private" && chat.ID != movie.ChatID) {
+			continue
+		}
+		trace.Logf(ctx, "[BOT] Announcing the release of %s in chat %d", movie.Title, chat.ID)
+		lang := b.chatLanguage(chat.ID)
+		b.send(ctx, tgbotapi.NewMessage(chat.ID, "🍿 "+i18n.Tf(lang, "%s is out! Voting on it is open.", fmt.Sprintf("%s (%d)", movie.Title, movie.Year))))
+		b.createOrUpdateVoteMessage(ctx, chat.ID, movie.ID)
+	}
+}
@@ -0,0 +1,185 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// reminderTick is how often the reminder scheduler looks at the nights.
+const reminderTick = time.Minute
+
+// nightReminders returns how long before a night chatID is reminded of it,
+// longest first; none when the chat turned them off.
+func (b *Bot) nightReminders(chatID int64) []time.Duration {
+	leads := b.NightReminders
+	if v := b.Store.ChatSetting(chatID, "reminders"); v != "" {
+		leads = parseLeads(v)
+	}
+	leads = slices.Clone(leads)
+	slices.Sort(leads)
+	slices.Reverse(leads)
+	return leads
+}
+
+// reminderDMs reports whether chatID's reminders are also DMed to whoever
+// answered Going or Maybe.
+func (b *Bot) reminderDMs(chatID int64) bool {
+	switch b.Store.ChatSetting(chatID, "reminder_dms") {
+	case "on":
+		return true
+	case "off":
+		return false
+	}
+	return b.ReminderDMs
+}
+
+// parseLeads reads the "reminders" setting, "24h,1h" or "off".
+func parseLeads(v string) []time.Duration {
+	var leads []time.Duration
+	for _, f := range strings.Split(v, ",") {
+		if d, err := time.ParseDuration(strings.TrimSpace(f)); err == nil && d > 0 {
+			leads = append(leads, d)
+		}
+	}
+	return leads
+}
+
+func checkLeads(v string) error {
+	if v == "off" {
+		return nil
+	}
+	for _, f := range strings.Split(v, ",") {
+		if d, err := time.ParseDuration(strings.TrimSpace(f)); err != nil || d <= 0 {
+			return fmt.Errorf("%q is not a list of lead times like 24h,1h (or off)", v)
+		}
+	}
+	return nil
+}
+
+// =====================================================
+// REMINDER SCHEDULER
+// =====================================================
+
+// RunReminders sends the reminders of upcoming movie nights until ctx is
+// done. What was sent is kept on the nights, so after a restart the pending
+// ones pick up where they were. With the scheduler off it returns at once.
+func (b *Bot) RunReminders(ctx context.Context) {
+	if b.Disabled[featureScheduler] {
+		log.Printf("[BOT] Scheduler is off, no night reminders")
+		return
+	}
+	ticker := time.NewTicker(reminderTick)
+	defer ticker.Stop()
+	for {
+		if !b.InMaintenance() {
+			b.checkReminders(trace.NewContext(ctx), time.Now())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Bot) checkReminders(ctx context.Context, now time.Time) {
+	for _, n := range b.Store.PendingNights(now) {
+		lead, ok := dueReminder(b.nightReminders(n.ChatID), n, now)
+		if ok && b.Store.MarkReminded(ctx, n.ID, lead) {
+			b.remind(ctx, n, n.At.Sub(now))
+		}
+	}
+}
+
+// dueReminder picks the reminder of n to send at now: the shortest lead
+// time that was reached and not reminded of yet. Longer ones missed while
+// the bot was down are skipped, as are ones that had passed by the time the
+// night was scheduled.
+func dueReminder(leads []time.Duration, n storage.Night, now time.Time) (time.Duration, bool) {
+	left := n.At.Sub(now)
+	var due time.Duration
+	for _, lead := range leads {
+		if lead < left || (n.Reminded > 0 && lead >= n.Reminded) || !n.CreatedAt.Before(n.At.Add(-lead)) {
+			continue
+		}
+		if due == 0 || lead < due {
+			due = lead
+		}
+	}
+	return due, due > 0
+}
+
+// remind tells the chat that n starts in left, under the night's card, and
+// DMs those who said they'd come unless they muted DMs with /notify off.
+func (b *Bot) remind(ctx context.Context, n storage.Night, left time.Duration) {
+	movie, ok := b.Store.GetMovieByID(n.MovieID)
+	if !ok {
+		movie = storage.Movie{Title: n.Title}
+	}
+	when := n.At.In(b.chatLocation(n.ChatID)).Format("Mon 2 Jan, 15:04")
+	trace.Logf(ctx, "[BOT] Reminding chat %d of %s, %s left", n.ChatID, n.Title, left.Round(time.Minute))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "⏰ Movie night in %s!\n\n🎬 %s (%d)\n📅 %s\n", leadText(left), movie.Title, movie.Year, when)
+	if going := b.rsvpNames(n, storage.RSVPGoing); len(going) > 0 {
+		fmt.Fprintf(&sb, "✅ Going: %s\n", strings.Join(going, ", "))
+	}
+	if n.WatchLink != "" {
+		fmt.Fprintf(&sb, "\n📺 Watch together: %s\n", n.WatchLink)
+	}
+	reminder := tgbotapi.NewMessage(n.ChatID, sb.String())
+	reminder.ReplyToMessageID = n.MessageID
+	reminder.AllowSendingWithoutReply = true
+	reminder.DisableWebPagePreview = true
+	b.send(ctx, reminder)
+
+	chat, _ := b.Store.GetChat(n.ChatID)
+	if !b.reminderDMs(n.ChatID) || chat.Type == "private" {
+		return
+	}
+	where := ""
+	if chat.Title != "" {
+		where = " in " + chat.Title
+	}
+	for userID, answer := range n.RSVP {
+		if answer == storage.RSVPNo {
+			continue
+		}
+		u, ok := b.Store.GetUser(userID)
+		id, err := strconv.ParseInt(userID, 10, 64)
+		if err != nil || (ok && u.Mute) {
+			continue
+		}
+		text := fmt.Sprintf("⏰ %s (%d) starts in %s%s, %s.", movie.Title, movie.Year, leadText(left), where, when)
+		if n.WatchLink != "" {
+			text += "\n📺 " + n.WatchLink
+		}
+		b.send(ctx, tgbotapi.NewMessage(id, text+"\n\nTurn these off with /notify off."))
+	}
+}
+
+// leadText is how long until a night, rounded to ten minutes: "2 days",
+// "1 hour", "1 h 30 min", "40 minutes".
+func leadText(d time.Duration) string {
+	d = max(d.Round(10*time.Minute), 10*time.Minute)
+	switch {
+	case d >= 48*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	case d == time.Hour:
+		return "1 hour"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%d hours", d/time.Hour)
+	case d > time.Hour:
+		return fmt.Sprintf("%d h %d min", d/time.Hour, d%time.Hour/time.Minute)
+	}
+	return fmt.Sprintf("%d minutes", d/time.Minute)
+}
@@ -0,0 +1,127 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// removeChoices caps the /remove keyboard; narrow it down with a title.
+const removeChoices = 20
+
+// =====================================================
+// /remove — admins only
+// =====================================================
+
+// handleRemove offers the movies matching a title, or all of them, as
+// buttons to remove.
+func (b *Bot) handleRemove(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isManager(ctx, msg.Chat.ID, msg.From.ID) {
+		b.replyText(ctx, msg, "⛔ Only chat admins can remove movies.")
+		return
+	}
+	query := strings.ToLower(strings.TrimSpace(msg.CommandArguments()))
+
+	var choices []storage.Movie
+	for _, m := range b.Store.GetLibrary(b.library(msg.Chat.ID)) {
+		if query == "" || strings.Contains(strings.ToLower(m.Title), query) {
+			choices = append(choices, m)
+		}
+	}
+	if len(choices) == 0 {
+		b.replyText(ctx, msg, "No movie to remove matches that.")
+		return
+	}
+
+	sort.Slice(choices, func(i, j int) bool { return choices[i].Title < choices[j].Title })
+	text := "🗑 Which movie should be removed? Votes and history go with it."
+	if len(choices) > removeChoices {
+		text = fmt.Sprintf("🗑 %d movies, showing the first %d. Use /remove <title> to narrow it down.", len(choices), removeChoices)
+		choices = choices[:removeChoices]
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, m := range choices {
+		label := fmt.Sprintf("%s (%d)", m.Title, m.Year)
+		if m.List != "" {
+			label += " 📂 " + m.List
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, "remove|"+m.ID),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✖️ Cancel", "remove|"),
+	))
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.ReplyToMessageID = msg.MessageID
+	reply.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.send(ctx, reply)
+}
+
+// handleRemoveCallback removes the picked movie if whoever pressed the
+// button may manage the chat's library.
+func (b *Bot) handleRemoveCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if cb.Message == nil {
+		return
+	}
+	chatID, panelID := cb.Message.Chat.ID, cb.Message.MessageID
+
+	id := strings.TrimPrefix(cb.Data, "remove|")
+	if id == "" {
+		b.request(ctx, tgbotapi.NewDeleteMessage(chatID, panelID))
+		return
+	}
+
+	movie, ok := b.Store.GetMovieByID(id)
+	if !ok {
+		b.answerToast(ctx, cb, "This movie is already gone")
+		return
+	}
+	if movie.ChatID != b.library(chatID) || !b.isManager(ctx, chatID, cb.From.ID) {
+		trace.Logf(ctx, "[BOT] Removal of %s denied for %s", movie.Title, cb.From.UserName)
+		b.answerToast(ctx, cb, "⛔ Only chat admins can remove movies")
+		return
+	}
+
+	movie, cards, err := b.Store.RemoveMovieByID(ctx, id)
+	if err != nil {
+		b.answerToast(ctx, cb, "❌ "+err.Error())
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s removed %s", cb.From.UserName, movie.Title)
+
+	b.takeDownCards(ctx, cards, fmt.Sprintf("🗑 %s (%d) was removed from the list.", movie.Title, movie.Year))
+	b.syncListMessages(ctx)
+	b.publish(ctx, events.MovieRemoved, cb.From, movie, false)
+
+	edit := tgbotapi.NewEditMessageText(chatID, panelID, fmt.Sprintf("🗑 %s (%d) was removed by %s.", movie.Title, movie.Year, cb.From.FirstName))
+	b.send(ctx, edit)
+}
+
+// takeDownCards deletes vote cards. Telegram only lets bots delete recent
+// messages, and no inline ones, so those are edited into note, without
+// buttons, instead.
+func (b *Bot) takeDownCards(ctx context.Context, cards []storage.MessageRef, note string) {
+	for _, ref := range cards {
+		if ref.InlineID != "" {
+			b.request(ctx, inlineEdit(ref.InlineID, note, nil))
+			continue
+		}
+		if _, err := b.request(ctx, tgbotapi.NewDeleteMessage(ref.ChatID, ref.MessageID)); err == nil {
+			continue
+		}
+		if ref.Photo {
+			b.send(ctx, tgbotapi.NewEditMessageCaption(ref.ChatID, ref.MessageID, note))
+		} else {
+			b.send(ctx, tgbotapi.NewEditMessageText(ref.ChatID, ref.MessageID, note))
+		}
+	}
+}
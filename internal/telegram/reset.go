@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// /reset confirm — fresh season, admins only
+// =====================================================
+
+// handleReset wipes the chat's data after a backup. A shared movie library
+// is only cleared when no other group uses the bot; a chat's own always is.
+func (b *Bot) handleReset(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isManager(ctx, msg.Chat.ID, msg.From.ID) {
+		b.replyText(ctx, msg, "⛔ Only chat admins can reset the chat.")
+		return
+	}
+
+	var others []string
+	if b.library(msg.Chat.ID) == 0 {
+		others = b.otherGroups(msg.Chat.ID)
+	}
+	withMovies := len(others) == 0
+
+	if strings.TrimSpace(msg.CommandArguments()) != "confirm" {
+		text := "♻️ This starts a fresh season and wipes the chat's settings, blocklist, movie nights and tracked messages"
+		if withMovies {
+			text += ", and the whole movie list with its votes and ratings"
+		} else {
+			text += fmt.Sprintf(". The movie list stays: it is shared with %d other groups", len(others))
+		}
+		text += ".\nA backup is made first. Send /reset confirm to go ahead."
+		b.replyText(ctx, msg, text)
+		return
+	}
+
+	dir, err := b.Store.Backup(ctx)
+	if err != nil {
+		trace.Logf(ctx, "[BOT] Backup before reset of chat %d failed: %v", msg.Chat.ID, err)
+		b.replyText(ctx, msg, "❌ The backup failed, nothing was reset.")
+		return
+	}
+
+	r := b.Store.ResetChat(ctx, msg.Chat.ID, b.library(msg.Chat.ID), withMovies)
+	trace.Logf(ctx, "[BOT] Chat %d reset by %s (movies: %v), backup in %s", msg.Chat.ID, msg.From.UserName, withMovies, dir)
+
+	b.rememberList(msg.Chat.ID, listView{filtered: true})
+	b.syncListMessages(ctx)
+
+	text := fmt.Sprintf("♻️ Fresh season! Wiped %d movie nights and %d tracked messages", r.Nights, r.Refs)
+	if withMovies {
+		text += fmt.Sprintf(", and %d movies", r.Movies)
+	}
+	text += fmt.Sprintf(". Backup: %s", filepath.Base(dir))
+	b.replyText(ctx, msg, text)
+}
+
+// otherGroups returns the titles of the groups other than chatID the bot
+// is still in.
+func (b *Bot) otherGroups(chatID int64) []string {
+	var out []string
+	for _, c := range b.Store.GetChats() {
+		if c.ID != chatID && !c.Left && c.Type != "private" {
+			out = append(out, c.Title)
+		}
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// REWATCH
+// =====================================================
+
+// reopenForRewatch puts a watched movie back up for voting and refreshes its
+// cards and the lists.
+func (b *Bot) reopenForRewatch(ctx context.Context, user *tgbotapi.User, movieID string) (storage.Movie, error) {
+	movie, err := b.Store.ReopenForRewatch(ctx, movieID)
+	if err != nil {
+		trace.Logf(ctx, "[BOT] Rewatch of %s failed: %v", movieID, err)
+		return movie, err
+	}
+	trace.Logf(ctx, "[BOT] %s reopened %s for a rewatch", user.UserName, movie.Title)
+
+	b.syncMovie(ctx, movie)
+	b.publish(ctx, events.MovieUpdated, user, movie, true)
+	return movie, nil
+}
+
+// handleRewatch is /rewatch <movie>, for watched movies without a card at
+// hand.
+func (b *Bot) handleRewatch(ctx context.Context, msg *tgbotapi.Message) {
+	var watched []storage.Movie
+	for _, m := range b.Store.GetLibrary(b.library(msg.Chat.ID)) {
+		if storage.IsWatched(m) {
+			watched = append(watched, m)
+		}
+	}
+
+	movie, err := storage.FindMovie(watched, msg.CommandArguments())
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error()+"\nUsage: /rewatch <watched movie>")
+		return
+	}
+
+	movie, err = b.reopenForRewatch(ctx, msg.From, movie.ID)
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error())
+		return
+	}
+	b.replyText(ctx, msg, fmt.Sprintf("🔁 %s is back on the list for a rewatch. Votes start from zero.", movie.Title))
+}
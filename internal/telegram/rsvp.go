@@ -0,0 +1,157 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/i18n"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// rsvpAnswers are the announcement buttons, in card order.
+var rsvpAnswers = []struct {
+	answer, emoji, label string
+}{
+	{storage.RSVPGoing, "✅", "Going"},
+	{storage.RSVPMaybe, "🤔", "Maybe"},
+	{storage.RSVPNo, "❌", "Can't"},
+}
+
+// rsvpLabel is an answer's button label in lang.
+func rsvpLabel(lang, emoji, label string) string {
+	return emoji + " " + i18n.T(lang, label)
+}
+
+// =====================================================
+// MOVIE NIGHT RSVPs
+// =====================================================
+
+// announceNight sends the card of a new night with its RSVP buttons in
+// reply to replyTo and pins it, so the chat sees who is coming until the
+// night is wrapped up.
+func (b *Bot) announceNight(ctx context.Context, replyTo int, night storage.Night, movie storage.Movie) {
+	chatID := night.ChatID
+	reply := tgbotapi.NewMessage(chatID, b.nightCard(night, movie))
+	reply.ReplyToMessageID = replyTo
+	reply.AllowSendingWithoutReply = true
+	reply.DisableWebPagePreview = true
+	reply.ReplyMarkup = rsvpKeyboard(night, b.chatLanguage(chatID))
+	sent, err := b.send(ctx, reply)
+	if err != nil {
+		return
+	}
+
+	b.Store.SetNightMessage(ctx, night.ID, sent.MessageID)
+	pin := tgbotapi.PinChatMessageConfig{ChatID: chatID, MessageID: sent.MessageID, DisableNotification: true}
+	if _, err := b.request(ctx, pin); err != nil {
+		trace.Logf(ctx, "[BOT] Pinning night %s in chat %d failed: %v", night.ID, chatID, err)
+	}
+}
+
+// nightCard is the announcement with who answered what.
+func (b *Bot) nightCard(n storage.Night, movie storage.Movie) string {
+	var sb strings.Builder
+	sb.WriteString(nightText(n, movie, b.chatLocation(n.ChatID)))
+
+	lang := b.chatLanguage(n.ChatID)
+	for _, a := range rsvpAnswers {
+		if names := b.rsvpNames(n, a.answer); len(names) > 0 {
+			fmt.Fprintf(&sb, "\n%s: %s", rsvpLabel(lang, a.emoji, a.label), strings.Join(names, ", "))
+		}
+	}
+	return sb.String()
+}
+
+// rsvpNames lists who gave answer, sorted by user ID.
+func (b *Bot) rsvpNames(n storage.Night, answer string) []string {
+	var names []string
+	for _, id := range slices.Sorted(maps.Keys(n.RSVP)) {
+		if n.RSVP[id] == answer {
+			names = append(names, b.userLabel(id))
+		}
+	}
+	return names
+}
+
+func rsvpKeyboard(n storage.Night, lang string) tgbotapi.InlineKeyboardMarkup {
+	counts := make(map[string]int)
+	for _, answer := range n.RSVP {
+		counts[answer]++
+	}
+
+	var row []tgbotapi.InlineKeyboardButton
+	for _, a := range rsvpAnswers {
+		label := rsvpLabel(lang, a.emoji, a.label)
+		if c := counts[a.answer]; c > 0 {
+			label += fmt.Sprintf(" (%d)", c)
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("rsvp|%s|%s", n.ID, a.answer)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(row)
+}
+
+// handleRSVPCallback handles the announcement buttons: "rsvp|<night>|going",
+// "maybe" or "no". Tapping your answer again takes it back.
+func (b *Bot) handleRSVPCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cb.Data, "|")
+	if len(parts) != 3 {
+		return
+	}
+	userID := strconv.FormatInt(cb.From.ID, 10)
+
+	night, err := b.Store.SetRSVP(ctx, parts[1], userID, parts[2])
+	if err != nil {
+		b.answerToast(ctx, cb, "❌ "+err.Error())
+		return
+	}
+
+	lang := b.userLanguage(night.ChatID, cb.From)
+	toast := i18n.T(lang, "Answer taken back.")
+	for _, a := range rsvpAnswers {
+		if night.RSVP[userID] == a.answer {
+			toast = i18n.Tf(lang, "You're down as %s", rsvpLabel(lang, a.emoji, a.label))
+		}
+	}
+	b.answerToast(ctx, cb, toast)
+	b.syncNightCard(ctx, night)
+}
+
+// syncNightCard re-renders a night's announcement. Wrapped-up nights lose
+// their buttons.
+func (b *Bot) syncNightCard(ctx context.Context, night storage.Night) {
+	if night.MessageID == 0 {
+		return
+	}
+	movie, ok := b.Store.GetMovieByID(night.MovieID)
+	if !ok {
+		movie = storage.Movie{Title: night.Title}
+	}
+
+	edit := tgbotapi.NewEditMessageText(night.ChatID, night.MessageID, b.nightCard(night, movie))
+	edit.DisableWebPagePreview = true
+	if night.Recap == nil {
+		keyboard := rsvpKeyboard(night, b.chatLanguage(night.ChatID))
+		edit.ReplyMarkup = &keyboard
+	}
+	if _, err := b.send(ctx, edit); err != nil && !notModified(err) {
+		trace.Logf(ctx, "[BOT] Updating the card of night %s failed: %v", night.ID, err)
+	}
+}
+
+// retireNightCard unpins a wrapped-up night's announcement and takes its
+// buttons off.
+func (b *Bot) retireNightCard(ctx context.Context, night storage.Night) {
+	if night.MessageID == 0 {
+		return
+	}
+	b.syncNightCard(ctx, night)
+	if _, err := b.request(ctx, tgbotapi.UnpinChatMessageConfig{ChatID: night.ChatID, MessageID: night.MessageID}); err != nil {
+		trace.Logf(ctx, "[BOT] Unpinning night %s failed: %v", night.ID, err)
+	}
+}
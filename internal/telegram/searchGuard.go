@@ -0,0 +1,132 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/i18n"
+	"moviebot/internal/search"
+	"moviebot/internal/trace"
+)
+
+// searchGuardWindow is how long after someone searched a title the same
+// search by someone else in the chat is pointed at theirs instead of run.
+const searchGuardWindow = 2 * time.Minute
+
+// searchGuard remembers the recent /movie searches per chat and title, so
+// that in a busy group two people after the same movie share one selection
+// and one vote card.
+type searchGuard struct {
+	mu     sync.Mutex
+	recent map[string]recentSearch // "<chat>|<title key>"
+}
+
+type recentSearch struct {
+	userID    int64
+	name      string
+	at        time.Time
+	sessionID string // the selection, once results are in
+	movieID   string // the pick, once made
+}
+
+// searchKey identifies a query regardless of case, spacing, punctuation
+// and file-name clutter.
+func searchKey(chatID int64, query string) string {
+	q, year := search.NormalizeQuery(query)
+	return fmt.Sprintf("%d|%s|%d", chatID, quizKey(q), year)
+}
+
+// claim records user's search for key, unless someone else searched it
+// within searchGuardWindow; then it returns theirs.
+func (g *searchGuard) claim(key string, user *tgbotapi.User, now time.Time) (recentSearch, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if r, ok := g.recent[key]; ok && r.userID != user.ID && now.Sub(r.at) < searchGuardWindow {
+		return r, true
+	}
+	if g.recent == nil {
+		g.recent = make(map[string]recentSearch)
+	}
+	for k, r := range g.recent {
+		if now.Sub(r.at) >= searchGuardWindow {
+			delete(g.recent, k)
+		}
+	}
+	g.recent[key] = recentSearch{userID: user.ID, name: user.FirstName, at: now}
+	return recentSearch{}, false
+}
+
+// update changes the search recorded for key, if it is still there.
+func (g *searchGuard) update(key string, change func(*recentSearch)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if r, ok := g.recent[key]; ok {
+		change(&r)
+		g.recent[key] = r
+	}
+}
+
+// release forgets the search for key, e.g. when it found nothing.
+func (g *searchGuard) release(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.recent, key)
+}
+
+// duplicateSearch checks msg's search for query against the chat's recent
+// ones. When someone else is on it, msg is answered with a pointer to their
+// selection or to the vote card of their pick, and true is returned;
+// otherwise the search is claimed for msg's sender.
+func (b *Bot) duplicateSearch(ctx context.Context, msg *tgbotapi.Message, query string) bool {
+	key := searchKey(msg.Chat.ID, query)
+	first, dup := b.searches.claim(key, msg.From, time.Now())
+	if !dup {
+		return false
+	}
+	lang := b.userLanguage(msg.Chat.ID, msg.From)
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, "")
+	reply.ReplyToMessageID = msg.MessageID
+	switch {
+	case first.movieID != "":
+		card := 0
+		for _, ref := range b.Store.GetMessages(first.movieID) {
+			if ref.ChatID == msg.Chat.ID && ref.InlineID == "" {
+				card = ref.MessageID
+			}
+		}
+		if card == 0 {
+			b.createOrUpdateVoteMessage(ctx, msg.Chat.ID, first.movieID)
+			return true
+		}
+		reply.Text = "👆 " + i18n.Tf(lang, "%s just added that one, vote on it here.", first.name)
+		reply.ReplyToMessageID = card
+	case first.sessionID != "":
+		b.sessMu.Lock()
+		sess := b.sessions[first.sessionID]
+		var selection int
+		if sess != nil && len(sess.ActiveMsgIDs) > 0 {
+			selection = sess.ActiveMsgIDs[len(sess.ActiveMsgIDs)-1]
+		}
+		b.sessMu.Unlock()
+		if selection == 0 {
+			// Their search ended without a pick; this one may run.
+			b.searches.release(key)
+			b.searches.claim(key, msg.From, time.Now())
+			return false
+		}
+		reply.Text = "👆 " + i18n.Tf(lang, "%s is picking from the results for that right now, the vote card follows.", first.name)
+		reply.ReplyToMessageID = selection
+	default:
+		reply.Text = "🔎 " + i18n.Tf(lang, "%s is already searching for that, hang on.", first.name)
+	}
+	reply.AllowSendingWithoutReply = true
+	trace.Logf(ctx, "[BOT] %s's search for '%s' joins %s's", msg.From.UserName, strings.TrimSpace(query), first.name)
+	b.send(ctx, reply)
+	return true
+}
@@ -0,0 +1,433 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/i18n"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// chatSetting describes a key a chat can override with /settings.
+type chatSetting struct {
+	help    string
+	choices []string                 // offered by the /settings menu; none keeps it to /settings set
+	check   func(value string) error // nil: one of choices
+}
+
+// validate checks value for the setting.
+func (s chatSetting) validate(value string) error {
+	if s.check == nil {
+		return checkOneOf(s.choices...)(value)
+	}
+	return s.check(value)
+}
+
+// chatSettings lists the per-chat settings; anything not here is rejected
+// by /settings set and skipped on import.
+var chatSettings = map[string]chatSetting{
+	"alternatives": {"how many search results /movie offers one after another", []string{"1", "3", "5", "10"}, checkRange(1, 10)},
+	"autodelete":   {"how long search results and unanswered /movie prompts stay, e.g. 5m", []string{"1m", "5m", "15m", "1h"}, checkAutoDelete},
+	"cards":        {"picking a movie that has a card here: new (another card), bump (move it down) or reply (point at it)", []string{"new", "bump", "reply"}, nil},
+	"cooldown":     {"minimum gap between movie nights, e.g. 48h", []string{"0s", "24h", "48h", "168h"}, checkDuration},
+	"dates":        {"relative (3d ago) or exact dates in lists", []string{"relative", "exact"}, nil},
+	"format":       {"the table format of /list, also set with /list <format>", slices.Sorted(maps.Keys(tableFormats)), nil},
+	"language":     {"language of lists, cards and relative times (replies follow each user's Telegram app): " + strings.Join(i18n.Languages(), ", "), i18n.Languages(), nil},
+	"list":         {"where /list goes: copies (a new message each time) or pinned (one pinned message kept up to date)", []string{"copies", "pinned"}, nil},
+	"reminder_dms": {"on or off: also DM movie night reminders to those who answered Going or Maybe", []string{"on", "off"}, nil},
+	"reminders":    {"how long before a movie night the chat is reminded, e.g. 24h,1h, or off", nil, checkLeads},
+	"timezone":     {"time zone for dates and /schedule, e.g. Europe/Rome", nil, checkTimezone},
+}
+
+func checkOneOf(values ...string) func(string) error {
+	return func(v string) error {
+		if !slices.Contains(values, v) {
+			return fmt.Errorf("%q must be one of %s", v, strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
+
+func checkTimezone(v string) error {
+	if _, err := time.LoadLocation(v); err != nil {
+		return fmt.Errorf("%q is not a time zone like Europe/Berlin or America/New_York", v)
+	}
+	return nil
+}
+
+func checkRange(lo, hi int) func(string) error {
+	return func(v string) error {
+		if n, err := strconv.Atoi(v); err != nil || n < lo || n > hi {
+			return fmt.Errorf("%q is not a number from %d to %d", v, lo, hi)
+		}
+		return nil
+	}
+}
+
+func checkAutoDelete(v string) error {
+	if d, err := time.ParseDuration(v); err != nil || d < time.Minute {
+		return fmt.Errorf("%q is not a duration of a minute or more, like 5m or 1h", v)
+	}
+	return nil
+}
+
+func checkDuration(v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return fmt.Errorf("%q is not a duration like 36h or 90m", v)
+	}
+	return nil
+}
+
+// settingsExport is the file /settings export sends and /settings import
+// reads back.
+type settingsExport struct {
+	Chat     string            `json:"chat,omitempty"`
+	Exported time.Time         `json:"exported"`
+	Settings map[string]string `json:"settings"`
+}
+
+// chatLanguage returns the chat's language setting or the bot's default.
+func (b *Bot) chatLanguage(chatID int64) string {
+	if lang := b.Store.ChatSetting(chatID, "language"); lang != "" {
+		return lang
+	}
+	return b.Language
+}
+
+// userLanguage is the language to address u in: their Telegram app's when
+// there are messages in it, the chat's otherwise. Toasts, prompts and
+// replies use it; what the whole chat sees goes by chatLanguage.
+func (b *Bot) userLanguage(chatID int64, u *tgbotapi.User) string {
+	if u != nil {
+		if lang := i18n.Match(u.LanguageCode); lang != "" {
+			return lang
+		}
+	}
+	return b.chatLanguage(chatID)
+}
+
+// chatLocation returns the chat's time zone, the server's when unset.
+func (b *Bot) chatLocation(chatID int64) *time.Location {
+	if name := b.Store.ChatSetting(chatID, "timezone"); name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// timeStyle is how chatID wants times in lists.
+func (b *Bot) timeStyle(chatID int64) storage.TimeStyle {
+	return storage.TimeStyle{
+		Locale:   b.chatLanguage(chatID),
+		Location: b.chatLocation(chatID),
+		Exact:    b.Store.ChatSetting(chatID, "dates") == "exact",
+	}
+}
+
+// nightCooldown returns the chat's cooldown between movie nights.
+func (b *Bot) nightCooldown(chatID int64) time.Duration {
+	if d, err := time.ParseDuration(b.Store.ChatSetting(chatID, "cooldown")); err == nil {
+		return d
+	}
+	return b.NightCooldown
+}
+
+// maxAlternatives is how many search results chatID is offered.
+func (b *Bot) maxAlternatives(chatID int64) int {
+	if n, err := strconv.Atoi(b.Store.ChatSetting(chatID, "alternatives")); err == nil {
+		return n
+	}
+	return b.MaxAlt
+}
+
+// autoDelete is how long chatID keeps search results and prompts, fallback
+// unless the chat set its own.
+func (b *Bot) autoDelete(chatID int64, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(b.Store.ChatSetting(chatID, "autodelete")); err == nil {
+		return d
+	}
+	return fallback
+}
+
+// =====================================================
+// /settings — per-chat configuration, admins only
+// =====================================================
+
+func (b *Bot) handleSettings(ctx context.Context, msg *tgbotapi.Message) {
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, b.settingsText(msg.Chat.ID))
+		reply.ReplyToMessageID = msg.MessageID
+		reply.ReplyMarkup = b.settingsMenu(msg.Chat.ID)
+		b.send(ctx, reply)
+		return
+	}
+
+	if !b.isAdmin(ctx, msg.Chat.ID, msg.From.ID) {
+		trace.Logf(ctx, "[BOT] /settings %s denied for %s", args[0], msg.From.UserName)
+		b.replyText(ctx, msg, "⛔ Only chat admins can do that.")
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "set":
+		if len(args) < 3 {
+			b.replyText(ctx, msg, "Usage: /settings set <key> <value>")
+			return
+		}
+		key, value := strings.ToLower(args[1]), strings.Join(args[2:], " ")
+		spec, ok := chatSettings[key]
+		if !ok {
+			b.replyText(ctx, msg, fmt.Sprintf("❌ Unknown setting %q.", key))
+			return
+		}
+		if err := spec.validate(value); err != nil {
+			b.replyText(ctx, msg, "❌ "+err.Error())
+			return
+		}
+		b.Store.SetChatSetting(ctx, msg.Chat.ID, key, value)
+		b.replyText(ctx, msg, fmt.Sprintf("✅ %s = %s", key, value))
+
+	case "unset":
+		if len(args) != 2 {
+			b.replyText(ctx, msg, "Usage: /settings unset <key>")
+			return
+		}
+		b.Store.SetChatSetting(ctx, msg.Chat.ID, strings.ToLower(args[1]), "")
+		b.replyText(ctx, msg, fmt.Sprintf("✅ %s is back to the default.", strings.ToLower(args[1])))
+
+	case "export":
+		b.exportSettings(ctx, msg)
+
+	case "import":
+		b.importSettings(ctx, msg)
+
+	default:
+		b.replyText(ctx, msg, "Usage: /settings [set <key> <value> | unset <key> | export | import]")
+	}
+}
+
+// settingsText lists every setting with the chat's value or the default.
+func (b *Bot) settingsText(chatID int64) string {
+	chat, _ := b.Store.GetChat(chatID)
+
+	var sb strings.Builder
+	sb.WriteString("⚙️ Chat settings\n\n")
+	for _, key := range slices.Sorted(maps.Keys(chatSettings)) {
+		value, ok := chat.Settings[key]
+		if !ok {
+			value = "default"
+		}
+		fmt.Fprintf(&sb, "• %s = %s\n  %s\n", key, value, chatSettings[key].help)
+	}
+	sb.WriteString("\nAdmins: tap a setting below, or /settings set <key> <value>, /settings unset <key>, /settings export, /settings import")
+	return sb.String()
+}
+
+// =====================================================
+// /settings menu
+// =====================================================
+
+// settingsMenu has a button per setting with choices, "settings|key|<key>".
+func (b *Bot) settingsMenu(chatID int64) tgbotapi.InlineKeyboardMarkup {
+	chat, _ := b.Store.GetChat(chatID)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, key := range slices.Sorted(maps.Keys(chatSettings)) {
+		if len(chatSettings[key].choices) == 0 {
+			continue
+		}
+		value, ok := chat.Settings[key]
+		if !ok {
+			value = "default"
+		}
+		button := tgbotapi.NewInlineKeyboardButtonData(key+": "+value, "settings|key|"+key)
+		if n := len(rows); n > 0 && len(rows[n-1]) < 2 {
+			rows[n-1] = append(rows[n-1], button)
+		} else {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(button))
+		}
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// settingMenu offers a setting's choices, the chat's one ticked, as
+// "settings|set|<key>|<value>".
+func (b *Bot) settingMenu(chatID int64, key string) (string, tgbotapi.InlineKeyboardMarkup) {
+	current := b.Store.ChatSetting(chatID, key)
+	value := current
+	if value == "" {
+		value = "default"
+	}
+	text := fmt.Sprintf("⚙️ %s = %s\n\n%s", key, value, chatSettings[key].help)
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, v := range chatSettings[key].choices {
+		label := v
+		if v == current {
+			label = "✅ " + v
+		}
+		button := tgbotapi.NewInlineKeyboardButtonData(label, "settings|set|"+key+"|"+v)
+		if i%3 == 0 {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(button))
+		} else {
+			rows[len(rows)-1] = append(rows[len(rows)-1], button)
+		}
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("↩️ Default", "settings|unset|"+key),
+		tgbotapi.NewInlineKeyboardButtonData("⬅️ Back", "settings|menu"),
+	))
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// handleSettingsCallback runs the /settings menu: "settings|menu",
+// "settings|key|<key>", "settings|set|<key>|<value>" and
+// "settings|unset|<key>". Like /settings set, it is for admins only.
+func (b *Bot) handleSettingsCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if cb.Message == nil {
+		return
+	}
+	chatID := cb.Message.Chat.ID
+	if !b.isAdmin(ctx, chatID, cb.From.ID) {
+		b.answerToast(ctx, cb, "⛔ Only chat admins can do that.")
+		return
+	}
+
+	parts := strings.Split(cb.Data, "|")
+	var key string
+	if len(parts) > 2 {
+		key = parts[2]
+	}
+	spec, known := chatSettings[key]
+
+	text, keyboard := b.settingsText(chatID), b.settingsMenu(chatID)
+	switch action := parts[1]; {
+	case action == "menu":
+	case !known:
+		b.answerToast(ctx, cb, fmt.Sprintf("❌ Unknown setting %q.", key))
+		return
+	case action == "key":
+		text, keyboard = b.settingMenu(chatID, key)
+	case action == "set" && len(parts) == 4:
+		if err := spec.validate(parts[3]); err != nil {
+			b.answerToast(ctx, cb, "❌ "+err.Error())
+			return
+		}
+		b.Store.SetChatSetting(ctx, chatID, key, parts[3])
+		trace.Logf(ctx, "[BOT] %s set %s = %s in chat %d", cb.From.UserName, key, parts[3], chatID)
+		b.answerToast(ctx, cb, fmt.Sprintf("✅ %s = %s", key, parts[3]))
+		text, keyboard = b.settingsText(chatID), b.settingsMenu(chatID)
+	case action == "unset":
+		b.Store.SetChatSetting(ctx, chatID, key, "")
+		trace.Logf(ctx, "[BOT] %s reset %s in chat %d", cb.From.UserName, key, chatID)
+		b.answerToast(ctx, cb, fmt.Sprintf("✅ %s is back to the default.", key))
+		text, keyboard = b.settingsText(chatID), b.settingsMenu(chatID)
+	default:
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, cb.Message.MessageID, text)
+	edit.ReplyMarkup = &keyboard
+	if _, err := b.send(ctx, edit); err != nil && !notModified(err) {
+		trace.Logf(ctx, "[BOT] Updating the settings menu failed: %v", err)
+	}
+}
+
+func (b *Bot) exportSettings(ctx context.Context, msg *tgbotapi.Message) {
+	chat, _ := b.Store.GetChat(msg.Chat.ID)
+	export := settingsExport{
+		Chat:     chatTitle(msg.Chat),
+		Exported: time.Now().UTC(),
+		Settings: chat.Settings,
+	}
+	if export.Settings == nil {
+		export.Settings = map[string]string{}
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		trace.Logf(ctx, "[BOT] Settings export failed: %v", err)
+		b.replyText(ctx, msg, "❌ Export failed.")
+		return
+	}
+	trace.Logf(ctx, "[BOT] Exported %d settings of chat %d for %s", len(export.Settings), msg.Chat.ID, msg.From.UserName)
+
+	doc := tgbotapi.NewDocument(msg.Chat.ID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("settings-%s.json", time.Now().Format("2006-01-02")),
+		Bytes: data,
+	})
+	doc.Caption = "Reply to this file with /settings import in another chat to copy the setup."
+	doc.ReplyToMessageID = msg.MessageID
+	b.send(ctx, doc)
+}
+
+// importSettings replaces the chat's settings with an export, taken from the
+// command itself or from the file or message it replies to. Unknown keys are
+// skipped so exports from newer versions still load; invalid values abort.
+func (b *Bot) importSettings(ctx context.Context, msg *tgbotapi.Message) {
+	args := strings.TrimSpace(msg.CommandArguments())
+	data := []byte(strings.TrimSpace(args[len("import"):]))
+
+	if len(data) == 0 && msg.ReplyToMessage != nil {
+		switch reply := msg.ReplyToMessage; {
+		case reply.Document != nil:
+			var err error
+			if data, err = b.downloadDocument(ctx, reply.Document); err != nil {
+				trace.Logf(ctx, "[BOT] Settings import download failed: %v", err)
+				b.replyText(ctx, msg, "❌ Could not download the file.")
+				return
+			}
+		default:
+			data = []byte(reply.Text)
+		}
+	}
+	if len(data) == 0 {
+		b.replyText(ctx, msg, "Reply to a settings export with /settings import, or paste the JSON after the command.")
+		return
+	}
+
+	var export settingsExport
+	if err := json.Unmarshal(data, &export); err != nil || export.Settings == nil {
+		b.replyText(ctx, msg, "❌ That is not a settings export.")
+		return
+	}
+
+	settings := make(map[string]string, len(export.Settings))
+	var skipped []string
+	for key, value := range export.Settings {
+		spec, ok := chatSettings[key]
+		if !ok {
+			skipped = append(skipped, key)
+			continue
+		}
+		if err := spec.validate(value); err != nil {
+			b.replyText(ctx, msg, fmt.Sprintf("❌ %s: %v. Nothing was imported.", key, err))
+			return
+		}
+		settings[key] = value
+	}
+
+	b.Store.ReplaceChatSettings(ctx, msg.Chat.ID, settings)
+	trace.Logf(ctx, "[BOT] Imported %d settings into chat %d from %q by %s", len(settings), msg.Chat.ID, export.Chat, msg.From.UserName)
+
+	text := fmt.Sprintf("✅ Imported %d settings", len(settings))
+	if export.Chat != "" {
+		text += " from " + export.Chat
+	}
+	text += "."
+	if len(skipped) > 0 {
+		slices.Sort(skipped)
+		text += "\nSkipped unknown: " + strings.Join(skipped, ", ")
+	}
+	b.replyText(ctx, msg, text)
+}
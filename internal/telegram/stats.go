@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// 📊 /stats [@user]
+// =====================================================
+
+// handleStats shows what someone nominated, voted for and watched in the
+// chat's library: the sender, the @user given, or the author of the message
+// replied to.
+func (b *Bot) handleStats(ctx context.Context, msg *tgbotapi.Message) {
+	userID := strconv.FormatInt(msg.From.ID, 10)
+	switch arg := strings.TrimSpace(msg.CommandArguments()); {
+	case arg != "":
+		u, ok := b.Store.UserByUsername(arg)
+		if !ok {
+			b.replyText(ctx, msg, fmt.Sprintf("❌ I don't know %s yet.\nUsage: /stats [@user]", arg))
+			return
+		}
+		userID = u.ID
+	case msg.ReplyToMessage != nil && msg.ReplyToMessage.From != nil && !msg.ReplyToMessage.From.IsBot:
+		userID = strconv.FormatInt(msg.ReplyToMessage.From.ID, 10)
+	}
+	trace.Logf(ctx, "[BOT] /stats of %s from %s", userID, msg.From.UserName)
+
+	library := b.library(msg.Chat.ID)
+	st := b.Store.UserStats(library, userID)
+	b.replyText(ctx, msg, statsText(b.userLabel(userID), st, b.Store.UserAchievements(library, userID)))
+}
+
+// statsText renders one user's stats and the badges they unlocked.
+func statsText(name string, st storage.UserStats, badges []storage.Achievement) string {
+	if st.Nominated+st.Voted+st.Watched == 0 {
+		return fmt.Sprintf("📊 %s hasn't nominated, voted for or watched anything here yet.", name)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📊 %s\n", name)
+	fmt.Fprintf(&sb, "\n🎬 Nominated: %d", st.Nominated)
+	if st.Nominated > 0 {
+		fmt.Fprintf(&sb, "\n🏆 Picks the group watched: %d (%.0f%%)", st.Won, st.WinRate()*100)
+	}
+	fmt.Fprintf(&sb, "\n👍 Voted for: %d", st.Voted)
+	fmt.Fprintf(&sb, "\n👁 Watched: %d", st.Watched)
+	if len(badges) > 0 {
+		fmt.Fprintf(&sb, "\n\n🏅 Badges (%d/%d):", len(badges), len(storage.Achievements))
+		for _, a := range badges {
+			fmt.Fprintf(&sb, "\n%s %s", a.Badge, a.Name)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,83 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// 🏷 /tag — genre tags for /list genre=...
+// =====================================================
+
+// handleTag is /tag <movie> [+tag -tag ...]. Without changes it shows the
+// movie's tags. The movie is a title or a row number of the last /list.
+func (b *Bot) handleTag(ctx context.Context, msg *tgbotapi.Message) {
+	const usage = "Usage: /tag <movie> [+tag -tag ...]"
+
+	var ref, add, remove []string
+	for _, f := range strings.Fields(msg.CommandArguments()) {
+		switch {
+		case len(f) > 1 && f[0] == '+':
+			add = append(add, f[1:])
+		case len(f) > 1 && f[0] == '-':
+			remove = append(remove, f[1:])
+		default:
+			ref = append(ref, f)
+		}
+	}
+	if len(ref) == 0 {
+		b.replyText(ctx, msg, usage)
+		return
+	}
+
+	movie, err := b.taggedMovie(msg.Chat.ID, strings.Join(ref, " "))
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error()+"\n"+usage)
+		return
+	}
+
+	if len(add)+len(remove) > 0 {
+		movie, err = b.Store.TagMovie(ctx, movie.ID, add, remove)
+		if err != nil {
+			b.replyText(ctx, msg, "❌ "+err.Error())
+			return
+		}
+		trace.Logf(ctx, "[BOT] /tag %s +%v -%v by %s", movie.Title, add, remove, msg.From.UserName)
+		b.publish(ctx, events.MovieUpdated, msg.From, movie, true)
+	}
+
+	tags := storage.MovieTags(movie)
+	if len(tags) == 0 {
+		b.replyText(ctx, msg, fmt.Sprintf("🏷 %s has no tags.", movie.Title))
+		return
+	}
+	b.replyText(ctx, msg, fmt.Sprintf("🏷 %s: %s", movie.Title, strings.Join(tags, ", ")))
+}
+
+// taggedMovie resolves /tag's movie: a row number of the last /list, or a
+// title in the chat's library ("1917" is tried as both).
+func (b *Bot) taggedMovie(chatID int64, ref string) (storage.Movie, error) {
+	library := b.Store.GetLibrary(b.library(chatID))
+	if !isRowNumber(ref) {
+		return storage.FindMovie(library, ref)
+	}
+	movie, err := b.listedMovie(chatID, ref)
+	if err != nil {
+		if m, ferr := storage.FindMovie(library, ref); ferr == nil {
+			return m, nil
+		}
+	}
+	return movie, err
+}
+
+// isRowNumber reports whether ref is "7" or "#7".
+func isRowNumber(ref string) bool {
+	ref = strings.TrimPrefix(ref, "#")
+	return ref != "" && strings.Trim(ref, "0123456789") == ""
+}
@@ -1,26 +1,149 @@
 package telegram
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"hash/fnv"
+	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/alerts"
+	"moviebot/internal/events"
+	"moviebot/internal/i18n"
+	"moviebot/internal/maintenance"
 	"moviebot/internal/omdb"
+	"moviebot/internal/posters"
+	"moviebot/internal/refresh"
+	"moviebot/internal/search"
 	"moviebot/internal/storage"
+	"moviebot/internal/streaming"
+	"moviebot/internal/tmdb"
+	"moviebot/internal/trace"
+	"moviebot/internal/trailers"
+	"moviebot/internal/transcribe"
+	"moviebot/internal/watchparty"
 )
 
 type Bot struct {
-	API    *tgbotapi.BotAPI
-	OMDb   *omdb.OMDbClient
+	API    API
+	OMDb   omdb.API
 	Store  *storage.Store
 	MaxAlt int
 
-	sessMu   sync.Mutex
-	sessions map[string]*userSession // sessionID -> session
+	// Events receives notable changes for integrations; may be nil.
+	Events *events.Bus
+
+	// OwnerIDs are the Telegram user IDs allowed to run operator commands.
+	OwnerIDs []int64
+
+	// AdminIDs are users who count as admins in every chat, see isAdmin.
+	AdminIDs []int64
+
+	// PublicBaseURL is where the web server is reachable; /publiclist is
+	// disabled while it is empty.
+	PublicBaseURL string
+
+	// NotifySuggesters DMs whoever added a movie when it gets its first
+	// vote, reaches Quorum votes and is scheduled.
+	NotifySuggesters bool
+	Quorum           int
+
+	// Language is the fallback language of chats without a "language"
+	// setting.
+	Language string
+
+	// SlowHandler is how long handling one update may take before it is
+	// logged as slow; 0 disables the warning.
+	SlowHandler time.Duration
+
+	// NightCooldown is the default minimum gap between two movie nights in a
+	// chat (see the "cooldown" chat setting); a new one also waits until the
+	// pending one was watched.
+	NightCooldown time.Duration
+
+	// NightReminders are how long before a movie night chats are reminded
+	// of it, and ReminderDMs whether those who answered Going or Maybe get
+	// a DM too; chats can override both in /settings.
+	NightReminders []time.Duration
+	ReminderDMs    bool
+
+	// ListPageSize is how many movies a /list page shows before Prev/Next
+	// buttons appear; 0 sends the whole list at once.
+	ListPageSize int
+
+	// ListSyncIdle stops re-rendering list messages in chats without
+	// activity for this long; their next sync after someone speaks up
+	// catches them up. 0 syncs every chat.
+	ListSyncIdle time.Duration
+
+	// SessionTTL is how long a /movie prompt waits for a title before it
+	// is withdrawn; 0 means 5 minutes.
+	SessionTTL time.Duration
+
+	// TMDB adds popularity to the ranking of search results; may be nil.
+	TMDB *tmdb.Client
+
+	// WatchParty adds watch-together links to movie nights; may be nil.
+	WatchParty *watchparty.Generator
+
+	// Transcriber turns voice replies into search queries; may be nil.
+	Transcriber *transcribe.Client
+
+	// Trailers finds the links of /trailer and the cards' Trailer button;
+	// both are off while nil.
+	Trailers *trailers.Client
+
+	// Streaming finds the services of /where and the cards' Where button;
+	// both are off while nil.
+	Streaming *streaming.Client
+
+	// Posters supplies the images for /quiz; the quiz is off while nil.
+	Posters *posters.Cache
+
+	// Disabled are the features switched off in the config, by their names
+	// there ("games"); see commandFeatures.
+	Disabled map[string]bool
+
+	// Discussions opens a thread per movie once it is watched, as a forum
+	// topic when DiscussionTopics is set and the chat allows it.
+	Discussions      bool
+	DiscussionTopics bool
+
+	// FullSyncs re-push everything to the integrations (sheets, notion);
+	// run from the admin panel.
+	FullSyncs []func()
+
+	// Maintenance is shared with the background jobs; owners flip it with
+	// /maintenance or from /admin.
+	Maintenance *maintenance.Mode
+
+	// Alerts DMs the owners about failures that keep happening; requests
+	// Telegram rejects are reported to it. May be nil.
+	Alerts *alerts.Alerter
+
+	// BuildTime and Commit identify the running build for /version; main
+	// sets them from its ldflags.
+	BuildTime string
+	Commit    string
+
+	started    time.Time
+	sessMu     sync.Mutex
+	outage     outage
+	debounce   debouncer
+	countdowns countdowns[int64]
+	releases   countdowns[string]
+	admins     adminCache
+	metrics    handlerMetrics
+	views      listViews
+	quizzes    quizzes
+	searches   searchGuard
+	sessions   map[string]*userSession // sessionID -> session
 }
 
 type userSession struct {
@@ -31,22 +154,36 @@ type userSession struct {
 	Results       []omdb.SearchResult
 	OrigMessageID int
 	ActiveMsgIDs  []int
-	
+	List          string // named list the pick goes to, "" for the main one
+	Lang          string // language of the prompts, see userLanguage
+
 	WaitingForQuery bool
 	PromptMessageID int
+	PromptedAt      time.Time // the prompt is withdrawn SessionTTL later
+
+	// Queue holds further searches (from a bulk add) to resolve one after
+	// another once this one is done.
+	Queue []queuedSearch
 }
 
 // =====================================================
 // INIT
 // =====================================================
 
-func NewBot(api *tgbotapi.BotAPI, omdb *omdb.OMDbClient, store *storage.Store, maxAlt int) *Bot {
+func NewBot(api API, omdb omdb.API, store *storage.Store, maxAlt int) *Bot {
 	return &Bot{
-		API:      api,
-		OMDb:     omdb,
-		Store:    store,
-		MaxAlt:   maxAlt,
-		sessions: make(map[string]*userSession),
+		API:           api,
+		OMDb:          omdb,
+		Store:         store,
+		MaxAlt:        maxAlt,
+		NightCooldown: 24 * time.Hour,
+		ListPageSize:  25,
+		ListSyncIdle:  7 * 24 * time.Hour,
+		SlowHandler:   2 * time.Second,
+		Language:      "en",
+		Maintenance:   maintenance.New(false, ""),
+		sessions:      make(map[string]*userSession),
+		started:       time.Now(),
 	}
 }
 
@@ -54,21 +191,60 @@ func NewBot(api *tgbotapi.BotAPI, omdb *omdb.OMDbClient, store *storage.Store, m
 // UPDATE HANDLER
 // =====================================================
 
-func (b *Bot) HandleUpdate(update tgbotapi.Update) {
+func (b *Bot) HandleUpdate(update Update) {
+	ctx := trace.NewContext(context.Background())
+	trace.Logf(ctx, "[BOT] Handling update %d", update.UpdateID)
+
+	start := time.Now()
+	defer func() { b.observe(ctx, handlerName(update), time.Since(start)) }()
+
+	if b.holdForMaintenance(ctx, update) {
+		return
+	}
+
 	if update.CallbackQuery != nil {
-		b.handleCallback(update.CallbackQuery)
+		b.seeUser(ctx, update.CallbackQuery.From)
+		b.answerCallback(ctx, update.CallbackQuery)
+	}
+	if update.InlineQuery != nil && !b.Disabled[featureInline] {
+		b.handleInlineQuery(ctx, update.InlineQuery)
+	}
+	if update.ChosenInlineResult != nil && !b.Disabled[featureInline] {
+		b.seeUser(ctx, update.ChosenInlineResult.From)
+		b.handleChosenInlineResult(ctx, update.ChosenInlineResult)
+	}
+	if update.PollAnswer != nil {
+		b.seeUser(ctx, &update.PollAnswer.User)
+		b.handlePollAnswer(ctx, update.PollAnswer)
+	}
+	if update.Poll != nil {
+		b.handlePollUpdate(ctx, update.Poll)
+	}
+	if update.Message != nil {
+		b.seeUser(ctx, update.Message.From)
+		b.seeChat(ctx, update.Message.Chat)
+	}
+	if update.MessageReaction != nil {
+		b.seeUser(ctx, update.MessageReaction.User)
+		b.handleMessageReaction(ctx, update.MessageReaction)
+	}
+	if update.MyChatMember != nil {
+		b.handleMyChatMember(ctx, update.MyChatMember)
 	}
 	if update.Message != nil && update.Message.IsCommand() {
-		b.handleCommand(update.Message)
+		b.handleCommand(ctx, update.Message)
 	}
 	if update.Message != nil && !update.Message.IsCommand() {
-		b.handleText(update.Message)
+		b.handleText(ctx, update.Message)
 	}
 }
 
-func (b *Bot) handleText(msg *tgbotapi.Message) {
-	// MUST match how you created it
-	sessionID := fmt.Sprintf("wait:%d:%d", msg.Chat.ID, msg.From.ID)
+func (b *Bot) handleText(ctx context.Context, msg *tgbotapi.Message) {
+	if b.collectRating(ctx, msg) || b.collectQuote(ctx, msg) || b.collectGuess(ctx, msg) || b.offerImdbLinks(ctx, msg) {
+		return
+	}
+
+	sessionID := waitSessionID(msg.Chat.ID, msg.From.ID)
 
 	b.sessMu.Lock()
 	sess, ok := b.sessions[sessionID]
@@ -78,25 +254,39 @@ func (b *Bot) handleText(msg *tgbotapi.Message) {
 		return
 	}
 
-	// 🔥 Ensure this is a reply to our forced prompt
+	// 🔥 Ensure this is a reply to our prompt
 	if msg.ReplyToMessage == nil ||
 		msg.ReplyToMessage.MessageID != sess.PromptMessageID {
 		return
 	}
 
 	query := strings.TrimSpace(msg.Text)
+	if query == "" && msg.Voice != nil {
+		query = b.transcribeVoice(ctx, msg)
+	}
 	if query == "" {
 		return
 	}
 
 	// Remove waiting session
-	b.cleanupSession(sessionID)
+	b.cleanupSession(ctx, sessionID)
 
-	log.Printf("[OMDb] Searching for '%s' requested by %s", query, msg.From.UserName)
+	if b.duplicateSearch(ctx, msg, query) {
+		return
+	}
+	trace.Logf(ctx, "[OMDb] Searching for '%s' requested by %s", query, msg.From.UserName)
 
-	results, err := b.OMDb.Search(query)
-	if err != nil || len(results) == 0 {
-		b.API.Send(tgbotapi.NewMessage(msg.Chat.ID, "No results found"))
+	lang := b.userLanguage(msg.Chat.ID, msg.From)
+	results, err := search.Query(ctx, b.OMDb, b.TMDB, query)
+	if len(results) == 0 {
+		b.searches.release(searchKey(msg.Chat.ID, query))
+	}
+	if len(results) == 0 && searchFailed(err) {
+		b.replyText(ctx, msg, "⚠️ "+i18n.T(lang, searchDownText))
+		return
+	}
+	if len(results) == 0 {
+		b.send(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(lang, "No results found")))
 		return
 	}
 
@@ -110,104 +300,170 @@ func (b *Bot) handleText(msg *tgbotapi.Message) {
 		Query:         query,
 		Results:       results,
 		OrigMessageID: msg.MessageID, // reply to user’s answer
+		List:          sess.List,
+		Lang:          lang,
 	}
 
 	b.sessMu.Lock()
 	b.sessions[newSessionID] = newSess
 	b.sessMu.Unlock()
+	b.searches.update(searchKey(msg.Chat.ID, query), func(r *recentSearch) { r.sessionID = newSessionID })
 
-	b.sendMovieSelection(newSess, 0)
+	b.sendMovieSelection(ctx, newSess, 0)
 }
+
 // =====================================================
 // COMMANDS
 // =====================================================
 
-func (b *Bot) handleCommand(msg *tgbotapi.Message) {
-	switch msg.Command() {
+// handleCommand runs a command from the registry in commands.go.
+func (b *Bot) handleCommand(ctx context.Context, msg *tgbotapi.Message) {
+	cmd, ok := commandByName(msg.Command())
+	if !ok {
+		return
+	}
+	if b.Disabled[commandFeatures[cmd.name]] {
+		trace.Logf(ctx, "[BOT] /%s from %s, but %s is off", cmd.name, msg.From.UserName, commandFeatures[cmd.name])
+		b.replyText(ctx, msg, "🚫 /"+cmd.name+" is switched off on this bot.")
+		return
+	}
+	cmd.run(b, ctx, msg)
+}
 
-	case "start":
-		log.Printf("[BOT] /start from %s", msg.From.UserName)
-		b.sendKeyboard(msg.Chat.ID)
+// handleMovie is /movie [list] [title]: search right away, or prompt for a
+// title; "/movie bulk" adds a whole list of titles.
+func (b *Bot) handleMovie(ctx context.Context, msg *tgbotapi.Message) {
+	firstLine, _, _ := strings.Cut(msg.CommandArguments(), "\n")
+	if list, query := parseMovieArgs(firstLine); query == "bulk" {
+		b.handleBulkAdd(ctx, msg, list)
+		return
+	}
 
-	case "movie":
-		query := strings.TrimSpace(msg.CommandArguments())
+	args, until, hasUntil, err := cutUntil(msg.CommandArguments(), time.Now().In(b.chatLocation(msg.Chat.ID)))
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error()+"\nUsage: /movie --until <today|tomorrow|fri|2006-01-02> [20:00] [title]")
+		return
+	}
+	if hasUntil && !b.attachDeadline(ctx, msg, until) {
+		return
+	}
 
-if query == "" {
-	// Create chat-scoped waiting session (safer for groups)
-	sessionID := fmt.Sprintf("wait:%d:%d", msg.Chat.ID, msg.From.ID)
+	list, query := parseMovieArgs(args)
+	if rest, ok := strings.CutPrefix(query, "add-manual"); ok {
+		b.handleManualAdd(ctx, msg, list, rest)
+		return
+	}
 
-	waitSess := &userSession{
-		ID:              sessionID,
-		UserID:          msg.From.ID,
-		ChatID:          msg.Chat.ID,
-		WaitingForQuery: true,
+	if query == "" {
+		// Create chat-scoped waiting session (safer for groups), replacing
+		// an earlier prompt
+		sessionID := waitSessionID(msg.Chat.ID, msg.From.ID)
+		b.withdrawPrompt(ctx, sessionID)
+
+		waitSess := &userSession{
+			ID:              sessionID,
+			UserID:          msg.From.ID,
+			ChatID:          msg.Chat.ID,
+			List:            list,
+			WaitingForQuery: true,
+		}
 
-	}
+		// Send the prompt with its cancel button; a message carries one
+		// markup, so it's not a forced reply and the title has to be a
+		// reply to it (checked in handleText)
+		lang := b.userLanguage(msg.Chat.ID, msg.From)
+		prompt := tgbotapi.NewMessage(
+			msg.Chat.ID,
+			"🎬 "+i18n.T(lang, "What movie would you like to search for?")+"\n"+
+				i18n.T(lang, "Reply to this message with the title."),
+		)
+
+		prompt.ReplyToMessageID = msg.MessageID
+		prompt.ReplyMarkup = cancelKeyboard(lang, msg.From.ID)
+
+		sent, err := b.send(ctx, prompt)
+		if err != nil {
+			return
+		}
 
-	// Send forced reply prompt
-	prompt := tgbotapi.NewMessage(
-		msg.Chat.ID,
-		"🎬 What movie would you like to search for?",
-	)
+		// Store prompt message ID so we can validate the reply
+		waitSess.PromptMessageID = sent.MessageID
+		waitSess.PromptedAt = time.Now()
 
-	prompt.ReplyToMessageID = msg.MessageID
+		b.sessMu.Lock()
+		b.sessions[sessionID] = waitSess
+		b.sessMu.Unlock()
 
-	prompt.ReplyMarkup = tgbotapi.ForceReply{
-		ForceReply: true,
-		Selective:  true, // only the command sender sees forced reply UI
+		return
 	}
 
-	sent, err := b.API.Send(prompt)
-	if err != nil {
+	if b.duplicateSearch(ctx, msg, query) {
+		return
+	}
+	trace.Logf(ctx, "[OMDb] Searching for '%s' requested by %s", query, msg.From.UserName)
+	lang := b.userLanguage(msg.Chat.ID, msg.From)
+	results, err := search.Query(ctx, b.OMDb, b.TMDB, query)
+	if len(results) == 0 {
+		b.searches.release(searchKey(msg.Chat.ID, query))
+	}
+	if len(results) == 0 && searchFailed(err) {
+		b.replyText(ctx, msg, "⚠️ "+i18n.T(lang, searchDownText))
 		return
 	}
+	if len(results) == 0 {
+		b.send(ctx, tgbotapi.NewMessage(msg.Chat.ID, i18n.T(lang, "No results found")))
+		return
+	}
+
+	sessionID := fmt.Sprintf("%d:%d", msg.From.ID, time.Now().UnixNano())
 
-	// Store prompt message ID so we can validate the reply
-	waitSess.PromptMessageID = sent.MessageID
+	sess := &userSession{
+		ID:            sessionID,
+		UserID:        msg.From.ID,
+		ChatID:        msg.Chat.ID,
+		Query:         query,
+		Results:       results,
+		OrigMessageID: msg.MessageID,
+		List:          list,
+		Lang:          lang,
+	}
 
 	b.sessMu.Lock()
-	b.sessions[sessionID] = waitSess
+	b.sessions[sessionID] = sess
 	b.sessMu.Unlock()
+	b.searches.update(searchKey(msg.Chat.ID, query), func(r *recentSearch) { r.sessionID = sessionID })
 
-	return
+	b.sendMovieSelection(ctx, sess, 0)
 }
 
-		log.Printf("[OMDb] Searching for '%s' requested by %s", query, msg.From.UserName)
-		results, err := b.OMDb.Search(query)
-		if err != nil || len(results) == 0 {
-			b.API.Send(tgbotapi.NewMessage(msg.Chat.ID, "No results found"))
-			return
-		}
-
-		sessionID := fmt.Sprintf("%d:%d", msg.From.ID, time.Now().UnixNano())
-
-		sess := &userSession{
-			ID:            sessionID,
-			UserID:        msg.From.ID,
-			ChatID:        msg.Chat.ID,
-			Query:         query,
-			Results:       results,
-			OrigMessageID: msg.MessageID,
-		}
+// handleList is /list [format | list name | collection=... | genre=...].
+func (b *Bot) handleList(ctx context.Context, msg *tgbotapi.Message) {
+	args, filter := parseListArgs(msg.CommandArguments())
 
-		b.sessMu.Lock()
-		b.sessions[sessionID] = sess
-		b.sessMu.Unlock()
-
-		b.sendMovieSelection(sess, 0)
-
-case "list":
-	args := strings.TrimSpace(msg.CommandArguments())
+	if !filter.IsZero() {
+		trace.Logf(ctx, "[BOT] Filtered /list %+v from %s", filter, msg.From.UserName)
+		b.sendFilteredList(ctx, msg.Chat.ID, msg.MessageID, filter)
+		return
+	}
 
 	if args != "" {
-		if format, ok := tableFormats[args]; ok {
-			// ✅ Valid format selected
-			currentTableFormat = format
-			log.Printf("[BOT] Table format set to %s", args)
+		if list := storage.NormalizeListName(args); b.Store.ListNames(b.library(msg.Chat.ID))[list] > 0 {
+			trace.Logf(ctx, "[BOT] /list %s from %s", list, msg.From.UserName)
+			b.sendList(ctx, msg.Chat.ID, msg.MessageID, list)
+			return
+		}
+		if _, ok := tableFormats[args]; ok {
+			if !b.isManager(ctx, msg.Chat.ID, msg.From.ID) {
+				b.replyText(ctx, msg, "⛔ "+i18n.T(b.userLanguage(msg.Chat.ID, msg.From), "Only chat admins can change the table format."))
+				return
+			}
+			// ✅ Valid format selected, for this chat
+			b.Store.SetChatSetting(ctx, msg.Chat.ID, "format", args)
+			trace.Logf(ctx, "[BOT] Table format of chat %d set to %s", msg.Chat.ID, args)
 
 		} else {
 			// ❌ Invalid format
-			log.Printf("[BOT] Invalid table format '%s' requested by %s", args, msg.From.UserName)
+			trace.Logf(ctx, "[BOT] Invalid table format '%s' requested by %s", args, msg.From.UserName)
 
 			// Build keyboard with available formats
 			var row []tgbotapi.KeyboardButton
@@ -221,24 +477,23 @@ case "list":
 
 			msgToSend := tgbotapi.NewMessage(
 				msg.Chat.ID,
-				"Unknown table format. Please choose one of the available formats:",
+				i18n.T(b.userLanguage(msg.Chat.ID, msg.From), "Unknown table format or list. Please choose one of the available formats (or see /lists):"),
 			)
 			msgToSend.ReplyMarkup = keyboard
-			b.API.Send(msgToSend)
+			b.send(ctx, msgToSend)
 			return
 		}
 	}
 
-	log.Printf("[BOT] /list from %s", msg.From.UserName)
-	b.sendList(msg.Chat.ID, msg.MessageID)
-	}
+	trace.Logf(ctx, "[BOT] /list from %s", msg.From.UserName)
+	b.sendList(ctx, msg.Chat.ID, msg.MessageID, "")
 }
 
 // =====================================================
 // CALLBACKS
 // =====================================================
 
-func (b *Bot) handleCallback(cb *tgbotapi.CallbackQuery) {
+func (b *Bot) handleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
 	if cb == nil || cb.From == nil {
 		return
 	}
@@ -247,7 +502,19 @@ func (b *Bot) handleCallback(cb *tgbotapi.CallbackQuery) {
 	userID := cb.From.ID
 	userIDStr := strconv.FormatInt(userID, 10)
 
-	log.Printf("[CALLBACK] '%s' from %s", data, cb.From.UserName)
+	trace.Logf(ctx, "[CALLBACK] '%s' from %s", data, cb.From.UserName)
+
+	var chatID int64
+	if cb.Message != nil {
+		chatID = cb.Message.Chat.ID
+	}
+	lang := b.userLanguage(chatID, cb.From)
+
+	if prefix, _, _ := strings.Cut(data, "|"); b.Disabled[callbackFeatures[prefix]] {
+		trace.Logf(ctx, "[CALLBACK] '%s' from %s, but %s is off", data, cb.From.UserName, callbackFeatures[prefix])
+		b.answerToast(ctx, cb, "🚫 "+i18n.T(lang, "This is switched off on this bot"))
+		return
+	}
 
 	// -------------------------
 	// GLOBAL CALLBACKS
@@ -255,22 +522,176 @@ func (b *Bot) handleCallback(cb *tgbotapi.CallbackQuery) {
 
 	if strings.HasPrefix(data, "vote|") {
 		id := strings.TrimPrefix(data, "vote|")
-		movie, err := b.Store.ToggleVoteByID(id, userIDStr)
+		if _, ok := b.chatMovie(chatID, id); !ok {
+			trace.Logf(ctx, "[CALLBACK] Ignoring vote on %s, not in chat %d's library", id, chatID)
+			b.answerToast(ctx, cb, "❌ "+i18n.T(lang, "Sorry, this message is too old"))
+			return
+		}
+		if cb.Message != nil && b.votingClosed(cb.Message.Chat.ID) {
+			b.answerToast(ctx, cb, "🔒 "+i18n.T(lang, "Voting is closed here"))
+			return
+		}
+		if movie, ok := b.Store.GetMovieByID(id); ok && movie.Upcoming && !movie.Votes[userIDStr] {
+			b.answerToast(ctx, cb, "📅 "+i18n.T(lang, "Voting opens once it's out"))
+			return
+		}
+		if b.debounce.tooSoon(userIDStr+"|"+data, time.Now()) {
+			trace.Logf(ctx, "[CALLBACK] Ignoring double tap on %s", data)
+			if movie, ok := b.Store.GetMovieByID(id); ok {
+				b.answerToast(ctx, cb, voteToast(lang, movie.Votes[userIDStr]))
+			}
+			return
+		}
+		movie, err := b.Store.ToggleVoteByID(ctx, id, userIDStr)
 		if err == nil {
-			b.syncMovie(movie)
+			b.answerToast(ctx, cb, voteToast(lang, movie.Votes[userIDStr]))
+			b.syncMovie(ctx, movie)
+			b.publish(ctx, events.VoteChanged, cb.From, movie, movie.Votes[userIDStr])
+			if cb.Message != nil {
+				b.awardBadges(ctx, cb.Message.Chat.ID, userIDStr)
+			}
 		}
 		return
 	}
 
+	if strings.HasPrefix(data, "settings|") {
+		b.handleSettingsCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "cancel|") {
+		b.handleCancelCallback(ctx, cb, lang)
+		return
+	}
+
+	if strings.HasPrefix(data, "react|") {
+		b.handleReactCallback(ctx, cb, lang)
+		return
+	}
+
 	if strings.HasPrefix(data, "watched|") {
 		id := strings.TrimPrefix(data, "watched|")
-		movie, err := b.Store.ToggleWatchedByID(id, userIDStr)
+		if _, ok := b.chatMovie(chatID, id); !ok {
+			trace.Logf(ctx, "[CALLBACK] Ignoring watched mark on %s, not in chat %d's library", id, chatID)
+			b.answerToast(ctx, cb, "❌ "+i18n.T(lang, "Sorry, this message is too old"))
+			return
+		}
+		if b.debounce.tooSoon(userIDStr+"|"+data, time.Now()) {
+			trace.Logf(ctx, "[CALLBACK] Ignoring double tap on %s", data)
+			if movie, ok := b.Store.GetMovieByID(id); ok {
+				b.answerToast(ctx, cb, watchedToast(lang, movie.Watched[userIDStr]))
+			}
+			return
+		}
+		movie, err := b.Store.ToggleWatchedByID(ctx, id, userIDStr)
 		if err == nil {
-			b.syncMovie(movie)
+			b.answerToast(ctx, cb, watchedToast(lang, movie.Watched[userIDStr]))
+			if cb.Message != nil {
+				movie = b.maybeOpenDiscussion(ctx, cb.Message.Chat.ID, cb.Message.MessageID, movie)
+				b.offerRating(ctx, cb.Message.Chat.ID, cb.From, movie)
+			}
+			b.syncMovie(ctx, movie)
+			b.publish(ctx, events.MovieWatched, cb.From, movie, movie.Watched[userIDStr])
+			if cb.Message != nil {
+				b.awardBadges(ctx, cb.Message.Chat.ID, userIDStr, movie.AddedBy)
+			}
+		}
+		return
+	}
+
+	if strings.HasPrefix(data, "rewatch|") {
+		id := strings.TrimPrefix(data, "rewatch|")
+		if _, ok := b.chatMovie(chatID, id); !ok {
+			trace.Logf(ctx, "[CALLBACK] Ignoring rewatch of %s, not in chat %d's library", id, chatID)
+			b.answerToast(ctx, cb, "❌ "+i18n.T(lang, "Sorry, this message is too old"))
+			return
+		}
+		if _, err := b.reopenForRewatch(ctx, cb.From, id); err != nil {
+			b.answerToast(ctx, cb, "❌ "+err.Error())
 		}
 		return
 	}
 
+	if strings.HasPrefix(data, "rate|") {
+		b.handleRateCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "imdb|") {
+		b.handleImdbCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "mine|") {
+		b.handleMineCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "quiz|") {
+		b.handleQuizCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "random|") {
+		b.handleRandomCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "remove|") {
+		b.handleRemoveCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "listpage|") {
+		b.handleListPageCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "inline|") {
+		b.handleInlineCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "rank|") {
+		b.handleRankCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "vround|") {
+		b.handleRoundCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "links|") {
+		b.handleLinksCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "trailer|") {
+		b.handleTrailerCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "where|") {
+		b.handleWhereCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "tonight|") {
+		b.handleTonightCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "rsvp|") {
+		b.handleRSVPCallback(ctx, cb)
+		return
+	}
+
+	if strings.HasPrefix(data, "admin|") {
+		b.handleAdminCallback(ctx, cb)
+		return
+	}
+
 	// -------------------------
 	// SESSION CALLBACKS
 	// format: action|sessionID|index
@@ -278,7 +699,7 @@ func (b *Bot) handleCallback(cb *tgbotapi.CallbackQuery) {
 
 	parts := strings.Split(data, "|")
 	if len(parts) != 3 {
-		log.Printf("[CALLBACK] Malformed data: %s", data)
+		trace.Logf(ctx, "[CALLBACK] Malformed data: %s", data)
 		return
 	}
 
@@ -293,23 +714,23 @@ func (b *Bot) handleCallback(cb *tgbotapi.CallbackQuery) {
 	b.sessMu.Unlock()
 
 	if !ok || sess == nil {
-		log.Printf("[CALLBACK] Session not found: %s", sessionID)
+		trace.Logf(ctx, "[CALLBACK] Session not found: %s", sessionID)
 
 		// Remove inline keyboard so old buttons are dead
 		if cb.Message != nil {
-			log.Printf("[CALLBACK] Removing buttons from stale message %d", cb.Message.MessageID)
-			b.removeInlineKeyboard(cb.Message.Chat.ID, cb.Message.MessageID)
+			trace.Logf(ctx, "[CALLBACK] Removing buttons from stale message %d", cb.Message.MessageID)
+			b.removeInlineKeyboard(ctx, cb.Message.Chat.ID, cb.Message.MessageID)
 		}
 
 		// Send a toast to the user
-		b.answerToast(cb, "⏱️ Sorry, this message is too old")
+		b.answerToast(ctx, cb, "⏱️ "+i18n.T(lang, "Sorry, this message is too old"))
 
 		return
 	}
 
 	if sess.UserID != userID {
-		log.Printf("[CALLBACK] User %d tried to access session %s", userID, sessionID)
-		b.answerToast(cb, "🚫 This movie selection isn’t for you")
+		trace.Logf(ctx, "[CALLBACK] User %d tried to access session %s", userID, sessionID)
+		b.answerToast(ctx, cb, "🚫 "+i18n.T(lang, "This movie selection isn’t for you"))
 		return
 	}
 
@@ -318,25 +739,115 @@ func (b *Bot) handleCallback(cb *tgbotapi.CallbackQuery) {
 	}
 
 	if cb.Message != nil {
-		b.API.Request(tgbotapi.NewDeleteMessage(cb.Message.Chat.ID, cb.Message.MessageID))
+		b.request(ctx, tgbotapi.NewDeleteMessage(cb.Message.Chat.ID, cb.Message.MessageID))
 	}
 
 	switch action {
 
 	case "select":
 		m := sess.Results[index]
-		year, _ := strconv.Atoi(m.Year)
-		log.Printf("[BOT] %s selected '%s' (%d)", cb.From.UserName, m.Title, year)
-
-		movieID := b.Store.NotifyNewMovie(m.Title, year, m.Poster)
-		if movieID != "" {
-			b.createOrUpdateVoteMessage(sess.ChatID, movieID)
+		trace.Logf(ctx, "[BOT] %s selected '%s' (%s)", cb.From.UserName, m.Title, m.Year)
+
+		if term, blocked := b.Store.BlockedTerm(sess.ChatID, m.Title); blocked {
+			trace.Logf(ctx, "[BOT] '%s' refused, blocked by %q", m.Title, term)
+			b.send(ctx, tgbotapi.NewMessage(sess.ChatID, refusal(m.Title)))
+		} else if movieID, _ := b.addSearchResult(ctx, sess.ChatID, cb.From, m, sess.List); movieID != "" {
+			b.createOrUpdateVoteMessage(ctx, sess.ChatID, movieID)
+			b.searches.update(searchKey(sess.ChatID, sess.Query), func(r *recentSearch) { r.movieID = movieID })
 		}
 
-		b.cleanupSession(sessionID)
+		b.cleanupSession(ctx, sessionID)
+		b.resumeQueue(ctx, sess)
 
 	case "alt":
-		b.sendMovieSelection(sess, index)
+		b.sendMovieSelection(ctx, sess, index)
+	}
+}
+
+// addSearchResult stores a picked search result on list in chatID's library
+// and announces it when it is new. It returns the movie ID, "" if it could
+// not be stored.
+func (b *Bot) addSearchResult(ctx context.Context, chatID int64, user *tgbotapi.User, r omdb.SearchResult, list string) (string, bool) {
+	year, _ := strconv.Atoi(r.Year)
+	movieID, created := b.Store.NotifyNewMovie(ctx, storage.Movie{
+		Title:   r.Title,
+		Year:    year,
+		Poster:  r.Poster,
+		ImdbID:  r.ImdbID,
+		List:    list,
+		ChatID:  b.library(chatID),
+		AddedBy: strconv.FormatInt(user.ID, 10),
+	})
+	if created {
+		if movie, ok := b.Store.GetMovieByID(movieID); ok {
+			b.publish(ctx, events.MovieAdded, user, movie, true)
+		}
+		b.awardBadges(ctx, chatID, strconv.FormatInt(user.ID, 10))
+		go b.enrichMovie(ctx, movieID)
+	}
+	return movieID, created
+}
+
+// enrichMovie fetches the full OMDb record of a just added movie (plot,
+// genre, director, ratings, ...) so features need no lookups of their own.
+// It runs in the background; the vote card is updated once it is in.
+func (b *Bot) enrichMovie(ctx context.Context, movieID string) {
+	movie, ok := b.Store.GetMovieByID(movieID)
+	if !ok {
+		return
+	}
+
+	var (
+		d   *omdb.Details
+		err error
+	)
+	if movie.ImdbID != "" {
+		d, err = b.OMDb.GetByID(ctx, movie.ImdbID)
+	} else {
+		d, err = b.OMDb.GetByTitle(ctx, movie.Title, movie.Year)
+	}
+	if err != nil || d == nil {
+		trace.Logf(ctx, "[OMDb] Enriching %s (%d) failed: %v", movie.Title, movie.Year, err)
+		return
+	}
+
+	movie, changed, err := b.Store.UpdateMetadata(ctx, movieID, refresh.MetadataFrom(d))
+	if err != nil || !changed {
+		return
+	}
+	trace.Logf(ctx, "[BOT] Enriched %s: %s, %s", movie.Title, movie.Genre, movie.Runtime)
+	b.syncMovie(ctx, movie)
+	b.publish(ctx, events.MovieUpdated, nil, movie, true)
+}
+
+// publish emits an event about movie on behalf of user.
+func (b *Bot) publish(ctx context.Context, typ string, user *tgbotapi.User, movie storage.Movie, active bool) {
+	e := events.Event{Type: typ, Source: "telegram", Movie: &movie, Active: active}
+	if user != nil {
+		e.UserID = user.ID
+		e.Username = user.UserName
+	}
+	b.Events.Publish(ctx, e)
+}
+
+// HandleEvent is an events.Bus subscriber that keeps Telegram vote cards and
+// lists current when another frontend changes a movie, and tells suggesters
+// about their movies' milestones.
+func (b *Bot) HandleEvent(e events.Event) {
+	if e.Movie == nil {
+		return
+	}
+	ctx := trace.WithID(context.Background(), e.TraceID)
+	b.notifySuggester(ctx, e)
+	if e.Source == "telegram" {
+		return
+	}
+	if b.telegramDown() {
+		b.deferSync(e.Movie.ID)
+		return
+	}
+	if movie, ok := b.Store.GetMovieByID(e.Movie.ID); ok {
+		b.syncMovie(ctx, movie)
 	}
 }
 
@@ -344,7 +855,7 @@ func (b *Bot) handleCallback(cb *tgbotapi.CallbackQuery) {
 // SESSION HELPERS
 // =====================================================
 
-func (b *Bot) cleanupSession(sessionID string) {
+func (b *Bot) cleanupSession(ctx context.Context, sessionID string) {
 	b.sessMu.Lock()
 	defer b.sessMu.Unlock()
 
@@ -354,7 +865,7 @@ func (b *Bot) cleanupSession(sessionID string) {
 	}
 
 	for _, msgID := range sess.ActiveMsgIDs {
-		b.API.Request(tgbotapi.NewDeleteMessage(sess.ChatID, msgID))
+		b.request(ctx, tgbotapi.NewDeleteMessage(sess.ChatID, msgID))
 	}
 
 	delete(b.sessions, sessionID)
@@ -364,52 +875,46 @@ func (b *Bot) cleanupSession(sessionID string) {
 // MOVIE SELECTION
 // =====================================================
 
-func (b *Bot) sendMovieSelection(sess *userSession, offset int) {
-if offset >= len(sess.Results) || offset >= b.MaxAlt {
+func (b *Bot) sendMovieSelection(ctx context.Context, sess *userSession, offset int) {
+	if offset >= len(sess.Results) || offset >= b.maxAlternatives(sess.ChatID) {
 
-    // Clean up previous selection messages
-    for _, msgID := range sess.ActiveMsgIDs {
-        b.API.Request(tgbotapi.NewDeleteMessage(sess.ChatID, msgID))
-    }
+		// Clean up previous selection messages
+		for _, msgID := range sess.ActiveMsgIDs {
+			b.request(ctx, tgbotapi.NewDeleteMessage(sess.ChatID, msgID))
+		}
 
-    // Notify user
-    msg := tgbotapi.NewMessage(sess.ChatID, "❌ No more alternatives available.")
-    msg.ReplyToMessageID = sess.OrigMessageID
-    b.API.Send(msg)
+		// Notify user
+		msg := tgbotapi.NewMessage(sess.ChatID, "❌ "+i18n.T(sess.Lang, "No more alternatives available."))
+		msg.ReplyToMessageID = sess.OrigMessageID
+		b.send(ctx, msg)
 
-    // Destroy session
-    b.cleanupSession(sess.ID)
-    return
-}
+		// Destroy session
+		b.cleanupSession(ctx, sess.ID)
+		b.resumeQueue(ctx, sess)
+		return
+	}
 
 	for _, msgID := range sess.ActiveMsgIDs {
-		b.API.Request(tgbotapi.NewDeleteMessage(sess.ChatID, msgID))
+		b.request(ctx, tgbotapi.NewDeleteMessage(sess.ChatID, msgID))
 	}
 	sess.ActiveMsgIDs = nil
 
 	m := sess.Results[offset]
-	text := fmt.Sprintf(
-		"*%s* (%s)\n\n[Poster](%s)",
-		m.Title, m.Year, m.Poster,
-	)
-
-	msg := tgbotapi.NewMessage(sess.ChatID, text)
-	msg.ParseMode = "Markdown"
-	msg.ReplyToMessageID = sess.OrigMessageID
-	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+	text := fmt.Sprintf("*%s* (%s)", m.Title, m.Year)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData(
-				"✅ Select this movie",
+				"✅ "+i18n.T(sess.Lang, "Select this movie"),
 				fmt.Sprintf("select|%s|%d", sess.ID, offset),
 			),
 			tgbotapi.NewInlineKeyboardButtonData(
-				"👎 Search Another",
+				"👎 "+i18n.T(sess.Lang, "Search Another"),
 				fmt.Sprintf("alt|%s|%d", sess.ID, offset+1),
 			),
 		),
 	)
 
-	sent, err := b.API.Send(msg)
+	sent, _, err := b.sendCard(ctx, sess.ChatID, sess.OrigMessageID, m.Poster, text, keyboard)
 	if err != nil {
 		return
 	}
@@ -417,155 +922,522 @@ if offset >= len(sess.Results) || offset >= b.MaxAlt {
 	sess.ActiveMsgIDs = append(sess.ActiveMsgIDs, sent.MessageID)
 
 	go func(chatID int64, msgID int, sessionID string) {
-		time.Sleep(5 * time.Minute)
-		b.API.Request(tgbotapi.NewDeleteMessage(chatID, msgID))
-		b.cleanupSession(sessionID)
+		time.Sleep(b.autoDelete(chatID, searchResultsTTL))
+		b.request(ctx, tgbotapi.NewDeleteMessage(chatID, msgID))
+		b.cleanupSession(ctx, sessionID)
 	}(sent.Chat.ID, sent.MessageID, sess.ID)
 }
 
+// callbackAnsweredKey marks, in a callback's context, whether the query was
+// answered yet. Telegram takes one answer per query.
+type callbackAnsweredKey struct{}
+
+// answerCallback runs handleCallback and makes sure the query is answered,
+// so the client's spinner stops: handlers that toast answer it themselves,
+// the others get a silent ack, also when they bail out early or panic. A
+// panic is logged and goes no further, the update loop keeps running.
+func (b *Bot) answerCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	answered := new(atomic.Bool)
+	ctx = context.WithValue(ctx, callbackAnsweredKey{}, answered)
+	defer func() {
+		if r := recover(); r != nil {
+			trace.Logf(ctx, "[CALLBACK] Handler for '%s' panicked: %v\n%s", cb.Data, r, debug.Stack())
+		}
+		if !answered.Load() {
+			b.request(ctx, tgbotapi.NewCallback(cb.ID, ""))
+		}
+	}()
+	b.handleCallback(ctx, cb)
+}
+
+// answerToast answers cb with a toast. Only the first answer per callback
+// counts; later ones are dropped.
+func (b *Bot) answerToast(ctx context.Context, cb *tgbotapi.CallbackQuery, text string) {
+	if answered, ok := ctx.Value(callbackAnsweredKey{}).(*atomic.Bool); ok && answered.Swap(true) {
+		trace.Logf(ctx, "[CALLBACK] Already answered, dropping toast %q", text)
+		return
+	}
+	resp := tgbotapi.NewCallback(cb.ID, text)
+	resp.ShowAlert = false // toast, not popup
+	b.request(ctx, resp)
+}
+
+// send delivers c through the Telegram API, logging the outcome with the
+// update's trace ID.
+func (b *Bot) send(ctx context.Context, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	msg, err := b.API.Send(c)
+	b.apiResult(ctx, err)
+	if err != nil {
+		trace.Logf(ctx, "[TG] Send %T failed: %v", c, err)
+		return msg, err
+	}
+	trace.Logf(ctx, "[TG] Sent %T (message %d)", c, msg.MessageID)
+	return msg, nil
+}
+
+// request is the Request counterpart of send, for calls that return no
+// message (deletes, callback answers, markup edits).
+func (b *Bot) request(ctx context.Context, c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	resp, err := b.API.Request(c)
+	b.apiResult(ctx, err)
+	if err != nil {
+		trace.Logf(ctx, "[TG] Request %T failed: %v", c, err)
+		return resp, err
+	}
+	trace.Logf(ctx, "[TG] Requested %T", c)
+	return resp, nil
+}
 
-func (b *Bot) answerToast(cb *tgbotapi.CallbackQuery, text string) {
-    resp := tgbotapi.NewCallback(cb.ID, text)
-    resp.ShowAlert = false // toast, not popup
-    b.API.Send(resp)
+// call is request for raw Bot API methods.
+func (b *Bot) call(ctx context.Context, method string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	resp, err := b.API.Call(method, params)
+	b.apiResult(ctx, err)
+	if err != nil {
+		trace.Logf(ctx, "[TG] Call %s failed: %v", method, err)
+		return resp, err
+	}
+	trace.Logf(ctx, "[TG] Called %s", method)
+	return resp, nil
 }
 
-func (b *Bot) removeInlineKeyboard(chatID int64, messageID int) error {
+func (b *Bot) removeInlineKeyboard(ctx context.Context, chatID int64, messageID int) error {
 	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, tgbotapi.InlineKeyboardMarkup{})
 	edit.ReplyMarkup = nil // THIS removes the keyboard
 
-	_, err := b.API.Send(edit)
+	_, err := b.send(ctx, edit)
 	return err
 }
 
+// hasPoster reports whether poster is an image Telegram can fetch; OMDb
+// has "N/A" for none.
+func hasPoster(poster string) bool {
+	return strings.HasPrefix(poster, "http")
+}
+
+// sendCard sends a card as the poster with text as its caption, or as text
+// when there is no poster or Telegram can't fetch it. It reports whether
+// the card went out as a photo.
+func (b *Bot) sendCard(ctx context.Context, chatID int64, replyTo int, poster, text string, keyboard tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, bool, error) {
+	if hasPoster(poster) {
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(poster))
+		photo.Caption = text
+		photo.ParseMode = "Markdown"
+		photo.ReplyToMessageID = replyTo
+		photo.ReplyMarkup = keyboard
+		if sent, err := b.send(ctx, photo); err == nil {
+			return sent, true, nil
+		}
+		trace.Logf(ctx, "[BOT] Sending the poster failed, sending the card as text")
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyToMessageID = replyTo
+	msg.ReplyMarkup = keyboard
+	sent, err := b.send(ctx, msg)
+	return sent, false, err
+}
+
+// editCard re-renders a card sent by sendCard: its caption when it is a
+// photo, else its text.
+func editCard(chatID int64, messageID int, photo bool, text string, keyboard tgbotapi.InlineKeyboardMarkup) tgbotapi.Chattable {
+	if photo {
+		edit := tgbotapi.NewEditMessageCaption(chatID, messageID, text)
+		edit.ParseMode = "Markdown"
+		edit.ReplyMarkup = &keyboard
+		return edit
+	}
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
+	edit.ParseMode = "Markdown"
+	return edit
+}
+
 // =====================================================
 // VOTES / LIST (UNCHANGED LOGIC)
 // =====================================================
 
-func (b *Bot) buildVoteMessageConfig(movie storage.Movie) (string, tgbotapi.InlineKeyboardMarkup) {
-	text := fmt.Sprintf(
-		"*%s* (%d)\n\n👍 Votes: *%d*\n👁 Watched: %d\n\n[Poster](%s)\n\nVote 👍 to add to the list or mark as watched.",
-		movie.Title, movie.Year, len(movie.Votes), len(movie.Watched), movie.Poster,
-	)
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("👍 Vote (%d)", len(movie.Votes)),
-				fmt.Sprintf("vote|%s", movie.ID),
-			),
-			tgbotapi.NewInlineKeyboardButtonData(
-				fmt.Sprintf("👁️ Watched (%d)", len(movie.Watched)),
-				fmt.Sprintf("watched|%s", movie.ID),
-			),
-		),
-	)
+// buildVoteMessageConfig renders a movie's vote card as shown in chatID.
+func (b *Bot) buildVoteMessageConfig(movie storage.Movie, chatID int64) (string, tgbotapi.InlineKeyboardMarkup) {
+	return b.voteCard(movie, chatID, false)
+}
+
+// voteCard is buildVoteMessageConfig for a card that may have its links
+// block open.
+func (b *Bot) voteCard(movie storage.Movie, chatID int64, links bool) (string, tgbotapi.InlineKeyboardMarkup) {
+	lang := b.chatLanguage(chatID)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%s* (%d)\n", storage.LocalTitle(movie, lang), movie.Year)
+	if movie.List != "" {
+		fmt.Fprintf(&sb, "📂 %s\n", movie.List)
+	}
+	if movie.Runtime != "" {
+		fmt.Fprintf(&sb, "⏱ %s\n", movie.Runtime)
+	}
+	if movie.ImdbRating != "" {
+		fmt.Fprintf(&sb, "⭐ IMDb %s/10\n", movie.ImdbRating)
+	}
+	if movie.Upcoming {
+		fmt.Fprintf(&sb, "📅 %s\n", releaseText(lang, movie, time.Now()))
+	}
+	if movie.Rewatch {
+		fmt.Fprintf(&sb, "🔁 Rewatch, round %d\n", len(movie.History)+1)
+	}
+	if movie.Manual {
+		sb.WriteString("📝 Added by hand, details follow once OMDb is back\n")
+	}
+	if avg, n := storage.AverageRating(movie); n > 0 {
+		fmt.Fprintf(&sb, "🍿 Group rating %.1f/10 (%d)\n", avg, n)
+	}
+	chat, _ := b.Store.GetChat(chatID)
+	switch {
+	case chat.VoteDeadline.IsZero():
+	case chat.VotingClosed:
+		fmt.Fprintf(&sb, "🔒 Voting closed %s\n", b.deadlineTime(chatID, chat.VoteDeadline))
+	default:
+		fmt.Fprintf(&sb, "⏳ Voting closes %s\n", b.deadlineText(chatID, chat.VoteDeadline, time.Now()))
+	}
+	discussion, hasDiscussion := movie.DiscussionIn(chatID)
+	link := discussionLink(chatID, discussion)
+	if hasDiscussion && link == "" {
+		sb.WriteString("💬 Discussion: reply to the thread below the card\n")
+	}
+	reactions := len(storage.VoteOptions()) > 0
+	fmt.Fprintf(&sb, "\n👍 %s: *%d*\n", i18n.T(lang, "Votes"), len(movie.Votes))
+	if reactions {
+		sb.WriteString(reactionLine(movie) + "\n")
+	}
+	fmt.Fprintf(&sb, "👁 %s: %d\n\n", i18n.T(lang, "Watched"), len(movie.Watched))
+	if links {
+		sb.WriteString(linksText(movie) + "\n\n")
+	}
+	switch {
+	case movie.Upcoming:
+		sb.WriteString(i18n.T(lang, "Voting opens once it's out."))
+	case chat.VotingClosed:
+		sb.WriteString(i18n.T(lang, "Votes are frozen until an admin reopens voting."))
+	case reactions:
+		sb.WriteString(i18n.T(lang, "React to rank it, or mark as watched."))
+	default:
+		sb.WriteString(i18n.T(lang, "Vote 👍 to add to the list or mark as watched."))
+	}
+	text := sb.String()
+	open := !chat.VotingClosed && !movie.Upcoming
+	var buttons []tgbotapi.InlineKeyboardButton
+	if open && !reactions {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("👍 %s (%d)", i18n.T(lang, "Vote"), len(movie.Votes)),
+			fmt.Sprintf("vote|%s", movie.ID),
+		))
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+		fmt.Sprintf("👁️ %s (%d)", i18n.T(lang, "Watched"), len(movie.Watched)),
+		fmt.Sprintf("watched|%s", movie.ID),
+	))
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(buttons)
+	if open && reactions {
+		// one button per reaction, above Watched
+		keyboard.InlineKeyboard = append([][]tgbotapi.InlineKeyboardButton{reactionButtons(movie)}, keyboard.InlineKeyboard...)
+	}
+	if hasDiscussion && link != "" {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL("💬 "+i18n.T(lang, "Discussion"), link),
+		))
+	}
+	if storage.IsWatched(movie) {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔁 "+i18n.T(lang, "Rewatch"), "rewatch|"+movie.ID),
+		))
+	}
+	bottom := tgbotapi.NewInlineKeyboardRow(linksButton(movie, links))
+	if b.Trailers != nil && chatID != 0 {
+		bottom = append(bottom, trailerButton(movie))
+	}
+	if b.Streaming != nil && chatID != 0 {
+		bottom = append(bottom, whereButton(movie))
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, bottom)
 	return text, keyboard
 }
 
-func (b *Bot) createOrUpdateVoteMessage(chatID int64, movieID string) {
+// createOrUpdateVoteMessage shows a movie's vote card in chatID. When the
+// chat already has one, the "cards" setting decides: post another (the
+// default), bump it (delete and post anew) or refresh it and reply to it.
+func (b *Bot) createOrUpdateVoteMessage(ctx context.Context, chatID int64, movieID string) {
 	movie, exists := b.Store.GetMovieByID(movieID)
 	if !exists {
 		return
 	}
 
-	text, keyboard := b.buildVoteMessageConfig(movie)
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = keyboard
+	var existing []storage.MessageRef
+	for _, ref := range b.Store.GetMessages(movie.ID) {
+		if ref.ChatID == chatID {
+			existing = append(existing, ref)
+		}
+	}
+	text, keyboard := b.buildVoteMessageConfig(movie, chatID)
+
+	if len(existing) > 0 {
+		switch b.Store.ChatSetting(chatID, "cards") {
+		case "bump":
+			trace.Logf(ctx, "[BOT] Bumping %d cards of %s in chat %d", len(existing), movie.Title, chatID)
+			for _, ref := range existing {
+				b.Store.UnregisterMessage(ctx, movie.ID, ref.ChatID, ref.MessageID)
+			}
+			b.takeDownCards(ctx, existing, fmt.Sprintf("⬇️ %s (%d) moved to a newer card.", movie.Title, movie.Year))
+
+		case "reply":
+			last := existing[len(existing)-1]
+			b.send(ctx, editCard(chatID, last.MessageID, last.Photo, text, keyboard))
+
+			reply := tgbotapi.NewMessage(chatID, "☝️ "+i18n.Tf(b.chatLanguage(chatID), "%s is already here, vote on it above.", movie.Title))
+			reply.ReplyToMessageID = last.MessageID
+			if _, err := b.send(ctx, reply); err == nil {
+				return
+			}
+			// The card is gone (deleted by someone); post a new one.
+			b.Store.UnregisterMessage(ctx, movie.ID, last.ChatID, last.MessageID)
+		}
+	}
 
-	sent, err := b.API.Send(msg)
+	sent, photo, err := b.sendCard(ctx, chatID, 0, movie.Poster, text, keyboard)
 	if err != nil {
 		return
 	}
 
-	b.Store.RegisterMessage(movie.ID, sent.Chat.ID, sent.MessageID)
+	b.Store.RegisterMessage(ctx, movie.ID, sent.Chat.ID, sent.MessageID)
+	if photo {
+		b.Store.SetMessagePhoto(ctx, movie.ID, sent.Chat.ID, sent.MessageID)
+	}
 }
 
-func (b *Bot) syncMovie(movie storage.Movie) {
-	text, keyboard := b.buildVoteMessageConfig(movie)
+func (b *Bot) syncMovie(ctx context.Context, movie storage.Movie) {
 	refs := b.Store.GetMessages(movie.ID)
 
 	for _, ref := range refs {
-		editText := tgbotapi.NewEditMessageText(ref.ChatID, ref.MessageID, text)
-		editText.ParseMode = "Markdown"
-		b.API.Send(editText)
+		text, keyboard := b.voteCard(movie, ref.ChatID, ref.Links)
+		if ref.InlineID != "" {
+			edit := inlineEdit(ref.InlineID, text, &keyboard)
+			edit.ParseMode = "Markdown"
+			b.request(ctx, edit)
+			continue
+		}
+		b.send(ctx, editCard(ref.ChatID, ref.MessageID, ref.Photo, text, keyboard))
+	}
 
-		editKeyboard := tgbotapi.NewEditMessageReplyMarkup(ref.ChatID, ref.MessageID, keyboard)
-		b.API.Send(editKeyboard)
+	b.syncListMessages(ctx)
+}
+
+// listFormat is chatID's table format in its time style.
+func (b *Bot) listFormat(chatID int64) storage.TableFormat {
+	format := currentTableFormat
+	if f, ok := tableFormats[b.Store.ChatSetting(chatID, "format")]; ok {
+		format = f
 	}
+	format.Time = b.timeStyle(chatID)
+	return format
+}
 
-	b.syncListMessages()
+// renderedList is one page of a list as sent to a chat.
+type renderedList struct {
+	text        string
+	ids         []string // movie IDs of the whole list, row n is ids[n-1]
+	page, pages int
+	hash        string // of text and pager, see MessageRef.Hash
 }
 
+// renderList renders one page of a list ("" for the main watchlist) as a
+// numbered Markdown code block for chatID; named lists get their name on
+// top. page is clamped to the pages there are.
+func (b *Bot) renderList(list string, chatID int64, page int) renderedList {
+	movies := b.Store.GetMovies(b.library(chatID), list)
+	format := b.listFormat(chatID)
+	format.PageSize = b.ListPageSize
+	pages := storage.PageCount(len(movies), b.ListPageSize)
+	format.Page = max(0, min(page, pages-1))
+
+	body, ids := storage.BuildNumberedList(movies, format) // Use the new list builder logic
+	if list != "" {
+		body = "📂 " + list + "\n\n" + body
+	}
+	r := renderedList{text: "```\n" + body + "\n```", ids: ids, page: format.Page, pages: pages}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\x00%d/%d", r.text, r.page, r.pages)
+	r.hash = strconv.FormatUint(h.Sum64(), 36)
+	return r
+}
 
-func (b *Bot) sendList(chatID int64, replyTo int) {
-    text := "```\n" +storage.BuildListMessage( b.Store.GetAllMovies(), currentTableFormat) + "\n```" // Use the new list builder logic
-   
-	msg := tgbotapi.NewMessage(chatID, text)
+func (b *Bot) sendList(ctx context.Context, chatID int64, replyTo int, list string) {
+	pinned := b.pinnedLists(chatID)
+	if pinned && b.bumpPinnedList(ctx, chatID, replyTo, list) {
+		return
+	}
+
+	r := b.renderList(list, chatID, 0)
+	msg := tgbotapi.NewMessage(chatID, r.text)
 	msg.ParseMode = "Markdown"
 	msg.ReplyToMessageID = replyTo
-	sent, _ := b.API.Send(msg)
-	b.Store.RegisterMessage("list", sent.Chat.ID, sent.MessageID)
+	if kb := listPageKeyboard(r.page, r.pages); kb != nil {
+		msg.ReplyMarkup = kb
+	}
+	sent, err := b.send(ctx, msg)
+	if err != nil {
+		return
+	}
+	b.rememberList(chatID, listView{list: list, ids: r.ids})
+	b.Store.RegisterMessage(ctx, storage.ListKey(list), sent.Chat.ID, sent.MessageID)
+	b.Store.SetMessageHash(ctx, storage.ListKey(list), sent.Chat.ID, sent.MessageID, r.hash)
+	if pinned {
+		b.pinList(ctx, list, sent)
+	}
 }
 
-func (b *Bot) syncListMessages() {
-    text :=  "```\n" + storage.BuildListMessage( b.Store.GetAllMovies(), currentTableFormat)+ "\n```" // Use the new list builder logic
+// sendFilteredList sends a one-off filtered list. It is not registered for
+// syncing, since live list messages always show the whole list.
+func (b *Bot) sendFilteredList(ctx context.Context, chatID int64, replyTo int, filter storage.ListFilter) {
+	format := b.listFormat(chatID)
+	format.Filter = filter
+	body, ids := storage.BuildNumberedList(b.Store.GetMovies(b.library(chatID), ""), format)
 
-	refs := b.Store.GetMessages("list")
-	for _, ref := range refs {
-		edit := tgbotapi.NewEditMessageText(ref.ChatID, ref.MessageID, text)
-		edit.ParseMode = "Markdown"
-		b.API.Send(edit)
+	msg := tgbotapi.NewMessage(chatID, "```\n"+body+"\n```")
+	msg.ParseMode = "Markdown"
+	msg.ReplyToMessageID = replyTo
+	if _, err := b.send(ctx, msg); err == nil {
+		b.rememberList(chatID, listView{filtered: true, ids: ids})
 	}
 }
 
+// listFilterKeys are the filters /list takes, "tag" standing for "genre".
+var listFilterKeys = []string{"collection=", "genre=", "tag="}
+
+// parseListArgs splits /list arguments into a table format name and
+// key=value filters. A filter value runs to the next filter or the end of
+// the arguments, so "/list genre=horror collection=The Lord of the Rings"
+// works without quoting.
+func parseListArgs(args string) (string, storage.ListFilter) {
+	args = strings.TrimSpace(args)
+	var filter storage.ListFilter
+
+	lower := strings.ToLower(args)
+	type found struct {
+		key string
+		at  int
+	}
+	var keys []found
+	for _, k := range listFilterKeys {
+		if i := strings.Index(lower, k); i >= 0 && (i == 0 || lower[i-1] == ' ') {
+			keys = append(keys, found{k, i})
+		}
+	}
+	if len(keys) == 0 {
+		return args, filter
+	}
+	slices.SortFunc(keys, func(a, b found) int { return a.at - b.at })
+	for n, k := range keys {
+		end := len(args)
+		if n+1 < len(keys) {
+			end = keys[n+1].at
+		}
+		value := strings.TrimSpace(args[k.at+len(k.key) : end])
+		switch k.key {
+		case "collection=":
+			filter.Collection = value
+		case "genre=", "tag=":
+			filter.Genre = value
+		}
+	}
+	return strings.TrimSpace(args[:keys[0].at]), filter
+}
+
+// syncListMessages re-renders every tracked copy of every list, once per
+// chat and page since chats may format times differently. Copies that
+// would not change, and those in idle chats, are left alone.
+func (b *Bot) syncListMessages(ctx context.Context) {
+	type view struct {
+		chatID int64
+		page   int
+	}
+	idle := make(map[int64]bool)
+	edits := 0
+	for _, list := range b.Store.AllListNames() {
+		key := storage.ListKey(list)
+		rendered := make(map[view]renderedList)
+		for _, ref := range b.Store.GetMessages(key) {
+			if b.idleChat(ref.ChatID) {
+				idle[ref.ChatID] = true
+				continue
+			}
+			v := view{ref.ChatID, ref.Page}
+			r, ok := rendered[v]
+			if !ok {
+				r = b.renderList(list, ref.ChatID, ref.Page)
+				rendered[v] = r
+				b.relisted(ref.ChatID, list, r.ids)
+			}
+			if r.hash == ref.Hash {
+				continue
+			}
+			edits++
+			if _, err := b.send(ctx, listPageEdit(ref.ChatID, ref.MessageID, r)); err == nil || notModified(err) {
+				b.Store.SetMessageHash(ctx, key, ref.ChatID, ref.MessageID, r.hash)
+			}
+		}
+	}
+	if edits > 0 || len(idle) > 0 {
+		trace.Logf(ctx, "[BOT] List sync: %d edits, skipped %d idle chats", edits, len(idle))
+	}
+}
+
+// idleChat reports whether chatID has been quiet for longer than
+// ListSyncIdle. Chats the registry doesn't know count as active.
+func (b *Bot) idleChat(chatID int64) bool {
+	if b.ListSyncIdle <= 0 {
+		return false
+	}
+	c, ok := b.Store.GetChat(chatID)
+	return ok && !c.LastActivity.IsZero() && time.Since(c.LastActivity) > b.ListSyncIdle
+}
+
 // =====================================================
 // LIST BUILDER
 // =====================================================
 
 var tableFormats = map[string]storage.TableFormat{
-"default": {
-    Columns: []storage.MovieColumn{
-        {"Title", 	25, storage.FormatTitle},
-        {"Year",	4,	storage.FormatYear},
-        {"Votes", 	5,  storage.FormatVotes},
-        {"Seen", 	4,  storage.FormatWatched},
-    },
-    SortBy:         	storage.SortByVotes,   	// Default sort by votes
-    SeparateWatched: 	true,         			// Default to separate watched/unwatched movies
-},
-	"detail": {    Columns: []storage.MovieColumn{
-        {"Title", 	20, storage.FormatTitle},
-        {"Year", 	4,  storage.FormatYear},
-        {"Votes", 	5,  storage.FormatVotes},
-        {"Seen", 	4,  storage.FormatWatched},
-        {"Added", 	10, storage.FormatAdded}, 	
-    },
-    SortBy:         	storage.SortByVotes,   	// Default sort by votes
-    SeparateWatched: 	true,         			// Default to separate watched/unwatched movies
-},
+	"default": storage.DefaultTableFormat(),
+	"detail": {Columns: []storage.MovieColumn{
+		{Header: "Title", Width: 20, Format: storage.FormatTitle},
+		{Header: "Year", Width: 4, Format: storage.FormatYear},
+		{Header: "Votes", Width: 5, Format: storage.FormatVotes},
+		{Header: "Seen", Width: 4, Format: storage.FormatWatched},
+		{Header: "IMDb", Width: 4, Format: storage.FormatImdbRating},
+		{Header: "Ours", Width: 4, Format: storage.FormatGroupRating},
+		{Header: "Added", Width: 10, FormatTime: storage.FormatAddedTime},
+	},
+		SortBy:           storage.SortByVotes, // Default sort by votes
+		SeparateWatched:  true,                // Default to separate watched/unwatched movies
+		GroupCollections: true,
+	},
 	"wide": {
-    Columns: []storage.MovieColumn{
-        {"Title", 	40, storage.FormatTitle},
-        {"Year", 	4,  storage.FormatYear},
-        {"Votes", 	5,  storage.FormatVotes},
-        {"Seen", 	4,  storage.FormatWatched},
-        {"Added", 	10, storage.FormatAdded}, 	
-    },
-    SortBy:         	storage.SortByVotes,   	// Default sort by votes
-    SeparateWatched: 	true,         			// Default to separate watched/unwatched movies
-},
+		Columns: []storage.MovieColumn{
+			{Header: "Title", Width: 40, Format: storage.FormatTitle},
+			{Header: "Year", Width: 4, Format: storage.FormatYear},
+			{Header: "Votes", Width: 5, Format: storage.FormatVotes},
+			{Header: "Seen", Width: 4, Format: storage.FormatWatched},
+			{Header: "IMDb", Width: 4, Format: storage.FormatImdbRating},
+			{Header: "Genre", Width: 20, Format: storage.FormatGenre},
+			{Header: "Ours", Width: 4, Format: storage.FormatGroupRating},
+			{Header: "Added", Width: 10, FormatTime: storage.FormatAddedTime},
+		},
+		SortBy:           storage.SortByVotes, // Default sort by votes
+		SeparateWatched:  true,                // Default to separate watched/unwatched movies
+		GroupCollections: true,
+	},
 }
 var currentTableFormat = tableFormats["default"]
 
-
-
-
 // =====================================================
 // UI
 // =====================================================
 
-func (b *Bot) sendKeyboard(chatID int64) {
+func (b *Bot) sendKeyboard(ctx context.Context, chatID int64) {
 	msg := tgbotapi.NewMessage(chatID, "👍 Movie bot ready")
 	msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
@@ -573,5 +1445,5 @@ func (b *Bot) sendKeyboard(chatID int64) {
 			tgbotapi.NewKeyboardButton("/list"),
 		),
 	)
-	b.API.Send(msg)
-}
\ No newline at end of file
+	b.send(ctx, msg)
+}
@@ -0,0 +1,266 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/events"
+	"moviebot/internal/i18n"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+// Tonight poll defaults: how many candidates, how many must be in, and how
+// long it runs. It closes pollLead before the night at the latest, and the
+// night must be at least minLead away.
+const (
+	tonightCandidates = 3
+	tonightQuorum     = 3
+	tonightPollFor    = time.Hour
+	tonightPollLead   = 15 * time.Minute
+	tonightMinLead    = 30 * time.Minute
+)
+
+// =====================================================
+// /tonight — quick "watch tonight?" poll
+// =====================================================
+
+// handleTonight asks the chat who is in for a movie tonight
+// ("/tonight [20:00] [people]"). When the poll ends with enough people in,
+// the top movie they want most is scheduled and announced, so reminders
+// follow as for /schedule.
+func (b *Bot) handleTonight(ctx context.Context, msg *tgbotapi.Message) {
+	usage := fmt.Sprintf("Usage: /tonight [start time, default %d:00] [people needed, default %d]", defaultNightHour, tonightQuorum)
+	chatID := msg.Chat.ID
+	if p, ok := b.Store.OpenTonight(chatID); ok {
+		reply := tgbotapi.NewMessage(chatID, "🍿 Already asking, answer here.")
+		reply.ReplyToMessageID = p.MessageID
+		reply.AllowSendingWithoutReply = true
+		b.send(ctx, reply)
+		return
+	}
+
+	when := []string{"tonight"}
+	need := tonightQuorum
+	for _, f := range strings.Fields(msg.CommandArguments()) {
+		if n, err := strconv.Atoi(f); err == nil && n > 0 {
+			need = n
+		} else {
+			when = append(when, f)
+		}
+	}
+	loc := b.chatLocation(chatID)
+	now := time.Now().In(loc)
+	at, used, err := parseWhen(when, now)
+	switch {
+	case err == nil && used != len(when):
+		err = fmt.Errorf("%q is not a time like 20:30", when[1])
+	case err == nil && at.Sub(now) < tonightMinLead:
+		err = fmt.Errorf("that's too soon to ask around, start at %s or later", now.Add(tonightMinLead).Format("15:04"))
+	}
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error()+"\n"+usage)
+		return
+	}
+	if err := b.checkNightCooldown(chatID, at, loc); err != nil {
+		b.replyText(ctx, msg, "⏳ "+err.Error()+".")
+		return
+	}
+
+	format := b.listFormat(chatID)
+	format.SortBy = storage.SortByVotes
+	format.Render = storage.RenderCards
+	format.Limit = tonightCandidates
+	_, ids := storage.BuildNumberedList(b.Store.GetMovies(b.library(chatID), ""), format)
+	if len(ids) == 0 {
+		b.replyText(ctx, msg, "🍿 Nothing left to watch, add some movies with /movie.")
+		return
+	}
+
+	ends := now.Add(tonightPollFor)
+	if cutoff := at.Add(-tonightPollLead); cutoff.Before(ends) {
+		ends = cutoff
+	}
+	p := b.Store.AddTonight(ctx, storage.TonightPoll{
+		ChatID:    chatID,
+		MovieIDs:  ids,
+		At:        at,
+		Need:      need,
+		StartedBy: msg.From.UserName,
+		StartedAt: now,
+		EndsAt:    ends,
+	})
+	card := tgbotapi.NewMessage(chatID, b.tonightText(p))
+	card.ReplyMarkup = tonightKeyboard(p)
+	sent, err := b.send(ctx, card)
+	if err != nil {
+		b.Store.CloseTonight(ctx, p.ID, "")
+		b.replyText(ctx, msg, "❌ Couldn't start the poll.")
+		return
+	}
+	b.Store.SetTonightMessage(ctx, p.ID, sent.MessageID)
+	trace.Logf(ctx, "[BOT] %s asked who's in for %v, %d needed", msg.From.UserName, at, need)
+}
+
+// tonightText is the poll's message: the candidates and who answered.
+func (b *Bot) tonightText(p storage.TonightPoll) string {
+	loc := b.chatLocation(p.ChatID)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🍿 Movie tonight at %s?\n", p.At.In(loc).Format("15:04"))
+	if p.Closed {
+		sb.WriteString("The poll is closed.\n\n")
+	} else {
+		fmt.Fprintf(&sb, "With %d in by %s, I'll pick what you want most of:\n\n", p.Need, p.EndsAt.In(loc).Format("15:04"))
+	}
+	for i, id := range p.MovieIDs {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, b.movieLabel(id))
+	}
+	for _, a := range []struct {
+		in    bool
+		label string
+	}{{true, "🙋 In"}, {false, "🙅 Can't"}} {
+		var names []string
+		for _, id := range p.Answered(a.in) {
+			names = append(names, b.userLabel(id))
+		}
+		if len(names) > 0 {
+			fmt.Fprintf(&sb, "\n%s: %s", a.label, strings.Join(names, ", "))
+		}
+	}
+	return sb.String()
+}
+
+func tonightKeyboard(p storage.TonightPoll) tgbotapi.InlineKeyboardMarkup {
+	in, out := len(p.Answered(true)), len(p.Answered(false))
+	label := func(text string, n int) string {
+		if n > 0 {
+			return fmt.Sprintf("%s (%d)", text, n)
+		}
+		return text
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(label("🙋 In!", in), "tonight|"+p.ID+"|in"),
+		tgbotapi.NewInlineKeyboardButtonData(label("🙅 Can't", out), "tonight|"+p.ID+"|out"),
+	))
+}
+
+// handleTonightCallback handles the poll's buttons, "tonight|<poll>|in" or
+// "out". Tapping your answer again takes it back.
+func (b *Bot) handleTonightCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cb.Data, "|")
+	if len(parts) != 3 {
+		return
+	}
+	userID := strconv.FormatInt(cb.From.ID, 10)
+	in := parts[2] == "in"
+
+	p, err := b.Store.TonightAnswer(ctx, parts[1], userID, in, time.Now())
+	if err != nil {
+		b.answerToast(ctx, cb, "❌ "+err.Error())
+		return
+	}
+	lang := b.userLanguage(p.ChatID, cb.From)
+	switch answer, ok := p.Answers[userID]; {
+	case !ok:
+		b.answerToast(ctx, cb, i18n.T(lang, "Answer taken back."))
+	case answer:
+		b.answerToast(ctx, cb, "🙋 "+i18n.T(lang, "You're in!"))
+	default:
+		b.answerToast(ctx, cb, "🙅 "+i18n.T(lang, "Maybe next time."))
+	}
+	b.syncTonight(ctx, p)
+}
+
+// syncTonight edits the poll's message to the current answers. Closed polls
+// lose their buttons.
+func (b *Bot) syncTonight(ctx context.Context, p storage.TonightPoll) {
+	edit := tgbotapi.NewEditMessageText(p.ChatID, p.MessageID, b.tonightText(p))
+	if !p.Closed {
+		keyboard := tonightKeyboard(p)
+		edit.ReplyMarkup = &keyboard
+	}
+	if _, err := b.send(ctx, edit); err != nil && !notModified(err) {
+		trace.Logf(ctx, "[BOT] Updating tonight poll %s failed: %v", p.ID, err)
+	}
+}
+
+// checkTonight settles the tonight polls whose time is up.
+func (b *Bot) checkTonight(ctx context.Context, now time.Time) {
+	for _, p := range b.Store.OpenTonights() {
+		if !now.Before(p.EndsAt) {
+			b.settleTonight(ctx, p)
+		}
+	}
+}
+
+// settleTonight closes a poll. With enough people in it schedules the
+// candidate they want most, with everyone in down as going.
+func (b *Bot) settleTonight(ctx context.Context, p storage.TonightPoll) {
+	in := p.Answered(true)
+	var movies []storage.Movie
+	for _, id := range p.MovieIDs {
+		if m, ok := b.Store.GetMovieByID(id); ok && !storage.IsWatched(m) {
+			movies = append(movies, m)
+		}
+	}
+	movie, _, ok := pickFor(movies, in)
+	if len(in) < p.Need || !ok {
+		p, err := b.Store.CloseTonight(ctx, p.ID, "")
+		if err != nil {
+			return
+		}
+		b.syncTonight(ctx, p)
+		text := fmt.Sprintf("😴 Only %d of the %d needed are in, no movie tonight.", len(in), p.Need)
+		if len(in) >= p.Need {
+			text = "🤷 Everyone in has seen all the candidates, no movie tonight."
+		}
+		reply := tgbotapi.NewMessage(p.ChatID, text)
+		reply.ReplyToMessageID = p.MessageID
+		reply.AllowSendingWithoutReply = true
+		b.send(ctx, reply)
+		trace.Logf(ctx, "[BOT] Tonight poll %s closed with %d of %d in", p.ID, len(in), p.Need)
+		return
+	}
+
+	night := storage.Night{
+		ChatID:    p.ChatID,
+		MovieID:   movie.ID,
+		Title:     movie.Title,
+		At:        p.At,
+		CreatedBy: p.StartedBy,
+	}
+	if b.WatchParty != nil {
+		link, err := b.WatchParty.Link(ctx, movie, p.At)
+		if err != nil {
+			trace.Logf(ctx, "[BOT] Watch-party link for %s failed: %v", movie.Title, err)
+		}
+		night.WatchLink = link
+	}
+	night = b.Store.AddNight(ctx, night)
+	for _, id := range in {
+		if n, err := b.Store.SetRSVP(ctx, night.ID, id, storage.RSVPGoing); err == nil {
+			night = n
+		}
+	}
+	p, err := b.Store.CloseTonight(ctx, p.ID, night.ID)
+	if err != nil {
+		return
+	}
+	b.syncTonight(ctx, p)
+	trace.Logf(ctx, "[BOT] Tonight poll %s picked %s with %d in", p.ID, movie.Title, len(in))
+
+	b.announceNight(ctx, p.MessageID, night, movie)
+	b.Events.Publish(ctx, events.Event{
+		Type:     events.NightScheduled,
+		Source:   "telegram",
+		ChatID:   p.ChatID,
+		Username: p.StartedBy,
+		Active:   true,
+		Movie:    &movie,
+		Night:    &night,
+	})
+}
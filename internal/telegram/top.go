@@ -0,0 +1,67 @@
+package telegram
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+)
+
+const (
+	defaultTop = 5
+	// maxTop keeps the card within a photo caption (1024 characters).
+	maxTop = 10
+)
+
+// =====================================================
+// /top [n]
+// =====================================================
+
+// handleTop sends the n highest-voted unwatched movies as a compact card:
+// the leader's poster with the ranking as its caption. Its rows can be used
+// with /vote and /seen like those of /list.
+func (b *Bot) handleTop(ctx context.Context, msg *tgbotapi.Message) {
+	n := defaultTop
+	if arg := strings.TrimSpace(msg.CommandArguments()); arg != "" {
+		v, err := strconv.Atoi(arg)
+		if err != nil || v < 1 {
+			b.replyText(ctx, msg, "Usage: /top [n]")
+			return
+		}
+		n = min(v, maxTop)
+	}
+	trace.Logf(ctx, "[BOT] /top %d from %s", n, msg.From.UserName)
+
+	format := b.listFormat(msg.Chat.ID)
+	format.SortBy = storage.SortByVotes
+	format.Render = storage.RenderCards
+	format.Limit = n
+
+	body, ids := storage.BuildNumberedList(b.Store.GetMovies(b.library(msg.Chat.ID), ""), format)
+	if len(ids) == 0 {
+		b.replyText(ctx, msg, "🏆 Nothing left to watch, add something with /movie.")
+		return
+	}
+	text := "🏆 Top " + strconv.Itoa(len(ids)) + "\n\n" + body
+
+	var err error
+	if leader, ok := b.Store.GetMovieByID(ids[0]); ok && strings.HasPrefix(leader.Poster, "http") {
+		photo := tgbotapi.NewPhoto(msg.Chat.ID, tgbotapi.FileURL(leader.Poster))
+		photo.Caption = text
+		photo.ParseMode = "Markdown"
+		photo.ReplyToMessageID = msg.MessageID
+		_, err = b.send(ctx, photo)
+	} else {
+		reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+		reply.ParseMode = "Markdown"
+		reply.DisableWebPagePreview = true
+		reply.ReplyToMessageID = msg.MessageID
+		_, err = b.send(ctx, reply)
+	}
+	if err == nil {
+		b.rememberList(msg.Chat.ID, listView{filtered: true, ids: ids})
+	}
+}
@@ -0,0 +1,80 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+	"moviebot/internal/trailers"
+)
+
+// =====================================================
+// 🎬 TRAILERS — /trailer and the Trailer button
+// =====================================================
+
+// handleTrailer posts the trailer of a movie on the list, or of any title
+// OMDb knows.
+func (b *Bot) handleTrailer(ctx context.Context, msg *tgbotapi.Message) {
+	if b.Trailers == nil {
+		b.replyText(ctx, msg, "🎬 Trailers are not available on this bot.")
+		return
+	}
+	ref := strings.TrimSpace(msg.CommandArguments())
+	if ref == "" {
+		b.replyText(ctx, msg, "Usage: /trailer <title or IMDb ID>")
+		return
+	}
+	trace.Logf(ctx, "[BOT] /trailer %q from %s", ref, msg.From.UserName)
+
+	movie, _, err := b.infoMovie(ctx, msg.Chat.ID, ref)
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error())
+		return
+	}
+	b.postTrailer(ctx, msg.Chat.ID, msg.MessageID, movie)
+}
+
+// trailerButton sits next to the Links button of chat cards, "trailer|<movie>".
+func trailerButton(movie storage.Movie) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData("🎬 Trailer", "trailer|"+movie.ID)
+}
+
+// handleTrailerCallback answers a card's Trailer button with the link, as a
+// reply to the card.
+func (b *Bot) handleTrailerCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if cb.Message == nil || b.Trailers == nil {
+		return
+	}
+	movie, ok := b.Store.GetMovieByID(strings.TrimPrefix(cb.Data, "trailer|"))
+	if !ok {
+		b.answerToast(ctx, cb, "This movie is no longer on the list.")
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s asked for the trailer of %s", cb.From.UserName, movie.Title)
+	if !b.postTrailer(ctx, cb.Message.Chat.ID, cb.Message.MessageID, movie) {
+		b.answerToast(ctx, cb, "🎬 No trailer found")
+	}
+}
+
+// postTrailer replies to replyTo with the trailer of movie, leaving the
+// link preview on so it plays in the chat. It reports whether one was found.
+func (b *Bot) postTrailer(ctx context.Context, chatID int64, replyTo int, movie storage.Movie) bool {
+	t, err := b.Trailers.Find(ctx, movie)
+	if err != nil {
+		if !errors.Is(err, trailers.ErrNotFound) {
+			trace.Logf(ctx, "[TRAILERS] Looking up %s failed: %v", movie.Title, err)
+		}
+		reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("🎬 No trailer found for %s (%d).", movie.Title, movie.Year))
+		reply.ReplyToMessageID = replyTo
+		b.send(ctx, reply)
+		return false
+	}
+	reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("🎬 %s (%d)\n%s", movie.Title, movie.Year, t.URL))
+	reply.ReplyToMessageID = replyTo
+	b.send(ctx, reply)
+	return true
+}
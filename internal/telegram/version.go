@@ -0,0 +1,93 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// ℹ️ /version — owners and AdminIDs
+// =====================================================
+
+// handleVersion reports which build is running, for how long, how much it
+// handled and holds, and which features are switched on.
+func (b *Bot) handleVersion(ctx context.Context, msg *tgbotapi.Message) {
+	if !b.isOwner(msg.From.ID) && !slices.Contains(b.AdminIDs, msg.From.ID) {
+		trace.Logf(ctx, "[BOT] /version denied for %s", msg.From.UserName)
+		return
+	}
+	b.replyText(ctx, msg, b.versionText())
+}
+
+// versionText renders /version and the panel's Version button.
+func (b *Bot) versionText() string {
+	var sb strings.Builder
+	build, commit := b.BuildTime, b.Commit
+	if build == "" {
+		build = "unknown"
+	}
+	if commit == "" {
+		commit = "unknown"
+	}
+	fmt.Fprintf(&sb, "ℹ️ Built %s, commit %s\n", build, commit)
+	fmt.Fprintf(&sb, "⏱ Up %s, since %s UTC\n", uptimeText(time.Since(b.started)), b.started.UTC().Format("2006-01-02 15:04"))
+
+	var updates, commands, callbacks, slow int
+	for _, h := range b.HandlerStats() {
+		updates += h.Count
+		slow += h.Slow
+		switch {
+		case strings.HasPrefix(h.Name, "/"):
+			commands += h.Count
+		case strings.HasPrefix(h.Name, "cb:"):
+			callbacks += h.Count
+		}
+	}
+	fmt.Fprintf(&sb, "📨 %d updates: %d commands, %d button taps, %d other", updates, commands, callbacks, updates-commands-callbacks)
+	if slow > 0 {
+		fmt.Fprintf(&sb, " (%d slow)", slow)
+	}
+
+	st := b.Store.Stats()
+	fmt.Fprintf(&sb, "\n📊 %d movies (%d watched) on %d lists, %d tracked messages, %d users, %d chats, %d movie nights",
+		st.Movies, st.Watched, st.Lists, st.Messages, st.Users, st.Chats, st.Nights)
+
+	var on, off []string
+	for _, name := range slices.Sorted(maps.Keys(b.Disabled)) {
+		if b.Disabled[name] {
+			off = append(off, name)
+		} else {
+			on = append(on, name)
+		}
+	}
+	sb.WriteString("\n")
+	if len(off) == 0 {
+		sb.WriteString("\n✅ All features on")
+	}
+	if len(on) > 0 && len(off) > 0 {
+		fmt.Fprintf(&sb, "\n✅ On: %s", strings.Join(on, ", "))
+	}
+	if len(off) > 0 {
+		fmt.Fprintf(&sb, "\n🚫 Off: %s", strings.Join(off, ", "))
+	}
+	return sb.String()
+}
+
+// uptimeText renders a duration as "3d 4h 12m", "4h 12m" or "12m".
+func uptimeText(d time.Duration) string {
+	days, hours, minutes := int(d.Hours())/24, int(d.Hours())%24, int(d.Minutes())%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
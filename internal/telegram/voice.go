@@ -0,0 +1,43 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/trace"
+)
+
+// transcribeVoice turns a voice reply to the "What movie?" prompt into a
+// search query. It returns "" (after telling the user) when that is not
+// possible, leaving the prompt open for a typed answer.
+func (b *Bot) transcribeVoice(ctx context.Context, msg *tgbotapi.Message) string {
+	if b.Transcriber == nil {
+		b.replyText(ctx, msg, "🎙 Voice requests are not enabled here, please type the title.")
+		return ""
+	}
+	if msg.Voice.FileSize > maxDownloadSize {
+		b.replyText(ctx, msg, "🎙 That voice message is too long, please type the title.")
+		return ""
+	}
+
+	audio, err := b.downloadFile(ctx, msg.Voice.FileID)
+	if err != nil {
+		trace.Logf(ctx, "[BOT] Voice download failed: %v", err)
+		b.replyText(ctx, msg, "❌ Could not download the voice message, please type the title.")
+		return ""
+	}
+
+	text, err := b.Transcriber.Transcribe(ctx, audio, "voice.ogg")
+	// Whisper punctuates sentences; titles rarely end in one.
+	text = strings.TrimRight(text, ".!?")
+	if err != nil || text == "" {
+		trace.Logf(ctx, "[BOT] Voice transcription failed: %v", err)
+		b.replyText(ctx, msg, "🎙 Sorry, I didn't catch that. Please try again or type the title.")
+		return ""
+	}
+
+	trace.Logf(ctx, "[BOT] Voice from %s transcribed as '%s'", msg.From.UserName, text)
+	b.replyText(ctx, msg, "🎙 Heard: "+text)
+	return text
+}
@@ -0,0 +1,374 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/i18n"
+	"moviebot/internal/storage"
+	"moviebot/internal/trace"
+	"moviebot/internal/web"
+)
+
+// Voting round limits: candidates on the keyboard, and how short or long a
+// round may run.
+const (
+	maxRoundCandidates = 8
+	minRoundLength     = 10 * time.Minute
+	maxRoundLength     = 14 * 24 * time.Hour
+)
+
+// maxRoundHistory is how many past rounds /voteround history lists.
+const maxRoundHistory = 10
+
+// =====================================================
+// /voteround — time-boxed vote over a fixed set of movies
+// =====================================================
+
+// handleVoteRound starts a voting round over the top unwatched movies
+// ("/voteround 48h [n]", admins only). Only taps on the round's buttons
+// before it ends count; standings are posted along the way and the winner
+// is announced when time is up. "/voteround close" ends it early,
+// "/voteround guest [name]" makes a one-time web ballot for someone not on
+// Telegram and "/voteround history" lists past rounds.
+func (b *Bot) handleVoteRound(ctx context.Context, msg *tgbotapi.Message) {
+	usage := fmt.Sprintf("Usage: /voteround <length, e.g. 48h or 2d> [candidates, 2-%d], /voteround close, /voteround guest [name] or /voteround history", maxRoundCandidates)
+	fields := strings.Fields(msg.CommandArguments())
+	chatID := msg.Chat.ID
+	open, hasOpen := b.Store.OpenRound(chatID)
+
+	if len(fields) == 0 {
+		if !hasOpen {
+			b.replyText(ctx, msg, "No voting round is running.\n"+usage)
+			return
+		}
+		reply := tgbotapi.NewMessage(chatID, "🗳 Voting closes "+b.deadlineText(chatID, open.EndsAt, time.Now())+", vote here.")
+		reply.ReplyToMessageID = open.MessageID
+		reply.AllowSendingWithoutReply = true
+		b.send(ctx, reply)
+		return
+	}
+
+	switch fields[0] {
+	case "history":
+		b.replyText(ctx, msg, b.roundHistory(chatID))
+		return
+	case "close":
+		if !b.isAdmin(ctx, chatID, msg.From.ID) {
+			b.replyText(ctx, msg, "⛔ Only chat admins can close the voting round.")
+			return
+		}
+		if !hasOpen {
+			b.replyText(ctx, msg, "There is no voting round running here.")
+			return
+		}
+		b.closeRound(ctx, open)
+		return
+	case "guest":
+		if !b.isAdmin(ctx, chatID, msg.From.ID) {
+			b.replyText(ctx, msg, "⛔ Only chat admins can invite guests to vote.")
+			return
+		}
+		b.addGuest(ctx, msg, open, hasOpen, strings.Join(fields[1:], " "))
+		return
+	}
+
+	if !b.isAdmin(ctx, chatID, msg.From.ID) {
+		b.replyText(ctx, msg, "⛔ Only chat admins can start a voting round.")
+		return
+	}
+	if hasOpen {
+		b.replyText(ctx, msg, "🗳 A voting round is already running until "+b.deadlineTime(chatID, open.EndsAt)+". Close it first with /voteround close.")
+		return
+	}
+
+	length, err := parseRoundLength(fields[0])
+	n := 5
+	if err == nil && len(fields) > 1 {
+		n, err = strconv.Atoi(fields[1])
+		if err == nil && n < 2 {
+			err = fmt.Errorf("a round needs at least two candidates")
+		}
+	}
+	if err != nil || len(fields) > 2 {
+		text := usage
+		if err != nil {
+			text = "❌ " + err.Error() + "\n" + usage
+		}
+		b.replyText(ctx, msg, text)
+		return
+	}
+
+	format := b.listFormat(chatID)
+	format.SortBy = storage.SortByVotes
+	format.Render = storage.RenderCards
+	format.Limit = min(n, maxRoundCandidates)
+	_, ids := storage.BuildNumberedList(b.Store.GetMovies(b.library(chatID), ""), format)
+	if len(ids) < 2 {
+		b.replyText(ctx, msg, "🗳 A voting round needs at least two unwatched movies, add some with /movie.")
+		return
+	}
+	titles := make([]string, len(ids))
+	for i, id := range ids {
+		titles[i] = b.movieLabel(id)
+	}
+
+	now := time.Now()
+	r := b.Store.AddRound(ctx, storage.VotingRound{
+		ChatID:      chatID,
+		MovieIDs:    ids,
+		Titles:      titles,
+		StartedBy:   strconv.FormatInt(msg.From.ID, 10),
+		StartedAt:   now,
+		EndsAt:      now.Add(length),
+		StandingsAt: now,
+	})
+	card := tgbotapi.NewMessage(chatID, b.votingRoundText(r, now))
+	card.ReplyMarkup = roundKeyboard(r)
+	sent, err := b.send(ctx, card)
+	if err != nil {
+		b.Store.CloseRound(ctx, r.ID)
+		b.replyText(ctx, msg, "❌ Couldn't start the voting round.")
+		return
+	}
+	b.Store.SetRoundMessage(ctx, r.ID, sent.MessageID)
+	trace.Logf(ctx, "[BOT] %s started voting round %s over %d movies for %v", msg.From.UserName, r.ID, len(ids), length)
+}
+
+// parseRoundLength reads a round's length: a Go duration like "48h" or
+// "90m", or whole days like "2d".
+func parseRoundLength(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	}
+	switch {
+	case err != nil:
+		return 0, fmt.Errorf("%q is not a length like 48h or 2d", s)
+	case d < minRoundLength || d > maxRoundLength:
+		return 0, fmt.Errorf("a round runs between %s and %d days", leadText(minRoundLength), maxRoundLength/(24*time.Hour))
+	}
+	return d, nil
+}
+
+// votingRoundText is the round's message: when it closes and the standings.
+func (b *Bot) votingRoundText(r storage.VotingRound, now time.Time) string {
+	var sb strings.Builder
+	if r.Closed {
+		sb.WriteString("🏁 Voting round closed\n\n")
+	} else {
+		fmt.Fprintf(&sb, "🗳 Voting round! Voting closes %s.\nVote for as many as you like, tap again to take a vote back.\n\n", b.deadlineText(r.ChatID, r.EndsAt, now))
+	}
+	sb.WriteString(standingsText(r))
+	fmt.Fprintf(&sb, "\n\nVoters: %d", len(r.Votes))
+	if guests := guestVoters(r); guests > 0 {
+		fmt.Fprintf(&sb, " (%d via guest links)", guests)
+	}
+	return sb.String()
+}
+
+// guestVoters counts the votes cast through guest links.
+func guestVoters(r storage.VotingRound) int {
+	n := 0
+	for token, pass := range r.Guests {
+		if _, ok := r.Votes[storage.GuestVoter(token)]; ok && !pass.UsedAt.IsZero() {
+			n++
+		}
+	}
+	return n
+}
+
+// standingsText lists the candidates by votes.
+func standingsText(r storage.VotingRound) string {
+	counts := r.Counts()
+	lines := make([]string, 0, len(r.MovieIDs))
+	for i, id := range r.Standings() {
+		lines = append(lines, fmt.Sprintf("%d. %s — %d", i+1, r.Title(id), counts[id]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func roundKeyboard(r storage.VotingRound) tgbotapi.InlineKeyboardMarkup {
+	counts := r.Counts()
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, id := range r.MovieIDs {
+		label := truncateRunes(fmt.Sprintf("👍 %s (%d)", r.Title(id), counts[id]), 60)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("vround|%s|%d", r.ID, i)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// handleRoundCallback handles the vote buttons, "vround|<round>|<n>"
+// toggling the vote for candidate n.
+func (b *Bot) handleRoundCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cb.Data, "|")
+	if len(parts) != 3 {
+		return
+	}
+	r, ok := b.Store.GetRound(parts[1])
+	i, err := strconv.Atoi(parts[2])
+	if !ok || err != nil || i < 0 || i >= len(r.MovieIDs) {
+		b.answerToast(ctx, cb, "This voting round is over.")
+		return
+	}
+
+	now := time.Now()
+	on, err := b.Store.RoundVote(ctx, r.ID, strconv.FormatInt(cb.From.ID, 10), r.MovieIDs[i], now)
+	if err != nil {
+		b.answerToast(ctx, cb, "❌ "+err.Error())
+		return
+	}
+	lang := b.userLanguage(r.ChatID, cb.From)
+	if on {
+		b.answerToast(ctx, cb, "👍 "+i18n.Tf(lang, "Voted for %s", r.Title(r.MovieIDs[i])))
+	} else {
+		b.answerToast(ctx, cb, i18n.Tf(lang, "Vote for %s taken back", r.Title(r.MovieIDs[i])))
+	}
+
+	if r, ok = b.Store.GetRound(r.ID); ok {
+		b.syncRound(ctx, r)
+	}
+}
+
+// addGuest replies with a one-time link to the open round's ballot on the
+// web server, for a friend who is not on Telegram.
+func (b *Bot) addGuest(ctx context.Context, msg *tgbotapi.Message, r storage.VotingRound, ok bool, name string) {
+	switch {
+	case b.PublicBaseURL == "":
+		b.replyText(ctx, msg, "🌐 Guest voting needs the web server, enable it in the config first.")
+		return
+	case !ok:
+		b.replyText(ctx, msg, "There is no voting round running here.")
+		return
+	}
+	token, err := b.Store.AddGuestPass(ctx, r.ID, name, strconv.FormatInt(msg.From.ID, 10))
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error())
+		return
+	}
+
+	who := "a guest"
+	if name != "" {
+		who = name
+	}
+	text := fmt.Sprintf("🎟 Voting link for %s, good for one vote until %s:\n%s", who, b.deadlineTime(r.ChatID, r.EndsAt), web.GuestURL(b.PublicBaseURL, token))
+	b.replyText(ctx, msg, text)
+	trace.Logf(ctx, "[BOT] %s invited %q to vote in round %s", msg.From.UserName, name, r.ID)
+}
+
+// RefreshRound updates a round's message after a vote cast outside
+// Telegram, such as a guest's on the web server.
+func (b *Bot) RefreshRound(r storage.VotingRound) {
+	b.syncRound(trace.NewContext(context.Background()), r)
+}
+
+// syncRound edits the round's message to the current standings.
+func (b *Bot) syncRound(ctx context.Context, r storage.VotingRound) {
+	edit := tgbotapi.NewEditMessageTextAndMarkup(r.ChatID, r.MessageID, b.votingRoundText(r, time.Now()), roundKeyboard(r))
+	if _, err := b.send(ctx, edit); err != nil && !notModified(err) {
+		trace.Logf(ctx, "[BOT] Updating voting round %s failed: %v", r.ID, err)
+	}
+}
+
+// checkRounds closes the rounds whose time is up and posts the standings of
+// the others when they are due.
+func (b *Bot) checkRounds(ctx context.Context, now time.Time) {
+	for _, r := range b.Store.OpenRounds() {
+		switch {
+		case !now.Before(r.EndsAt):
+			b.closeRound(ctx, r)
+		case now.Sub(r.StandingsAt) >= standingsEvery(r) && r.EndsAt.Sub(now) > standingsEvery(r)/2:
+			b.Store.MarkStandings(ctx, r.ID, now)
+			text := fmt.Sprintf("📊 Standings with %s to go:\n\n%s", leadText(r.EndsAt.Sub(now)), standingsText(r))
+			reply := tgbotapi.NewMessage(r.ChatID, text)
+			reply.ReplyToMessageID = r.MessageID
+			reply.AllowSendingWithoutReply = true
+			b.send(ctx, reply)
+			// Refresh the countdown on the round's message too.
+			b.syncRound(ctx, r)
+		}
+	}
+}
+
+// standingsEvery is how often a round's standings are posted: four times
+// over its length, but at most hourly and at least daily.
+func standingsEvery(r storage.VotingRound) time.Duration {
+	return min(max(r.EndsAt.Sub(r.StartedAt)/4, time.Hour), 24*time.Hour)
+}
+
+// closeRound ends a round, turns its message into the final standings and
+// announces the winner.
+func (b *Bot) closeRound(ctx context.Context, r storage.VotingRound) {
+	r, err := b.Store.CloseRound(ctx, r.ID)
+	if err != nil {
+		return
+	}
+
+	var text string
+	counts := r.Counts()
+	switch len(r.Winners) {
+	case 0:
+		text = "🗳 The voting round closed without a single vote."
+	case 1:
+		text = fmt.Sprintf("🏆 %s wins the voting round with %s!", r.Title(r.Winners[0]), votesText(counts[r.Winners[0]]))
+	default:
+		names := make([]string, len(r.Winners))
+		for i, id := range r.Winners {
+			names[i] = r.Title(id)
+		}
+		text = fmt.Sprintf("🤝 The voting round ends in a tie at %s: %s. Try /election to settle it.", votesText(counts[r.Winners[0]]), strings.Join(names, ", "))
+	}
+
+	edit := tgbotapi.NewEditMessageText(r.ChatID, r.MessageID, b.votingRoundText(r, time.Now()))
+	if _, err := b.send(ctx, edit); err != nil && !notModified(err) {
+		// The round's message is gone; announce the standings with the result.
+		text = standingsText(r) + "\n\n" + text
+	}
+	reply := tgbotapi.NewMessage(r.ChatID, text)
+	reply.ReplyToMessageID = r.MessageID
+	reply.AllowSendingWithoutReply = true
+	b.send(ctx, reply)
+	trace.Logf(ctx, "[BOT] Voting round %s closed, winners %v", r.ID, r.Winners)
+}
+
+// roundHistory lists the chat's latest closed rounds with their winners.
+func (b *Bot) roundHistory(chatID int64) string {
+	rounds := b.Store.PastRounds(chatID)
+	if len(rounds) == 0 {
+		return "🗳 No voting rounds yet. Admins start one with /voteround 48h."
+	}
+	loc := b.chatLocation(chatID)
+	var sb strings.Builder
+	sb.WriteString("🗳 Past voting rounds:\n")
+	for i, r := range rounds {
+		if i == maxRoundHistory {
+			fmt.Fprintf(&sb, "\n… and %d earlier", len(rounds)-i)
+			break
+		}
+		winner := "no votes"
+		if len(r.Winners) > 0 {
+			names := make([]string, len(r.Winners))
+			for j, id := range r.Winners {
+				names[j] = r.Title(id)
+			}
+			winner = "🏆 " + strings.Join(names, " / ") + ", " + votesText(r.Counts()[r.Winners[0]])
+		}
+		fmt.Fprintf(&sb, "\n%s: %s (voters: %d)", r.EndsAt.In(loc).Format("Mon 2 Jan 2006"), winner, len(r.Votes))
+	}
+	return sb.String()
+}
+
+func votesText(n int) string {
+	if n == 1 {
+		return "1 vote"
+	}
+	return strconv.Itoa(n) + " votes"
+}
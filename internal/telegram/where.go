@@ -0,0 +1,105 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"moviebot/internal/storage"
+	"moviebot/internal/streaming"
+	"moviebot/internal/trace"
+)
+
+// =====================================================
+// 📺 /where — streaming services carrying a movie
+// =====================================================
+
+// handleWhere lists the services that stream, rent or sell a movie on the
+// list, or any title OMDb knows, in the configured region.
+func (b *Bot) handleWhere(ctx context.Context, msg *tgbotapi.Message) {
+	if b.Streaming == nil {
+		b.replyText(ctx, msg, "📺 Streaming lookups are not available on this bot.")
+		return
+	}
+	ref := strings.TrimSpace(msg.CommandArguments())
+	if ref == "" {
+		b.replyText(ctx, msg, "Usage: /where <title or IMDb ID>")
+		return
+	}
+	trace.Logf(ctx, "[BOT] /where %q from %s", ref, msg.From.UserName)
+
+	movie, _, err := b.infoMovie(ctx, msg.Chat.ID, ref)
+	if err != nil {
+		b.replyText(ctx, msg, "❌ "+err.Error())
+		return
+	}
+	b.postWhere(ctx, msg.Chat.ID, msg.MessageID, movie)
+}
+
+// whereButton sits next to the Links button of chat cards, "where|<movie>".
+func whereButton(movie storage.Movie) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData("📺 Where", "where|"+movie.ID)
+}
+
+// handleWhereCallback answers a card's Where button with the services, as
+// a reply to the card.
+func (b *Bot) handleWhereCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if cb.Message == nil || b.Streaming == nil {
+		return
+	}
+	movie, ok := b.Store.GetMovieByID(strings.TrimPrefix(cb.Data, "where|"))
+	if !ok {
+		b.answerToast(ctx, cb, "This movie is no longer on the list.")
+		return
+	}
+	trace.Logf(ctx, "[BOT] %s asked where to watch %s", cb.From.UserName, movie.Title)
+	b.postWhere(ctx, cb.Message.Chat.ID, cb.Message.MessageID, movie)
+}
+
+// postWhere replies to replyTo with where movie can be watched, with a
+// button to the page listing the offers.
+func (b *Bot) postWhere(ctx context.Context, chatID int64, replyTo int, movie storage.Movie) {
+	reply := tgbotapi.NewMessage(chatID, "")
+	reply.ReplyToMessageID = replyTo
+
+	a, err := b.Streaming.Find(ctx, movie)
+	switch {
+	case errors.Is(err, streaming.ErrNotFound):
+		reply.Text = fmt.Sprintf("📺 No streaming data for %s (%d).", movie.Title, movie.Year)
+	case err != nil:
+		trace.Logf(ctx, "[STREAMING] Looking up %s failed: %v", movie.Title, err)
+		reply.Text = "⚠️ The streaming lookup failed, try again later."
+	default:
+		reply.Text = whereText(movie, a)
+		reply.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL("🔎 All offers", a.Link),
+		))
+	}
+	b.send(ctx, reply)
+}
+
+// whereText lists the services by kind of offer.
+func whereText(movie storage.Movie, a streaming.Availability) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📺 Where to watch %s (%d) in %s\n", movie.Title, movie.Year, a.Region)
+	if !a.Available() {
+		sb.WriteString("\nNo service carries it there right now.")
+	}
+	for _, offer := range []struct {
+		label     string
+		providers []string
+	}{
+		{"▶️ Stream", a.Stream},
+		{"🆓 Free", a.Free},
+		{"💵 Rent", a.Rent},
+		{"🛒 Buy", a.Buy},
+	} {
+		if len(offer.providers) > 0 {
+			fmt.Fprintf(&sb, "\n%s: %s", offer.label, strings.Join(offer.providers, ", "))
+		}
+	}
+	sb.WriteString("\n\nData by JustWatch")
+	return sb.String()
+}
@@ -0,0 +1,190 @@
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+const apiBase = "https://api.themoviedb.org/3"
+
+// Client is a minimal TMDB v3 client authenticated with an API key.
+type Client struct {
+	APIKey string
+	http   *http.Client
+}
+
+func NewClient(apiKey string) *Client {
+	return &Client{APIKey: apiKey, http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Collection is a TMDB collection (franchise) a movie belongs to.
+type Collection struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Movie is the subset of TMDB's movie record the bot uses.
+type Movie struct {
+	ID                  int         `json:"id"`
+	Title               string      `json:"title"`
+	ImdbID              string      `json:"imdb_id"`
+	Popularity          float64     `json:"popularity"`
+	ReleaseDate         string      `json:"release_date"`
+	BelongsToCollection *Collection `json:"belongs_to_collection"`
+
+	Translations struct {
+		Translations []Translation `json:"translations"`
+	} `json:"translations"` // filled by Client.Movie only
+}
+
+// Translation is a movie's title in one language, as spoken in one country.
+type Translation struct {
+	Language string `json:"iso_639_1"`
+	Country  string `json:"iso_3166_1"`
+	Data     struct {
+		Title string `json:"title"`
+	} `json:"data"`
+}
+
+// LocalTitles maps ISO 639-1 language codes to the movie's title in that
+// language. Where a language has several countries' versions, the country
+// sharing its code wins ("de" in DE over AT), then the first one listed.
+func (m *Movie) LocalTitles() map[string]string {
+	titles := make(map[string]string)
+	for _, t := range m.Translations.Translations {
+		if t.Data.Title == "" {
+			continue
+		}
+		if _, seen := titles[t.Language]; !seen || strings.EqualFold(t.Country, t.Language) {
+			titles[t.Language] = t.Data.Title
+		}
+	}
+	return titles
+}
+
+func (c *Client) get(ctx context.Context, path string, params url.Values, out any) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("api_key", c.APIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiBase+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		trace.Logf(ctx, "[TMDB] HTTP error: %v", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("TMDB %s: HTTP %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// FindByImdbID returns the TMDB movie ID for an IMDb ID, or 0 if unknown.
+func (c *Client) FindByImdbID(ctx context.Context, imdbID string) (int, error) {
+	var r struct {
+		MovieResults []struct {
+			ID int `json:"id"`
+		} `json:"movie_results"`
+	}
+	params := url.Values{}
+	params.Set("external_source", "imdb_id")
+	if err := c.get(ctx, "/find/"+url.PathEscape(imdbID), params, &r); err != nil {
+		return 0, err
+	}
+	if len(r.MovieResults) == 0 {
+		return 0, nil
+	}
+	return r.MovieResults[0].ID, nil
+}
+
+// Movie fetches a movie's details by TMDB ID, with its translations.
+func (c *Client) Movie(ctx context.Context, id int) (*Movie, error) {
+	var m Movie
+	params := url.Values{}
+	params.Set("append_to_response", "translations")
+	if err := c.get(ctx, fmt.Sprintf("/movie/%d", id), params, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Search returns the first page of TMDB's movie search for query, with each
+// result's popularity.
+func (c *Client) Search(ctx context.Context, query string) ([]Movie, error) {
+	var r struct {
+		Results []Movie `json:"results"`
+	}
+	params := url.Values{}
+	params.Set("query", query)
+	if err := c.get(ctx, "/search/movie", params, &r); err != nil {
+		return nil, err
+	}
+	return r.Results, nil
+}
+
+// Video is a clip TMDB lists for a movie, hosted on YouTube or Vimeo.
+type Video struct {
+	Name        string    `json:"name"`
+	Key         string    `json:"key"`  // the video ID on Site
+	Site        string    `json:"site"` // "YouTube", "Vimeo"
+	Type        string    `json:"type"` // "Trailer", "Teaser", "Clip", ...
+	Official    bool      `json:"official"`
+	Language    string    `json:"iso_639_1"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Videos lists a movie's videos by TMDB ID, in language and in English.
+func (c *Client) Videos(ctx context.Context, id int, language string) ([]Video, error) {
+	var r struct {
+		Results []Video `json:"results"`
+	}
+	params := url.Values{}
+	params.Set("include_video_language", language+",en,null")
+	if err := c.get(ctx, fmt.Sprintf("/movie/%d/videos", id), params, &r); err != nil {
+		return nil, err
+	}
+	return r.Results, nil
+}
+
+// Provider is a streaming service, store or rental shop.
+type Provider struct {
+	ID       int    `json:"provider_id"`
+	Name     string `json:"provider_name"`
+	Priority int    `json:"display_priority"` // lower first
+}
+
+// WatchOptions are where a movie can be watched in one country. TMDB's
+// data comes from JustWatch and must be credited to it.
+type WatchOptions struct {
+	Link     string     `json:"link"` // TMDB's watch page for the country
+	Flatrate []Provider `json:"flatrate"`
+	Free     []Provider `json:"free"`
+	Ads      []Provider `json:"ads"`
+	Rent     []Provider `json:"rent"`
+	Buy      []Provider `json:"buy"`
+}
+
+// WatchProviders lists where a movie by TMDB ID can be watched, by ISO
+// 3166-1 country code.
+func (c *Client) WatchProviders(ctx context.Context, id int) (map[string]WatchOptions, error) {
+	var r struct {
+		Results map[string]WatchOptions `json:"results"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/movie/%d/watch/providers", id), nil, &r); err != nil {
+		return nil, err
+	}
+	return r.Results, nil
+}
@@ -0,0 +1,48 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+)
+
+type ctxKey struct{}
+
+// New returns a short random trace ID, good enough to tell concurrent
+// updates apart in the logs.
+func New() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "00000000"
+	}
+	return hex.EncodeToString(b)
+}
+
+// NewContext returns a copy of ctx carrying a freshly generated trace ID.
+func NewContext(ctx context.Context) context.Context {
+	return WithID(ctx, New())
+}
+
+// WithID returns a copy of ctx carrying the given trace ID.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// ID returns the trace ID stored in ctx, or "-" when there is none.
+func ID(ctx context.Context) string {
+	if ctx == nil {
+		return "-"
+	}
+	if id, ok := ctx.Value(ctxKey{}).(string); ok {
+		return id
+	}
+	return "-"
+}
+
+// Logf logs like log.Printf and appends a trace=<id> field so every line
+// belonging to one update can be grepped together.
+func Logf(ctx context.Context, format string, args ...any) {
+	log.Printf("%s trace=%s", fmt.Sprintf(format, args...), ID(ctx))
+}
@@ -0,0 +1,181 @@
+// Package trailers finds the official trailer of a movie on YouTube, from
+// TMDB's video listings or else with the YouTube Data API.
+package trailers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/storage"
+	"moviebot/internal/tmdb"
+	"moviebot/internal/trace"
+)
+
+const youtubeSearchURL = "https://www.googleapis.com/youtube/v3/search"
+
+// ErrNotFound is returned when neither source knows a trailer.
+var ErrNotFound = errors.New("no trailer found")
+
+// Trailer is a video on YouTube.
+type Trailer struct {
+	Name string
+	URL  string
+}
+
+// Client looks trailers up and remembers them, so a card's Trailer button
+// pressed again costs no API quota.
+type Client struct {
+	cfg  config.TrailersConfig
+	tmdb *tmdb.Client
+	http *http.Client
+
+	mu    sync.Mutex
+	found map[string]Trailer // by IMDb ID, or title and year
+}
+
+// NewClient looks trailers up on TMDB through tmdbClient (may be nil) and
+// with the YouTube key of cfg (may be empty).
+func NewClient(cfg config.TrailersConfig, tmdbClient *tmdb.Client) *Client {
+	if cfg.Language == "" {
+		cfg.Language = "en"
+	}
+	return &Client{
+		cfg:   cfg,
+		tmdb:  tmdbClient,
+		http:  &http.Client{Timeout: 10 * time.Second},
+		found: make(map[string]Trailer),
+	}
+}
+
+// Find returns the trailer of m, trying TMDB before YouTube.
+func (c *Client) Find(ctx context.Context, m storage.Movie) (Trailer, error) {
+	key := m.ImdbID
+	if key == "" {
+		key = fmt.Sprintf("%s (%d)", strings.ToLower(m.Title), m.Year)
+	}
+	c.mu.Lock()
+	t, ok := c.found[key]
+	c.mu.Unlock()
+	if ok {
+		return t, nil
+	}
+
+	t, err := c.fromTMDB(ctx, m)
+	if err != nil {
+		trace.Logf(ctx, "[TRAILERS] TMDB has no trailer of %s: %v", m.Title, err)
+		t, err = c.fromYouTube(ctx, m)
+	}
+	if err != nil {
+		return Trailer{}, err
+	}
+
+	c.mu.Lock()
+	c.found[key] = t
+	c.mu.Unlock()
+	return t, nil
+}
+
+// fromTMDB picks among the movie's YouTube videos on TMDB: trailers over
+// teasers, official ones, the preferred language, then the newest.
+func (c *Client) fromTMDB(ctx context.Context, m storage.Movie) (Trailer, error) {
+	if c.tmdb == nil {
+		return Trailer{}, ErrNotFound
+	}
+	id := m.TmdbID
+	if id == 0 && m.ImdbID != "" {
+		var err error
+		if id, err = c.tmdb.FindByImdbID(ctx, m.ImdbID); err != nil {
+			return Trailer{}, err
+		}
+	}
+	if id == 0 {
+		return Trailer{}, ErrNotFound
+	}
+	videos, err := c.tmdb.Videos(ctx, id, c.cfg.Language)
+	if err != nil {
+		return Trailer{}, err
+	}
+
+	var best *tmdb.Video
+	bestScore := 0
+	for i, v := range videos {
+		if v.Site != "YouTube" || (v.Type != "Trailer" && v.Type != "Teaser") {
+			continue
+		}
+		score := 1
+		if v.Type == "Trailer" {
+			score += 8
+		}
+		if v.Official {
+			score += 4
+		}
+		if v.Language == c.cfg.Language {
+			score += 2
+		}
+		if score > bestScore || (score == bestScore && v.PublishedAt.After(best.PublishedAt)) {
+			best, bestScore = &videos[i], score
+		}
+	}
+	if best == nil {
+		return Trailer{}, ErrNotFound
+	}
+	return Trailer{Name: best.Name, URL: "https://www.youtube.com/watch?v=" + url.QueryEscape(best.Key)}, nil
+}
+
+// fromYouTube takes the top YouTube search hit for the official trailer.
+func (c *Client) fromYouTube(ctx context.Context, m storage.Movie) (Trailer, error) {
+	if c.cfg.YouTubeAPIKey == "" {
+		return Trailer{}, ErrNotFound
+	}
+	q := m.Title + " official trailer"
+	if m.Year > 0 {
+		q = fmt.Sprintf("%s %d official trailer", m.Title, m.Year)
+	}
+	params := url.Values{}
+	params.Set("part", "snippet")
+	params.Set("type", "video")
+	params.Set("maxResults", "1")
+	params.Set("q", q)
+	params.Set("relevanceLanguage", c.cfg.Language)
+	params.Set("key", c.cfg.YouTubeAPIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, youtubeSearchURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return Trailer{}, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return Trailer{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Trailer{}, fmt.Errorf("YouTube search: HTTP %d", resp.StatusCode)
+	}
+
+	var r struct {
+		Items []struct {
+			ID struct {
+				VideoID string `json:"videoId"`
+			} `json:"id"`
+			Snippet struct {
+				Title string `json:"title"`
+			} `json:"snippet"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return Trailer{}, err
+	}
+	if len(r.Items) == 0 || r.Items[0].ID.VideoID == "" {
+		return Trailer{}, ErrNotFound
+	}
+	it := r.Items[0]
+	return Trailer{Name: it.Snippet.Title, URL: "https://www.youtube.com/watch?v=" + url.QueryEscape(it.ID.VideoID)}, nil
+}
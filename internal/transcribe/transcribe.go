@@ -0,0 +1,76 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"moviebot/internal/config"
+)
+
+// Client sends audio to a Whisper-style transcription endpoint: the OpenAI
+// /v1/audio/transcriptions API or a local server speaking the same protocol
+// (faster-whisper-server, whisper.cpp's /inference, ...).
+type Client struct {
+	cfg  config.TranscriptionConfig
+	http *http.Client
+}
+
+func NewClient(cfg config.TranscriptionConfig) *Client {
+	return &Client{cfg: cfg, http: &http.Client{Timeout: 60 * time.Second}}
+}
+
+// Transcribe returns the text spoken in audio. filename only hints the
+// format to the backend (Telegram voice notes are "voice.ogg").
+func (c *Client) Transcribe(ctx context.Context, audio []byte, filename string) (string, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	part, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		return "", err
+	}
+	part.Write(audio)
+	if c.cfg.Model != "" {
+		mw.WriteField("model", c.cfg.Model)
+	}
+	if c.cfg.Language != "" {
+		mw.WriteField("language", c.cfg.Language)
+	}
+	mw.WriteField("response_format", "json")
+	mw.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("transcribe: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("transcribe: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var out struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("transcribe: %w", err)
+	}
+	return strings.TrimSpace(out.Text), nil
+}
@@ -0,0 +1,111 @@
+package watchparty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/storage"
+)
+
+// Generator builds watch-together links for scheduled movie nights. A
+// Jellyfin server that has the movie wins over the URL template.
+type Generator struct {
+	cfg    config.WatchPartyConfig
+	client *http.Client
+}
+
+func New(cfg config.WatchPartyConfig) *Generator {
+	return &Generator{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Link returns a watch-together URL for movie at the given time, or "" when
+// nothing is configured. A nil Generator returns "".
+func (g *Generator) Link(ctx context.Context, movie storage.Movie, at time.Time) (string, error) {
+	if g == nil {
+		return "", nil
+	}
+
+	if g.cfg.Jellyfin.URL != "" && g.cfg.Jellyfin.APIKey != "" {
+		link, err := g.jellyfinLink(ctx, movie)
+		if err != nil {
+			log.Printf("[WATCHPARTY] Jellyfin lookup for %s failed: %v", movie.Title, err)
+		} else if link != "" {
+			return link, nil
+		}
+	}
+
+	if g.cfg.URLTemplate == "" {
+		return "", nil
+	}
+	return expand(g.cfg.URLTemplate, movie, at), nil
+}
+
+// expand fills {title}, {year}, {imdb_id} and {time} (RFC 3339) in tmpl with
+// query-escaped values.
+func expand(tmpl string, movie storage.Movie, at time.Time) string {
+	return strings.NewReplacer(
+		"{title}", url.QueryEscape(movie.Title),
+		"{year}", strconv.Itoa(movie.Year),
+		"{imdb_id}", url.QueryEscape(movie.ImdbID),
+		"{time}", url.QueryEscape(at.Format(time.RFC3339)),
+	).Replace(tmpl)
+}
+
+// jellyfinLink finds the movie in the Jellyfin library, matched by IMDb ID
+// when known, and links to its page. SyncPlay groups are tied to a user
+// session, so members start or join the group from the player there.
+func (g *Generator) jellyfinLink(ctx context.Context, movie storage.Movie) (string, error) {
+	base := strings.TrimRight(g.cfg.Jellyfin.URL, "/")
+
+	q := url.Values{}
+	q.Set("Recursive", "true")
+	q.Set("IncludeItemTypes", "Movie")
+	q.Set("SearchTerm", movie.Title)
+	q.Set("Fields", "ProviderIds,ProductionYear")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/Items?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Emby-Token", g.cfg.Jellyfin.APIKey)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Items []struct {
+			ID             string            `json:"Id"`
+			ProductionYear int               `json:"ProductionYear"`
+			ProviderIds    map[string]string `json:"ProviderIds"`
+		} `json:"Items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", err
+	}
+
+	for _, it := range out.Items {
+		match := it.ProductionYear == movie.Year
+		if movie.ImdbID != "" && it.ProviderIds["Imdb"] != "" {
+			match = it.ProviderIds["Imdb"] == movie.ImdbID
+		}
+		if match {
+			return base + "/web/#/details?id=" + it.ID, nil
+		}
+	}
+	return "", nil
+}
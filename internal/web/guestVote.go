@@ -0,0 +1,112 @@
+package web
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"moviebot/internal/trace"
+)
+
+// GuestURL is the public link of the one-time voting page for token.
+func GuestURL(baseURL, token string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/vote/" + token
+}
+
+var guestTemplate = template.Must(template.New("guest").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Movie night vote</title>
+<style>
+body{font-family:system-ui,sans-serif;background:#14161a;color:#e8e8e8;margin:0;padding:1.5rem;max-width:32rem}
+h1{margin-top:0}
+label{display:block;background:#1f2228;border-radius:8px;padding:.7rem .8rem;margin:.5rem 0;cursor:pointer}
+button{margin-top:1rem;padding:.6rem 1.4rem;border:0;border-radius:8px;background:#3b82f6;color:#fff;font-size:1rem;cursor:pointer}
+.note{color:#999}
+</style>
+</head>
+<body>
+<h1>🗳 Movie night vote</h1>
+{{if .Message}}<p>{{.Message}}</p>
+{{else}}<p>{{with .Name}}Hi {{.}}! {{end}}Pick the movie you want to watch. You have one vote, and it can't be changed once cast.</p>
+<form method="post">
+{{range $i, $t := .Titles}}<label><input type="radio" name="movie" value="{{$i}}" required> {{$t}}</label>
+{{end}}<button type="submit">Vote</button>
+</form>
+<p class="note">Voting closes in {{.Left}}.</p>
+{{end}}
+</body>
+</html>
+`))
+
+type guestPage struct {
+	Name    string
+	Titles  []string
+	Left    string
+	Message string
+}
+
+// handleGuestVote serves a guest's one-time voting link: the ballot on GET,
+// the vote on POST. A used link or a finished round only says so.
+func (s *Server) handleGuestVote(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+	round, pass, ok := s.store.RoundByGuestToken(token)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	now := time.Now()
+	page := guestPage{Name: pass.Name, Titles: round.Titles}
+	status := http.StatusOK
+	switch {
+	case !pass.UsedAt.IsZero():
+		page.Message, status = "This link has already been used to vote. Thanks!", http.StatusGone
+	case round.Closed || !now.Before(round.EndsAt):
+		page.Message, status = "This vote is over.", http.StatusGone
+	case r.Method == http.MethodPost:
+		i, err := strconv.Atoi(r.FormValue("movie"))
+		if err != nil || i < 0 || i >= len(round.MovieIDs) {
+			http.Error(w, "pick one of the movies", http.StatusBadRequest)
+			return
+		}
+		ctx := trace.NewContext(r.Context())
+		voted, err := s.store.GuestVote(ctx, token, round.MovieIDs[i], now)
+		if err != nil {
+			page.Message, status = "Your vote could not be counted: "+err.Error()+".", http.StatusConflict
+			break
+		}
+		page.Message = "Thanks! Your vote for " + round.Title(round.MovieIDs[i]) + " is in."
+		if s.OnGuestVote != nil {
+			s.OnGuestVote(voted)
+		}
+	default:
+		page.Left = leftText(round.EndsAt.Sub(now))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(status)
+	if err := guestTemplate.Execute(w, page); err != nil {
+		log.Printf("[WEB] Rendering guest ballot of round %s failed: %v", round.ID, err)
+	}
+}
+
+// leftText reads the time left in a round roughly, like "2 days".
+func leftText(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return strconv.Itoa(int(d/(24*time.Hour))) + " days"
+	case d >= 2*time.Hour:
+		return strconv.Itoa(int(d/time.Hour)) + " hours"
+	case d >= 2*time.Minute:
+		return strconv.Itoa(int(d/time.Minute)) + " minutes"
+	}
+	return "a minute"
+}
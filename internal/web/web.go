@@ -0,0 +1,72 @@
+package web
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"moviebot/internal/config"
+	"moviebot/internal/storage"
+)
+
+// Server is the bot's own HTTP server. It serves each chat's watchlist
+// read-only at /list/<token>, so members can show it to friends who are not
+// in the chat, and one-time ballots at /vote/<token> for guests of a voting
+// round.
+type Server struct {
+	cfg   config.WebConfig
+	store *storage.Store
+	mux   *http.ServeMux
+
+	// OnGuestVote, if set, is called with the round after a guest voted.
+	OnGuestVote func(storage.VotingRound)
+}
+
+func New(cfg config.WebConfig, store *storage.Store) *Server {
+	s := &Server{cfg: cfg, store: store, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /list/{token}", s.handleList)
+	s.mux.HandleFunc("GET /vote/{token}", s.handleGuestVote)
+	s.mux.HandleFunc("POST /vote/{token}", s.handleGuestVote)
+	return s
+}
+
+// ListURL is the public link of the list page for token.
+func ListURL(baseURL, token string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/list/" + token
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	srv := &http.Server{Addr: s.cfg.Listen, Handler: s.mux}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	log.Printf("[WEB] Listening on %s (public base %s)", s.cfg.Listen, s.cfg.BaseURL)
+	return srv.ListenAndServe()
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	chat, ok := s.store.ChatByPublicToken(r.PathValue("token"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	title := "Movie night watchlist"
+	if chat.Title != "" {
+		title = chat.Title + " — watchlist"
+	}
+	page, err := storage.ExportHTML(s.store.GetMovies(s.store.LibraryOf(chat.ID), ""), title)
+	if err != nil {
+		log.Printf("[WEB] Rendering list of chat %d failed: %v", chat.ID, err)
+		http.Error(w, "could not render the list", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.Write(page)
+}
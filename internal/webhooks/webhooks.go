@@ -0,0 +1,135 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"moviebot/internal/config"
+	"moviebot/internal/events"
+)
+
+const (
+	queueSize  = 100
+	maxRetries = 3
+)
+
+// SignatureHeader carries "sha256=<hex HMAC of the body>" when the hook has a
+// secret, in the same shape GitHub uses so existing verifiers work.
+const SignatureHeader = "X-Moviebot-Signature"
+
+type delivery struct {
+	body []byte
+	typ  string
+}
+
+// Dispatcher posts events as JSON to the configured webhook URLs. Each hook
+// has its own queue and background worker, so slow receivers never block
+// update handling, and a dead one never holds up the others.
+type Dispatcher struct {
+	hooks  []config.WebhookConfig
+	client *http.Client
+	queues []chan delivery // one per hook, same order
+}
+
+func NewDispatcher(hooks []config.WebhookConfig) *Dispatcher {
+	d := &Dispatcher{
+		hooks:  hooks,
+		client: &http.Client{Timeout: 10 * time.Second},
+		queues: make([]chan delivery, len(hooks)),
+	}
+	for i, h := range hooks {
+		d.queues[i] = make(chan delivery, queueSize)
+		go d.run(h, d.queues[i])
+	}
+	log.Printf("[WEBHOOK] Dispatcher started with %d hooks", len(hooks))
+	return d
+}
+
+// Handle is an events.Bus subscriber.
+func (d *Dispatcher) Handle(e events.Event) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("[WEBHOOK] Failed to marshal %s event: %v", e.Type, err)
+		return
+	}
+
+	for i, h := range d.hooks {
+		if !wants(h, e.Type) {
+			continue
+		}
+		select {
+		case d.queues[i] <- delivery{body: body, typ: e.Type}:
+		default:
+			log.Printf("[WEBHOOK] Queue full, dropping %s event for %s", e.Type, h.URL)
+		}
+	}
+}
+
+func wants(h config.WebhookConfig, typ string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, t := range h.Events {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// run delivers hook's queue in order, retrying each event with a growing
+// pause before it is dropped.
+func (d *Dispatcher) run(hook config.WebhookConfig, queue <-chan delivery) {
+	for dl := range queue {
+		for attempt := 1; attempt <= maxRetries; attempt++ {
+			err := d.post(hook, dl)
+			if err == nil {
+				break
+			}
+			log.Printf("[WEBHOOK] %s delivery to %s failed (attempt %d/%d): %v",
+				dl.typ, hook.URL, attempt, maxRetries, err)
+			if attempt == maxRetries {
+				log.Printf("[WEBHOOK] Dropping %s event for %s", dl.typ, hook.URL)
+				break
+			}
+			time.Sleep(time.Duration(attempt) * 2 * time.Second)
+		}
+	}
+}
+
+func (d *Dispatcher) post(hook config.WebhookConfig, dl delivery) error {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(dl.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Moviebot-Event", dl.typ)
+	if hook.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(hook.Secret, dl.body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}